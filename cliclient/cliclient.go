@@ -3,6 +3,7 @@ package cliclient
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 
 	errorsPkg "github.com/pkg/errors"
@@ -23,6 +24,8 @@ type MasterClient struct {
 	ProjectClient    *projectClient.Client
 	UserConfig       *config.ServerConfig
 	CAPIClient       *capiClient.Client
+	// DryRun is set when mutating requests are printed instead of being sent
+	DryRun bool
 }
 
 // NewMasterClient returns a new MasterClient with Cluster, Management and Project
@@ -63,6 +66,43 @@ func NewManagementClient(config *config.ServerConfig) (*MasterClient, error) {
 	return mc, nil
 }
 
+// NewCAPIClient returns a new MasterClient with only the CAPI client
+func NewCAPIClient(config *config.ServerConfig) (*MasterClient, error) {
+	mc := &MasterClient{
+		UserConfig: config,
+	}
+
+	err := mc.newCAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return mc, nil
+}
+
+// NewClusterV1Client returns a client for the /v1 API of the downstream
+// cluster clusterID, which serves the Kubernetes resources of that cluster.
+func NewClusterV1Client(config *config.ServerConfig, clusterID string) (*clientbase.APIBaseClient, error) {
+	options := createClientOpts(config)
+	options.URL = strings.TrimSuffix(options.URL, "/v3") + "/k8s/clusters/" + clusterID + "/v1"
+
+	var client clientbase.APIBaseClient
+	err := newCachedClient(config, options, func() (*clientbase.APIBaseClient, error) {
+		var err error
+		client, err = clientbase.NewAPIClient(options)
+		return &client, err
+	})
+	if err != nil {
+		if clientbase.IsNotFound(err) {
+			err = errorsPkg.WithMessagef(err, "Cluster %s not available. Error", clusterID)
+		}
+		return nil, err
+	}
+	if err := configureTransport(options); err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
 // NewClusterClient returns a new MasterClient with only the Cluster client
 func NewClusterClient(config *config.ServerConfig) (*MasterClient, error) {
 	clustProj := CheckProject(config.Project)
@@ -103,10 +143,20 @@ func (mc *MasterClient) newManagementClient() error {
 	options := createClientOpts(mc.UserConfig)
 
 	// Setup the management client
-	mClient, err := managementClient.NewClient(options)
+	var mClient *managementClient.Client
+	err := newCachedClient(mc.UserConfig, options, func() (*clientbase.APIBaseClient, error) {
+		var err error
+		if mClient, err = managementClient.NewClient(options); err != nil {
+			return nil, err
+		}
+		return &mClient.APIBaseClient, nil
+	})
 	if err != nil {
 		return err
 	}
+	if err := configureTransport(options); err != nil {
+		return err
+	}
 	mc.ManagementClient = mClient
 
 	return nil
@@ -117,13 +167,23 @@ func (mc *MasterClient) newClusterClient() error {
 	options.URL = options.URL + "/clusters/" + mc.UserConfig.FocusedCluster()
 
 	// Setup the project client
-	cc, err := clusterClient.NewClient(options)
+	var cc *clusterClient.Client
+	err := newCachedClient(mc.UserConfig, options, func() (*clientbase.APIBaseClient, error) {
+		var err error
+		if cc, err = clusterClient.NewClient(options); err != nil {
+			return nil, err
+		}
+		return &cc.APIBaseClient, nil
+	})
 	if err != nil {
 		if clientbase.IsNotFound(err) {
 			err = errorsPkg.WithMessage(err, "Current cluster not available, try running `rancher context switch`. Error")
 		}
 		return err
 	}
+	if err := configureTransport(options); err != nil {
+		return err
+	}
 	mc.ClusterClient = cc
 
 	return nil
@@ -134,13 +194,23 @@ func (mc *MasterClient) newProjectClient() error {
 	options.URL = options.URL + "/projects/" + mc.UserConfig.Project
 
 	// Setup the project client
-	pc, err := projectClient.NewClient(options)
+	var pc *projectClient.Client
+	err := newCachedClient(mc.UserConfig, options, func() (*clientbase.APIBaseClient, error) {
+		var err error
+		if pc, err = projectClient.NewClient(options); err != nil {
+			return nil, err
+		}
+		return &pc.APIBaseClient, nil
+	})
 	if err != nil {
 		if clientbase.IsNotFound(err) {
 			err = errorsPkg.WithMessage(err, "Current project not available, try running `rancher context switch`. Error")
 		}
 		return err
 	}
+	if err := configureTransport(options); err != nil {
+		return err
+	}
 	mc.ProjectClient = pc
 
 	return nil
@@ -151,24 +221,45 @@ func (mc *MasterClient) newCAPIClient() error {
 	options.URL = strings.TrimSuffix(options.URL, "/v3") + "/v1"
 
 	// Setup the CAPI client
-	cc, err := capiClient.NewClient(options)
+	var cc *capiClient.Client
+	err := newCachedClient(mc.UserConfig, options, func() (*clientbase.APIBaseClient, error) {
+		var err error
+		if cc, err = capiClient.NewClient(options); err != nil {
+			return nil, err
+		}
+		return &cc.APIBaseClient, nil
+	})
 	if err != nil {
 		return err
 	}
+	if err := configureTransport(options); err != nil {
+		return err
+	}
 	mc.CAPIClient = cc
 
 	return nil
 }
 
+// ByID fetches resource with whichever client knows its type; clients that
+// were not constructed are skipped.
 func (mc *MasterClient) ByID(resource *ntypes.Resource, respObject interface{}) error {
-	if strings.HasPrefix(resource.Type, "cluster.x-k8s.io") {
+	if strings.HasPrefix(resource.Type, "cluster.x-k8s.io") && mc.CAPIClient != nil {
 		return mc.CAPIClient.ByID(resource.Type, resource.ID, &respObject)
-	} else if _, ok := mc.ManagementClient.APIBaseClient.Types[resource.Type]; ok {
-		return mc.ManagementClient.ByID(resource.Type, resource.ID, &respObject)
-	} else if _, ok := mc.ProjectClient.APIBaseClient.Types[resource.Type]; ok {
-		return mc.ProjectClient.ByID(resource.Type, resource.ID, &respObject)
-	} else if _, ok := mc.ClusterClient.APIBaseClient.Types[resource.Type]; ok {
-		return mc.ClusterClient.ByID(resource.Type, resource.ID, &respObject)
+	}
+	if mc.ManagementClient != nil {
+		if _, ok := mc.ManagementClient.APIBaseClient.Types[resource.Type]; ok {
+			return mc.ManagementClient.ByID(resource.Type, resource.ID, &respObject)
+		}
+	}
+	if mc.ProjectClient != nil {
+		if _, ok := mc.ProjectClient.APIBaseClient.Types[resource.Type]; ok {
+			return mc.ProjectClient.ByID(resource.Type, resource.ID, &respObject)
+		}
+	}
+	if mc.ClusterClient != nil {
+		if _, ok := mc.ClusterClient.APIBaseClient.Types[resource.Type]; ok {
+			return mc.ClusterClient.ByID(resource.Type, resource.ID, &respObject)
+		}
 	}
 	return fmt.Errorf("MasterClient - unknown resource type %v", resource.Type)
 }
@@ -181,10 +272,12 @@ func createClientOpts(config *config.ServerConfig) *clientbase.ClientOpts {
 	}
 
 	options := &clientbase.ClientOpts{
-		URL:       serverURL,
-		AccessKey: config.AccessKey,
-		SecretKey: config.SecretKey,
-		CACerts:   config.CACerts,
+		URL:        serverURL,
+		AccessKey:  config.AccessKey,
+		SecretKey:  config.SecretKey,
+		CACerts:    config.CACerts,
+		HTTPClient: &http.Client{},
+		Timeout:    getRequestTimeout(),
 	}
 	return options
 }
@@ -0,0 +1,21 @@
+package cliclient
+
+import (
+	"testing"
+
+	"github.com/rancher/norman/clientbase"
+	ntypes "github.com/rancher/norman/types"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByIDSkipsMissingClients(t *testing.T) {
+	mc := &MasterClient{
+		ManagementClient: &managementClient.Client{
+			APIBaseClient: clientbase.APIBaseClient{Types: map[string]ntypes.Schema{}},
+		},
+	}
+
+	err := mc.ByID(&ntypes.Resource{ID: "p-1", Type: "namespace"}, &map[string]interface{}{})
+	assert.EqualError(t, err, "MasterClient - unknown resource type namespace")
+}
@@ -0,0 +1,188 @@
+package cliclient
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rancher/norman/clientbase"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultRetries is how many times a request failing with a transient
+	// error is retried by default
+	DefaultRetries = 3
+
+	retryInitialDelay = 500 * time.Millisecond
+	retryMaxDelay     = 30 * time.Second
+)
+
+var (
+	retriesLock sync.RWMutex
+	retries     = DefaultRetries
+)
+
+// SetRetries sets how many times the clients created afterwards retry requests
+// failing with a transient error, 0 disables retries.
+func SetRetries(n int) {
+	retriesLock.Lock()
+	defer retriesLock.Unlock()
+	retries = n
+}
+
+func getRetries() int {
+	retriesLock.RLock()
+	defer retriesLock.RUnlock()
+	return retries
+}
+
+// retryTransport retries requests failing because of rate limiting, an
+// unavailable server or a network error, waiting exponentially longer between
+// the attempts. Requests that aren't idempotent are only retried when the
+// server can't have processed them.
+type retryTransport struct {
+	next         http.RoundTripper
+	retries      int
+	initialDelay time.Duration
+}
+
+func newRetryTransport(next http.RoundTripper, retries int) http.RoundTripper {
+	if retries <= 0 {
+		return next
+	}
+	return &retryTransport{next: next, retries: retries, initialDelay: retryInitialDelay}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if attempt >= t.retries || !t.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+		// the body can't be sent again
+		if req.Body != nil && req.GetBody == nil {
+			return resp, err
+		}
+
+		delay := t.delay(attempt, resp)
+		if err != nil {
+			logrus.Debugf("Retrying %s %s in %s after error: %v", req.Method, req.URL, delay, err)
+		} else {
+			logrus.Debugf("Retrying %s %s in %s after status %d", req.Method, req.URL, delay, resp.StatusCode)
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+func (t *retryTransport) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			// the request never reached the server
+			return true
+		}
+		return isIdempotent(req.Method)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return isIdempotent(req.Method)
+	}
+	return false
+}
+
+// delay returns how long to wait before the next attempt, honoring the
+// Retry-After header sent with rate limited or unavailable responses.
+func (t *retryTransport) delay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, retryMaxDelay)
+		}
+	}
+	delay := min(t.initialDelay<<attempt, retryMaxDelay)
+	// half of the delay is random so that clients don't retry in lockstep
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// retryClientCreation calls newClient, which creates a client and fetches the
+// schemas of its API, until it succeeds or fails with an error that isn't
+// transient. norman does the fetch with a transport of its own, so it is
+// retried here with the retries and delays of the retry transport.
+func retryClientCreation(newClient func() error) error {
+	return retryCreation(newClient, getRetries(), retryInitialDelay)
+}
+
+func retryCreation(newClient func() error, retries int, initialDelay time.Duration) error {
+	t := &retryTransport{retries: retries, initialDelay: initialDelay}
+	for attempt := 0; ; attempt++ {
+		err := newClient()
+		if err == nil || attempt >= t.retries || !isTransientError(err) {
+			return err
+		}
+		delay := t.delay(attempt, nil)
+		logrus.Debugf("Retrying the schema discovery in %s after error: %v", delay, err)
+		time.Sleep(delay)
+	}
+}
+
+// isTransientError reports whether a failed GET is worth retrying, like
+// retryTransport.shouldRetry does for a response.
+func isTransientError(err error) bool {
+	var apiErr *clientbase.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	// an untrusted certificate is reported to the user, such as by login
+	var certErr *tls.CertificateVerificationError
+	if errors.As(err, &certErr) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
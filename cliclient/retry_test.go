@@ -0,0 +1,104 @@
+package cliclient
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rancher/norman/clientbase"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		statuses []int
+		want     int
+		attempts int
+	}{
+		{"retries unavailable server", http.MethodGet, []int{503, 429, 200}, 200, 3},
+		{"gives up after the retries", http.MethodGet, []int{503, 503, 503, 503, 200}, 503, 4},
+		{"doesn't retry client errors", http.MethodGet, []int{404, 200}, 404, 1},
+		{"doesn't retry POST after a bad gateway", http.MethodPost, []int{502, 200}, 502, 1},
+		{"retries POST when rate limited", http.MethodPost, []int{429, 201}, 201, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					body := make([]byte, 64)
+					n, _ := r.Body.Read(body)
+					assert.Equal(t, `{"name":"test"}`, string(body[:n]), "the body must be sent with each attempt")
+				}
+				w.WriteHeader(tt.statuses[attempts])
+				attempts++
+			}))
+			defer server.Close()
+
+			transport := &retryTransport{next: http.DefaultTransport, retries: 3, initialDelay: 1}
+			client := &http.Client{Transport: transport}
+			req, err := http.NewRequest(tt.method, server.URL, strings.NewReader(`{"name":"test"}`))
+			assert.NoError(t, err)
+
+			resp, err := client.Do(req)
+			assert.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, tt.want, resp.StatusCode)
+			assert.Equal(t, tt.attempts, attempts)
+		})
+	}
+}
+
+func TestRetryCreation(t *testing.T) {
+	tt := []struct {
+		name             string
+		errs             []error
+		expectedAttempts int
+		expectedErr      bool
+	}{
+		{
+			name:             "unavailable then created",
+			errs:             []error{&clientbase.APIError{StatusCode: http.StatusServiceUnavailable}, &net.OpError{Op: "dial", Err: errors.New("connection refused")}, nil},
+			expectedAttempts: 3,
+		},
+		{
+			name:             "unauthorized",
+			errs:             []error{&clientbase.APIError{StatusCode: http.StatusUnauthorized}},
+			expectedAttempts: 1,
+			expectedErr:      true,
+		},
+		{
+			name:             "untrusted certificate",
+			errs:             []error{&tls.CertificateVerificationError{Err: errors.New("x509: certificate signed by unknown authority")}},
+			expectedAttempts: 1,
+			expectedErr:      true,
+		},
+		{
+			name:             "retries exhausted",
+			errs:             []error{&clientbase.APIError{StatusCode: http.StatusBadGateway}, &clientbase.APIError{StatusCode: http.StatusBadGateway}, &clientbase.APIError{StatusCode: http.StatusBadGateway}},
+			expectedAttempts: 3,
+			expectedErr:      true,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			attempts := 0
+			err := retryCreation(func() error {
+				err := tc.errs[attempts]
+				attempts++
+				return err
+			}, 2, time.Millisecond)
+
+			assert.Equal(t, tc.expectedAttempts, attempts)
+			assert.Equal(t, tc.expectedErr, err != nil)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package cliclient
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rancher/cli/config"
+	"github.com/rancher/norman/clientbase"
+	ntypes "github.com/rancher/norman/types"
+	"github.com/sirupsen/logrus"
+)
+
+// SchemaCacheTTL is how long the schemas of a server are cached, it bounds
+// how long stale schemas are used when the server is upgraded without the
+// version in the config being refreshed by `rancher version` or a new login.
+const SchemaCacheTTL = 24 * time.Hour
+
+var (
+	schemaCacheLock sync.RWMutex
+	schemaCacheDir  string
+)
+
+// SetSchemaCache sets the directory where the schemas discovered while
+// creating a client are cached, keyed by the server version. An empty dir
+// disables the cache.
+func SetSchemaCache(dir string) {
+	schemaCacheLock.Lock()
+	defer schemaCacheLock.Unlock()
+	schemaCacheDir = dir
+}
+
+func getSchemaCacheDir() string {
+	schemaCacheLock.RLock()
+	defer schemaCacheLock.RUnlock()
+	return schemaCacheDir
+}
+
+// schemaCachePath returns the file caching the schemas of the API at
+// options.URL as seen by the user of the access key on serverVersion, as
+// schemas only list the methods the user is allowed to use.
+func schemaCachePath(dir, serverVersion string, options *clientbase.ClientOpts) string {
+	sum := sha256.Sum256([]byte(options.URL + "\n" + options.AccessKey + "\n" + serverVersion))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// newCachedClient creates a client with newClient, loading the schemas from
+// the on-disk cache when they were cached for the version of the server and
+// caching them otherwise. Schema discoveries failing with transient errors
+// are retried.
+//
+// norman always discovers the schemas over HTTP with a transport of its own,
+// so cached schemas are served to it from a loopback server, then the URL of
+// the client is set back to the one of the server. The links of the schemas
+// are absolute, the requests made with the client go to the server.
+func newCachedClient(sc *config.ServerConfig, options *clientbase.ClientOpts, newClient func() (*clientbase.APIBaseClient, error)) error {
+	var client *clientbase.APIBaseClient
+	discover := func() error {
+		return retryClientCreation(func() (err error) {
+			client, err = newClient()
+			return err
+		})
+	}
+
+	dir := getSchemaCacheDir()
+	if dir == "" || sc.ServerVersion == "" {
+		return discover()
+	}
+	path := schemaCachePath(dir, sc.ServerVersion, options)
+
+	if body, ok := readCachedSchemas(path); ok {
+		err := newClientFromSchemas(options, body, newClient)
+		if err == nil {
+			return nil
+		}
+		logrus.Debugf("Unable to use the cached schemas of %s: %v", options.URL, err)
+	}
+
+	if err := discover(); err != nil {
+		return err
+	}
+	writeCachedSchemas(path, client.Types)
+	return nil
+}
+
+func newClientFromSchemas(options *clientbase.ClientOpts, body []byte, newClient func() (*clientbase.APIBaseClient, error)) error {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	url := "http://" + listener.Addr().String()
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			rw.Header().Set("X-API-Schemas", url)
+			rw.Header().Set("Content-Type", "application/json")
+			rw.Write(body)
+		}),
+	}
+	go server.Serve(listener)
+	defer server.Close()
+
+	serverURL := options.URL
+	options.URL = url
+	defer func() {
+		options.URL = serverURL
+	}()
+	_, err = newClient()
+	return err
+}
+
+func readCachedSchemas(path string) ([]byte, bool) {
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > SchemaCacheTTL {
+		return nil, false
+	}
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	return body, true
+}
+
+// writeCachedSchemas caches the schemas, failures only cost the next command
+// a schema discovery so they are logged and ignored.
+func writeCachedSchemas(path string, schemas map[string]ntypes.Schema) {
+	if err := writeSchemas(path, schemas); err != nil {
+		logrus.Debugf("Unable to cache the schemas in %s: %v", path, err)
+	}
+}
+
+func writeSchemas(path string, schemas map[string]ntypes.Schema) error {
+	collection := ntypes.SchemaCollection{}
+	for _, schema := range schemas {
+		collection.Data = append(collection.Data, schema)
+	}
+	body, err := json.Marshal(collection)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	// written aside then renamed, so concurrent commands never read half a file
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,52 @@
+package cliclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rancher/cli/config"
+	"github.com/rancher/norman/clientbase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCachedClient(t *testing.T) {
+	requests := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-API-Schemas", server.URL+"/v3")
+		w.Write([]byte(`{"data":[{"id":"cluster","pluralName":"clusters","links":{"collection":"` + server.URL + `/v3/clusters"}}]}`))
+	}))
+	defer server.Close()
+
+	SetSchemaCache(t.TempDir())
+	defer SetSchemaCache("")
+
+	newClient := func(serverVersion string) *clientbase.APIBaseClient {
+		options := &clientbase.ClientOpts{URL: server.URL + "/v3", HTTPClient: &http.Client{}}
+		var client clientbase.APIBaseClient
+		err := newCachedClient(&config.ServerConfig{ServerVersion: serverVersion}, options, func() (*clientbase.APIBaseClient, error) {
+			var err error
+			client, err = clientbase.NewAPIClient(options)
+			return &client, err
+		})
+		require.NoError(t, err)
+		assert.Equal(t, server.URL+"/v3", client.Opts.URL)
+		return &client
+	}
+
+	newClient("v2.8.0")
+	assert.Equal(t, 1, requests)
+
+	client := newClient("v2.8.0")
+	assert.Equal(t, 1, requests, "the cached schemas must be used")
+	assert.Equal(t, server.URL+"/v3/clusters", client.Types["cluster"].Links["collection"])
+
+	newClient("v2.9.0")
+	assert.Equal(t, 2, requests, "the schemas of another version must be fetched")
+
+	newClient("")
+	assert.Equal(t, 3, requests, "nothing is cached without a version")
+}
@@ -0,0 +1,132 @@
+package cliclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rancher/norman/clientbase"
+)
+
+// TransportWrapper decorates the HTTP transport used for API requests.
+type TransportWrapper func(http.RoundTripper) http.RoundTripper
+
+var (
+	transportWrappersLock sync.RWMutex
+	transportWrappers     []TransportWrapper
+
+	requestTimeoutLock sync.RWMutex
+	requestTimeout     time.Duration
+
+	// sharedTransports are keyed by the CA certs they trust, so that all the
+	// clients talking to a server reuse the same connections and TLS sessions.
+	sharedTransportsLock sync.Mutex
+	sharedTransports     = map[string]*http.Transport{}
+)
+
+// AddTransportWrapper registers a wrapper applied to the transport of every
+// client created afterwards. Wrappers are applied in the order they were
+// added, so the last one added sees each request first. The schema discovery
+// done while creating a client is not wrapped, as norman replaces the
+// transport of the client it is given, it is retried by retryClientCreation
+// instead.
+func AddTransportWrapper(w TransportWrapper) {
+	transportWrappersLock.Lock()
+	defer transportWrappersLock.Unlock()
+	transportWrappers = append(transportWrappers, w)
+}
+
+// SetRequestTimeout sets how long each API request of the clients created
+// afterwards may take, 0 keeps the default of one minute.
+func SetRequestTimeout(timeout time.Duration) {
+	requestTimeoutLock.Lock()
+	defer requestTimeoutLock.Unlock()
+	requestTimeout = timeout
+}
+
+func getRequestTimeout() time.Duration {
+	requestTimeoutLock.RLock()
+	defer requestTimeoutLock.RUnlock()
+	return requestTimeout
+}
+
+// configureTransport replaces the transport created by norman for each client
+// with the transport shared by all clients of the server, retrying transient
+// errors, then applies the registered wrappers.
+func configureTransport(options *clientbase.ClientOpts) error {
+	transport, err := sharedTransport(options.CACerts)
+	if err != nil {
+		return err
+	}
+	options.HTTPClient.Transport = newRetryTransport(transport, getRetries())
+	wrapTransport(options.HTTPClient)
+	return nil
+}
+
+func sharedTransport(caCerts string) (*http.Transport, error) {
+	sharedTransportsLock.Lock()
+	defer sharedTransportsLock.Unlock()
+	if transport, ok := sharedTransports[caCerts]; ok {
+		return transport, nil
+	}
+
+	transport, err := newTransport(caCerts)
+	if err != nil {
+		return nil, err
+	}
+	sharedTransports[caCerts] = transport
+	return transport, nil
+}
+
+// newTransport returns a transport keeping connections alive between requests
+// and resuming TLS sessions when new connections are needed.
+func newTransport(caCerts string) (*http.Transport, error) {
+	tlsConfig := &tls.Config{
+		ClientSessionCache: tls.NewLRUClientSessionCache(0),
+	}
+	if caCerts != "" {
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM([]byte(caCerts)) {
+			return nil, errors.New("no valid CA certificates found in the config")
+		}
+		tlsConfig.RootCAs = roots
+	}
+
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   10,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+		TLSClientConfig:       tlsConfig,
+	}, nil
+}
+
+func wrapTransport(client *http.Client) {
+	transportWrappersLock.RLock()
+	defer transportWrappersLock.RUnlock()
+	for _, w := range transportWrappers {
+		client.Transport = w(client.Transport)
+	}
+}
+
+// TLSConfig returns the TLS configuration of the connections to a server
+// trusting caCerts, for the connections that aren't API requests such as
+// websockets.
+func TLSConfig(caCerts string) (*tls.Config, error) {
+	transport, err := sharedTransport(caCerts)
+	if err != nil {
+		return nil, err
+	}
+	return transport.TLSClientConfig.Clone(), nil
+}
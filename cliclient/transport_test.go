@@ -0,0 +1,21 @@
+package cliclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSharedTransport(t *testing.T) {
+	first, err := sharedTransport("")
+	assert.NoError(t, err)
+	second, err := sharedTransport("")
+	assert.NoError(t, err)
+
+	assert.Same(t, first, second, "clients of the same server must share connections")
+	assert.NotNil(t, first.TLSClientConfig.ClientSessionCache)
+	assert.NotZero(t, first.IdleConnTimeout)
+
+	_, err = sharedTransport("not a certificate")
+	assert.Error(t, err)
+}
@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rancher/cli/cliclient"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/urfave/cli"
+)
+
+const accessLsDescription = `
+Lists the clusters and projects a user or a group can access, from the role bindings of the
+global roles, of the clusters and of the projects. The owners of a cluster can access all of its
+projects, which are listed too with the cluster role in the VIA column.
+
+The principal is given by ID, as shown by the members commands, or by name. Access a user is
+given through groups is only listed when the groups are given too, as the groups of a user are
+known to the auth provider only.
+
+Example:
+	$ rancher access ls --principal okta_group://platform-team
+	$ rancher access ls --principal local://u-abc12 --principal okta_group://platform-team
+	$ rancher access ls --principal alice
+`
+
+// clusterOwnerRole is the cluster role granting access to every project of
+// the cluster.
+const clusterOwnerRole = "cluster-owner"
+
+// AccessEntry is a role a principal has on the server, a cluster or a project
+type AccessEntry struct {
+	Principal string
+	Scope     string
+	Cluster   string
+	Project   string
+	Role      string
+	Via       string
+}
+
+// accessPrincipal is a principal whose access is listed. The bindings of a
+// user refer to it either by principal or by user ID.
+type accessPrincipal struct {
+	ID     string
+	Name   string
+	Type   string
+	UserID string
+}
+
+func AccessCommand() cli.Command {
+	return cli.Command{
+		Name:  "access",
+		Usage: "Operations on the access of users and groups",
+		Subcommands: []cli.Command{
+			{
+				Name:        "ls",
+				Usage:       "List the clusters and projects users or groups can access",
+				Description: accessLsDescription,
+				ArgsUsage:   "None",
+				Action:      accessLs,
+				Flags: []cli.Flag{
+					cli.StringSliceFlag{
+						Name:  "principal",
+						Usage: "ID or name of the user or group, can be used multiple times",
+					},
+					formatFlag,
+					filterFlag,
+					labelSelectorFlag,
+					noHeadersFlag,
+				},
+			},
+		},
+	}
+}
+
+func accessLs(ctx *cli.Context) error {
+	if len(ctx.StringSlice("principal")) == 0 {
+		return NewUsageError(errors.New("--principal is required"))
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	var principals []accessPrincipal
+	for _, arg := range ctx.StringSlice("principal") {
+		principal, err := resolveAccessPrincipal(ctx, c, arg)
+		if err != nil {
+			return err
+		}
+		principals = append(principals, *principal)
+	}
+
+	globalCollection, err := c.ManagementClient.GlobalRoleBinding.List(defaultListOpts(nil))
+	if err != nil {
+		return err
+	}
+	globalBindings, err := listAll(nil, globalCollection, func(c *managementClient.GlobalRoleBindingCollection) []managementClient.GlobalRoleBinding {
+		return c.Data
+	})
+	if err != nil {
+		return err
+	}
+	clusterCollection, err := c.ManagementClient.ClusterRoleTemplateBinding.List(defaultListOpts(nil))
+	if err != nil {
+		return err
+	}
+	clusterBindings, err := listAll(nil, clusterCollection, func(c *managementClient.ClusterRoleTemplateBindingCollection) []managementClient.ClusterRoleTemplateBinding {
+		return c.Data
+	})
+	if err != nil {
+		return err
+	}
+	projectCollection, err := c.ManagementClient.ProjectRoleTemplateBinding.List(defaultListOpts(nil))
+	if err != nil {
+		return err
+	}
+	projectBindings, err := listAll(nil, projectCollection, func(c *managementClient.ProjectRoleTemplateBindingCollection) []managementClient.ProjectRoleTemplateBinding {
+		return c.Data
+	})
+	if err != nil {
+		return err
+	}
+	clusters, err := listAllClusters(nil, c.ManagementClient)
+	if err != nil {
+		return err
+	}
+	projects, err := listAllProjects(nil, c.ManagementClient)
+	if err != nil {
+		return err
+	}
+
+	writer := NewTableWriter([][]string{
+		{"PRINCIPAL", "Principal"},
+		{"SCOPE", "Scope"},
+		{"CLUSTER", "Cluster"},
+		{"PROJECT", "Project"},
+		{"ROLE", "Role"},
+		{"VIA", "Via"},
+	}, ctx)
+	for _, entry := range accessEntries(principals, globalBindings, clusterBindings, projectBindings, clusters, projects) {
+		writer.Write(entry)
+	}
+	writer.Close()
+	return writer.Err()
+}
+
+// resolveAccessPrincipal returns the principal given by ID, or else searched
+// by name. The user of a user principal is looked up by its principal IDs.
+func resolveAccessPrincipal(ctx *cli.Context, c *cliclient.MasterClient, arg string) (*accessPrincipal, error) {
+	var principal *managementClient.Principal
+	var err error
+	if strings.Contains(arg, "://") {
+		principal, err = c.ManagementClient.Principal.ByID(url.PathEscape(arg))
+	} else {
+		principal, err = searchForMember(ctx, c, arg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := &accessPrincipal{
+		ID:   principal.ID,
+		Name: valueOrDefault(principal.LoginName, principal.Name),
+		Type: principal.PrincipalType,
+	}
+	if principal.PrincipalType != "user" {
+		return resolved, nil
+	}
+	collection, err := c.ManagementClient.User.List(defaultListOpts(nil))
+	if err != nil {
+		return nil, err
+	}
+	users, err := listAll(nil, collection, func(c *managementClient.UserCollection) []managementClient.User { return c.Data })
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		if slices.Contains(user.PrincipalIDs, principal.ID) {
+			resolved.UserID = user.ID
+			break
+		}
+	}
+	return resolved, nil
+}
+
+// matches reports whether a binding to a user or a group principal is one of
+// the principal.
+func (p accessPrincipal) matches(userID, userPrincipalID, groupPrincipalID string) bool {
+	if p.Type == "user" {
+		return (p.UserID != "" && userID == p.UserID) || (userPrincipalID != "" && userPrincipalID == p.ID)
+	}
+	return groupPrincipalID != "" && groupPrincipalID == p.ID
+}
+
+// accessEntries returns the roles of principals on the server, the clusters
+// and the projects, including the projects of the clusters they own.
+func accessEntries(principals []accessPrincipal, globalBindings []managementClient.GlobalRoleBinding,
+	clusterBindings []managementClient.ClusterRoleTemplateBinding, projectBindings []managementClient.ProjectRoleTemplateBinding,
+	clusters []managementClient.Cluster, projects []managementClient.Project) []AccessEntry {
+	clusterNames := map[string]string{}
+	for _, cluster := range clusters {
+		clusterNames[cluster.ID] = cluster.Name
+	}
+	projectNames := map[string]string{}
+	for _, project := range projects {
+		projectNames[project.ID] = project.Name
+	}
+
+	var entries []AccessEntry
+	for _, principal := range principals {
+		for _, binding := range globalBindings {
+			if principal.matches(binding.UserID, "", binding.GroupPrincipalID) {
+				entries = append(entries, AccessEntry{
+					Principal: principal.Name,
+					Scope:     "global",
+					Role:      binding.GlobalRoleID,
+				})
+			}
+		}
+		for _, binding := range clusterBindings {
+			if !principal.matches(binding.UserID, binding.UserPrincipalID, binding.GroupPrincipalID) {
+				continue
+			}
+			entries = append(entries, AccessEntry{
+				Principal: principal.Name,
+				Scope:     "cluster",
+				Cluster:   valueOrDefault(clusterNames[binding.ClusterID], binding.ClusterID),
+				Role:      binding.RoleTemplateID,
+			})
+			if binding.RoleTemplateID != clusterOwnerRole {
+				continue
+			}
+			for _, project := range projects {
+				if project.ClusterID == binding.ClusterID {
+					entries = append(entries, AccessEntry{
+						Principal: principal.Name,
+						Scope:     "project",
+						Cluster:   valueOrDefault(clusterNames[project.ClusterID], project.ClusterID),
+						Project:   project.Name,
+						Role:      "project-owner",
+						Via:       clusterOwnerRole,
+					})
+				}
+			}
+		}
+		for _, binding := range projectBindings {
+			if !principal.matches(binding.UserID, binding.UserPrincipalID, binding.GroupPrincipalID) {
+				continue
+			}
+			clusterID, _, _ := strings.Cut(binding.ProjectID, ":")
+			entries = append(entries, AccessEntry{
+				Principal: principal.Name,
+				Scope:     "project",
+				Cluster:   valueOrDefault(clusterNames[clusterID], clusterID),
+				Project:   valueOrDefault(projectNames[binding.ProjectID], binding.ProjectID),
+				Role:      binding.RoleTemplateID,
+			})
+		}
+	}
+
+	scopes := map[string]int{"global": 0, "cluster": 1, "project": 2}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Principal != b.Principal {
+			return a.Principal < b.Principal
+		}
+		if scopes[a.Scope] != scopes[b.Scope] {
+			return scopes[a.Scope] < scopes[b.Scope]
+		}
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		if a.Project != b.Project {
+			return a.Project < b.Project
+		}
+		return a.Role < b.Role
+	})
+	return entries
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"testing"
+
+	ntypes "github.com/rancher/norman/types"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessEntries(t *testing.T) {
+	group := accessPrincipal{ID: "okta_group://platform", Name: "platform", Type: "group"}
+	user := accessPrincipal{ID: "local://u-1", Name: "alice", Type: "user", UserID: "u-1"}
+
+	globalBindings := []managementClient.GlobalRoleBinding{
+		{GlobalRoleID: "user", UserID: "u-1"},
+		{GlobalRoleID: "admin", UserID: "u-2"},
+	}
+	clusterBindings := []managementClient.ClusterRoleTemplateBinding{
+		{ClusterID: "c-1", RoleTemplateID: "cluster-owner", GroupPrincipalID: "okta_group://platform"},
+		{ClusterID: "c-2", RoleTemplateID: "cluster-member", UserPrincipalID: "local://u-1"},
+		{ClusterID: "c-2", RoleTemplateID: "cluster-owner", GroupPrincipalID: "okta_group://other"},
+	}
+	projectBindings := []managementClient.ProjectRoleTemplateBinding{
+		{ProjectID: "c-2:p-3", RoleTemplateID: "project-member", UserID: "u-1"},
+	}
+	clusters := []managementClient.Cluster{
+		{Resource: ntypes.Resource{ID: "c-1"}, Name: "prod"},
+		{Resource: ntypes.Resource{ID: "c-2"}, Name: "staging"},
+	}
+	projects := []managementClient.Project{
+		{Resource: ntypes.Resource{ID: "c-1:p-1"}, Name: "web", ClusterID: "c-1"},
+		{Resource: ntypes.Resource{ID: "c-1:p-2"}, Name: "api", ClusterID: "c-1"},
+		{Resource: ntypes.Resource{ID: "c-2:p-3"}, Name: "qa", ClusterID: "c-2"},
+	}
+
+	entries := accessEntries([]accessPrincipal{group, user}, globalBindings, clusterBindings, projectBindings, clusters, projects)
+	assert.Equal(t, []AccessEntry{
+		{Principal: "alice", Scope: "global", Role: "user"},
+		{Principal: "alice", Scope: "cluster", Cluster: "staging", Role: "cluster-member"},
+		{Principal: "alice", Scope: "project", Cluster: "staging", Project: "qa", Role: "project-member"},
+		{Principal: "platform", Scope: "cluster", Cluster: "prod", Role: "cluster-owner"},
+		{Principal: "platform", Scope: "project", Cluster: "prod", Project: "api", Role: "project-owner", Via: "cluster-owner"},
+		{Principal: "platform", Scope: "project", Cluster: "prod", Project: "web", Role: "project-owner", Via: "cluster-owner"},
+	}, entries)
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rancher/cli/cliclient"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+// deletedAppAnswersTTL is how long the answers of a deleted app are offered
+// as the defaults of an app installed with the same name.
+const deletedAppAnswersTTL = 7 * 24 * time.Hour
+
+const deletedAppsFile = "deleted-apps.json"
+
+var fromAppFlag = cli.StringFlag{
+	Name:  "from-app",
+	Usage: "Use the answers of another app as the defaults of the questions and answers",
+}
+
+type deletedAppEntry struct {
+	Answers          map[string]string `json:"answers,omitempty"`
+	AnswersSetString map[string]string `json:"answersSetString,omitempty"`
+	Deleted          time.Time         `json:"deleted"`
+}
+
+// answerHistory keeps on disk the answers of the apps deleted by the CLI, so
+// that recreating an app after deleting it starts from its last answers. The
+// file sits next to the config, which holds the credentials already. It's
+// disabled until configured with a path.
+type answerHistory struct {
+	mu   sync.Mutex
+	path string
+	now  func() time.Time
+}
+
+var deletedApps = &answerHistory{now: time.Now}
+
+func deletedAppsPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), deletedAppsFile)
+}
+
+// deletedAppKey identifies an app of a project, or a multi-cluster app when
+// project is empty, of a server.
+func deletedAppKey(server, project, name string) string {
+	return server + lookupCacheKeySep + project + lookupCacheKeySep + name
+}
+
+func (h *answerHistory) configure(path string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.path = path
+}
+
+// get returns the answers of the app deleted with key, nil when there are
+// none or they expired.
+func (h *answerHistory) get(key string) *deletedAppEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.path == "" {
+		return nil
+	}
+	entry, ok := h.load()[key]
+	if !ok || h.now().After(entry.Deleted.Add(deletedAppAnswersTTL)) {
+		return nil
+	}
+	return &entry
+}
+
+// record saves the answers of the app deleted with key. Failing to save them
+// isn't fatal, the app was deleted.
+func (h *answerHistory) record(key string, answers, answersSetString map[string]string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.path == "" || (len(answers) == 0 && len(answersSetString) == 0) {
+		return
+	}
+	entries := h.load()
+	now := h.now()
+	for key, entry := range entries {
+		if now.After(entry.Deleted.Add(deletedAppAnswersTTL)) {
+			delete(entries, key)
+		}
+	}
+	entries[key] = deletedAppEntry{Answers: answers, AnswersSetString: answersSetString, Deleted: now}
+
+	content, err := json.Marshal(entries)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(h.path), 0700)
+	}
+	if err == nil {
+		err = os.WriteFile(h.path, content, 0600)
+	}
+	if err != nil {
+		logrus.Debugf("Unable to save the answers of the deleted app: %v", err)
+	}
+}
+
+// load reads the saved answers, a missing or unreadable file is treated as
+// empty.
+func (h *answerHistory) load() map[string]deletedAppEntry {
+	entries := make(map[string]deletedAppEntry)
+	content, err := os.ReadFile(h.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logrus.Debugf("Unable to read the answers of deleted apps %s: %v", h.path, err)
+		}
+		return entries
+	}
+	if err := json.Unmarshal(content, &entries); err != nil {
+		logrus.Debugf("Ignoring the invalid answers of deleted apps %s: %v", h.path, err)
+		return make(map[string]deletedAppEntry)
+	}
+	return entries
+}
+
+// clear removes the saved answers.
+func (h *answerHistory) clear() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err := os.Remove(h.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// previousAppAnswers returns the answers an app installed as name starts
+// from: those of the app given with --from-app, or else those of the app
+// deleted with the same name. lookup returns the answers of an existing app.
+func previousAppAnswers(ctx *cli.Context, key string, lookup func(name string) (map[string]string, map[string]string, error)) (map[string]string, map[string]string, error) {
+	if from := ctx.String("from-app"); from != "" {
+		answers, answersSetString, err := lookup(from)
+		if err != nil {
+			return nil, nil, err
+		}
+		fmt.Fprintf(os.Stderr, "Using the answers of app %s as defaults\n", from)
+		return answers, answersSetString, nil
+	}
+	if entry := deletedApps.get(key); entry != nil {
+		fmt.Fprintf(os.Stderr, "Using the answers of the app deleted on %s as defaults, pass --from-app to start from another app\n",
+			entry.Deleted.Local().Format(time.DateTime))
+		return entry.Answers, entry.AnswersSetString, nil
+	}
+	return nil, nil, nil
+}
+
+// withAnswerDefaults returns tv with the defaults of its questions replaced by
+// previous answers, and the answers the install starts from. When
+// interactive the answers to the questions are left out, so that they are
+// asked with the previous answers as defaults.
+func withAnswerDefaults(tv *managementClient.TemplateVersion, previous map[string]string, interactive bool) (*managementClient.TemplateVersion, map[string]string) {
+	answers := make(map[string]string, len(previous))
+	for key, value := range previous {
+		answers[key] = value
+	}
+	if tv == nil || len(previous) == 0 {
+		return tv, answers
+	}
+
+	withDefaults := *tv
+	withDefaults.Questions = make([]managementClient.Question, len(tv.Questions))
+	for i, question := range tv.Questions {
+		if value, ok := previous[question.Variable]; ok {
+			question.Default = value
+			if interactive {
+				delete(answers, question.Variable)
+			}
+		}
+		question.Subquestions = make([]managementClient.SubQuestion, len(tv.Questions[i].Subquestions))
+		for j, subQuestion := range tv.Questions[i].Subquestions {
+			if value, ok := previous[subQuestion.Variable]; ok {
+				subQuestion.Default = value
+				if interactive {
+					delete(answers, subQuestion.Variable)
+				}
+			}
+			question.Subquestions[j] = subQuestion
+		}
+		withDefaults.Questions[i] = question
+	}
+	return &withDefaults, answers
+}
+
+// recordDeletedApp saves the answers of an app deleted from project, or of a
+// multi-cluster app when project is empty.
+func recordDeletedApp(c *cliclient.MasterClient, project, name string, answers, answersSetString map[string]string) {
+	if c.DryRun {
+		return
+	}
+	deletedApps.record(deletedAppKey(c.UserConfig.URL, project, name), answers, answersSetString)
+}
+
+// previousInstallAnswers returns the answers an app installed as name in the
+// current project starts from.
+func previousInstallAnswers(ctx *cli.Context, c *cliclient.MasterClient, name string) (map[string]string, map[string]string, error) {
+	return previousAppAnswers(ctx, deletedAppKey(c.UserConfig.URL, c.UserConfig.Project, name),
+		func(from string) (map[string]string, map[string]string, error) {
+			resource, err := Lookup(c, from, "app")
+			if err != nil {
+				return nil, nil, err
+			}
+			app, err := c.ProjectClient.App.ByID(resource.ID)
+			if err != nil {
+				return nil, nil, err
+			}
+			return app.Answers, app.AnswersSetString, nil
+		})
+}
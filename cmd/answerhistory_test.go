@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnswerHistory(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), deletedAppsFile)
+	key := deletedAppKey("https://rancher.example.com", "c-1:p-1", "redis")
+
+	history := &answerHistory{now: func() time.Time { return now }}
+	history.configure(path)
+	history.record(key, map[string]string{"replicas": "3"}, nil)
+
+	// a new invocation reads the answers from disk
+	reloaded := &answerHistory{now: func() time.Time { return now.Add(24 * time.Hour) }}
+	reloaded.configure(path)
+	entry := reloaded.get(key)
+	require.NotNil(t, entry)
+	assert.Equal(t, map[string]string{"replicas": "3"}, entry.Answers)
+	assert.Nil(t, reloaded.get(deletedAppKey("https://rancher.example.com", "c-1:p-2", "redis")))
+
+	reloaded.now = func() time.Time { return now.Add(deletedAppAnswersTTL + time.Hour) }
+	assert.Nil(t, reloaded.get(key), "expired answers must not be returned")
+
+	assert.NoError(t, reloaded.clear())
+	assert.NoFileExists(t, path)
+}
+
+func TestWithAnswerDefaults(t *testing.T) {
+	tv := &managementClient.TemplateVersion{
+		Questions: []managementClient.Question{
+			{Variable: "replicas", Default: "1"},
+			{Variable: "persistence.enabled", Default: "false", Subquestions: []managementClient.SubQuestion{
+				{Variable: "persistence.size", Default: "8Gi"},
+			}},
+		},
+	}
+	previous := map[string]string{"replicas": "3", "persistence.size": "20Gi", "image.tag": "7.2"}
+
+	questions, answers := withAnswerDefaults(tv, previous, true)
+	assert.Equal(t, "3", questions.Questions[0].Default)
+	assert.Equal(t, "false", questions.Questions[1].Default)
+	assert.Equal(t, "20Gi", questions.Questions[1].Subquestions[0].Default)
+	assert.Equal(t, map[string]string{"image.tag": "7.2"}, answers, "the questions are asked with the previous answers as defaults")
+	assert.Equal(t, "1", tv.Questions[0].Default, "the template version must not be changed")
+	assert.Equal(t, "8Gi", tv.Questions[1].Subquestions[0].Default)
+
+	_, answers = withAnswerDefaults(tv, previous, false)
+	assert.Equal(t, previous, answers)
+}
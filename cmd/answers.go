@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/urfave/cli"
+)
+
+// stdinInput is the name of an input file read from stdin
+const stdinInput = "-"
+
+// stdinOnce makes sure stdin is only read for one input file, a second read
+// would silently get nothing.
+var stdinOnce sync.Once
+
+// The strategies of --merge-answers for combining the answers given on
+// upgrade with the existing answers of an app.
+const (
+	// mergeOverride sets the given answers over the existing answers
+	mergeOverride = "override"
+	// mergeKeep only adds the given answers that aren't already set
+	mergeKeep = "keep"
+	// mergeReplace drops the existing answers
+	mergeReplace = "replace"
+)
+
+var mergeAnswersFlag = cli.StringFlag{
+	Name:  "merge-answers",
+	Usage: "How --answers and --set combine with the existing answers: 'override' them, 'keep' them or 'replace' them",
+	Value: mergeOverride,
+}
+
+var expandEnvFlag = cli.BoolFlag{
+	Name:  "expand-env",
+	Usage: "Substitute ${VAR} references in the string values of the answers and values files with environment variables",
+}
+
+// extendsKey is the key of an answers or values file listing the files it
+// extends. Their paths are relative to the directory of the file.
+const extendsKey = "extends"
+
+// envReference matches the ${VAR} references substituted by --expand-env,
+// $VAR is left alone as values such as passwords may contain a $.
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+var setJSONFlag = cli.StringSliceFlag{
+	Name:  "set-json",
+	Usage: "Set a value of the helm values to JSON keeping its type, can be used multiple times. Example: --set-json 'ingress.hosts=[\"a.example.com\"]'",
+}
+
+var showAnswersFlag = cli.BoolFlag{
+	Name:  "show-answers",
+	Usage: "Print the answers the app is upgraded with, secret answers are masked unless --show-secrets is given",
+}
+
+type AnswerData struct {
+	Key    string
+	Value  string
+	String bool
+}
+
+// answersMergeStrategy returns the --merge-answers strategy, --reset being
+// the same as replace.
+func answersMergeStrategy(ctx *cli.Context) (string, error) {
+	if ctx.Bool("reset") {
+		return mergeReplace, nil
+	}
+	switch strategy := ctx.String("merge-answers"); strategy {
+	case "":
+		return mergeOverride, nil
+	case mergeOverride, mergeKeep, mergeReplace:
+		return strategy, nil
+	default:
+		return "", NewUsageError(fmt.Errorf("invalid merge-answers %q, expected override, keep or replace", strategy))
+	}
+}
+
+// mergeAnswers merges updates into existing following strategy and returns
+// the resulting answers.
+func mergeAnswers(existing, updates map[string]string, strategy string) map[string]string {
+	merged := make(map[string]string, len(existing)+len(updates))
+	if strategy != mergeReplace {
+		for key, value := range existing {
+			merged[key] = value
+		}
+	}
+	for key, value := range updates {
+		if _, ok := existing[key]; ok && strategy == mergeKeep {
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// printAnswers writes the answers and the string answers of an app
+func printAnswers(ctx *cli.Context, answers, answersSetString map[string]string) error {
+	var rows []AnswerData
+	for key, value := range answers {
+		rows = append(rows, AnswerData{Key: key, Value: answerMasking.value(key, value)})
+	}
+	for key, value := range answersSetString {
+		rows = append(rows, AnswerData{Key: key, Value: answerMasking.value(key, value), String: true})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Key < rows[j].Key
+	})
+
+	writer := NewTableWriter([][]string{
+		{"KEY", "Key"},
+		{"VALUE", "Value"},
+		{"STRING", "String"},
+	}, ctx)
+
+	defer writer.Close()
+
+	for _, row := range rows {
+		writer.Write(row)
+	}
+	return writer.Err()
+}
+
+// readFileOrStdin reads an answers or values file, or stdin for "-".
+func readFileOrStdin(location string) ([]byte, error) {
+	if location != stdinInput {
+		return os.ReadFile(location)
+	}
+	read := false
+	stdinOnce.Do(func() { read = true })
+	if !read {
+		return nil, errors.New("stdin can only be read for one of --answers, --values and --targets-file")
+	}
+	return io.ReadAll(os.Stdin)
+}
+
+// readsStdin reports whether the answers, the values or the targets are read
+// from stdin
+func readsStdin(ctx *cli.Context) bool {
+	return ctx.String("answers") == stdinInput || ctx.String("values") == stdinInput ||
+		ctx.String("targets-file") == stdinInput
+}
+
+// expandEnv substitutes the ${VAR} references in the string values of parsed
+// answers or values with environment variables. The file is parsed first so
+// that a variable can't change its structure, such as with a newline, and
+// the values stay strings. Unset variables are an error, so that a missing
+// variable doesn't silently result in an empty answer.
+func expandEnv(values map[string]interface{}) error {
+	var missing []string
+	for key, value := range values {
+		values[key] = expandEnvValue(value, &missing)
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("environment variables not set: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// expandEnvValue substitutes the references in the strings of value, at any
+// depth, adding the unset variables to missing.
+func expandEnvValue(value interface{}, missing *[]string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return envReference.ReplaceAllStringFunc(v, func(reference string) string {
+			name := envReference.FindStringSubmatch(reference)[1]
+			value, ok := os.LookupEnv(name)
+			if !ok && !slices.Contains(*missing, name) {
+				*missing = append(*missing, name)
+			}
+			return value
+		})
+	case map[string]interface{}:
+		for key, nested := range v {
+			v[key] = expandEnvValue(nested, missing)
+		}
+	case map[interface{}]interface{}:
+		for key, nested := range v {
+			v[key] = expandEnvValue(nested, missing)
+		}
+	case []interface{}:
+		for i, nested := range v {
+			v[i] = expandEnvValue(nested, missing)
+		}
+	}
+	return value
+}
+
+// parseLayeredFile parses the answers or values file at location over the
+// files it extends. The extended files are merged in order, each one over the
+// previous ones, and the file itself over all of them. Nested maps are merged
+// key by key, other values are replaced. chain holds the files extending this
+// one, to detect cycles.
+func parseLayeredFile(location string, expand bool, chain []string) (map[string]interface{}, error) {
+	bytes, err := readFileOrStdin(location)
+	if err != nil {
+		return nil, err
+	}
+	values, err := createValuesMap(bytes)
+	if err != nil {
+		return nil, err
+	}
+	if expand {
+		if err := expandEnv(values); err != nil {
+			return nil, fmt.Errorf("%s: %w", location, err)
+		}
+	}
+
+	bases, err := extendedFiles(location, values[extendsKey])
+	if err != nil {
+		return nil, err
+	}
+	if len(bases) == 0 {
+		return values, nil
+	}
+	delete(values, extendsKey)
+
+	chain = append(chain, filepath.Clean(location))
+	merged := map[string]interface{}{}
+	for _, base := range bases {
+		if slices.Contains(chain, base) {
+			return nil, fmt.Errorf("%s: extending %s is a cycle: %s", location, base, strings.Join(append(chain, base), " -> "))
+		}
+		baseValues, err := parseLayeredFile(base, expand, chain)
+		if err != nil {
+			return nil, err
+		}
+		mergeValues(merged, baseValues)
+	}
+	mergeValues(merged, values)
+	return merged, nil
+}
+
+// extendedFiles returns the paths of the files listed by the extends key of
+// the file at location, a path or a list of paths.
+func extendedFiles(location string, extends interface{}) ([]string, error) {
+	var paths []string
+	switch extends := extends.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		paths = []string{extends}
+	case []interface{}:
+		for _, path := range extends {
+			s, ok := path.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: invalid %s entry %v, expected a path", location, extendsKey, path)
+			}
+			paths = append(paths, s)
+		}
+	default:
+		return nil, fmt.Errorf("%s: invalid %s %v, expected a path or a list of paths", location, extendsKey, extends)
+	}
+
+	// the paths in a file read from stdin are relative to the working directory
+	dir := "."
+	if location != stdinInput {
+		dir = filepath.Dir(location)
+	}
+	for i, path := range paths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		paths[i] = filepath.Clean(path)
+	}
+	return paths, nil
+}
+
+// mergeValues merges src into dst, merging the nested maps key by key.
+func mergeValues(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcOK := stringKeyedMap(value)
+		dstMap, dstOK := stringKeyedMap(dst[key])
+		if !srcOK || !dstOK {
+			dst[key] = value
+			continue
+		}
+		mergeValues(dstMap, srcMap)
+		dst[key] = dstMap
+	}
+}
+
+// stringKeyedMap returns value as a map with string keys when it's a map
+// decoded from JSON or YAML.
+func stringKeyedMap(value interface{}) (map[string]interface{}, bool) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		return value, true
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(value))
+		for key, v := range value {
+			converted[fmt.Sprintf("%v", key)] = v
+		}
+		return converted, true
+	}
+	return nil, false
+}
+
+// applySetJSON sets the KEY=JSON values of --set-json in values. KEY is a
+// dotted path of nested maps.
+func applySetJSON(values map[string]interface{}, entries []string) error {
+	for _, entry := range entries {
+		key, content, ok := strings.Cut(entry, "=")
+		if !ok || key == "" {
+			return NewUsageError(fmt.Errorf("invalid --set-json %q, expected KEY=JSON", entry))
+		}
+		if strings.Contains(key, "[") {
+			return NewUsageError(fmt.Errorf("invalid --set-json %q, list indexes aren't supported, set the whole list", entry))
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(content), &value); err != nil {
+			return NewUsageError(fmt.Errorf("invalid JSON in --set-json %q: %w", entry, err))
+		}
+		setNestedValue(values, strings.Split(key, "."), value)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeAnswers(t *testing.T) {
+	existing := map[string]string{"replicas": "2", "image": "nginx"}
+	updates := map[string]string{"replicas": "3", "port": "8080"}
+
+	tests := []struct {
+		strategy string
+		want     map[string]string
+	}{
+		{
+			strategy: mergeOverride,
+			want:     map[string]string{"replicas": "3", "image": "nginx", "port": "8080"},
+		},
+		{
+			strategy: mergeKeep,
+			want:     map[string]string{"replicas": "2", "image": "nginx", "port": "8080"},
+		},
+		{
+			strategy: mergeReplace,
+			want:     map[string]string{"replicas": "3", "port": "8080"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			assert.Equal(t, tt.want, mergeAnswers(existing, updates, tt.strategy))
+		})
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("REPLICAS", "3")
+	t.Setenv("DOMAIN", "example.com")
+
+	t.Setenv("INJECTED", "x\nadmin: true")
+
+	values, err := createValuesMap([]byte("replicas: ${REPLICAS}\ningress:\n  hosts:\n  - web.${DOMAIN}\npassword: pa$$word\nnote: ${INJECTED}\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, expandEnv(values))
+	assert.Equal(t, map[string]interface{}{
+		"replicas": "3",
+		"ingress":  map[interface{}]interface{}{"hosts": []interface{}{"web.example.com"}},
+		"password": "pa$$word",
+		// the value of a variable can't add keys
+		"note": "x\nadmin: true",
+	}, values)
+
+	err = expandEnv(map[string]interface{}{"a": "${UNSET_VAR}", "b": []interface{}{"${UNSET_VAR}", "${OTHER_UNSET_VAR}"}})
+	assert.EqualError(t, err, "environment variables not set: OTHER_UNSET_VAR, UNSET_VAR")
+}
+
+func TestApplySetJSON(t *testing.T) {
+	values := map[string]interface{}{"ingress": map[string]interface{}{"enabled": false}}
+
+	err := applySetJSON(values, []string{
+		`ingress.enabled=true`,
+		`ingress.hosts=["a.example.com"]`,
+		`resources={"limits":{"cpu":2}}`,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"ingress": map[string]interface{}{
+			"enabled": true,
+			"hosts":   []interface{}{"a.example.com"},
+		},
+		"resources": map[string]interface{}{
+			"limits": map[string]interface{}{"cpu": float64(2)},
+		},
+	}, values)
+
+	assert.Error(t, applySetJSON(values, []string{`replicas`}))
+	assert.Error(t, applySetJSON(values, []string{`hosts[0]="a"`}))
+	assert.Error(t, applySetJSON(values, []string{`replicas={`}))
+}
+
+func TestParseLayeredFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+	writeFile("base.yaml", "replicas: 2\nimage:\n  repository: nginx\n  tag: \"1.25\"\ningress:\n  enabled: false\n")
+	writeFile("tls.json", `{"ingress": {"enabled": true, "tls": true}}`)
+	prod := writeFile("prod.yaml", "extends:\n- base.yaml\n- tls.json\nreplicas: 5\nimage:\n  tag: \"1.27\"\n")
+
+	values, err := parseLayeredFile(prod, false, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"replicas": 5,
+		"image":    map[string]interface{}{"repository": "nginx", "tag": "1.27"},
+		"ingress":  map[string]interface{}{"enabled": true, "tls": true},
+	}, values)
+
+	a := writeFile("a.yaml", "extends: b.yaml\n")
+	writeFile("b.yaml", "extends: a.yaml\n")
+	_, err = parseLayeredFile(a, false, nil)
+	assert.EqualError(t, err, filepath.Join(dir, "b.yaml")+": extending "+a+" is a cycle: "+
+		a+" -> "+filepath.Join(dir, "b.yaml")+" -> "+a)
+
+	invalid := writeFile("invalid.yaml", "extends: [3]\n")
+	_, err = parseLayeredFile(invalid, false, nil)
+	assert.EqualError(t, err, invalid+": invalid extends entry 3, expected a path")
+}
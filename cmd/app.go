@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -13,7 +14,6 @@ import (
 	"strings"
 	"time"
 
-	gover "github.com/hashicorp/go-version"
 	"github.com/pkg/errors"
 	"github.com/rancher/cli/cliclient"
 	"github.com/rancher/norman/clientbase"
@@ -44,11 +44,37 @@ Example:
 	# Install the redis template and specify an answers file location
 	$ rancher app install --answers /example/answers.yaml redis appFoo
 
+	# Install the redis template with an answers file overlaying a base one. The files listed
+	# under 'extends:' are read first, each overriding the previous ones, then the file itself
+	# overrides them; --set overrides all of them. The same applies to --values files.
+	$ cat prod.yaml
+	extends: [base.yaml]
+	replicas: 5
+	$ rancher app install --answers prod.yaml redis appFoo
+
 	# Install the redis template and set multiple answers and the version to install
 	$ rancher app install --set foo=bar --set-string baz=bunk --version 1.0.1 redis appFoo
 
 	# Install the redis template and specify the namespace for the app
 	$ rancher app install --namespace bar redis appFoo
+
+	# Install the redis template with labels and annotations on the app
+	$ rancher app install --label team=payments --annotation cost-center=cc-123 redis appFoo
+
+	# Wait for the app and print the result as JSON for a CI system to archive
+	$ rancher app install --wait --output json redis appFoo > result.json
+
+	# Install the template version, answers and values of a lock printed by 'rancher app lock'
+	$ rancher app install --locked rancher-lock.yaml
+
+	# Install the app, or upgrade it with the version and answers given when it's already
+	# installed, like 'helm upgrade --install'
+	$ rancher app install --install-or-upgrade --version 1.0.2 --answers answers.yaml redis appFoo
+
+	# Install the redis template starting from the answers of another app, the questions are
+	# asked with its answers as defaults. An app reinstalled within a week of being deleted
+	# with 'rancher app delete' starts from the answers it had.
+	$ rancher app install --from-app appBar redis appFoo
 `
 	upgradeAppDescription = `
 Upgrade an existing app to a newer version via app template or app version in the current Rancher server.
@@ -62,7 +88,12 @@ Example:
 
 	# Upgrade the 'appFoo' app and set multiple answers and the 0.2.0 version to install
 	$ rancher app upgrade --set foo=bar --set-string baz=bunk appFoo 0.2.0
+
+	# Upgrade the 'appFoo' app setting a list in its helm values
+	$ rancher app upgrade --set-json 'ingress.hosts=["a.example.com","b.example.com"]' appFoo 0.2.0
 `
+	// namespaceWaitTimeout is how long to wait for a new namespace to become active
+	namespaceWaitTimeout = 30 * time.Second
 )
 
 type AppData struct {
@@ -71,17 +102,21 @@ type AppData struct {
 	Catalog  string
 	Template string
 	Version  string
+	Age      string
+	Message  string
 }
 
 type TemplateData struct {
 	ID       string
 	Template managementClient.Template
 	Category string
+	Metadata TemplateMetadata
 }
 
 type VersionData struct {
 	Current string
 	Version string
+	Notes   string
 }
 
 type revision struct {
@@ -89,6 +124,7 @@ type revision struct {
 	Name     string
 	Created  time.Time
 	Human    string
+	Age      string
 	Catalog  string
 	Template string
 	Version  string
@@ -109,6 +145,9 @@ type chartMetadata struct {
 	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
 	Keywords    []string `json:"keywords,omitempty" yaml:"keywords,omitempty"`
 	Icon        string   `json:"icon,omitempty" yaml:"icon,omitempty"`
+	Home        string   `json:"home,omitempty" yaml:"home,omitempty"`
+
+	Maintainers []ChartMaintainer `json:"maintainers,omitempty" yaml:"maintainers,omitempty"`
 }
 
 type revSlice []revision
@@ -124,6 +163,13 @@ func AppCommand() cli.Command {
 			Name:  "quiet,q",
 			Usage: "Only display IDs",
 		},
+		filterFlag,
+		labelSelectorFlag,
+		sortByFlag,
+		timestampsFlag,
+		noHeadersFlag,
+		limitFlag,
+		pageSizeFlag,
 	}
 
 	return cli.Command{
@@ -146,22 +192,25 @@ func AppCommand() cli.Command {
 				Usage:     "Delete an app",
 				Action:    appDelete,
 				ArgsUsage: "[APP_NAME/APP_ID]",
+				Flags:     deleteFlags,
 			},
 			{
-				Name:        "install",
-				Usage:       "Install an app template",
-				Description: installAppDescription,
-				Action:      templateInstall,
-				ArgsUsage:   "[TEMPLATE_NAME/TEMPLATE_PATH, APP_NAME]",
+				Name:         "install",
+				Usage:        "Install an app template",
+				Description:  installAppDescription,
+				Action:       templateInstall,
+				BashComplete: templateVersionCompletion,
+				ArgsUsage:    "[TEMPLATE_NAME/TEMPLATE_PATH, APP_NAME]",
 				Flags: []cli.Flag{
 					cli.StringFlag{
 						Name:  "answers,a",
-						Usage: "Path to an answers file, the format of the file is a map with key:value. This supports JSON and YAML.",
+						Usage: "Path to an answers file, the format of the file is a map with key:value. This supports JSON and YAML. Use - to read it from stdin",
 					},
 					cli.StringFlag{
 						Name:  "values",
-						Usage: "Path to a helm values file.",
+						Usage: "Path to a helm values file. Use - to read it from stdin",
 					},
+					expandEnvFlag,
 					cli.StringFlag{
 						Name:  "namespace,n",
 						Usage: "Namespace to install the app into",
@@ -174,6 +223,9 @@ func AppCommand() cli.Command {
 						Name:  "set-string",
 						Usage: "Set string answers for the template (Skips Helm's type conversion), can be used multiple times. Example: --set-string foo=bar",
 					},
+					setJSONFlag,
+					labelFlag,
+					annotationFlag,
 					cli.StringFlag{
 						Name:  "version",
 						Usage: "Version of the template to use",
@@ -191,6 +243,20 @@ func AppCommand() cli.Command {
 						Name:  "helm-wait",
 						Usage: "Helm will wait for as long as timeout value, for installed resources to be ready (pods, PVCs, deployments, etc.). Example: --helm-wait",
 					},
+					cli.BoolFlag{
+						Name:  "wait",
+						Usage: "Wait for the app to become active",
+					},
+					cli.IntFlag{
+						Name:  "wait-timeout",
+						Usage: "Time in seconds to wait for the app with --wait",
+						Value: 600,
+					},
+					installOutputFlag,
+					cleanupOnCancelFlag,
+					lockedFlag,
+					fromAppFlag,
+					installOrUpgradeFlag,
 				},
 			},
 			{
@@ -207,8 +273,19 @@ func AppCommand() cli.Command {
 						Name:  "force,f",
 						Usage: "Force rollback, deletes and recreates resources if needed during rollback. (default is false)",
 					},
+					sortByFlag,
+					timestampsFlag,
+					noHeadersFlag,
 				},
 			},
+			{
+				Name:        "prune-revisions",
+				Usage:       "Delete the old revisions of an app",
+				Description: pruneRevisionsDescription,
+				ArgsUsage:   "[APP_NAME/APP_ID]",
+				Action:      appPruneRevisions,
+				Flags:       pruneRevisionsFlags,
+			},
 			{
 				Name:        "upgrade",
 				Usage:       "Upgrade an existing app to a newer version",
@@ -218,12 +295,13 @@ func AppCommand() cli.Command {
 				Flags: []cli.Flag{
 					cli.StringFlag{
 						Name:  "answers,a",
-						Usage: "Path to an answers file, the format of the file is a map with key:value. Supports JSON and YAML",
+						Usage: "Path to an answers file, the format of the file is a map with key:value. Supports JSON and YAML. Use - to read it from stdin",
 					},
 					cli.StringFlag{
 						Name:  "values",
-						Usage: "Path to a helm values file.",
+						Usage: "Path to a helm values file. Use - to read it from stdin",
 					},
+					expandEnvFlag,
 					cli.StringSliceFlag{
 						Name:  "set",
 						Usage: "Set answers for the template, can be used multiple times. Example: --set foo=bar",
@@ -232,6 +310,7 @@ func AppCommand() cli.Command {
 						Name:  "set-string",
 						Usage: "Set string answers for the template (Skips Helm's type conversion), can be used multiple times. Example: --set-string foo=bar",
 					},
+					setJSONFlag,
 					cli.BoolFlag{
 						Name:  "show-versions,v",
 						Usage: "Display versions available to upgrade to",
@@ -242,8 +321,10 @@ func AppCommand() cli.Command {
 					},
 					cli.BoolFlag{
 						Name:  "force,f",
-						Usage: "Force upgrade, deletes and recreates resources if needed during upgrade. (default is false)",
+						Usage: "Force upgrade, deletes and recreates resources if needed during upgrade and allows versions the chart doesn't support upgrading to. (default is false)",
 					},
+					mergeAnswersFlag,
+					showAnswersFlag,
 				},
 			},
 			{
@@ -265,9 +346,10 @@ func AppCommand() cli.Command {
 				Name:        "show-template",
 				Aliases:     []string{"st"},
 				Usage:       "Show versions available to install for an app template",
-				Description: "\nShow all available versions of an app template",
+				Description: templateShowDescription,
 				ArgsUsage:   "[TEMPLATE_ID]",
 				Action:      templateShow,
+				Flags:       templateShowFlags,
 			},
 			{
 				Name:      "show-app",
@@ -277,8 +359,18 @@ func AppCommand() cli.Command {
 				Action:    showApp,
 				Flags: []cli.Flag{
 					formatFlag,
+					sortByFlag,
+					timestampsFlag,
+					noHeadersFlag,
 				},
 			},
+			{
+				Name:        "lock",
+				Usage:       "Print a lock file pinning an app to its template version and answers",
+				Description: appLockDescription,
+				ArgsUsage:   "[APP_NAME/APP_ID]",
+				Action:      appLock,
+			},
 			{
 				Name:      "show-notes",
 				Usage:     "Show contents of apps notes.txt",
@@ -295,7 +387,11 @@ func appLs(ctx *cli.Context) error {
 		return err
 	}
 
-	collection, err := c.ProjectClient.App.List(defaultListOpts(ctx))
+	collection, err := c.ProjectClient.App.List(filteredListOpts(ctx))
+	if err != nil {
+		return err
+	}
+	collection.Data, err = listAll(ctx, collection, func(c *projectClient.AppCollection) []projectClient.App { return c.Data })
 	if err != nil {
 		return err
 	}
@@ -307,6 +403,10 @@ func appLs(ctx *cli.Context) error {
 		{"CATALOG", "Catalog"},
 		{"TEMPLATE", "Template"},
 		{"VERSION", "Version"},
+		{"AGE", "Age"},
+		{"NAMESPACE", "App.TargetNamespace", wideFormat},
+		{"PROJECT", "App.ProjectID", wideFormat},
+		{"MESSAGE", "Message", wideFormat},
 	}, ctx)
 
 	defer writer.Close()
@@ -339,6 +439,8 @@ func appLs(ctx *cli.Context) error {
 			Catalog:  parsedInfo["catalog"],
 			Template: parsedInfo["template"],
 			Version:  parsedInfo["version"],
+			Age:      formatAge(ctx, item.Created),
+			Message:  FormatMessage(item.TransitioningMessage),
 		}
 		writer.Write(appData)
 	}
@@ -382,7 +484,7 @@ func parseTemplateInfo(appExternalID string, appTemplateFiles map[string]string)
 
 func appDelete(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
 	c, err := GetClient(ctx)
@@ -390,25 +492,31 @@ func appDelete(ctx *cli.Context) error {
 		return err
 	}
 
-	for _, arg := range ctx.Args() {
-		resource, err := Lookup(c, arg, "app")
-		if err != nil {
-			return err
-		}
-
-		app, err := c.ProjectClient.App.ByID(resource.ID)
-		if err != nil {
-			return err
-		}
-
-		err = c.ProjectClient.App.Delete(app)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return bulkDelete{
+		kind: "apps",
+		resolve: func(arg string) (*bulkTarget, error) {
+			resource, err := Lookup(c, arg, "app")
+			if err != nil {
+				return nil, err
+			}
 
+			app, err := c.ProjectClient.App.ByID(resource.ID)
+			if err != nil {
+				return nil, err
+			}
+			return &bulkTarget{
+				resource:     app.Resource,
+				descriptions: []string{fmt.Sprintf("%s (%s) in namespace %s", app.Name, app.ID, app.TargetNamespace)},
+				delete: func() error {
+					if err := c.ProjectClient.App.Delete(app); err != nil {
+						return err
+					}
+					recordDeletedApp(c, app.ProjectID, app.Name, app.Answers, app.AnswersSetString)
+					return nil
+				},
+			}, nil
+		},
+	}.run(ctx, c)
 }
 
 func appUpgrade(ctx *cli.Context) error {
@@ -422,7 +530,7 @@ func appUpgrade(ctx *cli.Context) error {
 	}
 
 	if ctx.NArg() < 2 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
 	appName := ctx.Args().First()
@@ -449,6 +557,11 @@ func appUpgrade(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	if ctx.Bool("show-answers") {
+		if err := printAnswers(ctx, answers, answersSetString); err != nil {
+			return err
+		}
+	}
 
 	force := ctx.Bool("force")
 
@@ -486,6 +599,14 @@ func appUpgrade(ctx *cli.Context) error {
 			return fmt.Errorf("version %s is not valid", appVersion)
 		}
 
+		current, err := templateVersionByExternalID(c, app.ExternalID)
+		if err != nil {
+			return err
+		}
+		if err := checkUpgradeVersion(current, &template.Data[0], force); err != nil {
+			return err
+		}
+
 		au.ExternalID = template.Data[0].ExternalID
 	}
 
@@ -514,7 +635,7 @@ func appRollback(ctx *cli.Context) error {
 	}
 
 	if ctx.NArg() < 2 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
 	force := ctx.Bool("force")
@@ -580,6 +701,7 @@ func templateLs(ctx *cli.Context) error {
 			ID:       item.ID,
 			Template: item,
 			Category: strings.Join(item.Categories, ","),
+			Metadata: templateMetadata(item),
 		})
 	}
 
@@ -588,7 +710,7 @@ func templateLs(ctx *cli.Context) error {
 
 func templateShow(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
 	c, err := GetClient(ctx)
@@ -611,6 +733,26 @@ func templateShow(ctx *cli.Context) error {
 		return err
 	}
 
+	// json and yaml get the metadata of the template with its versions
+	if format := ctx.String("format"); format != "" {
+		if format != "json" && format != "yaml" {
+			return NewUsageError(fmt.Errorf("invalid format %q, use json or yaml", format))
+		}
+
+		metadata := templateMetadata(*template)
+		if err := addChartMetadata(c, template, &metadata); err != nil {
+			return err
+		}
+		for _, version := range sortedVersions {
+			metadata.Versions = append(metadata.Versions, version.Original())
+		}
+
+		writer := NewTableWriter(nil, ctx)
+		defer writer.Close()
+		writer.Write(&metadata)
+		return writer.Err()
+	}
+
 	if len(sortedVersions) == 0 {
 		fmt.Println("No app versions available to install for this version of Rancher server")
 	}
@@ -623,27 +765,76 @@ func templateShow(ctx *cli.Context) error {
 }
 
 func templateInstall(ctx *cli.Context) error {
-	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+	var lock *AppLock
+	if ctx.String("locked") != "" {
+		if ctx.NArg() > 0 || ctx.String("version") != "" || ctx.String("from-app") != "" {
+			return NewUsageError(errors.New("the template, its version, the app name and its answers are given by the lock with --locked"))
+		}
+		var err error
+		if lock, err = readAppLock(ctx.String("locked")); err != nil {
+			return err
+		}
+	} else if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
 	}
 	templateName := ctx.Args().First()
 	appName := ctx.Args().Get(1)
+	if lock != nil {
+		appName = lock.Name
+	}
+	if ctx.Bool("install-or-upgrade") && appName == "" {
+		return errInstallOrUpgradeName
+	}
+
+	output, err := installOutputFormat(ctx)
+	if err != nil {
+		return err
+	}
 
 	c, err := GetClient(ctx)
 	if err != nil {
 		return err
 	}
 
+	labels, annotations, err := appMetadata(ctx)
+	if err != nil {
+		return err
+	}
+
 	app := &projectClient.App{
-		Name: appName,
+		Name:        appName,
+		Labels:      labels,
+		Annotations: annotations,
 	}
-	if resolveTemplatePath(templateName) {
+	if lock != nil {
+		templateVersion, err := templateVersionByExternalID(c, lock.ExternalID)
+		if err != nil {
+			return fmt.Errorf("the locked template version is no longer available: %w", err)
+		}
+		answers, answersSetString, err := processAnswerUpdates(ctx, lock.Answers, lock.AnswersSetString)
+		if err != nil {
+			return err
+		}
+		values, err := processValueInstall(ctx, templateVersion, lock.ValuesYaml)
+		if err != nil {
+			return err
+		}
+		app.Answers = answers
+		app.AnswersSetString = answersSetString
+		app.ValuesYaml = values
+		app.ExternalID = lock.ExternalID
+		app.TargetNamespace = valueOrDefault(ctx.String("namespace"), lock.Namespace)
+	} else if resolveTemplatePath(templateName) {
 		// if it is a path, install charts locally
 		chartName, files, err := walkTemplateDirectory(templateName)
 		if err != nil {
 			return err
 		}
-		answers, answersSetString, err := processAnswerInstall(ctx, nil, nil, nil, false, false)
+		previous, previousSetString, err := previousInstallAnswers(ctx, c, appName)
+		if err != nil {
+			return err
+		}
+		answers, answersSetString, err := processAnswerInstall(ctx, nil, previous, previousSetString, false, false)
 		if err != nil {
 			return err
 		}
@@ -656,15 +847,7 @@ func templateInstall(ctx *cli.Context) error {
 		app.Answers = answers
 		app.AnswersSetString = answersSetString
 		app.ValuesYaml = values
-		namespace := ctx.String("namespace")
-		if namespace == "" {
-			namespace = chartName + "-" + RandomLetters(5)
-		}
-		err = createNamespace(c, namespace)
-		if err != nil {
-			return err
-		}
-		app.TargetNamespace = namespace
+		app.TargetNamespace = valueOrDefault(ctx.String("namespace"), chartName+"-"+RandomLetters(5))
 	} else {
 		resource, err := Lookup(c, templateName, "template")
 		if err != nil {
@@ -676,45 +859,28 @@ func templateInstall(ctx *cli.Context) error {
 			return err
 		}
 
-		latestVersion, err := getTemplateLatestVersion(template)
+		templateVersionID, err := resolveTemplateVersionID(ctx, template, templateName, "app")
 		if err != nil {
 			return err
 		}
 
-		templateVersionID := templateVersionIDFromVersionLink(template.VersionLinks[latestVersion])
-		userVersion := ctx.String("version")
-		if userVersion != "" {
-			if link, ok := template.VersionLinks[userVersion]; ok {
-				templateVersionID = templateVersionIDFromVersionLink(link)
-			} else {
-				return fmt.Errorf(
-					"version %s for template %s is invalid, run 'rancher app show-template %s' for a list of versions",
-					userVersion,
-					templateName,
-					templateName,
-				)
-			}
-		}
-
 		templateVersion, err := c.ManagementClient.TemplateVersion.ByID(templateVersionID)
 		if err != nil {
 			return err
 		}
 
-		interactive := !ctx.Bool("no-prompt")
-		answers, answersSetString, err := processAnswerInstall(ctx, templateVersion, nil, nil, interactive, false)
+		// questions can't be answered when stdin holds the answers
+		interactive := !ctx.Bool("no-prompt") && !readsStdin(ctx)
+		previous, previousSetString, err := previousInstallAnswers(ctx, c, appName)
 		if err != nil {
 			return err
 		}
-		values, err := processValueInstall(ctx, templateVersion, "")
+		questions, previous := withAnswerDefaults(templateVersion, previous, interactive)
+		answers, answersSetString, err := processAnswerInstall(ctx, questions, previous, previousSetString, interactive, false)
 		if err != nil {
 			return err
 		}
-		namespace := ctx.String("namespace")
-		if namespace == "" {
-			namespace = template.Name + "-" + RandomLetters(5)
-		}
-		err = createNamespace(c, namespace)
+		values, err := processValueInstall(ctx, templateVersion, "")
 		if err != nil {
 			return err
 		}
@@ -722,20 +888,69 @@ func templateInstall(ctx *cli.Context) error {
 		app.AnswersSetString = answersSetString
 		app.ValuesYaml = values
 		app.ExternalID = templateVersion.ExternalID
-		app.TargetNamespace = namespace
+		app.TargetNamespace = valueOrDefault(ctx.String("namespace"), template.Name+"-"+RandomLetters(5))
 	}
 
 	app.Wait = ctx.Bool("helm-wait")
 	app.Timeout = ctx.Int64("helm-timeout")
 
+	if ctx.Bool("install-or-upgrade") {
+		existing, err := findApp(c, app.Name)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return upgradeInstalledApp(ctx, c, existing, app, output)
+		}
+	}
+
+	needed := []permission{projectPermission(c, projectClient.AppType, verbCreate)}
+	if ctx.GlobalBool("check-permissions") {
+		existing, err := findNamespace(c, app.TargetNamespace)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			needed = append(needed, clusterPermission(c, clusterClient.NamespaceType, verbCreate))
+		}
+	}
+	if err := checkPermissions(ctx, needed...); err != nil {
+		return err
+	}
+
+	stop := commandInterrupt.watch()
+	defer stop()
+	created := &createdResources{}
+	namespace, err := createNamespace(c, app.TargetNamespace)
+	if namespace != nil {
+		created.add("namespace", namespace.Name, func() error {
+			return c.ClusterClient.Namespace.Delete(namespace)
+		})
+	}
+	if err != nil {
+		return created.interrupted(os.Stderr, err, ctx.Bool("cleanup-on-cancel"))
+	}
+
+	start := time.Now()
 	madeApp, err := c.ProjectClient.App.Create(app)
 	if err != nil {
-		return err
+		return created.interrupted(os.Stderr, err, ctx.Bool("cleanup-on-cancel"))
+	}
+	created.add("app", madeApp.Name, func() error {
+		return c.ProjectClient.App.Delete(madeApp)
+	})
+
+	if ctx.Bool("wait") {
+		err = waitForResource(c, &madeApp.Resource, time.Duration(ctx.Int("wait-timeout"))*time.Second)
+		err = created.interrupted(os.Stderr, err, ctx.Bool("cleanup-on-cancel"))
+	}
+	if output != "" {
+		return writeInstallResult(os.Stdout, c, output, &madeApp.Resource, start, err)
 	}
 
 	fmt.Printf("run \"app show-notes %s\" to view app notes once app is ready\n", madeApp.Name)
 
-	return nil
+	return err
 }
 
 // appNotes prints notes from app's notes.txt file
@@ -746,7 +961,7 @@ func appNotes(ctx *cli.Context) error {
 	}
 
 	if ctx.NArg() < 1 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
 	resource, err := Lookup(c, ctx.Args().First(), "app")
@@ -837,7 +1052,7 @@ func walkTemplateDirectory(templatePath string) (string, map[string]string, erro
 
 func showApp(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
 	c, err := GetClient(ctx)
@@ -861,7 +1076,7 @@ func showApp(ctx *cli.Context) error {
 
 func outputVersions(ctx *cli.Context, c *cliclient.MasterClient) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
 	resource, err := Lookup(c, ctx.Args().First(), "app")
@@ -900,29 +1115,52 @@ func outputVersions(ctx *cli.Context, c *cliclient.MasterClient) error {
 		return nil
 	}
 
+	current, err := templateVersionByExternalID(c, externalID)
+	if err != nil {
+		return err
+	}
+
 	writer := NewTableWriter([][]string{
 		{"CURRENT", "Current"},
 		{"VERSION", "Version"},
+		{"NOTES", "Notes"},
 	}, ctx)
 
 	defer writer.Close()
 
 	for _, version := range sortedVersions {
-		var current string
+		var isCurrent string
 		if version.String() == externalInfo["version"] {
-			current = "*"
+			isCurrent = "*"
 		}
 		writer.Write(&VersionData{
-			Current: current,
+			Current: isCurrent,
 			Version: version.String(),
+			Notes:   versionNotes(template, current, version.String()),
 		})
 	}
 	return writer.Err()
 }
 
+// templateVersionByExternalID returns the template version an app with
+// externalID was installed from.
+func templateVersionByExternalID(c *cliclient.MasterClient, externalID string) (*managementClient.TemplateVersion, error) {
+	filter := defaultListOpts(nil)
+	filter.Filters["externalId"] = externalID
+
+	versions, err := c.ManagementClient.TemplateVersion.List(filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions.Data) == 0 {
+		return nil, notFoundErrorf("no template version found for %s", externalID)
+	}
+	return &versions.Data[0], nil
+}
+
 func outputRevisions(ctx *cli.Context, c *cliclient.MasterClient) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
 	resource, err := Lookup(c, ctx.Args().First(), "app")
@@ -972,6 +1210,7 @@ func outputRevisions(ctx *cli.Context, c *cliclient.MasterClient) error {
 		{"TEMPLATE", "Template"},
 		{"VERSION", "Version"},
 		{"CREATED", "Human"},
+		{"AGE", "Age"},
 	}, ctx)
 
 	defer writer.Close()
@@ -981,6 +1220,7 @@ func outputRevisions(ctx *cli.Context, c *cliclient.MasterClient) error {
 			rev.Current = "*"
 		}
 		rev.Human = rev.Created.Format("02 Jan 2006 15:04:05 MST")
+		rev.Age = formatTimeAge(ctx, rev.Created)
 
 		writer.Write(rev)
 	}
@@ -1026,48 +1266,20 @@ func getFilteredTemplate(ctx *cli.Context, c *cliclient.MasterClient, templateID
 	}
 
 	if len(template.Data) == 0 {
-		return nil, fmt.Errorf("template %v not found", templateID)
+		return nil, notFoundErrorf("template %v not found", templateID)
 	}
 	return &template.Data[0], nil
 }
 
-// getTemplateLatestVersion returns the newest version of the template
-func getTemplateLatestVersion(template *managementClient.Template) (string, error) {
-	if len(template.VersionLinks) == 0 {
-		return "", errors.New("no versions found for this template (the chart you are trying to install may be intentionally hidden or deprecated for your Rancher version)")
-	}
-	sorted, err := sortTemplateVersions(template)
-	if err != nil {
-		return "", err
-	}
-
-	return sorted[len(sorted)-1].String(), nil
-}
-
-func sortTemplateVersions(template *managementClient.Template) ([]*gover.Version, error) {
-	var versions []*gover.Version
-	for key := range template.VersionLinks {
-		v, err := gover.NewVersion(key)
-		if err != nil {
-			return nil, err
-		}
-		versions = append(versions, v)
-	}
-
-	sort.Sort(gover.Collection(versions))
-	return versions, nil
-}
-
-// createNamespace checks if a namespace exists and creates it if needed
-func createNamespace(c *cliclient.MasterClient, n string) error {
-	filter := defaultListOpts(nil)
-	filter.Filters["name"] = n
-	namespaces, err := c.ClusterClient.Namespace.List(filter)
+// createNamespace checks if a namespace exists and creates it if needed. The
+// namespace is returned when it was created, even if waiting for it failed.
+func createNamespace(c *cliclient.MasterClient, n string) (*clusterClient.Namespace, error) {
+	existing, err := findNamespace(c, n)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if len(namespaces.Data) == 0 {
+	if existing == nil {
 		newNamespace := &clusterClient.Namespace{
 			Name:      n,
 			ProjectID: c.UserConfig.Project,
@@ -1075,37 +1287,53 @@ func createNamespace(c *cliclient.MasterClient, n string) error {
 
 		ns, err := c.ClusterClient.Namespace.Create(newNamespace)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		if c.DryRun {
+			return ns, nil
 		}
 
-		nsID := ns.ID
-		startTime := time.Now()
-		for {
-			logrus.Debugf("Namespace create wait - Name: %s, State: %s, Transitioning: %s", ns.Name, ns.State, ns.Transitioning)
-			if time.Since(startTime)/time.Second > 30 {
-				return fmt.Errorf("timed out waiting for new namespace %s", ns.Name)
-			}
-			ns, err = c.ClusterClient.Namespace.ByID(nsID)
+		nsID, nsName := ns.ID, ns.Name
+		waitCtx, cancel := waitContext(namespaceWaitTimeout)
+		defer cancel()
+
+		err = pollUntil(waitCtx, newBackoff(500*time.Millisecond, 5*time.Second), func() (bool, error) {
+			ns, err := c.ClusterClient.Namespace.ByID(nsID)
 			if err != nil {
 				if e, ok := err.(*clientbase.APIError); ok && e.StatusCode == http.StatusForbidden {
 					//the new namespace is created successfully but cannot be got when RBAC rules are not ready.
-					continue
+					return false, nil
 				}
-				return err
-			}
-
-			if ns.State == "active" {
-				break
+				return false, err
 			}
-
-			time.Sleep(500 * time.Millisecond)
+			logrus.Debugf("Namespace create wait - Name: %s, State: %s, Transitioning: %s", ns.Name, ns.State, ns.Transitioning)
+			return ns.State == "active", nil
+		})
+		if errors.Is(err, context.DeadlineExceeded) {
+			return ns, timeoutErrorf("timed out waiting for new namespace %s", nsName)
 		}
-	} else {
-		if namespaces.Data[0].ProjectID != c.UserConfig.Project {
-			return fmt.Errorf("namespace %s already exists", n)
+		if errors.Is(err, context.Canceled) {
+			return ns, interruptedErrorf("interrupted waiting for new namespace %s", nsName)
 		}
+		return ns, err
 	}
-	return nil
+
+	if existing.ProjectID != c.UserConfig.Project {
+		return nil, fmt.Errorf("namespace %s already exists", n)
+	}
+	return nil, nil
+}
+
+// findNamespace returns the namespace of the cluster of the client named n,
+// nil when there's none.
+func findNamespace(c *cliclient.MasterClient, n string) (*clusterClient.Namespace, error) {
+	filter := defaultListOpts(nil)
+	filter.Filters["name"] = n
+	namespaces, err := c.ClusterClient.Namespace.List(filter)
+	if err != nil || len(namespaces.Data) == 0 {
+		return nil, err
+	}
+	return &namespaces.Data[0], nil
 }
 
 // processValueInstall creates a map of the values file and fills in missing entries with defaults
@@ -1155,11 +1383,14 @@ func processValues(ctx *cli.Context, existingValues string) (map[string]interfac
 	}
 	if ctx.String("values") != "" {
 		// if values file passed in, overwrite defaults with new key value pair
-		values, err = parseFile(ctx.String("values"))
+		values, err = parseFile(ctx, ctx.String("values"))
 		if err != nil {
 			return values, err
 		}
 	}
+	if err := applySetJSON(values, ctx.StringSlice("set-json")); err != nil {
+		return values, err
+	}
 	return values, nil
 }
 
@@ -1197,16 +1428,15 @@ func processAnswerInstall(
 
 func processAnswerUpdates(ctx *cli.Context, answers, answersSetString map[string]string) (map[string]string, map[string]string, error) {
 	logrus.Println("ok")
-	if answers == nil || ctx.Bool("reset") {
-		// this would not be possible without returning a map
-		answers = make(map[string]string)
-	}
-	if answersSetString == nil || ctx.Bool("reset") {
-		// this would not be possible without returning a map
-		answersSetString = make(map[string]string)
+	strategy, err := answersMergeStrategy(ctx)
+	if err != nil {
+		return answers, answersSetString, err
 	}
+
+	updates := make(map[string]string)
+	updatesSetString := make(map[string]string)
 	if ctx.String("answers") != "" {
-		err := parseAnswersFile(ctx.String("answers"), answers)
+		err := parseAnswersFile(ctx, ctx.String("answers"), updates)
 		if err != nil {
 			return answers, answersSetString, err
 		}
@@ -1214,17 +1444,17 @@ func processAnswerUpdates(ctx *cli.Context, answers, answersSetString map[string
 	for _, answer := range ctx.StringSlice("set") {
 		parts := strings.SplitN(answer, "=", 2)
 		if len(parts) == 2 {
-			answers[parts[0]] = parts[1]
+			updates[parts[0]] = parts[1]
 		}
 	}
 	for _, answer := range ctx.StringSlice("set-string") {
 		parts := strings.SplitN(answer, "=", 2)
 		logrus.Printf("%v\n", parts)
 		if len(parts) == 2 {
-			answersSetString[parts[0]] = parts[1]
+			updatesSetString[parts[0]] = parts[1]
 		}
 	}
-	return answers, answersSetString, nil
+	return mergeAnswers(answers, updates, strategy), mergeAnswers(answersSetString, updatesSetString, strategy), nil
 }
 
 // parseMapToYamlString create yaml string from answers map
@@ -1236,8 +1466,8 @@ func parseMapToYamlString(answerMap map[string]interface{}) (string, error) {
 	return string(yamlFileString), nil
 }
 
-func parseAnswersFile(location string, answers map[string]string) error {
-	holder, err := parseFile(location)
+func parseAnswersFile(ctx *cli.Context, location string, answers map[string]string) error {
+	holder, err := parseFile(ctx, location)
 	if err != nil {
 		return err
 	}
@@ -1252,12 +1482,8 @@ func parseAnswersFile(location string, answers map[string]string) error {
 	return nil
 }
 
-func parseFile(location string) (map[string]interface{}, error) {
-	bytes, err := os.ReadFile(location)
-	if err != nil {
-		return nil, err
-	}
-	return createValuesMap(bytes)
+func parseFile(ctx *cli.Context, location string) (map[string]interface{}, error) {
+	return parseLayeredFile(location, ctx.Bool("expand-env"), nil)
 }
 
 func createValuesMap(bytes []byte) (map[string]interface{}, error) {
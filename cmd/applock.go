@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	projectClient "github.com/rancher/rancher/pkg/client/generated/project/v3"
+	"github.com/urfave/cli"
+)
+
+const appLockDescription = `
+Prints a lock file pinning an app to its exact template version, answers and values, which
+'rancher app install --locked' installs again, for reproducible deployments.
+
+The lock holds the answers as they are, including the secret ones, which are listed on stderr:
+keep the lock out of version control or give them on install with --set.
+
+Example:
+	$ rancher app lock redis > rancher-lock.yaml
+	$ rancher app install --locked rancher-lock.yaml --set password=$REDIS_PASSWORD
+`
+
+var lockedFlag = cli.StringFlag{
+	Name:  "locked",
+	Usage: "Path to a lock file printed by 'rancher app lock', to install its template version, answers and values. --answers and --set override the answers of the lock",
+}
+
+// AppLock pins an app to the template version, answers and values it was
+// locked with
+type AppLock struct {
+	Name             string            `json:"name"`
+	Namespace        string            `json:"namespace"`
+	ExternalID       string            `json:"externalId"`
+	Template         string            `json:"template,omitempty"`
+	Version          string            `json:"version,omitempty"`
+	Answers          map[string]string `json:"answers,omitempty"`
+	AnswersSetString map[string]string `json:"answersSetString,omitempty"`
+	ValuesYaml       string            `json:"valuesYaml,omitempty"`
+}
+
+func appLock(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+
+	c, err := GetClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	resource, err := Lookup(c, ctx.Args().First(), "app")
+	if err != nil {
+		return err
+	}
+	app, err := c.ProjectClient.App.ByID(resource.ID)
+	if err != nil {
+		return err
+	}
+
+	lock, err := newAppLock(app)
+	if err != nil {
+		return err
+	}
+	if secrets := lock.secretAnswers(); len(secrets) > 0 {
+		fmt.Fprintf(os.Stderr, "The lock holds the secret answers %s\n", strings.Join(secrets, ", "))
+	}
+	return writeAppLock(os.Stdout, lock)
+}
+
+func newAppLock(app *projectClient.App) (*AppLock, error) {
+	if app.ExternalID == "" {
+		return nil, fmt.Errorf("app %s was installed from local files, only apps of a catalog can be locked", app.Name)
+	}
+	parsed, err := parseExternalID(app.ExternalID)
+	if err != nil {
+		return nil, err
+	}
+	return &AppLock{
+		Name:             app.Name,
+		Namespace:        app.TargetNamespace,
+		ExternalID:       app.ExternalID,
+		Template:         parsed["template"],
+		Version:          parsed["version"],
+		Answers:          app.Answers,
+		AnswersSetString: app.AnswersSetString,
+		ValuesYaml:       app.ValuesYaml,
+	}, nil
+}
+
+// secretAnswers returns the keys of the answers of the lock that are secrets,
+// whatever --show-secrets.
+func (l *AppLock) secretAnswers() []string {
+	masking := answerMasking
+	masking.show = false
+	var secrets []string
+	for _, answers := range []map[string]string{l.Answers, l.AnswersSetString} {
+		for key, value := range answers {
+			if value != "" && masking.isSecret(key) {
+				secrets = append(secrets, key)
+			}
+		}
+	}
+	sort.Strings(secrets)
+	return secrets
+}
+
+func writeAppLock(out io.Writer, lock *AppLock) error {
+	content, err := yaml.Marshal(lock)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(content)
+	return err
+}
+
+func readAppLock(path string) (*AppLock, error) {
+	content, err := readFileOrStdin(path)
+	if err != nil {
+		return nil, err
+	}
+	lock := &AppLock{}
+	if err := yaml.Unmarshal(content, lock); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if lock.Name == "" || lock.ExternalID == "" {
+		return nil, fmt.Errorf("%s: a lock needs the name and the externalId of the app", path)
+	}
+	return lock, nil
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	projectClient "github.com/rancher/rancher/pkg/client/generated/project/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppLock(t *testing.T) {
+	app := &projectClient.App{
+		Name:             "redis",
+		TargetNamespace:  "redis-prod",
+		ExternalID:       "catalog://?catalog=library&template=redis&version=10.5.7",
+		Answers:          map[string]string{"cluster.slaveCount": "2", "password": "s3cret"},
+		AnswersSetString: map[string]string{"image.tag": "6.0"},
+		ValuesYaml:       "persistence:\n  size: 8Gi\n",
+	}
+
+	lock, err := newAppLock(app)
+	assert.NoError(t, err)
+	assert.Equal(t, "redis", lock.Template)
+	assert.Equal(t, "10.5.7", lock.Version)
+	assert.Equal(t, []string{"password"}, lock.secretAnswers())
+
+	var out bytes.Buffer
+	assert.NoError(t, writeAppLock(&out, lock))
+	path := filepath.Join(t.TempDir(), "rancher-lock.yaml")
+	assert.NoError(t, os.WriteFile(path, out.Bytes(), 0600))
+
+	read, err := readAppLock(path)
+	assert.NoError(t, err)
+	assert.Equal(t, lock, read)
+
+	_, err = newAppLock(&projectClient.App{Name: "local"})
+	assert.EqualError(t, err, "app local was installed from local files, only apps of a catalog can be locked")
+
+	assert.NoError(t, os.WriteFile(path, []byte("name: redis\n"), 0600))
+	_, err = readAppLock(path)
+	assert.EqualError(t, err, path+": a lock needs the name and the externalId of the app")
+}
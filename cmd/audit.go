@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+// AuditEntry is a line of the audit log, recording a request that changed or
+// tried to change a resource.
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	User     string    `json:"user"`
+	Server   string    `json:"server"`
+	Method   string    `json:"method"`
+	Resource string    `json:"resource"`
+	Status   int       `json:"status,omitempty"`
+	Outcome  string    `json:"outcome"`
+	Error    string    `json:"error,omitempty"`
+}
+
+type AuditEntryData struct {
+	Entry AuditEntry
+	Time  string
+}
+
+// auditTransport appends every mutating API request to a JSONL audit log.
+type auditTransport struct {
+	next http.RoundTripper
+	log  *auditLog
+	now  func() time.Time
+}
+
+// auditLog serializes the writes of concurrent requests to the log file.
+type auditLog struct {
+	path string
+	lock sync.Mutex
+}
+
+func newAuditTransport(next http.RoundTripper, log *auditLog) http.RoundTripper {
+	return &auditTransport{
+		next: next,
+		log:  log,
+		now:  time.Now,
+	}
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return t.next.RoundTrip(req)
+	}
+
+	entry := AuditEntry{
+		Time:     t.now().UTC(),
+		Server:   req.URL.Host,
+		Method:   req.Method,
+		Resource: req.URL.RequestURI(),
+	}
+	// the access key is the name of the token, never its secret
+	entry.User, _, _ = req.BasicAuth()
+
+	resp, err := t.next.RoundTrip(req)
+	switch {
+	case err != nil:
+		entry.Outcome = "error"
+		entry.Error = err.Error()
+	case resp.StatusCode >= 400:
+		entry.Status = resp.StatusCode
+		entry.Outcome = "failure"
+	default:
+		entry.Status = resp.StatusCode
+		entry.Outcome = "success"
+	}
+
+	// the request was made, failing to record it shouldn't fail the command
+	if logErr := t.log.append(entry); logErr != nil {
+		logrus.Warnf("Unable to write the audit log: %v", logErr)
+	}
+	return resp, err
+}
+
+func (l *auditLog) append(entry AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0700); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func AuditCommand() cli.Command {
+	return cli.Command{
+		Name:  "audit",
+		Usage: "Read the local audit log",
+		Description: `
+The audit log records every request of the CLI that changes a resource, with the time, the
+token used, the server, the method and URL of the request and its outcome. It is enabled by
+giving its path with the global --audit-log flag or the RANCHER_AUDIT_LOG environment variable.
+`,
+		Subcommands: []cli.Command{
+			{
+				Name:      "tail",
+				Usage:     "Show the latest entries of the audit log",
+				ArgsUsage: "None",
+				Action:    auditTail,
+				Flags: []cli.Flag{
+					cli.IntFlag{
+						Name:  "lines,n",
+						Usage: "Number of entries to show",
+						Value: 20,
+					},
+					formatFlag,
+					noHeadersFlag,
+				},
+			},
+			{
+				Name:  "export",
+				Usage: "Export the audit log",
+				Description: `
+Writes the entries of the audit log as JSON lines or CSV.
+
+Example:
+	# Export the changes of the last week
+	$ rancher audit export --since 168h --format csv > changes.csv
+`,
+				ArgsUsage: "None",
+				Action:    auditExport,
+				Flags: []cli.Flag{
+					cli.DurationFlag{
+						Name:  "since",
+						Usage: "Only export the entries of this period up to now, e.g. 24h",
+					},
+					cli.StringFlag{
+						Name:  "format,o",
+						Usage: "'json' or 'csv'",
+						Value: "json",
+					},
+				},
+			},
+		},
+	}
+}
+
+func auditLogPath(ctx *cli.Context) (string, error) {
+	path := ctx.GlobalString("audit-log")
+	if path == "" {
+		return "", errors.New("the audit log is not enabled, set it with --audit-log or RANCHER_AUDIT_LOG")
+	}
+	return path, nil
+}
+
+// readAuditLog returns the entries of the audit log. Lines that can't be
+// parsed, such as a line being written, are skipped.
+func readAuditLog(path string) ([]AuditEntry, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		entry := AuditEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			logrus.Debugf("Skipping invalid audit log line: %v", err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+func auditTail(ctx *cli.Context) error {
+	path, err := auditLogPath(ctx)
+	if err != nil {
+		return err
+	}
+
+	entries, err := readAuditLog(path)
+	if err != nil {
+		return err
+	}
+	if n := ctx.Int("lines"); n >= 0 && len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+
+	writer := NewTableWriter([][]string{
+		{"TIME", "Time"},
+		{"USER", "Entry.User"},
+		{"SERVER", "Entry.Server"},
+		{"METHOD", "Entry.Method"},
+		{"RESOURCE", "Entry.Resource"},
+		{"STATUS", "Entry.Status"},
+		{"OUTCOME", "Entry.Outcome"},
+	}, ctx)
+
+	defer writer.Close()
+
+	for _, entry := range entries {
+		writer.Write(&AuditEntryData{
+			Entry: entry,
+			Time:  entry.Time.Local().Format(time.RFC3339),
+		})
+	}
+
+	return writer.Err()
+}
+
+func auditExport(ctx *cli.Context) error {
+	path, err := auditLogPath(ctx)
+	if err != nil {
+		return err
+	}
+
+	format := ctx.String("format")
+	if format != "json" && format != "csv" {
+		return fmt.Errorf("invalid format %q, supported formats are json and csv", format)
+	}
+
+	entries, err := readAuditLog(path)
+	if err != nil {
+		return err
+	}
+
+	var since time.Time
+	if ctx.Duration("since") > 0 {
+		since = time.Now().Add(-ctx.Duration("since"))
+	}
+
+	writer := NewTableWriterWithConfig([][]string{
+		{"TIME", "Time"},
+		{"USER", "Entry.User"},
+		{"SERVER", "Entry.Server"},
+		{"METHOD", "Entry.Method"},
+		{"RESOURCE", "Entry.Resource"},
+		{"STATUS", "Entry.Status"},
+		{"OUTCOME", "Entry.Outcome"},
+		{"ERROR", "Entry.Error"},
+	}, &TableWriterConfig{
+		Format: format,
+		Writer: os.Stdout,
+	})
+
+	defer writer.Close()
+
+	for _, entry := range entries {
+		if entry.Time.Before(since) {
+			continue
+		}
+		if format == "json" {
+			writer.Write(entry)
+		} else {
+			writer.Write(&AuditEntryData{Entry: entry, Time: entry.Time.Format(time.RFC3339)})
+		}
+	}
+
+	return writer.Err()
+}
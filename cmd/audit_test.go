@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v3/forbidden" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	log := &auditLog{path: filepath.Join(t.TempDir(), "audit", "audit.jsonl")}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	client := &http.Client{Transport: &auditTransport{
+		next: http.DefaultTransport,
+		log:  log,
+		now:  func() time.Time { return now },
+	}}
+
+	do := func(method, path string) {
+		req, err := http.NewRequest(method, server.URL+path, nil)
+		require.NoError(t, err)
+		req.SetBasicAuth("token-abc", "secret")
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	do(http.MethodGet, "/v3/projects")
+	do(http.MethodPost, "/v3/projects?action=create")
+	do(http.MethodDelete, "/v3/forbidden")
+
+	entries, err := readAuditLog(log.path)
+	require.NoError(t, err)
+	require.Len(t, entries, 2, "reads are not audited")
+
+	assert.Equal(t, AuditEntry{
+		Time:     now,
+		User:     "token-abc",
+		Server:   server.Listener.Addr().String(),
+		Method:   http.MethodPost,
+		Resource: "/v3/projects?action=create",
+		Status:   http.StatusOK,
+		Outcome:  "success",
+	}, entries[0])
+	assert.Equal(t, "failure", entries[1].Outcome)
+	assert.Equal(t, http.StatusForbidden, entries[1].Status)
+}
@@ -0,0 +1,414 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rancher/cli/cliclient"
+	"github.com/rancher/norman/types"
+	"github.com/urfave/cli"
+)
+
+const (
+	backupType  = "resources.cattle.io.backup"
+	restoreType = "resources.cattle.io.restore"
+
+	defaultResourceSet = "rancher-resource-set"
+)
+
+type backupS3Location struct {
+	BucketName                string `json:"bucketName,omitempty"`
+	Folder                    string `json:"folder,omitempty"`
+	Region                    string `json:"region,omitempty"`
+	Endpoint                  string `json:"endpoint,omitempty"`
+	CredentialSecretName      string `json:"credentialSecretName,omitempty"`
+	CredentialSecretNamespace string `json:"credentialSecretNamespace,omitempty"`
+	InsecureTLSSkipVerify     bool   `json:"insecureTLSSkipVerify,omitempty"`
+}
+
+type backupStorageLocation struct {
+	S3 *backupS3Location `json:"s3,omitempty"`
+}
+
+type Backup struct {
+	types.Resource
+	Metadata objectMeta `json:"metadata,omitempty"`
+	Spec     struct {
+		ResourceSetName            string                 `json:"resourceSetName,omitempty"`
+		EncryptionConfigSecretName string                 `json:"encryptionConfigSecretName,omitempty"`
+		Schedule                   string                 `json:"schedule,omitempty"`
+		RetentionCount             int64                  `json:"retentionCount,omitempty"`
+		StorageLocation            *backupStorageLocation `json:"storageLocation,omitempty"`
+	} `json:"spec,omitempty"`
+	Status struct {
+		Filename        string            `json:"filename,omitempty"`
+		StorageLocation string            `json:"storageLocation,omitempty"`
+		BackupType      string            `json:"backupType,omitempty"`
+		LastSnapshotTS  string            `json:"lastSnapshotTs,omitempty"`
+		Conditions      []objectCondition `json:"conditions,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+type BackupCollection struct {
+	types.Collection
+	Data []Backup `json:"data,omitempty"`
+}
+
+type Restore struct {
+	types.Resource
+	Metadata objectMeta `json:"metadata,omitempty"`
+	Spec     struct {
+		BackupFilename             string                 `json:"backupFilename,omitempty"`
+		Prune                      *bool                  `json:"prune,omitempty"`
+		EncryptionConfigSecretName string                 `json:"encryptionConfigSecretName,omitempty"`
+		StorageLocation            *backupStorageLocation `json:"storageLocation,omitempty"`
+	} `json:"spec,omitempty"`
+	Status struct {
+		RestoreCompletionTS string            `json:"restoreCompletionTs,omitempty"`
+		Conditions          []objectCondition `json:"conditions,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+type BackupData struct {
+	ID       string
+	Backup   Backup
+	Name     string
+	Location string
+	State    string
+}
+
+var (
+	backupEncryptionFlag = cli.StringFlag{
+		Name:  "encryption-secret",
+		Usage: "Name of the secret in cattle-resources-system holding the encryption configuration",
+	}
+
+	backupS3Flags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "s3-bucket",
+			Usage: "S3 bucket to use instead of the operator's default storage location",
+		},
+		cli.StringFlag{
+			Name:  "s3-folder",
+			Usage: "Folder in the S3 bucket",
+		},
+		cli.StringFlag{
+			Name:  "s3-region",
+			Usage: "Region of the S3 bucket",
+		},
+		cli.StringFlag{
+			Name:  "s3-endpoint",
+			Usage: "S3 endpoint, e.g. s3.us-west-2.amazonaws.com or a MinIO server",
+		},
+		cli.StringFlag{
+			Name:  "s3-credential-secret",
+			Usage: "Name of the secret holding accessKey and secretKey for the bucket",
+		},
+		cli.StringFlag{
+			Name:  "s3-credential-secret-namespace",
+			Usage: "Namespace of the credential secret",
+			Value: "default",
+		},
+		cli.BoolFlag{
+			Name:  "s3-insecure-tls",
+			Usage: "Skip verifying the S3 endpoint's certificate",
+		},
+	}
+
+	backupWaitFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "wait,w",
+			Usage: "Wait for the operation to complete",
+		},
+		cli.IntFlag{
+			Name:  "wait-timeout",
+			Usage: "Time in seconds to wait with --wait",
+			Value: 1800,
+		},
+	}
+)
+
+func BackupCommand() cli.Command {
+	createFlags := append([]cli.Flag{
+		backupEncryptionFlag,
+		cli.StringFlag{
+			Name:  "resource-set",
+			Usage: "ResourceSet describing what to back up",
+			Value: defaultResourceSet,
+		},
+		cli.StringFlag{
+			Name:  "schedule",
+			Usage: "Cron schedule for recurring backups, e.g. '@every 1h', by default a single backup is taken",
+		},
+		cli.Int64Flag{
+			Name:  "retention-count",
+			Usage: "Number of recurring backups to keep",
+		},
+	}, backupS3Flags...)
+	createFlags = append(createFlags, backupWaitFlags...)
+
+	restoreFlags := append([]cli.Flag{
+		backupEncryptionFlag,
+		cli.StringFlag{
+			Name:  "name",
+			Usage: "Name of the restore, by default a name is generated",
+		},
+		cli.BoolFlag{
+			Name:  "no-prune",
+			Usage: "Keep resources that are not in the backup instead of deleting them",
+		},
+		forceFlag,
+	}, backupS3Flags...)
+	restoreFlags = append(restoreFlags, backupWaitFlags...)
+
+	return cli.Command{
+		Name:    "backups",
+		Aliases: []string{"backup"},
+		Usage:   "Operations on backups of the Rancher server",
+		Description: `
+Backs up and restores the Rancher management plane with the rancher-backup operator, which must
+be installed in the local cluster. Without the S3 flags the operator's default storage location
+is used.
+`,
+		Action: defaultAction(backupLs),
+		Subcommands: []cli.Command{
+			{
+				Name:  "create",
+				Usage: "Back up the Rancher server",
+				Description: `
+Example:
+	$ rancher backup create --s3-bucket rancher-backups --s3-region us-west-2 \
+		--s3-credential-secret s3-creds --wait nightly
+`,
+				ArgsUsage: "[NAME]",
+				Action:    backupCreate,
+				Flags:     createFlags,
+			},
+			{
+				Name:      "ls",
+				Usage:     "List backups",
+				ArgsUsage: "None",
+				Action:    backupLs,
+				Flags: []cli.Flag{
+					formatFlag,
+					quietFlag,
+					filterFlag,
+					labelSelectorFlag,
+					sortByFlag,
+					noHeadersFlag,
+				},
+			},
+			{
+				Name:  "restore",
+				Usage: "Restore the Rancher server from a backup file",
+				Description: `
+Restores the resources in a backup file, as listed in the FILENAME column of 'rancher backup ls'.
+Resources missing from the backup are deleted unless --no-prune is given.
+`,
+				ArgsUsage: "[FILENAME]",
+				Action:    backupRestore,
+				Flags:     restoreFlags,
+			},
+		},
+	}
+}
+
+func backupCreate(ctx *cli.Context) error {
+	c, err := getBackupClient(ctx, backupType)
+	if err != nil {
+		return err
+	}
+
+	backup := &Backup{}
+	backup.Type = backupType
+	backup.Metadata.Name = ctx.Args().First()
+	if backup.Metadata.Name == "" {
+		backup.Metadata.GenerateName = "backup-"
+	}
+	backup.Spec.ResourceSetName = ctx.String("resource-set")
+	backup.Spec.EncryptionConfigSecretName = ctx.String("encryption-secret")
+	backup.Spec.Schedule = ctx.String("schedule")
+	backup.Spec.RetentionCount = ctx.Int64("retention-count")
+	backup.Spec.StorageLocation = backupLocationFromFlags(ctx)
+
+	created := &Backup{}
+	if err := c.CAPIClient.Create(backupType, backup, created); err != nil {
+		return err
+	}
+	fmt.Printf("Created backup %s\n", created.Metadata.Name)
+
+	if !ctx.Bool("wait") {
+		return nil
+	}
+
+	err = waitForBackupOperator(ctx, "backup "+created.Metadata.Name, func() (bool, error) {
+		if err := c.CAPIClient.ByID(backupType, created.ID, created); err != nil {
+			return false, err
+		}
+		if err := backupOperatorError(created.Status.Conditions); err != nil {
+			return false, err
+		}
+		return created.Status.Filename != "" && conditionIsTrue(created.Status.Conditions, "Ready"), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Backup written to %s\n", created.Status.Filename)
+	return nil
+}
+
+func backupLs(ctx *cli.Context) error {
+	c, err := getBackupClient(ctx, backupType)
+	if err != nil {
+		return err
+	}
+
+	collection := &BackupCollection{}
+	if err := c.CAPIClient.List(backupType, &types.ListOpts{}, collection); err != nil {
+		return err
+	}
+
+	writer := NewTableWriter([][]string{
+		{"NAME", "Name"},
+		{"LOCATION", "Location"},
+		{"TYPE", "Backup.Status.BackupType"},
+		{"FILENAME", "Backup.Status.Filename"},
+		{"LAST-BACKUP", "Backup.Status.LastSnapshotTS"},
+		{"STATE", "State"},
+	}, ctx)
+
+	defer writer.Close()
+
+	for _, item := range collection.Data {
+		writer.Write(&BackupData{
+			ID:       item.ID,
+			Backup:   item,
+			Name:     item.Metadata.Name,
+			Location: item.Status.StorageLocation,
+			State:    objectState(item.Metadata),
+		})
+	}
+
+	return writer.Err()
+}
+
+func backupRestore(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+	filename := ctx.Args().First()
+
+	c, err := getBackupClient(ctx, restoreType)
+	if err != nil {
+		return err
+	}
+
+	prune := !ctx.Bool("no-prune")
+	message := fmt.Sprintf("The Rancher server will be restored from %s.", filename)
+	if prune {
+		message += " Resources that are not in the backup will be deleted."
+	}
+	if !confirmAction(ctx, message) {
+		return errCancelled
+	}
+
+	restore := &Restore{}
+	restore.Type = restoreType
+	restore.Metadata.Name = ctx.String("name")
+	if restore.Metadata.Name == "" {
+		restore.Metadata.GenerateName = "restore-"
+	}
+	restore.Spec.BackupFilename = filename
+	restore.Spec.Prune = &prune
+	restore.Spec.EncryptionConfigSecretName = ctx.String("encryption-secret")
+	restore.Spec.StorageLocation = backupLocationFromFlags(ctx)
+
+	created := &Restore{}
+	if err := c.CAPIClient.Create(restoreType, restore, created); err != nil {
+		return err
+	}
+	fmt.Printf("Created restore %s\n", created.Metadata.Name)
+
+	if !ctx.Bool("wait") {
+		return nil
+	}
+
+	err = waitForBackupOperator(ctx, "restore "+created.Metadata.Name, func() (bool, error) {
+		if err := c.CAPIClient.ByID(restoreType, created.ID, created); err != nil {
+			return false, err
+		}
+		if err := backupOperatorError(created.Status.Conditions); err != nil {
+			return false, err
+		}
+		return created.Status.RestoreCompletionTS != "", nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Restore completed at %s\n", created.Status.RestoreCompletionTS)
+	return nil
+}
+
+// getBackupClient returns a client for the /v1 API of the local cluster,
+// checking that the backup operator is installed.
+func getBackupClient(ctx *cli.Context, schemaType string) (*cliclient.MasterClient, error) {
+	return getLocalV1Client(ctx, schemaType, "the rancher-backup operator is not installed")
+}
+
+// backupLocationFromFlags returns the S3 location given with the --s3 flags,
+// nil to use the operator's default location.
+func backupLocationFromFlags(ctx *cli.Context) *backupStorageLocation {
+	if ctx.String("s3-bucket") == "" {
+		return nil
+	}
+	s3 := &backupS3Location{
+		BucketName:            ctx.String("s3-bucket"),
+		Folder:                ctx.String("s3-folder"),
+		Region:                ctx.String("s3-region"),
+		Endpoint:              ctx.String("s3-endpoint"),
+		CredentialSecretName:  ctx.String("s3-credential-secret"),
+		InsecureTLSSkipVerify: ctx.Bool("s3-insecure-tls"),
+	}
+	if s3.CredentialSecretName != "" {
+		s3.CredentialSecretNamespace = ctx.String("s3-credential-secret-namespace")
+	}
+	return &backupStorageLocation{S3: s3}
+}
+
+func conditionIsTrue(conditions []objectCondition, conditionType string) bool {
+	condition := findCondition(conditions, conditionType)
+	return condition != nil && condition.Status == "True"
+}
+
+// backupOperatorError returns the error the operator reported, which it does
+// by setting the Ready or Reconciling condition to False with reason Error.
+func backupOperatorError(conditions []objectCondition) error {
+	for _, conditionType := range []string{"Ready", "Reconciling"} {
+		condition := findCondition(conditions, conditionType)
+		if condition != nil && condition.Status == "False" && condition.Reason == "Error" {
+			return errors.New(condition.Message)
+		}
+	}
+	return nil
+}
+
+func waitForBackupOperator(ctx *cli.Context, what string, check func() (bool, error)) error {
+	waitCtx, cancel := waitContext(time.Duration(ctx.Int("wait-timeout")) * time.Second)
+	defer cancel()
+
+	err := pollUntil(waitCtx, newBackoff(pollInitialInterval, pollMaxInterval), check)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return timeoutErrorf("timed out waiting for %s", what)
+	}
+	if errors.Is(err, context.Canceled) {
+		return interruptedErrorf("interrupted waiting for %s", what)
+	}
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", what, err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupOperatorError(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []objectCondition
+		wantErr    string
+	}{
+		{"no conditions", nil, ""},
+		{"ready", []objectCondition{{Type: "Ready", Status: "True"}}, ""},
+		{"reconciling", []objectCondition{{Type: "Reconciling", Status: "True"}, {Type: "Ready", Status: "False"}}, ""},
+		{
+			"error",
+			[]objectCondition{{Type: "Ready", Status: "False", Reason: "Error", Message: "bucket not found"}},
+			"bucket not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := backupOperatorError(tt.conditions)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
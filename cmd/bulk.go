@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rancher/cli/cliclient"
+	"github.com/rancher/norman/clientbase"
+	ntypes "github.com/rancher/norman/types"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+	"golang.org/x/sync/errgroup"
+)
+
+// bulkWorkers is how many resources are resolved or deleted concurrently.
+const bulkWorkers = 8
+
+var (
+	deleteWaitFlag = cli.BoolFlag{
+		Name:  "wait,w",
+		Usage: "Wait until the deleted resources are removed",
+	}
+
+	deleteWaitTimeoutFlag = cli.IntFlag{
+		Name:  "wait-timeout",
+		Usage: "Time in seconds to wait for the resources to be removed with --wait",
+		Value: 600,
+	}
+
+	deleteFlags = []cli.Flag{forceFlag, deleteWaitFlag, deleteWaitTimeoutFlag}
+)
+
+// bulkTarget is a resource resolved from an argument of a delete command.
+type bulkTarget struct {
+	arg          string
+	resource     ntypes.Resource
+	descriptions []string
+	delete       func() error
+}
+
+type bulkFailure struct {
+	arg string
+	err error
+}
+
+// bulkDelete deletes the resources named by the arguments of a delete command.
+// Arguments are resolved and deleted concurrently, a failure doesn't stop the
+// other deletions and is reported in the summary.
+type bulkDelete struct {
+	// kind is the plural name of the resources, e.g. "apps"
+	kind string
+	// warning is what the confirmation prompt lists, defaults to kind
+	warning string
+	// args name the resources, defaults to the arguments of the command
+	args []string
+	// resolve returns the target named by arg, or nil to skip it
+	resolve func(arg string) (*bulkTarget, error)
+}
+
+func (b bulkDelete) run(ctx *cli.Context, c *cliclient.MasterClient) error {
+	gone := func(resource ntypes.Resource) (bool, error) {
+		if c.DryRun {
+			return true, nil
+		}
+		err := c.ByID(&resource, &map[string]interface{}{})
+		if clientbase.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	return b.runWith(ctx, gone)
+}
+
+func (b bulkDelete) runWith(ctx *cli.Context, gone func(ntypes.Resource) (bool, error)) error {
+	args := b.args
+	if args == nil {
+		args = ctx.Args()
+	}
+	targets, failures := b.resolveAll(args)
+
+	var resolved []*bulkTarget
+	var descriptions []string
+	for _, target := range targets {
+		if target != nil {
+			resolved = append(resolved, target)
+			descriptions = append(descriptions, target.descriptions...)
+		}
+	}
+
+	warning := b.warning
+	if warning == "" {
+		warning = b.kind
+	}
+	if len(resolved) > 0 && !confirmDeletion(ctx, warning, descriptions) {
+		return errCancelled
+	}
+
+	deleted := b.forEach(resolved, func(target *bulkTarget) error {
+		return target.delete()
+	}, &failures)
+
+	if ctx.Bool("wait") && len(deleted) > 0 {
+		timeout := time.Duration(ctx.Int("wait-timeout")) * time.Second
+		logrus.Infof("Waiting for %d %s to be removed", len(deleted), b.kind)
+		deleted = b.forEach(deleted, func(target *bulkTarget) error {
+			return waitForRemoval(target.resource, timeout, gone)
+		}, &failures)
+	}
+
+	return b.summarize(len(args), len(deleted), failures)
+}
+
+// resolveAll resolves the arguments concurrently, keeping their order.
+func (b bulkDelete) resolveAll(args []string) ([]*bulkTarget, []bulkFailure) {
+	targets := make([]*bulkTarget, len(args))
+	errs := make([]error, len(args))
+
+	var g errgroup.Group
+	g.SetLimit(bulkWorkers)
+	for i, arg := range args {
+		i, arg := i, arg
+		g.Go(func() error {
+			targets[i], errs[i] = b.resolve(arg)
+			if targets[i] != nil {
+				targets[i].arg = arg
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	var failures []bulkFailure
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, bulkFailure{arg: args[i], err: err})
+		}
+	}
+	return targets, failures
+}
+
+// forEach calls fn for each target concurrently, records the failures and
+// returns the targets fn succeeded for.
+func (b bulkDelete) forEach(targets []*bulkTarget, fn func(*bulkTarget) error, failures *[]bulkFailure) []*bulkTarget {
+	errs := make([]error, len(targets))
+
+	var g errgroup.Group
+	g.SetLimit(bulkWorkers)
+	for i, target := range targets {
+		i, target := i, target
+		g.Go(func() error {
+			errs[i] = fn(target)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	var succeeded []*bulkTarget
+	for i, err := range errs {
+		if err != nil {
+			*failures = append(*failures, bulkFailure{arg: targets[i].arg, err: err})
+			continue
+		}
+		succeeded = append(succeeded, targets[i])
+	}
+	return succeeded
+}
+
+// summarize reports the outcome of deleting several resources. A single
+// failed argument returns its own error, so that its exit code is kept.
+func (b bulkDelete) summarize(total, deleted int, failures []bulkFailure) error {
+	if total == 1 && len(failures) == 1 {
+		return failures[0].err
+	}
+	for _, failure := range failures {
+		logrus.Errorf("%s: %v", failure.arg, failure.err)
+	}
+	if total > 1 {
+		logrus.Infof("Deleted %d of %d %s", deleted, total, b.kind)
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to delete %d of %d %s", len(failures), total, b.kind)
+	}
+	return nil
+}
+
+// waitForRemoval polls until gone reports the resource doesn't exist anymore.
+func waitForRemoval(resource ntypes.Resource, timeout time.Duration, gone func(ntypes.Resource) (bool, error)) error {
+	ctx, cancel := waitContext(timeout)
+	defer cancel()
+
+	err := pollUntil(ctx, newBackoff(pollInitialInterval, pollMaxInterval), func() (bool, error) {
+		return gone(resource)
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return timeoutErrorf("timed out waiting for %s %s to be removed", resource.Type, resource.ID)
+	}
+	if errors.Is(err, context.Canceled) {
+		return interruptedErrorf("interrupted waiting for %s %s to be removed", resource.Type, resource.ID)
+	}
+	return err
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"sort"
+	"sync"
+	"testing"
+
+	ntypes "github.com/rancher/norman/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli"
+)
+
+func newDeleteContext(t *testing.T, wait bool, args ...string) *cli.Context {
+	set := flag.NewFlagSet("delete", flag.ContinueOnError)
+	set.Bool("force", true, "")
+	set.Bool("wait", wait, "")
+	set.Int("wait-timeout", 5, "")
+	assert.NoError(t, set.Parse(args))
+	return cli.NewContext(nil, set, nil)
+}
+
+func TestBulkDeleteContinuesOnError(t *testing.T) {
+	var mu sync.Mutex
+	var deleted []string
+
+	b := bulkDelete{
+		kind: "apps",
+		resolve: func(arg string) (*bulkTarget, error) {
+			if arg == "missing" {
+				return nil, notFoundErrorf("Not found: %s", arg)
+			}
+			return &bulkTarget{
+				resource: ntypes.Resource{ID: arg, Type: "app"},
+				delete: func() error {
+					if arg == "locked" {
+						return errors.New("forbidden")
+					}
+					mu.Lock()
+					deleted = append(deleted, arg)
+					mu.Unlock()
+					return nil
+				},
+			}, nil
+		},
+	}
+
+	err := b.runWith(newDeleteContext(t, false, "one", "missing", "locked", "two"), nil)
+
+	assert.EqualError(t, err, "failed to delete 2 of 4 apps")
+	sort.Strings(deleted)
+	assert.Equal(t, []string{"one", "two"}, deleted)
+}
+
+func TestBulkDeleteSingleFailureKeepsError(t *testing.T) {
+	b := bulkDelete{
+		kind: "apps",
+		resolve: func(arg string) (*bulkTarget, error) {
+			return nil, notFoundErrorf("Not found: %s", arg)
+		},
+	}
+
+	err := b.runWith(newDeleteContext(t, false, "missing"), nil)
+
+	assert.Equal(t, ExitCodeNotFound, ExitCode(err))
+}
+
+func TestBulkDeleteWait(t *testing.T) {
+	checks := 0
+	b := bulkDelete{
+		kind: "apps",
+		resolve: func(arg string) (*bulkTarget, error) {
+			return &bulkTarget{
+				resource: ntypes.Resource{ID: arg, Type: "app"},
+				delete:   func() error { return nil },
+			}, nil
+		},
+	}
+
+	err := b.runWith(newDeleteContext(t, true, "one"), func(resource ntypes.Resource) (bool, error) {
+		checks++
+		return true, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, checks)
+}
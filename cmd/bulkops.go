@@ -0,0 +1,465 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+const bulkDescription = `
+Runs the operations declared in a file, for changes across many projects or clusters. Each
+operation is run as a rancher command of its own, at most --concurrency at a time and starting
+at most --rate a second, so that the Rancher server isn't flooded with requests. A summary of
+the operations is printed once they are done.
+
+The first failure stops the operations not started yet, unless --continue-on-error is set.
+
+With the global --dry-run the requests each operation would send are printed, prefixed with
+the index of the operation, and nothing is changed.
+
+The file has a list of operations, each of one type:
+
+	install       install a template as an app of a project
+	upgrade       upgrade an app of a project
+	add-member    add a user to a project or a cluster with roles
+	command       run any rancher command, given by its arguments
+
+Example of a file:
+
+	operations:
+	- type: install
+	  project: c-abc12:p-xyz34
+	  template: rancher-monitoring
+	  name: monitoring
+	  namespace: cattle-monitoring-system
+	  version: 0.3.1
+	  answers:
+	    retention: 15d
+	- type: upgrade
+	  project: c-abc12:p-xyz34
+	  app: logging
+	  version: 0.4.0
+	- type: add-member
+	  project: c-abc12:p-xyz34
+	  user: alice
+	  roles: [project-member]
+	- type: add-member
+	  cluster: c-abc12
+	  user: bob
+	  roles: [cluster-member]
+	- type: command
+	  args: [namespace, create, team-a]
+
+Example:
+	$ rancher bulk -f operations.yaml
+	$ rancher bulk -f operations.yaml --concurrency 8 --rate 2 --continue-on-error
+`
+
+// bulkFile is the file of operations run by bulk
+type bulkFile struct {
+	Operations []bulkOperation `json:"operations"`
+}
+
+type bulkOperation struct {
+	Type string `json:"type"`
+	// Project is the ID of the project of installs, upgrades and members
+	Project string `json:"project,omitempty"`
+	// Cluster is the ID of the cluster of members
+	Cluster   string            `json:"cluster,omitempty"`
+	Template  string            `json:"template,omitempty"`
+	Name      string            `json:"name,omitempty"`
+	Namespace string            `json:"namespace,omitempty"`
+	App       string            `json:"app,omitempty"`
+	Version   string            `json:"version,omitempty"`
+	Answers   map[string]string `json:"answers,omitempty"`
+	User      string            `json:"user,omitempty"`
+	Roles     []string          `json:"roles,omitempty"`
+	Args      []string          `json:"args,omitempty"`
+}
+
+// BulkResult is the outcome of an operation run by bulk
+type BulkResult struct {
+	Index     int
+	Operation string
+	Status    string
+	Duration  string
+	Error     string
+}
+
+func BulkCommand() cli.Command {
+	return cli.Command{
+		Name:        "bulk",
+		Usage:       "Run the operations declared in a file",
+		Description: bulkDescription,
+		ArgsUsage:   "None",
+		Action:      bulkRun,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "file,f",
+				Usage: "Path to the file of operations. Use - to read it from stdin",
+			},
+			cli.IntFlag{
+				Name:  "concurrency",
+				Usage: "How many operations run at the same time",
+				Value: 4,
+			},
+			cli.Float64Flag{
+				Name:  "rate",
+				Usage: "How many operations start per second at most, 0 for no limit",
+				Value: 1,
+			},
+			cli.BoolFlag{
+				Name:  "continue-on-error",
+				Usage: "Keep starting operations after one failed",
+			},
+			formatFlag,
+			noHeadersFlag,
+		},
+	}
+}
+
+func bulkRun(ctx *cli.Context) error {
+	if ctx.String("file") == "" {
+		return NewUsageError(errors.New("--file is required"))
+	}
+	if ctx.Int("concurrency") < 1 {
+		return NewUsageError(errors.New("--concurrency must be at least 1"))
+	}
+	if ctx.Float64("rate") < 0 {
+		return NewUsageError(errors.New("--rate can't be negative"))
+	}
+
+	operations, err := readBulkFile(ctx.String("file"))
+	if err != nil {
+		return err
+	}
+	commands := make([][]string, len(operations))
+	for i, operation := range operations {
+		args, err := operation.args()
+		if err != nil {
+			return NewUsageError(fmt.Errorf("operation %d: %w", i+1, err))
+		}
+		commands[i] = append(bulkGlobalArgs(ctx), args...)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	// with --dry-run the payloads the operations would send are what matters,
+	// so their output is streamed, prefixed with the index of the operation
+	dryRun := ctx.GlobalBool("dry-run")
+	var stdoutLock sync.Mutex
+	run := func(runCtx context.Context, index int, args []string) error {
+		if !dryRun {
+			return runBulkCommand(runCtx, executable, args, io.Discard)
+		}
+		stdout := &prefixWriter{out: os.Stdout, lock: &stdoutLock, prefix: fmt.Sprintf("[%d] ", index)}
+		defer stdout.Flush()
+		return runBulkCommand(runCtx, executable, args, stdout)
+	}
+	stop := commandInterrupt.watch()
+	defer stop()
+	results := runBulkOperations(commandInterrupt.context(), operations, commands, bulkSchedule{
+		concurrency:     ctx.Int("concurrency"),
+		interval:        bulkInterval(ctx.Float64("rate")),
+		continueOnError: ctx.Bool("continue-on-error"),
+	}, run)
+	if dryRun {
+		for i := range results {
+			if results[i].Status == "succeeded" {
+				results[i].Status = "dry-run"
+			}
+		}
+	}
+
+	writer := NewTableWriter([][]string{
+		{"#", "Index"},
+		{"OPERATION", "Operation"},
+		{"STATUS", "Status"},
+		{"DURATION", "Duration"},
+		{"ERROR", "Error"},
+	}, ctx)
+	for _, result := range results {
+		writer.Write(result)
+	}
+	writer.Close()
+	if err := writer.Err(); err != nil {
+		return err
+	}
+
+	var failed, skipped int
+	for _, result := range results {
+		switch result.Status {
+		case "failed":
+			failed++
+		case "skipped":
+			skipped++
+		}
+	}
+	if failed > 0 {
+		return partialErrorf("%d of %d operations failed, %d were skipped", failed, len(results), skipped)
+	}
+	return nil
+}
+
+func readBulkFile(path string) ([]bulkOperation, error) {
+	var content []byte
+	var err error
+	if path == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	file := &bulkFile{}
+	if err := yaml.Unmarshal(content, file); err != nil {
+		return nil, NewUsageError(fmt.Errorf("invalid file of operations %s: %w", path, err))
+	}
+	if len(file.Operations) == 0 {
+		return nil, NewUsageError(fmt.Errorf("no operations in %s", path))
+	}
+	return file.Operations, nil
+}
+
+// args returns the arguments of the rancher command running the operation
+func (o bulkOperation) args() ([]string, error) {
+	var args []string
+	switch o.Type {
+	case "install":
+		if o.Project == "" || o.Template == "" || o.Name == "" {
+			return nil, errors.New("install needs project, template and name")
+		}
+		args = []string{"--project", o.Project, "app", "install", "--no-prompt"}
+		if o.Namespace != "" {
+			args = append(args, "--namespace", o.Namespace)
+		}
+		if o.Version != "" {
+			args = append(args, "--version", o.Version)
+		}
+		args = append(args, bulkAnswerArgs(o.Answers)...)
+		args = append(args, o.Template, o.Name)
+	case "upgrade":
+		if o.Project == "" || o.App == "" || o.Version == "" {
+			return nil, errors.New("upgrade needs project, app and version")
+		}
+		args = []string{"--project", o.Project, "app", "upgrade"}
+		args = append(args, bulkAnswerArgs(o.Answers)...)
+		args = append(args, o.App, o.Version)
+	case "add-member":
+		if o.User == "" || len(o.Roles) == 0 {
+			return nil, errors.New("add-member needs user and roles")
+		}
+		switch {
+		case o.Project != "" && o.Cluster == "":
+			args = []string{"project", "add-member-role", "--project-id", o.Project}
+		case o.Cluster != "" && o.Project == "":
+			args = []string{"cluster", "add-member-role", "--cluster-id", o.Cluster}
+		default:
+			return nil, errors.New("add-member needs one of project or cluster")
+		}
+		args = append(args, o.User)
+		args = append(args, o.Roles...)
+	case "command":
+		if len(o.Args) == 0 {
+			return nil, errors.New("command needs args")
+		}
+		args = o.Args
+	default:
+		return nil, fmt.Errorf("invalid type %q, expected install, upgrade, add-member or command", o.Type)
+	}
+	return args, nil
+}
+
+// bulkAnswerArgs returns the --set flags of answers, sorted so that the
+// commands are reproducible.
+func bulkAnswerArgs(answers map[string]string) []string {
+	var keys []string
+	for key := range answers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var args []string
+	for _, key := range keys {
+		args = append(args, "--set", key+"="+answers[key])
+	}
+	return args
+}
+
+// bulkGlobalArgs returns the global flags the operations are run with, so
+// that they use the same config, server, dry run and inherited flags as bulk.
+func bulkGlobalArgs(ctx *cli.Context) []string {
+	args := []string{"--config", GetConfigPath(ctx)}
+	if server := ctx.GlobalString("server"); server != "" {
+		args = append(args, "--server", server)
+	}
+	if ctx.GlobalBool("dry-run") {
+		args = append(args, "--dry-run")
+	}
+	return append(args, inheritedGlobalArgs(ctx)...)
+}
+
+func bulkInterval(rate float64) time.Duration {
+	if rate == 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Second) / rate)
+}
+
+type bulkSchedule struct {
+	concurrency int
+	// interval is the least time between the starts of two operations
+	interval        time.Duration
+	continueOnError bool
+}
+
+// runBulkOperations runs the commands of operations with run as scheduled,
+// given the index of each operation counted from 1, and returns their results
+// in order. Once an operation fails or ctx is done
+// the operations not started yet are skipped, unless continuing on errors.
+func runBulkOperations(ctx context.Context, operations []bulkOperation, commands [][]string, schedule bulkSchedule, run func(context.Context, int, []string) error) []BulkResult {
+	results := make([]BulkResult, len(operations))
+	for i, operation := range operations {
+		results[i] = BulkResult{Index: i + 1, Operation: operation.describe(), Status: "skipped"}
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		failed  bool
+		slots   = make(chan struct{}, schedule.concurrency)
+		started time.Time
+	)
+	for i := range operations {
+		slots <- struct{}{}
+		if wait := schedule.interval - time.Since(started); !started.IsZero() && wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+			}
+		}
+		mu.Lock()
+		stop := (failed && !schedule.continueOnError) || ctx.Err() != nil
+		mu.Unlock()
+		if stop {
+			<-slots
+			break
+		}
+
+		started = time.Now()
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			start := time.Now()
+			err := run(ctx, i+1, commands[i])
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[i].Duration = time.Since(start).Round(time.Millisecond).String()
+			if err != nil {
+				failed = true
+				results[i].Status = "failed"
+				results[i].Error = err.Error()
+				logrus.Errorf("Operation %d (%s) failed: %v", i+1, results[i].Operation, err)
+				return
+			}
+			results[i].Status = "succeeded"
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// describe returns a short description of the operation for the summary
+func (o bulkOperation) describe() string {
+	switch o.Type {
+	case "install":
+		return fmt.Sprintf("install %s as %s in %s", o.Template, o.Name, o.Project)
+	case "upgrade":
+		return fmt.Sprintf("upgrade %s in %s to %s", o.App, o.Project, o.Version)
+	case "add-member":
+		return fmt.Sprintf("add %s to %s as %s", o.User, valueOrDefault(o.Project, o.Cluster), strings.Join(o.Roles, ","))
+	}
+	return strings.Join(o.Args, " ")
+}
+
+// runBulkCommand runs a rancher command writing its output to stdout, its
+// error is the last line it printed on stderr.
+func runBulkCommand(ctx context.Context, executable string, args []string, stdout io.Writer) error {
+	cmd := exec.CommandContext(ctx, executable, args...)
+	var stderr bytes.Buffer
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+	stdin, err := os.Open(os.DevNull)
+	if err != nil {
+		return err
+	}
+	defer stdin.Close()
+	cmd.Stdin = stdin
+
+	if err := cmd.Run(); err != nil {
+		lines := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+		if message := lines[len(lines)-1]; message != "" {
+			return errors.New(message)
+		}
+		return err
+	}
+	return nil
+}
+
+// prefixWriter writes each line to out with prefix, holding lock so that the
+// lines of concurrent writers aren't mixed. A last line without a newline is
+// written by Flush.
+type prefixWriter struct {
+	out    io.Writer
+	lock   *sync.Mutex
+	prefix string
+	line   []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.line = append(w.line, p...)
+	for {
+		end := bytes.IndexByte(w.line, '\n')
+		if end < 0 {
+			return len(p), nil
+		}
+		if err := w.writeLine(w.line[:end+1]); err != nil {
+			return 0, err
+		}
+		w.line = w.line[end+1:]
+	}
+}
+
+// Flush writes the last line when it doesn't end with a newline.
+func (w *prefixWriter) Flush() error {
+	if len(w.line) == 0 {
+		return nil
+	}
+	err := w.writeLine(append(w.line, '\n'))
+	w.line = nil
+	return err
+}
+
+func (w *prefixWriter) writeLine(line []byte) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	_, err := fmt.Fprintf(w.out, "%s%s", w.prefix, line)
+	return err
+}
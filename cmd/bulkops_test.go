@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkOperationArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation bulkOperation
+		expected  []string
+		err       string
+	}{
+		{
+			name: "install",
+			operation: bulkOperation{
+				Type: "install", Project: "c-1:p-1", Template: "monitoring", Name: "mon", Namespace: "cattle-monitoring",
+				Version: "0.3.1", Answers: map[string]string{"b": "2", "a": "1"},
+			},
+			expected: []string{"--project", "c-1:p-1", "app", "install", "--no-prompt", "--namespace", "cattle-monitoring",
+				"--version", "0.3.1", "--set", "a=1", "--set", "b=2", "monitoring", "mon"},
+		},
+		{
+			name:      "upgrade",
+			operation: bulkOperation{Type: "upgrade", Project: "c-1:p-1", App: "logging", Version: "0.4.0"},
+			expected:  []string{"--project", "c-1:p-1", "app", "upgrade", "logging", "0.4.0"},
+		},
+		{
+			name:      "project member",
+			operation: bulkOperation{Type: "add-member", Project: "c-1:p-1", User: "alice", Roles: []string{"project-member", "read-only"}},
+			expected:  []string{"project", "add-member-role", "--project-id", "c-1:p-1", "alice", "project-member", "read-only"},
+		},
+		{
+			name:      "cluster member",
+			operation: bulkOperation{Type: "add-member", Cluster: "c-1", User: "bob", Roles: []string{"cluster-member"}},
+			expected:  []string{"cluster", "add-member-role", "--cluster-id", "c-1", "bob", "cluster-member"},
+		},
+		{
+			name:      "command",
+			operation: bulkOperation{Type: "command", Args: []string{"namespace", "create", "team-a"}},
+			expected:  []string{"namespace", "create", "team-a"},
+		},
+		{
+			name:      "install without project",
+			operation: bulkOperation{Type: "install", Template: "monitoring", Name: "mon"},
+			err:       "install needs project, template and name",
+		},
+		{
+			name:      "member of both",
+			operation: bulkOperation{Type: "add-member", Project: "c-1:p-1", Cluster: "c-1", User: "alice", Roles: []string{"owner"}},
+			err:       "add-member needs one of project or cluster",
+		},
+		{
+			name:      "unknown type",
+			operation: bulkOperation{Type: "delete"},
+			err:       `invalid type "delete", expected install, upgrade, add-member or command`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := tt.operation.args()
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, args)
+		})
+	}
+}
+
+func TestRunBulkOperations(t *testing.T) {
+	operations := []bulkOperation{
+		{Type: "command", Args: []string{"ok"}},
+		{Type: "command", Args: []string{"fail"}},
+		{Type: "command", Args: []string{"ok"}},
+	}
+	commands := [][]string{{"ok"}, {"fail"}, {"ok"}}
+	run := func(_ context.Context, _ int, args []string) error {
+		if args[0] == "fail" {
+			return errors.New("boom")
+		}
+		return nil
+	}
+	statuses := func(results []BulkResult) []string {
+		var statuses []string
+		for _, result := range results {
+			statuses = append(statuses, result.Status)
+		}
+		return statuses
+	}
+
+	results := runBulkOperations(context.Background(), operations, commands, bulkSchedule{concurrency: 1}, run)
+	assert.Equal(t, []string{"succeeded", "failed", "skipped"}, statuses(results))
+	assert.Equal(t, "boom", results[1].Error)
+
+	results = runBulkOperations(context.Background(), operations, commands, bulkSchedule{concurrency: 1, continueOnError: true}, run)
+	assert.Equal(t, []string{"succeeded", "failed", "succeeded"}, statuses(results))
+}
+
+func TestRunBulkOperationsConcurrency(t *testing.T) {
+	operations := make([]bulkOperation, 10)
+	commands := make([][]string, 10)
+	var running, most int32
+	run := func(_ context.Context, _ int, _ []string) error {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			seen := atomic.LoadInt32(&most)
+			if current <= seen || atomic.CompareAndSwapInt32(&most, seen, current) {
+				break
+			}
+		}
+		atomic.AddInt32(&running, -1)
+		return nil
+	}
+
+	results := runBulkOperations(context.Background(), operations, commands, bulkSchedule{concurrency: 3}, run)
+	assert.Len(t, results, 10)
+	assert.LessOrEqual(t, atomic.LoadInt32(&most), int32(3))
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	var lock sync.Mutex
+	w := &prefixWriter{out: &out, lock: &lock, prefix: "[2] "}
+	fmt.Fprint(w, "POST /v3/apps\n{\"name\"")
+	fmt.Fprint(w, ":\"web\"}\nlast")
+	assert.Equal(t, "[2] POST /v3/apps\n[2] {\"name\":\"web\"}\n", out.String())
+	assert.NoError(t, w.Flush())
+	assert.Equal(t, "[2] POST /v3/apps\n[2] {\"name\":\"web\"}\n[2] last\n", out.String())
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+const schemaCacheDir = "schemas"
+
+func schemaCachePath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), schemaCacheDir)
+}
+
+// CacheCommand defines the 'rancher cache' sub-commands
+func CacheCommand() cli.Command {
+	return cli.Command{
+		Name:  "cache",
+		Usage: "Operations on the local caches",
+		Description: `
+Names of clusters, projects and templates resolved to IDs are cached on disk
+for --cache-ttl so that consecutive commands don't list whole collections.
+
+The output of the last successful run of each read-only command, such as ls,
+is cached on disk too, it's printed with --cached when the server is
+unreachable.
+
+The answers of the apps deleted by the CLI are kept for a week, as the defaults of an app
+installed again with the same name.
+
+The kubeconfigs generated for kubectl are kept until their token expires.
+
+The API schemas of each server are kept for a day, or until the version of the
+server recorded by 'rancher login' or 'rancher version' changes.
+`,
+		Subcommands: []cli.Command{
+			{
+				Name:  "clear",
+				Usage: "Remove all cached name to ID mappings, listings, answers of deleted apps, kubeconfigs and schemas",
+				Action: func(ctx *cli.Context) error {
+					cache := &lookupCache{path: lookupCachePath(GetConfigPath(ctx))}
+					if err := cache.clear(); err != nil {
+						return err
+					}
+					if err := os.RemoveAll(outputCachePath(GetConfigPath(ctx))); err != nil {
+						return err
+					}
+					history := &answerHistory{path: deletedAppsPath(GetConfigPath(ctx))}
+					if err := history.clear(); err != nil {
+						return err
+					}
+					if err := newKubeconfigCache(GetConfigPath(ctx)).clear(); err != nil {
+						return err
+					}
+					if err := os.RemoveAll(schemaCachePath(GetConfigPath(ctx))); err != nil {
+						return err
+					}
+					logrus.Info("Caches cleared")
+					return nil
+				},
+			},
+		},
+	}
+}
@@ -1,10 +1,11 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"time"
 
-	"github.com/pkg/errors"
 	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
@@ -59,6 +60,12 @@ func CatalogCommand() cli.Command {
 			Name:  "verbose,v",
 			Usage: "Include the catalog's state",
 		},
+		filterFlag,
+		labelSelectorFlag,
+		sortByFlag,
+		noHeadersFlag,
+		limitFlag,
+		pageSizeFlag,
 	}
 
 	return cli.Command{
@@ -123,17 +130,32 @@ func CatalogCommand() cli.Command {
 					},
 				},
 			},
+			{
+				Name:        "show-questions",
+				Usage:       "Show the questions of a template version",
+				Description: showQuestionsDescription,
+				ArgsUsage:   "[TEMPLATE_ID[:VERSION]]",
+				Action:      catalogShowQuestions,
+				Flags: []cli.Flag{
+					formatFlag,
+					noHeadersFlag,
+					cli.StringFlag{
+						Name:  "version",
+						Usage: "Version of the template, the newest version by default",
+					},
+				},
+			},
 		},
 	}
 }
 
 func catalogLs(ctx *cli.Context) error {
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	collection, err := c.ManagementClient.Catalog.List(defaultListOpts(ctx))
+	collection, err := c.ManagementClient.Catalog.List(filteredListOpts(ctx))
 	if err != nil {
 		return err
 	}
@@ -155,11 +177,17 @@ func catalogLs(ctx *cli.Context) error {
 
 	defer writer.Close()
 
-	for _, item := range collection.Data {
+	err = forEachPage(ctx, collection, func(c *managementClient.CatalogCollection) []managementClient.Catalog {
+		return c.Data
+	}, func(item managementClient.Catalog) error {
 		writer.Write(&CatalogData{
 			ID:      item.ID,
 			Catalog: item,
 		})
+		return nil
+	}, writer.Flush)
+	if err != nil {
+		return err
 	}
 
 	return writer.Err()
@@ -168,10 +196,10 @@ func catalogLs(ctx *cli.Context) error {
 
 func catalogAdd(ctx *cli.Context) error {
 	if len(ctx.Args()) < 2 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -194,10 +222,10 @@ func catalogAdd(ctx *cli.Context) error {
 
 func catalogDelete(ctx *cli.Context) error {
 	if len(ctx.Args()) < 1 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -223,10 +251,10 @@ func catalogDelete(ctx *cli.Context) error {
 
 func catalogRefresh(ctx *cli.Context) error {
 	if len(ctx.Args()) < 1 && !ctx.Bool("all") {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -274,6 +302,8 @@ func catalogRefresh(ctx *cli.Context) error {
 	if ctx.Bool("wait") {
 		timeout := time.Duration(ctx.Int("wait-timeout")) * time.Second
 		start := time.Now()
+		waitCtx, cancel := waitContext(timeout)
+		defer cancel()
 
 		logrus.Debugf("catalog: waiting for catalogs to become active (timeout=%v)", timeout)
 
@@ -286,21 +316,21 @@ func catalogRefresh(ctx *cli.Context) error {
 				return err
 			}
 
-			catalog, err := c.ManagementClient.Catalog.ByID(resource.ID)
-			if err != nil {
-				return err
-			}
-
-			for catalog.State != "active" {
-				time.Sleep(time.Second)
-				catalog, err = c.ManagementClient.Catalog.ByID(resource.ID)
+			err = pollUntil(waitCtx, newBackoff(pollInitialInterval, pollMaxInterval), func() (bool, error) {
+				catalog, err := c.ManagementClient.Catalog.ByID(resource.ID)
 				if err != nil {
-					return err
-				}
-
-				if timeout > 0 && time.Since(start) > timeout {
-					return errors.New("catalog: timed out waiting for refresh")
+					return false, err
 				}
+				return catalog.State == "active", nil
+			})
+			if errors.Is(err, context.DeadlineExceeded) {
+				return timeoutErrorf("catalog: timed out waiting for refresh")
+			}
+			if errors.Is(err, context.Canceled) {
+				return interruptedErrorf("catalog: interrupted waiting for %s to become active", catalog.Name)
+			}
+			if err != nil {
+				return err
 			}
 
 		}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	gover "github.com/hashicorp/go-version"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/urfave/cli"
+)
+
+const showQuestionsDescription = `
+Shows the questions of a template version, the schema of the answers an app of it is installed
+with. The newest version is shown unless a version is given after the template or with --version.
+
+With -o json or -o yaml the whole questions.yaml schema is printed, including the options,
+limits and subquestions, for tools that generate forms or validate answers files.
+
+Example:
+	$ rancher catalog show-questions cattle-global-data:library-mysql
+	$ rancher catalog show-questions cattle-global-data:library-mysql:1.6.0 -o json
+`
+
+// QuestionsSchema is the document printed by 'catalog show-questions' with
+// -o json or -o yaml.
+type QuestionsSchema struct {
+	Template  string                      `json:"template"`
+	Version   string                      `json:"version"`
+	Questions []managementClient.Question `json:"questions"`
+}
+
+type QuestionData struct {
+	Variable string
+	Type     string
+	Default  string
+	Required bool
+	Group    string
+	ShowIf   string
+	Label    string
+}
+
+func catalogShowQuestions(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+
+	templateName, version := splitTemplateVersion(ctx.Args().First())
+	if ctx.String("version") != "" {
+		version = ctx.String("version")
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	resource, err := Lookup(c, templateName, "template")
+	if err != nil {
+		return err
+	}
+	template, err := getFilteredTemplate(ctx, c, resource.ID)
+	if err != nil {
+		return err
+	}
+	list, err := getTemplateVersionList(template)
+	if err != nil {
+		return err
+	}
+	link, err := list.link(version)
+	if err != nil {
+		return err
+	}
+	if link == "" {
+		return notFoundErrorf("version %s of template %s not found, run 'rancher app show-template %s' for a list of versions",
+			version, templateName, templateName)
+	}
+
+	templateVersion, err := c.ManagementClient.TemplateVersion.ByID(templateVersionIDFromVersionLink(link))
+	if err != nil {
+		return err
+	}
+
+	writer := NewTableWriter([][]string{
+		{"VARIABLE", "Variable"},
+		{"TYPE", "Type"},
+		{"DEFAULT", "Default"},
+		{"REQUIRED", "Required"},
+		{"GROUP", "Group"},
+		{"SHOW IF", "ShowIf"},
+		{"LABEL", "Label", "wide"},
+	}, ctx)
+	defer writer.Close()
+
+	// json and yaml get the schema as a single document
+	if format := ctx.String("format"); format == "json" || format == "yaml" {
+		writer.Write(&QuestionsSchema{
+			Template:  template.ID,
+			Version:   templateVersion.Version,
+			Questions: templateVersion.Questions,
+		})
+		return writer.Err()
+	}
+
+	for _, row := range questionRows(templateVersion.Questions) {
+		writer.Write(row)
+	}
+	return writer.Err()
+}
+
+// splitTemplateVersion splits a TEMPLATE[:VERSION] argument. Template IDs
+// contain a ':' themselves, so the last part is only taken as the version when
+// it parses as one.
+func splitTemplateVersion(arg string) (string, string) {
+	i := strings.LastIndex(arg, ":")
+	if i < 0 {
+		return arg, ""
+	}
+	if _, err := gover.NewVersion(arg[i+1:]); err != nil {
+		return arg, ""
+	}
+	return arg[:i], arg[i+1:]
+}
+
+// questionRows flattens questions and their subquestions into rows, a
+// subquestion is only shown when its parent has the showSubquestionIf value.
+func questionRows(questions []managementClient.Question) []QuestionData {
+	var rows []QuestionData
+	for _, question := range questions {
+		rows = append(rows, QuestionData{
+			Variable: question.Variable,
+			Type:     question.Type,
+			Default:  question.Default,
+			Required: question.Required,
+			Group:    question.Group,
+			ShowIf:   question.ShowIf,
+			Label:    question.Label,
+		})
+
+		for _, sub := range question.Subquestions {
+			showIf := fmt.Sprintf("%s=%s", question.Variable, question.ShowSubquestionIf)
+			if sub.ShowIf != "" {
+				showIf += "&&" + sub.ShowIf
+			}
+			rows = append(rows, QuestionData{
+				Variable: sub.Variable,
+				Type:     sub.Type,
+				Default:  sub.Default,
+				Required: sub.Required,
+				Group:    valueOrDefault(sub.Group, question.Group),
+				ShowIf:   showIf,
+				Label:    sub.Label,
+			})
+		}
+	}
+	return rows
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"testing"
+
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitTemplateVersion(t *testing.T) {
+	tests := []struct {
+		arg      string
+		template string
+		version  string
+	}{
+		{arg: "cattle-global-data:library-mysql", template: "cattle-global-data:library-mysql"},
+		{arg: "cattle-global-data:library-mysql:1.6.0", template: "cattle-global-data:library-mysql", version: "1.6.0"},
+		{arg: "mysql:v2.0.0-rc1", template: "mysql", version: "v2.0.0-rc1"},
+		{arg: "mysql", template: "mysql"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			template, version := splitTemplateVersion(tt.arg)
+			assert.Equal(t, tt.template, template)
+			assert.Equal(t, tt.version, version)
+		})
+	}
+}
+
+func TestQuestionRows(t *testing.T) {
+	questions := []managementClient.Question{
+		{
+			Variable:          "persistence.enabled",
+			Type:              "boolean",
+			Default:           "false",
+			Group:             "Storage",
+			ShowSubquestionIf: "true",
+			Subquestions: []managementClient.SubQuestion{
+				{Variable: "persistence.size", Type: "string", Default: "8Gi"},
+				{Variable: "persistence.storageClass", Type: "storageclass", ShowIf: "ha=true"},
+			},
+		},
+	}
+
+	assert.Equal(t, []QuestionData{
+		{Variable: "persistence.enabled", Type: "boolean", Default: "false", Group: "Storage"},
+		{Variable: "persistence.size", Type: "string", Default: "8Gi", Group: "Storage", ShowIf: "persistence.enabled=true"},
+		{Variable: "persistence.storageClass", Type: "storageclass", Group: "Storage", ShowIf: "persistence.enabled=true&&ha=true"},
+	}, questionRows(questions))
+}
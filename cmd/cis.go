@@ -0,0 +1,386 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/rancher/norman/clientbase"
+	"github.com/rancher/norman/types"
+	"github.com/urfave/cli"
+)
+
+const (
+	cisScanType       = "cis.cattle.io.clusterscan"
+	cisScanReportType = "cis.cattle.io.clusterscanreport"
+)
+
+type cisSummary struct {
+	Total         int `json:"total"`
+	Pass          int `json:"pass"`
+	Fail          int `json:"fail"`
+	Skip          int `json:"skip"`
+	Warn          int `json:"warn"`
+	NotApplicable int `json:"notApplicable"`
+}
+
+type ClusterScan struct {
+	types.Resource
+	Metadata objectMeta `json:"metadata,omitempty"`
+	Spec     struct {
+		ScanProfileName string `json:"scanProfileName,omitempty"`
+	} `json:"spec,omitempty"`
+	Status struct {
+		LastRunTimestamp       string            `json:"lastRunTimestamp,omitempty"`
+		LastRunScanProfileName string            `json:"lastRunScanProfileName,omitempty"`
+		Summary                *cisSummary       `json:"summary,omitempty"`
+		Conditions             []objectCondition `json:"conditions,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+type ClusterScanCollection struct {
+	types.Collection
+	Data []ClusterScan `json:"data,omitempty"`
+}
+
+type ClusterScanReport struct {
+	types.Resource
+	Metadata objectMeta `json:"metadata,omitempty"`
+	Spec     struct {
+		BenchmarkVersion string `json:"benchmarkVersion,omitempty"`
+		LastRunTimestamp string `json:"lastRunTimestamp,omitempty"`
+		ReportJSON       string `json:"reportJSON,omitempty"`
+	} `json:"spec,omitempty"`
+}
+
+type ClusterScanReportCollection struct {
+	types.Collection
+	Data []ClusterScanReport `json:"data,omitempty"`
+}
+
+// cisReport is the content of a scan report's reportJSON
+type cisReport struct {
+	Version string `json:"version"`
+	cisSummary
+	Results []struct {
+		ID     string `json:"id"`
+		Text   string `json:"text"`
+		Checks []struct {
+			ID          string `json:"id"`
+			Description string `json:"description"`
+			State       string `json:"state"`
+			Remediation string `json:"remediation"`
+		} `json:"checks"`
+	} `json:"results"`
+}
+
+type ClusterScanData struct {
+	ID          string
+	ClusterScan ClusterScan
+	Name        string
+	Profile     string
+	State       string
+	Summary     cisSummary
+	LastRun     string
+}
+
+func CISCommand() cli.Command {
+	scanLsFlags := []cli.Flag{
+		formatFlag,
+		quietFlag,
+		filterFlag,
+		labelSelectorFlag,
+		sortByFlag,
+		noHeadersFlag,
+	}
+
+	return cli.Command{
+		Name:  "cis",
+		Usage: "Operations on CIS benchmark scans",
+		Description: `
+Runs CIS benchmark scans and collects their reports. Scans are run by the rancher-cis-benchmark
+app, which must be installed in the scanned cluster.
+`,
+		Subcommands: []cli.Command{
+			{
+				Name:    "scan",
+				Aliases: []string{"scans"},
+				Usage:   "Operations on CIS scans",
+				Subcommands: []cli.Command{
+					{
+						Name:  "run",
+						Usage: "Start a CIS scan of a cluster",
+						Description: `
+Example:
+	# Scan a cluster with a specific profile and wait for the results
+	$ rancher cis scan run --profile rke-profile-hardened-1.6 --wait mycluster
+`,
+						ArgsUsage: "[CLUSTERNAME/CLUSTERID]",
+						Action:    cisScanRun,
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:  "profile",
+								Usage: "Scan profile to use, by default the cluster's default profile is used",
+							},
+							cli.StringFlag{
+								Name:  "name",
+								Usage: "Name of the scan, by default a name is generated",
+							},
+							cli.BoolFlag{
+								Name:  "wait,w",
+								Usage: "Wait for the scan to complete and print its summary",
+							},
+							cli.IntFlag{
+								Name:  "wait-timeout",
+								Usage: "Time in seconds to wait for the scan with --wait",
+								Value: 1800,
+							},
+						},
+					},
+					{
+						Name:        "ls",
+						Usage:       "List CIS scans",
+						Description: "\nLists the CIS scans of a cluster, by default of the cluster in the current context.",
+						ArgsUsage:   "[CLUSTERNAME/CLUSTERID]",
+						Action:      cisScanLs,
+						Flags:       scanLsFlags,
+					},
+					{
+						Name:  "report",
+						Usage: "Print the report of the latest run of a CIS scan",
+						Description: `
+Example:
+	$ rancher cis scan report --cluster mycluster --format html scan-x7k2p > report.html
+`,
+						ArgsUsage: "[SCAN_NAME]",
+						Action:    cisScanReport,
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:  "cluster",
+								Usage: "Cluster the scan was run in, by default the cluster in the current context",
+							},
+							cli.StringFlag{
+								Name:  "format,o",
+								Usage: "'json' or 'html'",
+								Value: "json",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func cisScanRun(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+
+	client, err := getClusterV1Client(ctx, ctx.Args().First(), cisScanType, "CIS benchmark")
+	if err != nil {
+		return err
+	}
+
+	scan := &ClusterScan{}
+	scan.Type = cisScanType
+	scan.Metadata.Name = ctx.String("name")
+	if scan.Metadata.Name == "" {
+		scan.Metadata.GenerateName = "scan-"
+	}
+	scan.Spec.ScanProfileName = ctx.String("profile")
+
+	created := &ClusterScan{}
+	if err := client.Create(cisScanType, scan, created); err != nil {
+		return err
+	}
+	fmt.Printf("Started CIS scan %s\n", created.Metadata.Name)
+
+	if !ctx.Bool("wait") {
+		return nil
+	}
+
+	waitCtx, cancel := waitContext(time.Duration(ctx.Int("wait-timeout")) * time.Second)
+	defer cancel()
+
+	err = pollUntil(waitCtx, newBackoff(pollInitialInterval, pollMaxInterval), func() (bool, error) {
+		if err := client.ByID(cisScanType, created.ID, created); err != nil {
+			return false, err
+		}
+		if message, failed := cisScanFailed(created); failed {
+			return false, fmt.Errorf("CIS scan %s failed: %s", created.Metadata.Name, message)
+		}
+		return created.Status.LastRunTimestamp != "" && created.Status.Summary != nil, nil
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return timeoutErrorf("timed out waiting for CIS scan %s", created.Metadata.Name)
+	}
+	if errors.Is(err, context.Canceled) {
+		return interruptedErrorf("interrupted waiting for CIS scan %s", created.Metadata.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	s := created.Status.Summary
+	fmt.Printf("Pass: %d, Fail: %d, Warn: %d, Skip: %d, Not applicable: %d (total %d)\n",
+		s.Pass, s.Fail, s.Warn, s.Skip, s.NotApplicable, s.Total)
+	return nil
+}
+
+// cisScanFailed reports whether the operator gave up on scan
+func cisScanFailed(scan *ClusterScan) (string, bool) {
+	if condition := findCondition(scan.Status.Conditions, "Failed"); condition != nil && condition.Status == "True" {
+		return condition.Message, true
+	}
+	return "", false
+}
+
+func cisScanLs(ctx *cli.Context) error {
+	client, err := getClusterV1Client(ctx, ctx.Args().First(), cisScanType, "CIS benchmark")
+	if err != nil {
+		return err
+	}
+
+	collection := &ClusterScanCollection{}
+	if err := client.List(cisScanType, &types.ListOpts{}, collection); err != nil {
+		return err
+	}
+
+	writer := NewTableWriter([][]string{
+		{"NAME", "Name"},
+		{"PROFILE", "Profile"},
+		{"STATE", "State"},
+		{"PASS", "Summary.Pass"},
+		{"FAIL", "Summary.Fail"},
+		{"WARN", "Summary.Warn"},
+		{"SKIP", "Summary.Skip"},
+		{"LAST-RUN", "LastRun"},
+	}, ctx)
+
+	defer writer.Close()
+
+	for _, item := range collection.Data {
+		data := &ClusterScanData{
+			ID:          item.ID,
+			ClusterScan: item,
+			Name:        item.Metadata.Name,
+			Profile:     item.Status.LastRunScanProfileName,
+			State:       objectState(item.Metadata),
+			LastRun:     item.Status.LastRunTimestamp,
+		}
+		if data.Profile == "" {
+			data.Profile = item.Spec.ScanProfileName
+		}
+		if item.Status.Summary != nil {
+			data.Summary = *item.Status.Summary
+		}
+		writer.Write(data)
+	}
+
+	return writer.Err()
+}
+
+func cisScanReport(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+
+	format := ctx.String("format")
+	if format != "json" && format != "html" {
+		return fmt.Errorf("invalid format %q, supported formats are json and html", format)
+	}
+
+	client, err := getClusterV1Client(ctx, ctx.String("cluster"), cisScanReportType, "CIS benchmark")
+	if err != nil {
+		return err
+	}
+
+	report, err := latestCISReport(client, ctx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	if format == "html" {
+		return writeCISReportHTML(os.Stdout, report)
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, []byte(report.Spec.ReportJSON), "", "  "); err != nil {
+		return err
+	}
+	out.WriteString("\n")
+	_, err = out.WriteTo(os.Stdout)
+	return err
+}
+
+// latestCISReport returns the report of the most recent run of scan
+func latestCISReport(client *clientbase.APIBaseClient, scan string) (*ClusterScanReport, error) {
+	collection := &ClusterScanReportCollection{}
+	if err := client.List(cisScanReportType, &types.ListOpts{}, collection); err != nil {
+		return nil, err
+	}
+
+	var reports []ClusterScanReport
+	for _, report := range collection.Data {
+		if ownedBy(report.Metadata, "ClusterScan", scan) {
+			reports = append(reports, report)
+		}
+	}
+	if len(reports) == 0 {
+		return nil, notFoundErrorf("no report found for CIS scan %s, the scan may still be running", scan)
+	}
+
+	// RFC3339 timestamps sort chronologically as strings
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Spec.LastRunTimestamp < reports[j].Spec.LastRunTimestamp
+	})
+	return &reports[len(reports)-1], nil
+}
+
+var cisReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>CIS benchmark {{.Version}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+td, th { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+.pass { color: #2e7d32; } .fail { color: #c62828; } .warn { color: #ef6c00; }
+</style>
+</head>
+<body>
+<h1>CIS benchmark {{.Version}}</h1>
+<table>
+<tr><th>Total</th><th>Pass</th><th>Fail</th><th>Warn</th><th>Skip</th><th>Not applicable</th></tr>
+<tr><td>{{.Total}}</td><td>{{.Pass}}</td><td>{{.Fail}}</td><td>{{.Warn}}</td><td>{{.Skip}}</td><td>{{.NotApplicable}}</td></tr>
+</table>
+{{range .Results}}
+<h2>{{.ID}} {{.Text}}</h2>
+<table>
+<tr><th>ID</th><th>Description</th><th>State</th><th>Remediation</th></tr>
+{{range .Checks}}<tr><td>{{.ID}}</td><td>{{.Description}}</td><td class="{{.State}}">{{.State}}</td><td>{{.Remediation}}</td></tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
+
+func writeCISReportHTML(out io.Writer, report *ClusterScanReport) error {
+	parsed := &cisReport{}
+	if err := json.Unmarshal([]byte(report.Spec.ReportJSON), parsed); err != nil {
+		return fmt.Errorf("unable to parse report %s: %w", report.Metadata.Name, err)
+	}
+	if parsed.Version == "" {
+		parsed.Version = report.Spec.BenchmarkVersion
+	}
+	return cisReportTemplate.Execute(out, parsed)
+}
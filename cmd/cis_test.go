@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCISReportHTML(t *testing.T) {
+	report := &ClusterScanReport{}
+	report.Spec.BenchmarkVersion = "rke-cis-1.6"
+	report.Spec.ReportJSON = `{"total":2,"pass":1,"fail":1,"results":[{"id":"1","text":"Control Plane",` +
+		`"checks":[{"id":"1.1.1","description":"<b>perms</b>","state":"fail","remediation":"chmod 644"}]}]}`
+
+	var out bytes.Buffer
+	require.NoError(t, writeCISReportHTML(&out, report))
+
+	html := out.String()
+	assert.Contains(t, html, "<title>CIS benchmark rke-cis-1.6</title>")
+	assert.Contains(t, html, `<td class="fail">fail</td>`)
+	assert.Contains(t, html, "&lt;b&gt;perms&lt;/b&gt;")
+	assert.NotContains(t, html, "<b>perms</b>")
+
+	report.Spec.ReportJSON = "not json"
+	assert.Error(t, writeCISReportHTML(&out, report))
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/rancher/cli/cliclient"
+	"github.com/rancher/cli/config"
+	"github.com/urfave/cli"
+)
+
+// ConfigureClients applies the global flags that change how the API clients
+// talk to the server, it must be called before any client is created.
+func ConfigureClients(ctx *cli.Context) error {
+	nameCache.configure(lookupCachePath(GetConfigPath(ctx)), ctx.GlobalDuration("cache-ttl"))
+	deletedApps.configure(deletedAppsPath(GetConfigPath(ctx)))
+	if cf, err := loadConfig(ctx); err == nil {
+		if server, err := focusedServerConfig(ctx, cf); err == nil {
+			command := leafCommand(ctx.App.Commands, ctx.Args())
+			readOnly := command != nil && isReadOnlyCommand(command)
+			listingCache.configure(outputCachePath(GetConfigPath(ctx)), listingCacheScope(ctx, server), ctx.Args(), readOnly, ctx.GlobalBool("cached"))
+		}
+	}
+	cliclient.SetRequestTimeout(ctx.GlobalDuration("request-timeout"))
+	cliclient.SetRetries(ctx.GlobalInt("retries"))
+	cliclient.SetSchemaCache(schemaCachePath(GetConfigPath(ctx)))
+	answerMasking = newSecretMasking(ctx.GlobalBool("show-secrets"), ctx.GlobalString("secret-patterns"))
+
+	cliclient.AddTransportWrapper(func(next http.RoundTripper) http.RoundTripper {
+		return newInterruptTransport(next, commandInterrupt)
+	})
+
+	if ctx.GlobalBool("debug-http") || ctx.GlobalBool("debug-http-bodies") {
+		bodies := ctx.GlobalBool("debug-http-bodies")
+		masking := answerMasking
+		cliclient.AddTransportWrapper(func(next http.RoundTripper) http.RoundTripper {
+			return newDebugTransport(next, bodies, masking)
+		})
+	}
+
+	if path := ctx.GlobalString("audit-log"); path != "" {
+		log := &auditLog{path: path}
+		cliclient.AddTransportWrapper(func(next http.RoundTripper) http.RoundTripper {
+			return newAuditTransport(next, log)
+		})
+	}
+
+	if ctx.GlobalBool("stats") {
+		requestStats = newAPIStats(time.Now)
+		cliclient.AddTransportWrapper(func(next http.RoundTripper) http.RoundTripper {
+			return newStatsTransport(next, requestStats)
+		})
+	}
+
+	if user := ctx.GlobalString("as"); user != "" {
+		impersonation := &impersonation{user: user}
+		cliclient.AddTransportWrapper(func(next http.RoundTripper) http.RoundTripper {
+			return newImpersonateTransport(next, impersonation)
+		})
+	}
+
+	// added last so that requests not sent because of --dry-run aren't logged or audited
+	if ctx.GlobalBool("dry-run") {
+		format := ctx.GlobalString("dry-run-format")
+		cliclient.AddTransportWrapper(func(next http.RoundTripper) http.RoundTripper {
+			return newDryRunTransport(next, os.Stdout, format)
+		})
+	}
+	return nil
+}
+
+// listingCacheScope returns what the cached output of listings depends on
+// besides the command: the server, the project and the user impersonated.
+func listingCacheScope(ctx *cli.Context, server *config.ServerConfig) string {
+	scope := server.URL + " " + valueOrDefault(ctx.GlobalString("project"), server.Project)
+	if user := ctx.GlobalString("as"); user != "" {
+		scope += " as " + user
+	}
+	return scope
+}
@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/rancher/cli/cliclient"
 	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
@@ -34,6 +36,8 @@ type ClusterData struct {
 	CPU      string
 	RAM      string
 	Pods     string
+	Age      string
+	Message  string
 }
 
 func ClusterCommand() cli.Command {
@@ -55,6 +59,13 @@ func ClusterCommand() cli.Command {
 						Usage: "'json', 'yaml' or Custom format: '{{.Cluster.ID}} {{.Cluster.Name}}'",
 					},
 					quietFlag,
+					filterFlag,
+					labelSelectorFlag,
+					sortByFlag,
+					timestampsFlag,
+					noHeadersFlag,
+					limitFlag,
+					pageSizeFlag,
 				},
 			},
 			{
@@ -89,6 +100,15 @@ func ClusterCommand() cli.Command {
 						Name:  "rke-config",
 						Usage: "Location of an rke config file to import. Can be JSON or YAML format",
 					},
+					cli.BoolFlag{
+						Name:  "wait",
+						Usage: "Wait for the cluster to be provisioned, showing the progress of its conditions",
+					},
+					cli.IntFlag{
+						Name:  "wait-timeout",
+						Usage: "Time in seconds to wait for the cluster with --wait",
+						Value: 1800,
+					},
 				},
 			},
 			{
@@ -136,6 +156,7 @@ func ClusterCommand() cli.Command {
 				Usage:     "Delete a cluster",
 				ArgsUsage: "[CLUSTERID/CLUSTERNAME...]",
 				Action:    clusterDelete,
+				Flags:     deleteFlags,
 			},
 			{
 				Name:      "export",
@@ -144,11 +165,112 @@ func ClusterCommand() cli.Command {
 				Action:    clusterExport,
 			},
 			{
-				Name:      "kubeconfig",
-				Aliases:   []string{"kf"},
-				Usage:     "Return the kube config used to access the cluster",
+				Name:        "compare",
+				Usage:       "Compare the configuration of two clusters",
+				Description: clusterCompareDescription,
+				ArgsUsage:   "CLUSTER_A CLUSTER_B",
+				Action:      clusterCompare,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "section",
+						Usage: "Only compare part of the configuration, 'rkeConfig', 'addons' or 'networking'",
+					},
+				},
+			},
+			{
+				Name:        "shell",
+				Usage:       "Open an interactive kubectl shell in a cluster",
+				Description: clusterShellDescription,
+				ArgsUsage:   "[CLUSTERID CLUSTERNAME]",
+				Action:      clusterShell,
+			},
+			{
+				Name:    "kubeconfig",
+				Aliases: []string{"kf"},
+				Usage:   "Return the kube config used to access the cluster",
+				Description: `
+Prints a kubeconfig authenticating as the current user through Rancher. With --serviceaccount
+the kubeconfig authenticates as a service account of the cluster instead and talks to the
+cluster's API server directly, which gives automation credentials that don't depend on a user.
+
+Example:
+	# Create a service account bound to the cluster-admin role and print its kubeconfig
+	$ rancher cluster kubeconfig --serviceaccount ci/deployer --create --role cluster-admin mycluster
+`,
 				ArgsUsage: "[CLUSTERID CLUSTERNAME]",
 				Action:    clusterKubeConfig,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "serviceaccount",
+						Usage: "Service account to authenticate as, given as NAMESPACE/NAME",
+					},
+					cli.BoolFlag{
+						Name:  "create",
+						Usage: "Create the service account if it doesn't exist",
+					},
+					cli.StringFlag{
+						Name:  "role",
+						Usage: "ClusterRole to bind the service account to with --create, e.g. cluster-admin",
+					},
+				},
+			},
+			{
+				Name:        "health",
+				Usage:       "Show the health of a cluster and its components",
+				Description: clusterHealthDescription,
+				ArgsUsage:   "[CLUSTERNAME/CLUSTERID]",
+				Action:      clusterHealth,
+				Flags: []cli.Flag{
+					cli.IntFlag{
+						Name:  "events",
+						Usage: "Number of recent warning events to show, 0 to show none",
+						Value: 10,
+					},
+					timestampsFlag,
+					formatFlag,
+					noHeadersFlag,
+				},
+			},
+			{
+				Name:        "logs",
+				Usage:       "Show the provisioning log of a cluster",
+				Description: clusterLogsDescription,
+				ArgsUsage:   "[CLUSTERNAME/CLUSTERID]",
+				Action:      clusterLogs,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "provisioning",
+						Usage: "Show the provisioning log and conditions of the cluster",
+					},
+					cli.BoolFlag{
+						Name:  "follow,f",
+						Usage: "Keep printing new messages until the cluster is active",
+					},
+				},
+			},
+			ClusterRegistrationTokenCommand(),
+			ClusterAddonsCommand(),
+			ClusterMonitoringCommand(),
+			{
+				Name:        "set-registry",
+				Usage:       "Configure the registry mirrors of a cluster",
+				Description: setRegistryDescription,
+				ArgsUsage:   "[CLUSTERID CLUSTERNAME]",
+				Action:      clusterSetRegistry,
+				Flags: []cli.Flag{
+					cli.StringSliceFlag{
+						Name:  "mirror",
+						Usage: "Mirror of a registry given as REGISTRY=ENDPOINT, e.g. docker.io=registry.internal:5000",
+					},
+					cli.StringFlag{
+						Name:  "ca",
+						Usage: "File with the CA certificate of the mirrors",
+					},
+					cli.BoolFlag{
+						Name:  "insecure",
+						Usage: "Skip verifying the certificate of the mirrors",
+					},
+				},
 			},
 			{
 				Name:        "add-member-role",
@@ -196,17 +318,20 @@ func ClusterCommand() cli.Command {
 	}
 }
 
+func clusterPageData(c *managementClient.ClusterCollection) []managementClient.Cluster {
+	return c.Data
+}
+
 func clusterLs(ctx *cli.Context) error {
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	collection, err := c.ManagementClient.Cluster.List(defaultListOpts(ctx))
+	collection, err := c.ManagementClient.Cluster.List(filteredListOpts(ctx))
 	if err != nil {
 		return err
 	}
-
 	writer := NewTableWriter([][]string{
 		{"CURRENT", "Current"},
 		{"ID", "ID"},
@@ -217,11 +342,14 @@ func clusterLs(ctx *cli.Context) error {
 		{"CPU", "CPU"},
 		{"RAM", "RAM"},
 		{"PODS", "Pods"},
+		{"AGE", "Age"},
+		{"DRIVER", "Cluster.Driver", wideFormat},
+		{"MESSAGE", "Message", wideFormat},
 	}, ctx)
 
 	defer writer.Close()
 
-	for _, item := range collection.Data {
+	err = forEachPage(ctx, collection, clusterPageData, func(item managementClient.Cluster) error {
 		var current string
 		if item.ID == c.UserConfig.FocusedCluster() {
 			current = "*"
@@ -237,7 +365,13 @@ func clusterLs(ctx *cli.Context) error {
 			CPU:      getClusterCPU(item),
 			RAM:      getClusterRAM(item),
 			Pods:     getClusterPods(item),
+			Age:      formatAge(ctx, item.Created),
+			Message:  FormatMessage(item.TransitioningMessage),
 		})
+		return nil
+	}, writer.Flush)
+	if err != nil {
+		return err
 	}
 
 	return writer.Err()
@@ -245,9 +379,9 @@ func clusterLs(ctx *cli.Context) error {
 
 func clusterCreate(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -279,15 +413,18 @@ func clusterCreate(ctx *cli.Context) error {
 	}
 
 	fmt.Printf("Successfully created cluster %v\n", createdCluster.Name)
+	if ctx.Bool("wait") {
+		return waitForResource(c, &createdCluster.Resource, time.Duration(ctx.Int("wait-timeout"))*time.Second)
+	}
 	return nil
 }
 
 func clusterImport(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -325,10 +462,10 @@ func clusterImport(ctx *cli.Context) error {
 // clusterAddNode prints the command needed to add a node to a cluster
 func clusterAddNode(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -400,41 +537,43 @@ func clusterAddNode(ctx *cli.Context) error {
 
 func clusterDelete(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	for _, cluster := range ctx.Args() {
-
-		resource, err := Lookup(c, cluster, "cluster")
-		if err != nil {
-			return err
-		}
-
-		cluster, err := getClusterByID(c, resource.ID)
-		if err != nil {
-			return err
-		}
-
-		err = c.ManagementClient.Cluster.Delete(cluster)
-		if err != nil {
-			return err
-		}
-	}
+	return bulkDelete{
+		kind: "clusters",
+		resolve: func(arg string) (*bulkTarget, error) {
+			resource, err := Lookup(c, arg, "cluster")
+			if err != nil {
+				return nil, err
+			}
 
-	return nil
+			cluster, err := getClusterByID(c, resource.ID)
+			if err != nil {
+				return nil, err
+			}
+			return &bulkTarget{
+				resource:     cluster.Resource,
+				descriptions: []string{fmt.Sprintf("%s (%s) with %d nodes", getClusterName(cluster), cluster.ID, cluster.NodeCount)},
+				delete: func() error {
+					return c.ManagementClient.Cluster.Delete(cluster)
+				},
+			}, nil
+		},
+	}.run(ctx, c)
 }
 
 func clusterExport(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -464,10 +603,10 @@ func clusterExport(ctx *cli.Context) error {
 
 func clusterKubeConfig(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -482,6 +621,18 @@ func clusterKubeConfig(ctx *cli.Context) error {
 		return err
 	}
 
+	if ctx.String("serviceaccount") != "" {
+		config, err := serviceAccountKubeConfig(ctx, c, cluster)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(config)
+		return err
+	}
+	if ctx.Bool("create") || ctx.String("role") != "" {
+		return errors.New("--create and --role require --serviceaccount")
+	}
+
 	config, err := c.ManagementClient.Cluster.ActionGenerateKubeconfig(cluster)
 	if err != nil {
 		return err
@@ -492,14 +643,14 @@ func clusterKubeConfig(ctx *cli.Context) error {
 
 func addClusterMemberRoles(ctx *cli.Context) error {
 	if len(ctx.Args()) < 2 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
 	memberName := ctx.Args().First()
 
 	roles := ctx.Args()[1:]
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -535,14 +686,14 @@ func addClusterMemberRoles(ctx *cli.Context) error {
 
 func deleteClusterMemberRoles(ctx *cli.Context) error {
 	if len(ctx.Args()) < 2 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
 	memberName := ctx.Args().First()
 
 	roles := ctx.Args()[1:]
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -588,7 +739,7 @@ func listClusterRoles(ctx *cli.Context) error {
 }
 
 func listClusterMembers(ctx *cli.Context) error {
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/rancher/cli/cliclient"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/urfave/cli"
+)
+
+const clusterAddonsDescription = `
+Shows and updates the configuration of the addons RKE deploys in a cluster, without editing the
+whole cluster configuration. Updating the configuration redeploys the addon.
+
+--set takes FIELD=VALUE for a field of the addon configuration, or FIELD.KEY=VALUE for a key of
+its maps such as options, extraArgs and nodeSelector. Lists such as upstreamnameservers take a
+comma separated VALUE. --unset removes a FIELD or a FIELD.KEY.
+
+Addons: nginx-ingress, coredns and metrics-server.
+
+Example:
+	$ rancher cluster addons get --addon nginx-ingress mycluster
+	$ rancher cluster addons set --addon nginx-ingress --set options.use-forwarded-headers=true mycluster
+	$ rancher cluster addons set --addon coredns --set upstreamnameservers=1.1.1.1,8.8.8.8 --wait mycluster
+`
+
+// clusterAddons are the sections of the RKE configuration of each addon and
+// the provider the section must have for the addon to be deployed.
+var clusterAddons = map[string]struct {
+	field    string
+	provider string
+}{
+	"nginx-ingress":  {field: "ingress", provider: "nginx"},
+	"coredns":        {field: "dns", provider: "coredns"},
+	"metrics-server": {field: "monitoring", provider: "metrics-server"},
+}
+
+var clusterAddonFlag = cli.StringFlag{
+	Name:  "addon",
+	Usage: "Addon to show or update: nginx-ingress, coredns or metrics-server",
+}
+
+func ClusterAddonsCommand() cli.Command {
+	return cli.Command{
+		Name:        "addons",
+		Usage:       "Show and update the configuration of the RKE addons of a cluster",
+		Description: clusterAddonsDescription,
+		Subcommands: []cli.Command{
+			{
+				Name:        "get",
+				Usage:       "Show the configuration of an addon",
+				Description: clusterAddonsDescription,
+				ArgsUsage:   "[CLUSTERID CLUSTERNAME]",
+				Action:      clusterAddonsGet,
+				Flags: []cli.Flag{
+					clusterAddonFlag,
+					cli.StringFlag{
+						Name:  "format,o",
+						Usage: "'json' or 'yaml'",
+						Value: "yaml",
+					},
+				},
+			},
+			{
+				Name:        "set",
+				Usage:       "Update the configuration of an addon and redeploy it",
+				Description: clusterAddonsDescription,
+				ArgsUsage:   "[CLUSTERID CLUSTERNAME]",
+				Action:      clusterAddonsSet,
+				Flags: []cli.Flag{
+					clusterAddonFlag,
+					cli.StringSliceFlag{
+						Name:  "set",
+						Usage: "Set a FIELD=VALUE or FIELD.KEY=VALUE of the addon configuration, can be used multiple times",
+					},
+					cli.StringSliceFlag{
+						Name:  "unset",
+						Usage: "Remove a FIELD or FIELD.KEY of the addon configuration, can be used multiple times",
+					},
+					cli.BoolFlag{
+						Name:  "wait",
+						Usage: "Wait for the cluster to be active after the update",
+					},
+					cli.IntFlag{
+						Name:  "wait-timeout",
+						Usage: "Time in seconds to wait for the cluster with --wait",
+						Value: 1800,
+					},
+				},
+			},
+		},
+	}
+}
+
+func clusterAddonsGet(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+	_, _, addon, err := getClusterAddon(c, ctx.Args().First(), ctx.String("addon"))
+	if err != nil {
+		return err
+	}
+
+	var content []byte
+	switch format := ctx.String("format"); format {
+	case "json":
+		content, err = json.MarshalIndent(addon, "", "  ")
+		content = append(content, '\n')
+	case "yaml", "":
+		content, err = yaml.Marshal(addon)
+	default:
+		return NewUsageError(fmt.Errorf("invalid format %q, use json or yaml", format))
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(content))
+	return nil
+}
+
+func clusterAddonsSet(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+	if len(ctx.StringSlice("set")) == 0 && len(ctx.StringSlice("unset")) == 0 {
+		return NewUsageError(errors.New("nothing to update, use --set or --unset"))
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+	cluster, rkeConfig, addon, err := getClusterAddon(c, ctx.Args().First(), ctx.String("addon"))
+	if err != nil {
+		return err
+	}
+	if err := applyAddonSettings(addon, ctx.StringSlice("set"), ctx.StringSlice("unset")); err != nil {
+		return err
+	}
+
+	rkeConfig[clusterAddons[ctx.String("addon")].field] = addon
+	if _, err := c.ManagementClient.Cluster.Update(cluster, map[string]interface{}{
+		"rancherKubernetesEngineConfig": rkeConfig,
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("Updated the %s addon of cluster %s, the cluster is being updated\n", ctx.String("addon"), cluster.Name)
+
+	if ctx.Bool("wait") {
+		return waitForResource(c, &cluster.Resource, time.Duration(ctx.Int("wait-timeout"))*time.Second)
+	}
+	return nil
+}
+
+// getClusterAddon returns a cluster with its RKE configuration and the
+// section of it configuring the addon.
+func getClusterAddon(c *cliclient.MasterClient, clusterName, addonName string) (*managementClient.Cluster, map[string]interface{}, map[string]interface{}, error) {
+	addon, ok := clusterAddons[addonName]
+	if !ok {
+		var names []string
+		for name := range clusterAddons {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, nil, nil, NewUsageError(fmt.Errorf("invalid addon %q, expected one of %s", addonName, strings.Join(names, ", ")))
+	}
+
+	resource, err := Lookup(c, clusterName, "cluster")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	cluster, err := getClusterByID(c, resource.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if cluster.RancherKubernetesEngineConfig == nil {
+		return nil, nil, nil, fmt.Errorf("cluster %s isn't an RKE cluster, its addons aren't deployed by RKE", cluster.Name)
+	}
+
+	content, err := json.Marshal(cluster.RancherKubernetesEngineConfig)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	rkeConfig := map[string]interface{}{}
+	if err := json.Unmarshal(content, &rkeConfig); err != nil {
+		return nil, nil, nil, err
+	}
+
+	section, _ := rkeConfig[addon.field].(map[string]interface{})
+	if section == nil {
+		section = map[string]interface{}{}
+	}
+	if provider, _ := section["provider"].(string); provider != "" && provider != addon.provider {
+		return nil, nil, nil, fmt.Errorf("cluster %s deploys %s instead of %s", cluster.Name, provider, addonName)
+	}
+	return cluster, rkeConfig, section, nil
+}
+
+// applyAddonSettings applies the FIELD[.KEY]=VALUE settings and removes the
+// FIELD[.KEY] unsettings of an addon configuration. The values of fields are
+// typed like helm does, the values of map keys are strings and the values
+// of lists are comma separated.
+func applyAddonSettings(addon map[string]interface{}, settings, unsettings []string) error {
+	for _, setting := range settings {
+		key, value, ok := strings.Cut(setting, "=")
+		if !ok || key == "" {
+			return NewUsageError(fmt.Errorf("invalid --set %q, expected FIELD=VALUE or FIELD.KEY=VALUE", setting))
+		}
+
+		field, mapKey, isMapKey := strings.Cut(key, ".")
+		if isMapKey {
+			entries, ok := addon[field].(map[string]interface{})
+			if !ok {
+				if addon[field] != nil {
+					return NewUsageError(fmt.Errorf("invalid --set %q, %s isn't a map", setting, field))
+				}
+				entries = map[string]interface{}{}
+				addon[field] = entries
+			}
+			entries[mapKey] = value
+			continue
+		}
+
+		if _, isList := addon[field].([]interface{}); isList {
+			var items []interface{}
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			addon[field] = items
+			continue
+		}
+		addon[field] = helmTypedValue(value)
+	}
+
+	for _, key := range unsettings {
+		field, mapKey, isMapKey := strings.Cut(key, ".")
+		if !isMapKey {
+			delete(addon, field)
+			continue
+		}
+		if entries, ok := addon[field].(map[string]interface{}); ok {
+			delete(entries, mapKey)
+		}
+	}
+	return nil
+}
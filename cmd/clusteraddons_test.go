@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyAddonSettings(t *testing.T) {
+	addon := map[string]interface{}{
+		"provider":            "coredns",
+		"upstreamnameservers": []interface{}{"8.8.8.8"},
+		"nodeSelector":        map[string]interface{}{"app": "dns"},
+		"options":             map[string]interface{}{"stale": "true"},
+	}
+
+	err := applyAddonSettings(addon, []string{
+		"upstreamnameservers=1.1.1.1, 9.9.9.9",
+		"nodeSelector.node-role.kubernetes.io/worker=true",
+		"extraArgs.v=2",
+		"httpPort=8080",
+	}, []string{"options.stale", "nodeSelector.app"})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"provider":            "coredns",
+		"upstreamnameservers": []interface{}{"1.1.1.1", "9.9.9.9"},
+		"nodeSelector":        map[string]interface{}{"node-role.kubernetes.io/worker": "true"},
+		"options":             map[string]interface{}{},
+		"extraArgs":           map[string]interface{}{"v": "2"},
+		"httpPort":            int64(8080),
+	}, addon)
+
+	assert.Error(t, applyAddonSettings(addon, []string{"provider.name=nginx"}, nil))
+	assert.Error(t, applyAddonSettings(addon, []string{"httpPort"}, nil))
+}
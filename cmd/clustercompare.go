@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli"
+)
+
+const clusterCompareDescription = `
+Compares the configuration of two clusters field by field. Lines starting with '~' show values
+that differ, '-' fields only set in CLUSTER_A and '+' fields only set in CLUSTER_B. Fields that
+identify a cluster or report its status are never compared.
+
+--section limits the comparison to part of the configuration:
+	rkeConfig   the Kubernetes distribution configuration
+	addons      the add-ons deployed in the cluster
+	networking  the network plugin, CIDRs and DNS
+
+Example:
+	$ rancher cluster compare --section networking prod-eu prod-us
+`
+
+// ignoredCompareKeys are the fields of a cluster that differ between any two
+// clusters.
+var ignoredCompareKeys = []string{
+	"actions", "agentImage", "allocatable", "annotations", "apiEndpoint", "appliedEnableNetworkPolicy",
+	"appliedSpec", "authImage", "baseType", "caCert", "capabilities", "certificatesExpiration",
+	"componentStatuses", "conditions", "created", "createdTS", "creatorId", "currentCisRunName",
+	"description", "failedSpec", "id", "labels", "limits", "links", "name", "nodeCount", "nodeVersion",
+	"requested", "serviceAccountTokenSecret", "state", "transitioning", "transitioningMessage", "type",
+	"uuid", "version",
+}
+
+// clusterCompareSections are the fields compared by every --section
+var clusterCompareSections = map[string][]string{
+	"rkeConfig": {
+		"rancherKubernetesEngineConfig",
+		"rke2Config",
+		"k3sConfig",
+	},
+	"addons": {
+		"rancherKubernetesEngineConfig.addons",
+		"rancherKubernetesEngineConfig.addonsInclude",
+		"rancherKubernetesEngineConfig.ingress",
+		"rancherKubernetesEngineConfig.monitoring",
+		"enableClusterAlerting",
+		"enableClusterMonitoring",
+	},
+	"networking": {
+		"rancherKubernetesEngineConfig.network",
+		"rancherKubernetesEngineConfig.dns",
+		"rancherKubernetesEngineConfig.services.kubeApi.serviceClusterIpRange",
+		"rancherKubernetesEngineConfig.services.kubeApi.serviceNodePortRange",
+		"rancherKubernetesEngineConfig.services.kubeController.clusterCidr",
+		"rancherKubernetesEngineConfig.services.kubeController.serviceClusterIpRange",
+		"rancherKubernetesEngineConfig.services.kubelet.clusterDnsServer",
+		"rancherKubernetesEngineConfig.services.kubelet.clusterDomain",
+		"enableNetworkPolicy",
+	},
+}
+
+func clusterCompare(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return showSubcommandUsage(ctx)
+	}
+
+	var paths []string
+	if section := ctx.String("section"); section != "" {
+		var ok bool
+		if paths, ok = clusterCompareSections[section]; !ok {
+			sections := make([]string, 0, len(clusterCompareSections))
+			for name := range clusterCompareSections {
+				sections = append(sections, name)
+			}
+			sort.Strings(sections)
+			return NewUsageError(fmt.Errorf("invalid section %q, expected one of %s", section, strings.Join(sections, ", ")))
+		}
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	clusters := make([]map[string]interface{}, 2)
+	for i, arg := range ctx.Args() {
+		resource, err := Lookup(c, arg, "cluster")
+		if err != nil {
+			return err
+		}
+		cluster := map[string]interface{}{}
+		if err := c.ManagementClient.ByID("cluster", resource.ID, &cluster); err != nil {
+			return err
+		}
+		clusters[i] = clusterCompareFields(cluster, paths)
+	}
+
+	return printDiff(os.Stdout, diffMaps("", clusters[0], clusters[1], false))
+}
+
+// clusterCompareFields returns the fields of a cluster to compare, the fields
+// at paths or, without paths, all but the ignored fields.
+func clusterCompareFields(cluster map[string]interface{}, paths []string) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if len(paths) == 0 {
+		for key, value := range cluster {
+			fields[key] = value
+		}
+		for _, key := range ignoredCompareKeys {
+			delete(fields, key)
+		}
+		return fields
+	}
+
+	for _, path := range paths {
+		if value, ok := lookupPath(cluster, path); ok {
+			fields[path] = value
+		}
+	}
+	return fields
+}
+
+// lookupPath returns the value of the field at a dotted path of nested maps
+func lookupPath(object map[string]interface{}, path string) (interface{}, bool) {
+	var value interface{} = object
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, value != nil
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterCompareFields(t *testing.T) {
+	cluster := map[string]interface{}{
+		"id":                  "c-abc12",
+		"name":                "prod-eu",
+		"enableNetworkPolicy": true,
+		"rancherKubernetesEngineConfig": map[string]interface{}{
+			"network": map[string]interface{}{"plugin": "canal"},
+			"services": map[string]interface{}{
+				"kubeController": map[string]interface{}{"clusterCidr": "10.42.0.0/16"},
+			},
+		},
+	}
+
+	all := clusterCompareFields(cluster, nil)
+	assert.NotContains(t, all, "id")
+	assert.NotContains(t, all, "name")
+	assert.Contains(t, all, "rancherKubernetesEngineConfig")
+
+	assert.Equal(t, map[string]interface{}{
+		"rancherKubernetesEngineConfig.network":                             map[string]interface{}{"plugin": "canal"},
+		"rancherKubernetesEngineConfig.services.kubeController.clusterCidr": "10.42.0.0/16",
+		"enableNetworkPolicy":                                               true,
+	}, clusterCompareFields(cluster, clusterCompareSections["networking"]))
+}
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rancher/cli/cliclient"
+	"github.com/rancher/norman/clientbase"
+	"github.com/rancher/norman/types"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/urfave/cli"
+)
+
+const (
+	deploymentType = "apps.deployment"
+	eventType      = "event"
+
+	clusterAgentID = "cattle-system/cattle-cluster-agent"
+)
+
+const clusterHealthDescription = `
+Reports the health of a cluster in one place: the cluster conditions, the cattle-cluster-agent
+connecting it to Rancher, the kubelet of every node, etcd and the control plane components,
+followed by the latest warning events of the system namespaces.
+
+Example:
+	$ rancher cluster health mycluster
+`
+
+// healthEventNamespaces are the namespaces whose events explain an unhealthy
+// cluster.
+var healthEventNamespaces = []string{"cattle-system", "kube-system"}
+
+type ClusterHealthData struct {
+	Component string
+	Status    string
+	Message   string
+}
+
+type Deployment struct {
+	types.Resource
+	Metadata objectMeta `json:"metadata,omitempty"`
+	Status   struct {
+		Replicas      int64 `json:"replicas,omitempty"`
+		ReadyReplicas int64 `json:"readyReplicas,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+type Event struct {
+	types.Resource
+	Metadata       objectMeta `json:"metadata,omitempty"`
+	EventType      string     `json:"_type,omitempty"`
+	Reason         string     `json:"reason,omitempty"`
+	Message        string     `json:"message,omitempty"`
+	Count          int64      `json:"count,omitempty"`
+	LastTimestamp  string     `json:"lastTimestamp,omitempty"`
+	EventTime      string     `json:"eventTime,omitempty"`
+	InvolvedObject struct {
+		Kind string `json:"kind,omitempty"`
+		Name string `json:"name,omitempty"`
+	} `json:"involvedObject,omitempty"`
+}
+
+type EventCollection struct {
+	types.Collection
+	Data []Event `json:"data,omitempty"`
+}
+
+type EventData struct {
+	Event     Event
+	Age       string
+	Namespace string
+	Object    string
+}
+
+func clusterHealth(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	resource, err := Lookup(c, ctx.Args().First(), "cluster")
+	if err != nil {
+		return err
+	}
+	cluster, err := getClusterByID(c, resource.ID)
+	if err != nil {
+		return err
+	}
+	nodes, err := getNodesList(ctx, c, cluster.ID)
+	if err != nil {
+		return err
+	}
+
+	rows := clusterHealthRows(cluster, nodes.Data)
+
+	// the agent and the events are read from the cluster, which fails when
+	// the cluster is unavailable
+	var events []Event
+	client, err := cliclient.NewClusterV1Client(c.UserConfig, cluster.ID)
+	if err == nil {
+		rows = append(rows, clusterAgentHealth(client))
+		events, err = warningEvents(client, ctx.Int("events"))
+	}
+	if err != nil {
+		rows = append(rows, ClusterHealthData{
+			Component: "cluster API",
+			Status:    "Unreachable",
+			Message:   err.Error(),
+		})
+	}
+
+	writer := NewTableWriter([][]string{
+		{"COMPONENT", "Component"},
+		{"STATUS", "Status"},
+		{"MESSAGE", "Message"},
+	}, ctx)
+	for _, row := range rows {
+		writer.Write(row)
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	writer = NewTableWriter([][]string{
+		{"AGE", "Age"},
+		{"NAMESPACE", "Namespace"},
+		{"OBJECT", "Object"},
+		{"REASON", "Event.Reason"},
+		{"MESSAGE", "Event.Message"},
+	}, ctx)
+	defer writer.Close()
+
+	for _, event := range events {
+		writer.Write(&EventData{
+			Event:     event,
+			Age:       eventAge(ctx, event),
+			Namespace: event.Metadata.Namespace,
+			Object:    event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name,
+		})
+	}
+	return writer.Err()
+}
+
+// clusterHealthRows returns the health of the cluster, of the kubelet of its
+// nodes and of its components as reported by Rancher.
+func clusterHealthRows(cluster *managementClient.Cluster, nodes []managementClient.Node) []ClusterHealthData {
+	rows := []ClusterHealthData{{Component: "cluster", Status: cluster.State}}
+	for _, condition := range cluster.Conditions {
+		if condition.Type == "Ready" && condition.Status != "True" {
+			rows[0].Message = valueOrDefault(condition.Message, condition.Reason)
+		}
+	}
+
+	for _, node := range nodes {
+		row := ClusterHealthData{Component: "kubelet/" + getNodeName(node), Status: "Unknown"}
+		for _, condition := range node.Conditions {
+			if condition.Type != "Ready" {
+				continue
+			}
+			row.Status = "NotReady"
+			if condition.Status == "True" {
+				row.Status = "Ready"
+			} else {
+				row.Message = valueOrDefault(condition.Message, condition.Reason)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	for _, component := range cluster.ComponentStatuses {
+		row := ClusterHealthData{Component: component.Name, Status: "Unknown"}
+		for _, condition := range component.Conditions {
+			if condition.Type != "Healthy" {
+				continue
+			}
+			row.Status = "Unhealthy"
+			if condition.Status == "True" {
+				row.Status = "Healthy"
+			} else {
+				row.Message = valueOrDefault(condition.Error, condition.Message)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func clusterAgentHealth(client *clientbase.APIBaseClient) ClusterHealthData {
+	row := ClusterHealthData{Component: "cattle-cluster-agent"}
+	agent := &Deployment{}
+	if err := client.ByID(deploymentType, clusterAgentID, agent); err != nil {
+		row.Status = "Unknown"
+		row.Message = err.Error()
+		return row
+	}
+
+	row.Status = "Available"
+	if agent.Status.ReadyReplicas == 0 {
+		row.Status = "Unavailable"
+	}
+	row.Message = fmt.Sprintf("%d/%d replicas ready", agent.Status.ReadyReplicas, agent.Status.Replicas)
+	return row
+}
+
+// warningEvents returns the latest limit warning events of the system
+// namespaces, newest first.
+func warningEvents(client *clientbase.APIBaseClient, limit int) ([]Event, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	var events []Event
+	for _, namespace := range healthEventNamespaces {
+		collection := &EventCollection{}
+		opts := &types.ListOpts{Filters: map[string]interface{}{"metadata.namespace": namespace}}
+		if err := client.List(eventType, opts, collection); err != nil {
+			return nil, err
+		}
+		for _, event := range collection.Data {
+			if event.EventType == "Warning" {
+				events = append(events, event)
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTimestamp(events[i]).After(eventTimestamp(events[j]))
+	})
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+func eventTimestamp(event Event) time.Time {
+	for _, value := range []string{event.LastTimestamp, event.EventTime, event.Metadata.CreationTimestamp} {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func eventAge(ctx *cli.Context, event Event) string {
+	timestamp := eventTimestamp(event)
+	if timestamp.IsZero() {
+		return ""
+	}
+	return formatTimeAge(ctx, timestamp)
+}
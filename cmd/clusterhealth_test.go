@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"testing"
+
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterHealthRows(t *testing.T) {
+	cluster := &managementClient.Cluster{
+		State: "unavailable",
+		Conditions: []managementClient.ClusterCondition{
+			{Type: "Ready", Status: "False", Message: "cluster agent is not connected"},
+		},
+		ComponentStatuses: []managementClient.ClusterComponentStatus{
+			{Name: "etcd-0", Conditions: []managementClient.ComponentCondition{{Type: "Healthy", Status: "True"}}},
+			{Name: "scheduler", Conditions: []managementClient.ComponentCondition{{Type: "Healthy", Status: "False", Error: "connection refused"}}},
+		},
+	}
+	nodes := []managementClient.Node{
+		{NodeName: "node-1", Conditions: []managementClient.NodeCondition{{Type: "Ready", Status: "True"}}},
+		{NodeName: "node-2", Conditions: []managementClient.NodeCondition{{Type: "Ready", Status: "Unknown", Reason: "NodeStatusUnknown"}}},
+	}
+
+	assert.Equal(t, []ClusterHealthData{
+		{Component: "cluster", Status: "unavailable", Message: "cluster agent is not connected"},
+		{Component: "kubelet/node-1", Status: "Ready"},
+		{Component: "kubelet/node-2", Status: "NotReady", Message: "NodeStatusUnknown"},
+		{Component: "etcd-0", Status: "Healthy"},
+		{Component: "scheduler", Status: "Unhealthy", Message: "connection refused"},
+	}, clusterHealthRows(cluster, nodes))
+}
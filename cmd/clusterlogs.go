@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rancher/cli/cliclient"
+	"github.com/rancher/norman/clientbase"
+	"github.com/rancher/norman/types"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/urfave/cli"
+)
+
+const (
+	configMapType = "configmap"
+
+	// provisioningLogName is the config map Rancher writes the provisioning
+	// log of a cluster to, in the namespace named after the cluster's ID
+	provisioningLogName = "provisioning-log"
+
+	// logsPollMaxInterval is the longest wait between two reads of the log
+	// with --follow
+	logsPollMaxInterval = 5 * time.Second
+)
+
+const clusterLogsDescription = `
+Shows how the provisioning of a cluster is going: the changes of its state and conditions, and
+the provisioning log Rancher keeps for the cluster when you can read it. With --follow new
+messages are printed as they come until the cluster is active.
+
+Example:
+	$ rancher cluster logs --provisioning --follow mycluster
+`
+
+type ConfigMap struct {
+	types.Resource
+	Metadata objectMeta        `json:"metadata,omitempty"`
+	Data     map[string]string `json:"data,omitempty"`
+}
+
+// provisioningLog prints the provisioning messages of a cluster that are new
+// since it was last called.
+type provisioningLog struct {
+	out io.Writer
+	// lastLine is the last line of the provisioning log that was printed
+	lastLine string
+	// state is the last printed state and transitioning message
+	state string
+	// conditions are the last printed statuses and messages of the conditions
+	conditions map[string]string
+}
+
+func clusterLogs(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+	if !ctx.Bool("provisioning") {
+		return NewUsageError(errors.New("only the provisioning logs of a cluster are available, use --provisioning"))
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	resource, err := Lookup(c, ctx.Args().First(), "cluster")
+	if err != nil {
+		return err
+	}
+
+	// the provisioning log is kept in the local cluster, which not every
+	// user can read
+	local, err := cliclient.NewClusterV1Client(c.UserConfig, "local")
+	if err != nil {
+		local = nil
+	}
+
+	log := &provisioningLog{out: os.Stdout, conditions: map[string]string{}}
+	check := func() (bool, error) {
+		cluster, err := getClusterByID(c, resource.ID)
+		if err != nil {
+			return false, err
+		}
+		log.printStatus(cluster)
+		if content, ok := readProvisioningLog(local, cluster.ID); ok {
+			log.printLog(content)
+		}
+		return cluster.State == "active" && cluster.Transitioning != "yes", nil
+	}
+
+	if !ctx.Bool("follow") {
+		_, err := check()
+		return err
+	}
+
+	waitCtx, cancel := waitContext(0)
+	defer cancel()
+
+	err = pollUntil(waitCtx, newBackoff(pollInitialInterval, logsPollMaxInterval), check)
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// readProvisioningLog returns the provisioning log of a cluster, false when
+// it can't be read.
+func readProvisioningLog(client *clientbase.APIBaseClient, clusterID string) (string, bool) {
+	if client == nil {
+		return "", false
+	}
+	configMap := &ConfigMap{}
+	if err := client.ByID(configMapType, clusterID+"/"+provisioningLogName, configMap); err != nil {
+		return "", false
+	}
+	return configMap.Data["log"], true
+}
+
+// printLog prints the lines of content after the last printed line. Rancher
+// drops the oldest lines of a long log, so the last printed line is searched
+// instead of counting lines.
+func (l *provisioningLog) printLog(content string) {
+	content = strings.TrimRight(content, "\n")
+	if content == "" {
+		return
+	}
+
+	if l.lastLine != "" {
+		if strings.HasSuffix(content, l.lastLine) {
+			return
+		}
+		if i := strings.LastIndex(content, l.lastLine+"\n"); i >= 0 {
+			content = content[i+len(l.lastLine)+1:]
+		}
+	}
+
+	fmt.Fprintln(l.out, content)
+	lines := strings.Split(content, "\n")
+	l.lastLine = lines[len(lines)-1]
+}
+
+// printStatus prints the state and the conditions of a cluster that changed
+// since they were last printed.
+func (l *provisioningLog) printStatus(cluster *managementClient.Cluster) {
+	state := cluster.State
+	if cluster.TransitioningMessage != "" {
+		state += ": " + cluster.TransitioningMessage
+	}
+	if state != l.state {
+		fmt.Fprintf(l.out, "[state] %s\n", state)
+		l.state = state
+	}
+
+	for _, condition := range cluster.Conditions {
+		status := condition.Status
+		if message := valueOrDefault(condition.Message, condition.Reason); message != "" {
+			status += ": " + message
+		}
+		if l.conditions[condition.Type] == status {
+			continue
+		}
+		fmt.Fprintf(l.out, "[condition] %s %s\n", condition.Type, status)
+		l.conditions[condition.Type] = status
+	}
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvisioningLogPrintLog(t *testing.T) {
+	out := &bytes.Buffer{}
+	log := &provisioningLog{out: out, conditions: map[string]string{}}
+
+	log.printLog("[INFO ] creating etcd node\n[INFO ] waiting for etcd\n")
+	log.printLog("[INFO ] creating etcd node\n[INFO ] waiting for etcd\n")
+	// the oldest lines are dropped from a long log
+	log.printLog("[INFO ] waiting for etcd\n[INFO ] creating control plane node\n")
+
+	assert.Equal(t, "[INFO ] creating etcd node\n[INFO ] waiting for etcd\n[INFO ] creating control plane node\n", out.String())
+}
+
+func TestProvisioningLogPrintStatus(t *testing.T) {
+	out := &bytes.Buffer{}
+	log := &provisioningLog{out: out, conditions: map[string]string{}}
+
+	cluster := &managementClient.Cluster{
+		State:                "provisioning",
+		TransitioningMessage: "waiting for etcd",
+		Conditions: []managementClient.ClusterCondition{
+			{Type: "Provisioned", Status: "Unknown", Message: "waiting for etcd"},
+			{Type: "Ready", Status: "False"},
+		},
+	}
+	log.printStatus(cluster)
+	log.printStatus(cluster)
+
+	cluster.State = "active"
+	cluster.TransitioningMessage = ""
+	cluster.Conditions[0] = managementClient.ClusterCondition{Type: "Provisioned", Status: "True"}
+	log.printStatus(cluster)
+
+	assert.Equal(t, `[state] provisioning: waiting for etcd
+[condition] Provisioned Unknown: waiting for etcd
+[condition] Ready False
+[state] active
+[condition] Provisioned True
+`, out.String())
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/urfave/cli"
+)
+
+const clusterRegistrationTokenRotateDescription = `
+Replaces the registration tokens of a cluster with a new token and prints the registration
+commands using it. The commands printed before stop working, which is needed when they leaked or
+expired.
+
+The agents of the cluster keep the token they registered with, apply the new import command on
+the cluster, or run the new node command on its nodes, for them to use the new token.
+
+Example:
+	$ rancher cluster registration-token rotate mycluster
+`
+
+func ClusterRegistrationTokenCommand() cli.Command {
+	return cli.Command{
+		Name:  "registration-token",
+		Usage: "Operations on the token registering imported clusters and custom nodes",
+		Subcommands: []cli.Command{
+			{
+				Name:      "show",
+				Usage:     "Print the registration commands of a cluster",
+				ArgsUsage: "[CLUSTERID CLUSTERNAME]",
+				Action:    clusterRegistrationTokenShow,
+			},
+			{
+				Name:        "rotate",
+				Usage:       "Replace the registration token of a cluster",
+				Description: clusterRegistrationTokenRotateDescription,
+				ArgsUsage:   "[CLUSTERID CLUSTERNAME]",
+				Action:      clusterRegistrationTokenRotate,
+				Flags: []cli.Flag{
+					forceFlag,
+				},
+			},
+		},
+	}
+}
+
+func clusterRegistrationTokenShow(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	resource, err := Lookup(c, ctx.Args().First(), "cluster")
+	if err != nil {
+		return err
+	}
+
+	token, err := getClusterRegToken(ctx, c, resource.ID)
+	if err != nil {
+		return err
+	}
+	printRegistrationCommands(os.Stdout, token)
+	return nil
+}
+
+func clusterRegistrationTokenRotate(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	resource, err := Lookup(c, ctx.Args().First(), "cluster")
+	if err != nil {
+		return err
+	}
+
+	filter := defaultListOpts(ctx)
+	filter.Filters["clusterId"] = resource.ID
+	existing, err := c.ManagementClient.ClusterRegistrationToken.List(filter)
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("The registration token of cluster %s will be replaced, its registration commands will stop working.", ctx.Args().First())
+	if !confirmAction(ctx, message) {
+		return errCancelled
+	}
+
+	// the new token is created first so that the cluster always has one
+	token, err := c.ManagementClient.ClusterRegistrationToken.Create(&managementClient.ClusterRegistrationToken{
+		ClusterID: resource.ID,
+	})
+	if err != nil {
+		return err
+	}
+	for _, old := range existing.Data {
+		if err := c.ManagementClient.ClusterRegistrationToken.Delete(&old); err != nil {
+			return fmt.Errorf("deleting registration token %s, the new token %s was created: %w", old.ID, token.ID, err)
+		}
+	}
+
+	printRegistrationCommands(os.Stdout, *token)
+	return nil
+}
+
+// printRegistrationCommands prints the registration commands of a token
+// that are set, which depend on the kind of cluster.
+func printRegistrationCommands(out io.Writer, token managementClient.ClusterRegistrationToken) {
+	commands := []struct {
+		name    string
+		command string
+	}{
+		{"Import command", token.Command},
+		{"Insecure import command", token.InsecureCommand},
+		{"Node command", token.NodeCommand},
+		{"Insecure node command", token.InsecureNodeCommand},
+		{"Windows node command", token.WindowsNodeCommand},
+		{"Insecure Windows node command", token.InsecureWindowsNodeCommand},
+	}
+
+	first := true
+	for _, c := range commands {
+		if c.command == "" {
+			continue
+		}
+		if !first {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "%s:\n%s\n", c.name, c.command)
+		first = false
+	}
+	if first {
+		fmt.Fprintln(out, "The registration token has no commands yet, try again in a moment")
+	}
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintRegistrationCommands(t *testing.T) {
+	out := &bytes.Buffer{}
+	printRegistrationCommands(out, managementClient.ClusterRegistrationToken{
+		Command:         "kubectl apply -f https://rancher/v3/import/abc.yaml",
+		InsecureCommand: "curl --insecure -sfL https://rancher/v3/import/abc.yaml | kubectl apply -f -",
+	})
+	assert.Equal(t, "Import command:\nkubectl apply -f https://rancher/v3/import/abc.yaml\n\n"+
+		"Insecure import command:\ncurl --insecure -sfL https://rancher/v3/import/abc.yaml | kubectl apply -f -\n", out.String())
+
+	out.Reset()
+	printRegistrationCommands(out, managementClient.ClusterRegistrationToken{})
+	assert.Equal(t, "The registration token has no commands yet, try again in a moment\n", out.String())
+}
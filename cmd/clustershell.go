@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rancher/cli/cliclient"
+	"github.com/urfave/cli"
+	"golang.org/x/term"
+)
+
+const clusterShellDescription = `
+Opens an interactive kubectl shell in a cluster, the same shell as "Launch kubectl" in the
+Rancher UI. The shell runs in the cluster and is reached through the Rancher server, so it
+needs neither kubectl installed locally nor a network path to the cluster.
+
+The shell ends with 'exit' or Ctrl-D.
+
+Example:
+	$ rancher cluster shell mycluster
+`
+
+// shellProtocol is the websocket subprotocol of the Kubernetes exec streams,
+// each message is the channel followed by the base64 encoded data.
+const shellProtocol = "base64.channel.k8s.io"
+
+// The channels of the exec streams
+const (
+	shellStdin  = '0'
+	shellStdout = '1'
+	shellStderr = '2'
+	shellError  = '3'
+	shellResize = '4'
+)
+
+func clusterShell(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	resource, err := Lookup(c, ctx.Args().First(), "cluster")
+	if err != nil {
+		return err
+	}
+
+	shellURL, err := clusterShellURL(c.UserConfig.URL, resource.ID)
+	if err != nil {
+		return err
+	}
+	tlsConfig, err := cliclient.TLSConfig(c.UserConfig.CACerts)
+	if err != nil {
+		return err
+	}
+	dialer := &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		TLSClientConfig:  tlsConfig,
+		Subprotocols:     []string{shellProtocol},
+		HandshakeTimeout: 30 * time.Second,
+	}
+	header := http.Header{}
+	header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString(
+		[]byte(c.UserConfig.AccessKey+":"+c.UserConfig.SecretKey)))
+
+	stop := commandInterrupt.watch()
+	defer stop()
+	conn, resp, err := dialer.DialContext(commandInterrupt.context(), shellURL, header)
+	if err != nil {
+		if resp != nil {
+			return fmt.Errorf("unable to open a shell in cluster %s: %s", ctx.Args().First(), resp.Status)
+		}
+		return err
+	}
+	defer conn.Close()
+
+	session := &shellSession{conn: conn}
+	stdin := int(os.Stdin.Fd())
+	if term.IsTerminal(stdin) {
+		state, err := term.MakeRaw(stdin)
+		if err != nil {
+			return err
+		}
+		defer term.Restore(stdin, state)
+	}
+	if stdout := int(os.Stdout.Fd()); term.IsTerminal(stdout) {
+		go session.forwardResizes(stdout)
+	}
+	go session.forwardInput(os.Stdin)
+	return session.copyOutput(os.Stdout, os.Stderr)
+}
+
+// clusterShellURL returns the URL of the websocket of the kubectl shell of a
+// cluster.
+func clusterShellURL(serverURL, clusterID string) (string, error) {
+	u, err := url.Parse(strings.TrimSuffix(strings.TrimSuffix(serverURL, "/"), "/v3"))
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	case "http":
+		u.Scheme = "ws"
+	default:
+		return "", fmt.Errorf("invalid server URL %q", serverURL)
+	}
+	u.Path += "/v3/clusters/" + url.PathEscape(clusterID)
+	u.RawQuery = "shell=true"
+	return u.String(), nil
+}
+
+// shellSession streams a terminal to and from the websocket of a shell
+type shellSession struct {
+	conn *websocket.Conn
+	// the input and the resizes are written concurrently
+	lock sync.Mutex
+}
+
+func (s *shellSession) send(channel byte, data []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.conn.WriteMessage(websocket.TextMessage, encodeShellFrame(channel, data))
+}
+
+// forwardInput sends in to the shell until it's closed.
+func (s *shellSession) forwardInput(in io.Reader) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := in.Read(buf)
+		if n > 0 {
+			if sendErr := s.send(shellStdin, buf[:n]); sendErr != nil {
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// forwardResizes sends the size of the terminal when it changes.
+func (s *shellSession) forwardResizes(fd int) {
+	resizes := terminalResizes()
+	var width, height int
+	for {
+		w, h, err := term.GetSize(fd)
+		if err != nil {
+			return
+		}
+		if w != width || h != height {
+			width, height = w, h
+			size, _ := json.Marshal(map[string]int{"Width": width, "Height": height})
+			if err := s.send(shellResize, size); err != nil {
+				return
+			}
+		}
+		<-resizes
+	}
+}
+
+// copyOutput writes the output of the shell until it ends, and returns the
+// error it reports.
+func (s *shellSession) copyOutput(stdout, stderr io.Writer) error {
+	var shellErr error
+	for {
+		_, message, err := s.conn.ReadMessage()
+		if err != nil {
+			var closeErr *websocket.CloseError
+			if errors.As(err, &closeErr) || errors.Is(err, io.EOF) {
+				return shellErr
+			}
+			return err
+		}
+		channel, data, err := decodeShellFrame(message)
+		if err != nil {
+			return err
+		}
+		switch channel {
+		case shellStdout:
+			_, err = stdout.Write(data)
+		case shellStderr:
+			_, err = stderr.Write(data)
+		case shellError:
+			shellErr = shellStatusError(data)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func encodeShellFrame(channel byte, data []byte) []byte {
+	frame := make([]byte, 1+base64.StdEncoding.EncodedLen(len(data)))
+	frame[0] = channel
+	base64.StdEncoding.Encode(frame[1:], data)
+	return frame
+}
+
+func decodeShellFrame(frame []byte) (byte, []byte, error) {
+	if len(frame) == 0 {
+		return 0, nil, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(string(frame[1:]))
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid message from the shell: %w", err)
+	}
+	return frame[0], data, nil
+}
+
+// shellStatusError returns the error of the status sent when the shell ends,
+// nil when it succeeded.
+func shellStatusError(data []byte) error {
+	var status struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(data, &status); err != nil {
+		// older servers send the error as text
+		if message := strings.TrimSpace(string(data)); message != "" {
+			return errors.New(message)
+		}
+		return nil
+	}
+	if status.Status == "Success" || status.Status == "" {
+		return nil
+	}
+	return errors.New(status.Message)
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterShellURL(t *testing.T) {
+	shellURL, err := clusterShellURL("https://rancher.example.com/v3", "c-abc12")
+	assert.NoError(t, err)
+	assert.Equal(t, "wss://rancher.example.com/v3/clusters/c-abc12?shell=true", shellURL)
+
+	shellURL, err = clusterShellURL("http://localhost:8080/", "local")
+	assert.NoError(t, err)
+	assert.Equal(t, "ws://localhost:8080/v3/clusters/local?shell=true", shellURL)
+
+	_, err = clusterShellURL("rancher.example.com", "local")
+	assert.EqualError(t, err, `invalid server URL "rancher.example.com"`)
+}
+
+func TestShellSession(t *testing.T) {
+	upgrader := websocket.Upgrader{Subprotocols: []string{shellProtocol}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+
+		// echoes the input, then ends the shell with an error
+		_, message, err := conn.ReadMessage()
+		if !assert.NoError(t, err) {
+			return
+		}
+		channel, data, err := decodeShellFrame(message)
+		assert.NoError(t, err)
+		assert.Equal(t, byte(shellStdin), channel)
+		conn.WriteMessage(websocket.TextMessage, encodeShellFrame(shellStdout, data))
+		conn.WriteMessage(websocket.TextMessage, encodeShellFrame(shellStderr, []byte("warning\n")))
+		conn.WriteMessage(websocket.TextMessage, encodeShellFrame(shellError,
+			[]byte(`{"status":"Failure","message":"command terminated with non-zero exit code: 2"}`)))
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	}))
+	defer server.Close()
+
+	dialer := &websocket.Dialer{Subprotocols: []string{shellProtocol}}
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	session := &shellSession{conn: conn}
+	go session.forwardInput(strings.NewReader("kubectl get nodes\n"))
+	var stdout, stderr bytes.Buffer
+	err = session.copyOutput(&stdout, &stderr)
+	assert.EqualError(t, err, "command terminated with non-zero exit code: 2")
+	assert.Equal(t, "kubectl get nodes\n", stdout.String())
+	assert.Equal(t, "warning\n", stderr.String())
+}
+
+func TestShellStatusError(t *testing.T) {
+	assert.NoError(t, shellStatusError([]byte(`{"status":"Success"}`)))
+	assert.EqualError(t, shellStatusError([]byte("container not found")), "container not found")
+}
@@ -0,0 +1,26 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// terminalResizes returns a channel receiving a value when the terminal is
+// resized.
+func terminalResizes() <-chan struct{} {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGWINCH)
+	resizes := make(chan struct{}, 1)
+	go func() {
+		for range signals {
+			select {
+			case resizes <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return resizes
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package cmd
+
+import "time"
+
+// terminalResizes returns a channel receiving a value when the terminal may
+// have been resized, Windows has no signal for it so its size is polled.
+func terminalResizes() <-chan struct{} {
+	resizes := make(chan struct{})
+	go func() {
+		for range time.Tick(500 * time.Millisecond) {
+			resizes <- struct{}{}
+		}
+	}()
+	return resizes
+}
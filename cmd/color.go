@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli"
+	"golang.org/x/term"
+)
+
+// All color codes have the same length so that colored columns stay aligned
+// by the tabwriter, which counts the escape sequences as part of the width.
+const (
+	colorReset   = "\x1b[0m"
+	colorDefault = "\x1b[39m"
+	colorRed     = "\x1b[31m"
+	colorGreen   = "\x1b[32m"
+	colorYellow  = "\x1b[33m"
+)
+
+var (
+	// coloredColumns lists the headers of the columns colored by state
+	coloredColumns = []string{"STATE", "TRANSITIONING"}
+
+	healthyStates = []string{
+		"active",
+		"deployed",
+		"healthy",
+		"running",
+	}
+
+	transitioningStates = []string{
+		"activating",
+		"deploying",
+		"installing",
+		"pending",
+		"provisioning",
+		"removing",
+		"updating",
+		"upgrading",
+		"waiting",
+		"yes",
+	}
+
+	errorStates = []string{
+		"error",
+		"failed",
+		"unavailable",
+		"unhealthy",
+	}
+)
+
+// colorEnabled reports whether colored output should be used, that is when
+// stdout is a terminal, --no-color isn't set and NO_COLOR isn't set to a
+// non-empty value.
+func colorEnabled(ctx *cli.Context) bool {
+	if ctx.GlobalBool("no-color") {
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// colorState wraps a state value in the color matching its meaning.
+func colorState(value interface{}) string {
+	state := fmt.Sprint(value)
+	return stateColor(state) + state + colorReset
+}
+
+func stateColor(state string) string {
+	state = strings.ToLower(state)
+	switch {
+	case slices.Contains(healthyStates, state):
+		return colorGreen
+	case slices.Contains(transitioningStates, state):
+		return colorYellow
+	case slices.Contains(errorStates, state):
+		return colorRed
+	}
+	return colorDefault
+}
+
+// colorColumns returns a copy of columns where the state columns are rendered
+// through colorState. Headers get a colorless sequence of the same length to
+// keep them aligned with the values.
+func colorColumns(columns [][]string) [][]string {
+	var colored [][]string
+	for _, column := range columns {
+		if slices.Contains(coloredColumns, column[0]) && !strings.Contains(column[1], "{{") {
+			column = []string{
+				colorDefault + column[0] + colorReset,
+				"{{colorState ." + column[1] + "}}",
+			}
+		}
+		colored = append(colored, column)
+	}
+	return colored
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColorState(t *testing.T) {
+	assert.Equal(t, colorGreen+"active"+colorReset, colorState("active"))
+	assert.Equal(t, colorYellow+"Upgrading"+colorReset, colorState("Upgrading"))
+	assert.Equal(t, colorRed+"error"+colorReset, colorState("error"))
+	assert.Equal(t, colorDefault+"inactive"+colorReset, colorState("inactive"))
+}
+
+func TestTableWriterColorKeepsAlignment(t *testing.T) {
+	out := &bytes.Buffer{}
+	writer := NewTableWriterWithConfig([][]string{
+		{"STATE", "App.State"},
+		{"NAME", "App.Name"},
+	}, &TableWriterConfig{
+		Color:  true,
+		Writer: out,
+	})
+	writer.Write(newWriterTestRow("p-1", "app1", "active"))
+	writer.Write(newWriterTestRow("p-2", "app2", "provisioning"))
+	assert.NoError(t, writer.Close())
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 3)
+	header := strings.Index(lines[0], "NAME")
+	for _, line := range lines[1:] {
+		assert.Equal(t, header, strings.Index(line, "app"))
+	}
+	assert.Contains(t, lines[1], colorGreen+"active"+colorReset)
+	assert.Contains(t, lines[2], colorYellow+"provisioning"+colorReset)
+}
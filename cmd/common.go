@@ -30,6 +30,7 @@ import (
 	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
+	"golang.org/x/term"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 	"k8s.io/client-go/tools/clientcmd/api"
@@ -51,13 +52,53 @@ var (
 
 	formatFlag = cli.StringFlag{
 		Name:  "format,o",
-		Usage: "'json', 'yaml' or custom format",
+		Usage: "'json', 'yaml', 'wide', 'csv', 'custom-columns=HEADER:.Field,...', 'go-template-file=PATH' or custom format",
 	}
 
 	quietFlag = cli.BoolFlag{
 		Name:  "quiet,q",
 		Usage: "Only display IDs or suppress help text",
 	}
+
+	filterFlag = cli.StringSliceFlag{
+		Name:  "filter",
+		Usage: "Only list resources matching key=value, e.g. --filter state=active (can be repeated)",
+	}
+
+	noHeadersFlag = cli.BoolFlag{
+		Name:  "no-headers",
+		Usage: "Don't print the column headers",
+	}
+
+	limitFlag = cli.IntFlag{
+		Name:  "limit",
+		Usage: "Only list the first N resources, before --filter and --sort-by are applied",
+	}
+
+	pageSizeFlag = cli.IntFlag{
+		Name:  "page-size",
+		Usage: "Number of resources fetched per request, by default all of them are fetched at once",
+	}
+
+	timestampsFlag = cli.BoolFlag{
+		Name:  "timestamps,utc",
+		Usage: "Show exact RFC3339 UTC timestamps instead of relative ages in the AGE column",
+	}
+
+	forceFlag = cli.BoolFlag{
+		Name:  "force,f",
+		Usage: "Skip the confirmation prompt",
+	}
+
+	sortByFlag = cli.StringFlag{
+		Name:  "sort-by",
+		Usage: "Sort the output by the named column, append ':desc' to reverse the order, e.g. --sort-by NAME:desc",
+	}
+
+	labelSelectorFlag = cli.StringFlag{
+		Name:  "label-selector,l",
+		Usage: "Only list resources matching the label selector, e.g. 'env=prod,tier!=db'",
+	}
 )
 
 type MemberData struct {
@@ -148,7 +189,7 @@ func getKubeConfigForUser(ctx *cli.Context, user string) (*api.Config, error) {
 		return nil, err
 	}
 
-	focusedServer, err := cf.FocusedServer()
+	focusedServer, err := focusedServerConfig(ctx, cf)
 	if err != nil {
 		return nil, err
 	}
@@ -158,22 +199,19 @@ func getKubeConfigForUser(ctx *cli.Context, user string) (*api.Config, error) {
 }
 
 func setKubeConfigForUser(ctx *cli.Context, user string, kubeConfig *api.Config) error {
-	cf, err := loadConfig(ctx)
-	if err != nil {
-		return err
-	}
-
-	focusedServer, err := cf.FocusedServer()
-	if err != nil {
-		return err
-	}
+	return updateConfig(ctx, func(cf *config.Config) error {
+		focusedServer, err := focusedServerConfig(ctx, *cf)
+		if err != nil {
+			return err
+		}
 
-	if focusedServer.KubeConfigs == nil {
-		focusedServer.KubeConfigs = make(map[string]*api.Config)
-	}
+		if focusedServer.KubeConfigs == nil {
+			focusedServer.KubeConfigs = make(map[string]*api.Config)
+		}
 
-	focusedServer.KubeConfigs[fmt.Sprintf(kubeConfigKeyFormat, user, focusedServer.FocusedCluster())] = kubeConfig
-	return cf.Write()
+		focusedServer.KubeConfigs[fmt.Sprintf(kubeConfigKeyFormat, user, focusedServer.FocusedCluster())] = kubeConfig
+		return nil
+	})
 }
 
 func usersToNameMapping(u []managementClient.User) map[string]string {
@@ -265,28 +303,78 @@ func verifyCert(caCert []byte) (string, error) {
 	return string(caCert), nil
 }
 
+// GetConfigPath returns the path of the config file. The --config flag may
+// name either the config file itself or the directory holding cli2.json.
 func GetConfigPath(ctx *cli.Context) string {
 	// path will always be set by the global flag default
 	path := ctx.GlobalString("config")
+	if isConfigFile(path) {
+		return path
+	}
 	return filepath.Join(path, cfgFile)
 }
 
+// isConfigFile reports whether path is an existing regular file, or a .json
+// file that hasn't been created yet.
+func isConfigFile(path string) bool {
+	if info, err := os.Stat(path); err == nil {
+		return info.Mode().IsRegular()
+	}
+	return filepath.Ext(path) == ".json"
+}
+
 func loadConfig(ctx *cli.Context) (config.Config, error) {
 	path := GetConfigPath(ctx)
 	return config.LoadFromPath(path)
 }
 
+// updateConfig changes the config file with update, holding its lock from
+// loading it to saving it so that concurrent commands don't lose changes.
+func updateConfig(ctx *cli.Context, update func(cf *config.Config) error) error {
+	cf := config.Config{Path: GetConfigPath(ctx)}
+	return cf.Update(update)
+}
+
+// focusedServerConfig returns the server given with the global --server flag,
+// or else the current server. The flag doesn't change the current server.
+func focusedServerConfig(ctx *cli.Context, cf config.Config) (*config.ServerConfig, error) {
+	// the global --server is read from the app, as the token command has a
+	// --server flag of its own which would shadow it for its subcommands
+	root := ctx
+	for root.Parent() != nil {
+		root = root.Parent()
+	}
+	name := root.String("server")
+	if name == "" {
+		return cf.FocusedServer()
+	}
+	server, ok := cf.Servers[name]
+	if !ok || server == nil {
+		return nil, fmt.Errorf("server %s not found, run `rancher server ls` to see the configured servers", name)
+	}
+	return server, nil
+}
+
 func lookupConfig(ctx *cli.Context) (*config.ServerConfig, error) {
 	cf, err := loadConfig(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	cs, err := cf.FocusedServer()
+	cs, err := focusedServerConfig(ctx, cf)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := cs.ResolveToken(); err != nil {
+		return nil, err
+	}
+
+	// the global --project flag doesn't change the current project either
+	if project := ctx.GlobalString("project"); project != "" {
+		cs.Project = project
+	}
+
 	return cs, nil
 }
 
@@ -300,6 +388,37 @@ func GetClient(ctx *cli.Context) (*cliclient.MasterClient, error) {
 	if err != nil {
 		return nil, err
 	}
+	mc.DryRun = ctx.GlobalBool("dry-run")
+	warnIncompatibleServer(cf)
+
+	return mc, nil
+}
+
+// GetManagementClient returns a MasterClient with only the Management client
+// populated, for commands that never touch cluster or project resources and so
+// don't need to pay for the other clients' schema discovery.
+func GetManagementClient(ctx *cli.Context) (*cliclient.MasterClient, error) {
+	return getSingleClient(ctx, cliclient.NewManagementClient)
+}
+
+// GetCAPIClient returns a MasterClient with only the client for the /v1 API
+// populated, which serves the Kubernetes resources of the local cluster.
+func GetCAPIClient(ctx *cli.Context) (*cliclient.MasterClient, error) {
+	return getSingleClient(ctx, cliclient.NewCAPIClient)
+}
+
+func getSingleClient(ctx *cli.Context, newClient func(*config.ServerConfig) (*cliclient.MasterClient, error)) (*cliclient.MasterClient, error) {
+	cf, err := lookupConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mc, err := newClient(cf)
+	if err != nil {
+		return nil, err
+	}
+	mc.DryRun = ctx.GlobalBool("dry-run")
+	warnIncompatibleServer(cf)
 
 	return mc, nil
 }
@@ -340,6 +459,7 @@ func GetResourceType(c *cliclient.MasterClient, resource string) (string, error)
 
 func Lookup(c *cliclient.MasterClient, name string, types ...string) (*ntypes.Resource, error) {
 	var byName *ntypes.Resource
+	var cacheKey string
 
 	for _, schemaType := range types {
 		rt, err := GetResourceType(c, schemaType)
@@ -370,6 +490,24 @@ func Lookup(c *cliclient.MasterClient, name string, types ...string) (*ntypes.Re
 			}
 		}
 
+		// Names are only cached for single type lookups, otherwise a cached
+		// match would hide a resource with the same name of another type
+		if len(types) == 1 && c.UserConfig != nil && isCacheableLookupType(rt) {
+			cacheKey = lookupCacheKey(c.UserConfig.URL, rt, name)
+			if id, ok := nameCache.get(cacheKey); ok {
+				// the resource may have been renamed, or deleted and its
+				// name reused, since it was cached
+				var cached struct {
+					ntypes.Resource
+					Name string `json:"name"`
+				}
+				if err := schemaClient.ByID(schemaType, id, &cached); err == nil && cached.ID == id && cached.Name == name {
+					return &cached.Resource, nil
+				}
+				nameCache.delete(cacheKey)
+			}
+		}
+
 		// Attempt to get the resource by ID
 		var resource ntypes.Resource
 
@@ -418,9 +556,12 @@ func Lookup(c *cliclient.MasterClient, name string, types ...string) (*ntypes.Re
 	}
 
 	if byName == nil {
-		return nil, fmt.Errorf("Not found: %s", name)
+		return nil, notFoundErrorf("Not found: %s", name)
 	}
 
+	if cacheKey != "" {
+		nameCache.set(cacheKey, byName.ID)
+	}
 	return byName, nil
 }
 
@@ -447,11 +588,7 @@ func SimpleFormat(values [][]string) (string, string) {
 	valueBuffer := bytes.Buffer{}
 	for _, v := range values {
 		appendTabDelim(&headerBuffer, v[0])
-		if strings.Contains(v[1], "{{") {
-			appendTabDelim(&valueBuffer, v[1])
-		} else {
-			appendTabDelim(&valueBuffer, "{{."+v[1]+"}}")
-		}
+		appendTabDelim(&valueBuffer, columnTemplate(v[1]))
 	}
 
 	headerBuffer.WriteString("\n")
@@ -469,11 +606,21 @@ func defaultAction(fn func(ctx *cli.Context) error) func(ctx *cli.Context) error
 	}
 }
 
+// columnTemplate returns the template used to render a single column, field
+// is either a template already or a path such as "App.Name".
+func columnTemplate(field string) string {
+	if strings.Contains(field, "{{") {
+		return field
+	}
+	return "{{." + field + "}}"
+}
+
 func printTemplate(out io.Writer, templateContent string, obj interface{}) error {
 	funcMap := map[string]interface{}{
-		"endpoint": FormatEndpoint,
-		"ips":      FormatIPAddresses,
-		"json":     FormatJSON,
+		"endpoint":   FormatEndpoint,
+		"ips":        FormatIPAddresses,
+		"json":       FormatJSON,
+		"colorState": colorState,
 	}
 	tmpl, err := template.New("").Funcs(funcMap).Parse(templateContent)
 	if err != nil {
@@ -506,6 +653,51 @@ func selectFromList(header string, choices []string) int {
 	return selected - 1
 }
 
+// confirmDeletion shows what is about to be deleted and asks the user to
+// confirm it. There is no prompt when --force is set or stdin is not a
+// terminal.
+func confirmDeletion(ctx *cli.Context, kind string, descriptions []string) bool {
+	if ctx.Bool("force") || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return true
+	}
+	return promptDeletion(os.Stdin, os.Stdout, kind, descriptions)
+}
+
+func promptDeletion(in io.Reader, out io.Writer, kind string, descriptions []string) bool {
+	fmt.Fprintf(out, "The following %s will be deleted:\n", kind)
+	for _, description := range descriptions {
+		fmt.Fprintf(out, "  %s\n", description)
+	}
+	return promptContinue(in, out)
+}
+
+// confirmAction prints message and asks the user to confirm it, like
+// confirmDeletion.
+func confirmAction(ctx *cli.Context, message string) bool {
+	if ctx.Bool("force") || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return true
+	}
+	fmt.Fprintln(os.Stdout, message)
+	return promptContinue(os.Stdin, os.Stdout)
+}
+
+func promptContinue(in io.Reader, out io.Writer) bool {
+	fmt.Fprint(out, "Do you want to continue (yes/no)? ")
+
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		input := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if input == "yes" || input == "y" {
+			return true
+		} else if input == "no" || input == "n" {
+			break
+		}
+		fmt.Fprint(out, "Please type 'yes' or 'no': ")
+	}
+	fmt.Fprintln(out, "Aborted")
+	return false
+}
+
 func processExitCode(err error) error {
 	if exitErr, ok := err.(*exec.ExitError); ok {
 		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
@@ -603,6 +795,79 @@ func createdTimetoHuman(t string) (string, error) {
 	return parsedTime.Format("02 Jan 2006 15:04:05 MST"), nil
 }
 
+// formatAge renders an RFC3339 timestamp as returned by the API with
+// formatTimeAge, unparsable timestamps are shown as is.
+func formatAge(ctx *cli.Context, created string) string {
+	parsedTime, err := time.Parse(time.RFC3339, created)
+	if err != nil {
+		return created
+	}
+	return formatTimeAge(ctx, parsedTime)
+}
+
+// formatTimeAge renders t as the time elapsed since then, e.g. "3d4h", or as
+// an exact RFC3339 UTC timestamp when --timestamps is set.
+func formatTimeAge(ctx *cli.Context, t time.Time) string {
+	if ctx != nil && ctx.Bool("timestamps") {
+		return t.UTC().Format(time.RFC3339)
+	}
+	return humanizeDuration(time.Since(t))
+}
+
+// humanizeDuration formats d using its two most significant units.
+func humanizeDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	seconds := int(d.Seconds()) % 60
+
+	switch {
+	case days >= 365:
+		return fmt.Sprintf("%dy%dd", days/365, days%365)
+	case days > 0:
+		return fmt.Sprintf("%dd%dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%dm%ds", minutes, seconds)
+	}
+	return fmt.Sprintf("%ds", seconds)
+}
+
+// parseHumanDuration is the inverse of humanizeDuration.
+func parseHumanDuration(s string) (time.Duration, bool) {
+	units := map[byte]time.Duration{
+		'y': 365 * 24 * time.Hour,
+		'd': 24 * time.Hour,
+		'h': time.Hour,
+		'm': time.Minute,
+		's': time.Second,
+	}
+
+	var total time.Duration
+	var number string
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			number += string(s[i])
+			continue
+		}
+		unit, ok := units[s[i]]
+		if !ok || number == "" {
+			return 0, false
+		}
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return 0, false
+		}
+		total += time.Duration(n) * unit
+		number = ""
+	}
+	return total, s != "" && number == ""
+}
+
 func outputMembers(ctx *cli.Context, c *cliclient.MasterClient, members []managementClient.Member) error {
 	writer := NewTableWriter([][]string{
 		{"NAME", "Name"},
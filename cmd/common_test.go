@@ -1,7 +1,12 @@
 package cmd
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"gopkg.in/check.v1"
 )
@@ -59,6 +64,51 @@ func (s *CommonTestSuite) TestConvertSnakeCaseKeysToCamelCase(c *check.C) {
 	}
 }
 
+func (s *CommonTestSuite) TestHumanizeDuration(c *check.C) {
+	cases := []struct {
+		duration time.Duration
+		human    string
+	}{
+		{-time.Second, "0s"},
+		{42 * time.Second, "42s"},
+		{3*time.Minute + 5*time.Second, "3m5s"},
+		{2*time.Hour + 30*time.Minute, "2h30m"},
+		{76 * time.Hour, "3d4h"},
+		{400 * 24 * time.Hour, "1y35d"},
+	}
+
+	for _, tc := range cases {
+		c.Assert(humanizeDuration(tc.duration), check.Equals, tc.human)
+
+		parsed, ok := parseHumanDuration(tc.human)
+		c.Assert(ok, check.Equals, true)
+		c.Assert(humanizeDuration(parsed), check.Equals, tc.human)
+	}
+
+	_, ok := parseHumanDuration("active")
+	c.Assert(ok, check.Equals, false)
+}
+
+func (s *CommonTestSuite) TestPromptDeletion(c *check.C) {
+	cases := []struct {
+		input     string
+		confirmed bool
+	}{
+		{"yes\n", true},
+		{"Y\n", true},
+		{"no\n", false},
+		{"maybe\ny\n", true},
+		{"", false},
+	}
+
+	for _, tc := range cases {
+		out := &bytes.Buffer{}
+		confirmed := promptDeletion(strings.NewReader(tc.input), out, "apps", []string{"app1 (p-1:app1)"})
+		c.Assert(confirmed, check.Equals, tc.confirmed)
+		c.Assert(strings.HasPrefix(out.String(), "The following apps will be deleted:\n  app1 (p-1:app1)\n"), check.Equals, true)
+	}
+}
+
 func testParse(c *check.C, testID, expectedCluster, expectedProject string, errorExpected bool) {
 	actualCluster, actualProject, actualErr := parseClusterAndProjectID(testID)
 	c.Assert(actualCluster, check.Equals, expectedCluster)
@@ -69,3 +119,14 @@ func testParse(c *check.C, testID, expectedCluster, expectedProject string, erro
 		c.Assert(actualErr, check.IsNil)
 	}
 }
+
+func (s *CommonTestSuite) TestIsConfigFile(c *check.C) {
+	dir := c.MkDir()
+	file := filepath.Join(dir, "ci.conf")
+	c.Assert(os.WriteFile(file, []byte("{}"), 0600), check.IsNil)
+
+	c.Assert(isConfigFile(dir), check.Equals, false)
+	c.Assert(isConfigFile(file), check.Equals, true)
+	c.Assert(isConfigFile(filepath.Join(dir, "missing.json")), check.Equals, true)
+	c.Assert(isConfigFile(filepath.Join(dir, "missing")), check.Equals, false)
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rancher/cli/config"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+// ConfigCommand defines the 'rancher config' sub-commands
+func ConfigCommand() cli.Command {
+	return cli.Command{
+		Name:  "config",
+		Usage: "Export and import the local config",
+		Description: `Share server URLs, CA certs and default contexts with teammates.
+
+Example:
+	# Export the config without any credentials
+	$ rancher config export --redact-tokens > team.json
+
+	# Import it on another machine, then log in to each server
+	$ rancher config import team.json
+`,
+		Subcommands: []cli.Command{
+			{
+				Name:  "export",
+				Usage: "Print the local config as JSON",
+				Action: func(ctx *cli.Context) error {
+					cf, err := loadConfig(ctx)
+					if err != nil {
+						return err
+					}
+					return configExport(ctx.App.Writer, cf, ctx.Bool("redact-tokens"))
+				},
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "redact-tokens",
+						Usage: "Leave out API keys, tokens and cached kubeconfigs",
+					},
+				},
+			},
+			{
+				Name:      "import",
+				Usage:     "Merge an exported config into the local config",
+				ArgsUsage: "[FILE]",
+				Description: `
+Servers in the file are added to the local config. Servers that already exist
+keep their credentials unless the file contains new ones, or unless their URL or
+CA certs change, in which case you need to log in to them again. Token sources
+are only imported with --allow-token-sources, as a command: source runs a
+command whenever the server is used. Use '-' to read the file from stdin.
+`,
+				Action: configImportAction,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "allow-token-sources",
+						Usage: "Import the token sources of the servers too. A command: token source runs a command on this machine, only use with files you trust",
+					},
+				},
+			},
+		},
+	}
+}
+
+func configImportAction(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return showSubcommandUsage(ctx)
+	}
+
+	var content []byte
+	var err error
+	if name := ctx.Args().First(); name == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return err
+	}
+
+	var imported config.Config
+	if err := json.Unmarshal(content, &imported); err != nil {
+		return fmt.Errorf("unmarshaling %s: %w", ctx.Args().First(), err)
+	}
+
+	var count int
+	err = updateConfig(ctx, func(cf *config.Config) error {
+		count = configImport(cf, imported, ctx.Bool("allow-token-sources"))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	logrus.Infof("Imported %d server(s)", count)
+	return nil
+}
+
+// configExport writes the config as indented JSON, stripping credentials when
+// redact is set.
+func configExport(out io.Writer, cf config.Config, redact bool) error {
+	exported := config.Config{
+		Servers:       make(map[string]*config.ServerConfig, len(cf.Servers)),
+		CurrentServer: cf.CurrentServer,
+	}
+	for name, server := range cf.Servers {
+		if server == nil {
+			continue
+		}
+		s := *server
+		if redact {
+			s.AccessKey = ""
+			s.SecretKey = ""
+			s.TokenKey = ""
+			s.KubeCredentials = nil
+			s.KubeConfigs = nil
+		}
+		exported.Servers[name] = &s
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(exported)
+}
+
+// configImport merges the imported servers into cf and returns how many were
+// imported. Existing credentials are kept unless the import carries a token,
+// and are dropped when the URL or the CA certs of the server change so that
+// they are never sent to another host. Token sources are only imported when
+// allowTokenSources is set.
+func configImport(cf *config.Config, imported config.Config, allowTokenSources bool) int {
+	if cf.Servers == nil {
+		cf.Servers = make(map[string]*config.ServerConfig)
+	}
+
+	count := 0
+	for name, server := range imported.Servers {
+		if server == nil {
+			continue
+		}
+		count++
+
+		tokenSource := ""
+		if server.TokenSource != "" {
+			if allowTokenSources {
+				tokenSource = server.TokenSource
+				logrus.Warnf("Importing the token source %s of server %s", tokenSource, name)
+			} else {
+				logrus.Warnf("Not importing the token source of server %s, use --allow-token-sources to import it", name)
+			}
+		}
+
+		existing, ok := cf.Servers[name]
+		if !ok || existing == nil {
+			s := *server
+			s.TokenSource = tokenSource
+			cf.Servers[name] = &s
+			continue
+		}
+
+		if (server.URL != "" && server.URL != existing.URL) || (server.CACerts != "" && server.CACerts != existing.CACerts) {
+			if server.TokenKey == "" && (existing.TokenKey != "" || existing.TokenSource != "") {
+				logrus.Warnf("Server %s moved to another host or CA, its credentials were removed, log in to it again", name)
+			}
+			clearCredentials(existing)
+		}
+		if server.URL != "" {
+			existing.URL = server.URL
+		}
+		if server.CACerts != "" {
+			existing.CACerts = server.CACerts
+		}
+		if server.Project != "" {
+			existing.Project = server.Project
+		}
+		if server.TokenKey != "" {
+			clearCredentials(existing)
+			existing.AccessKey = server.AccessKey
+			existing.SecretKey = server.SecretKey
+			existing.TokenKey = server.TokenKey
+		}
+		if tokenSource != "" {
+			clearCredentials(existing)
+			existing.TokenSource = tokenSource
+		}
+	}
+
+	if _, ok := cf.Servers[cf.CurrentServer]; !ok || cf.CurrentServer == "" {
+		if _, ok := cf.Servers[imported.CurrentServer]; ok {
+			cf.CurrentServer = imported.CurrentServer
+		}
+	}
+	return count
+}
+
+// clearCredentials removes the keys of a server and everything derived from
+// them.
+func clearCredentials(server *config.ServerConfig) {
+	server.AccessKey = ""
+	server.SecretKey = ""
+	server.TokenKey = ""
+	server.TokenSource = ""
+	server.KubeCredentials = nil
+	server.KubeConfigs = nil
+}
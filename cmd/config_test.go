@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/rancher/cli/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestConfigExportRedactTokens(t *testing.T) {
+	cf := config.Config{
+		Path:          "/home/user/.rancher/cli2.json",
+		CurrentServer: "prod",
+		Servers: map[string]*config.ServerConfig{
+			"prod": {
+				AccessKey: "token-abc",
+				SecretKey: "secret",
+				TokenKey:  "token-abc:secret",
+				URL:       "https://rancher.example.com",
+				Project:   "c-1:p-1",
+				CACerts:   "cert",
+			},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, configExport(out, cf, true))
+
+	var exported config.Config
+	require.NoError(t, json.Unmarshal(out.Bytes(), &exported))
+	assert.Equal(t, "prod", exported.CurrentServer)
+	assert.Empty(t, exported.Path)
+	assert.Equal(t, &config.ServerConfig{
+		URL:     "https://rancher.example.com",
+		Project: "c-1:p-1",
+		CACerts: "cert",
+	}, exported.Servers["prod"])
+	assert.NotContains(t, out.String(), "token-abc")
+	// the source config must be left untouched
+	assert.Equal(t, "token-abc:secret", cf.Servers["prod"].TokenKey)
+}
+
+func TestConfigImport(t *testing.T) {
+	cf := config.Config{
+		Servers: map[string]*config.ServerConfig{
+			"prod": {
+				TokenKey: "token-abc:secret",
+				URL:      "https://rancher.example.com",
+			},
+			"dev": {
+				TokenKey:    "token-def:secret",
+				TokenSource: "env:RANCHER_DEV_TOKEN",
+				URL:         "https://old.example.com",
+				KubeConfigs: map[string]*api.Config{"c-1": {}},
+			},
+		},
+	}
+	imported := config.Config{
+		CurrentServer: "staging",
+		Servers: map[string]*config.ServerConfig{
+			"prod":    {URL: "https://rancher.example.com", Project: "c-1:p-1"},
+			"dev":     {URL: "https://dev.example.com"},
+			"staging": {URL: "https://staging.example.com", TokenSource: "command:cat token"},
+		},
+	}
+
+	count := configImport(&cf, imported, false)
+
+	assert.Equal(t, 3, count)
+	assert.Equal(t, "staging", cf.CurrentServer)
+	assert.Equal(t, &config.ServerConfig{
+		TokenKey: "token-abc:secret",
+		URL:      "https://rancher.example.com",
+		Project:  "c-1:p-1",
+	}, cf.Servers["prod"])
+	// the credentials of the old host must not be sent to the new one
+	assert.Equal(t, &config.ServerConfig{
+		URL: "https://dev.example.com",
+	}, cf.Servers["dev"])
+	assert.Equal(t, &config.ServerConfig{
+		URL: "https://staging.example.com",
+	}, cf.Servers["staging"])
+}
+
+func TestConfigImportAllowTokenSources(t *testing.T) {
+	cf := config.Config{
+		Servers: map[string]*config.ServerConfig{
+			"prod": {
+				TokenKey: "token-abc:secret",
+				URL:      "https://rancher.example.com",
+			},
+		},
+	}
+	imported := config.Config{
+		Servers: map[string]*config.ServerConfig{
+			"prod":    {URL: "https://rancher.example.com", TokenSource: "vault:secret/rancher"},
+			"staging": {URL: "https://staging.example.com", TokenSource: "command:pass show rancher"},
+		},
+	}
+
+	configImport(&cf, imported, true)
+
+	assert.Equal(t, &config.ServerConfig{
+		URL:         "https://rancher.example.com",
+		TokenSource: "vault:secret/rancher",
+	}, cf.Servers["prod"])
+	assert.Equal(t, "command:pass show rancher", cf.Servers["staging"].TokenSource)
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/rancher/cli/cliclient"
+	"github.com/rancher/cli/config"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 )
@@ -39,7 +40,7 @@ func contextSwitch(ctx *cli.Context) error {
 		return err
 	}
 
-	server, err := cf.FocusedServer()
+	server, err := focusedServerConfig(ctx, cf)
 	if err != nil {
 		return err
 	}
@@ -71,9 +72,14 @@ func contextSwitch(ctx *cli.Context) error {
 
 	logrus.Infof("Setting new context to project %s", project.Name)
 
-	server.Project = project.ID
-
-	err = cf.Write()
+	err = updateConfig(ctx, func(cf *config.Config) error {
+		server, err := focusedServerConfig(ctx, *cf)
+		if err != nil {
+			return err
+		}
+		server.Project = project.ID
+		return nil
+	})
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	redacted = "REDACTED"
+	// maxLoggedBody limits how much of a body is logged with --debug-http-bodies
+	maxLoggedBody = 4096
+)
+
+// debugTransport logs each API request with its status and duration.
+// The secrets of the logged bodies are masked with the patterns of masking.
+type debugTransport struct {
+	next    http.RoundTripper
+	bodies  bool
+	masking secretMasking
+	now     func() time.Time
+}
+
+func newDebugTransport(next http.RoundTripper, bodies bool, masking secretMasking) http.RoundTripper {
+	return &debugTransport{
+		next:    next,
+		bodies:  bodies,
+		masking: masking,
+		now:     time.Now,
+	}
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.bodies && req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		logrus.Infof("HTTP %s %s request body: %s", req.Method, req.URL, redactBody(body, t.masking))
+	}
+
+	start := t.now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := t.now().Sub(start).Round(time.Millisecond)
+	if err != nil {
+		logrus.Infof("HTTP %s %s failed after %s: %v", req.Method, req.URL, elapsed, err)
+		return resp, err
+	}
+	logrus.Infof("HTTP %s %s %d in %s", req.Method, req.URL, resp.StatusCode, elapsed)
+
+	if t.bodies && resp.Body != nil {
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return resp, err
+		}
+		logrus.Infof("HTTP %s %s response body: %s", req.Method, req.URL, redactBody(body, t.masking))
+	}
+	return resp, nil
+}
+
+// redactBody hides the values of the secret fields of a JSON body and
+// truncates long bodies.
+func redactBody(body []byte, masking secretMasking) string {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err == nil {
+		if content, err := json.Marshal(masking.redact(data)); err == nil {
+			body = content
+		}
+	}
+	if len(body) > maxLoggedBody {
+		return string(body[:maxLoggedBody]) + "..."
+	}
+	return string(body)
+}
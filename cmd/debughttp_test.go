@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactBody(t *testing.T) {
+	tt := []struct {
+		name     string
+		body     string
+		patterns string
+		expected string
+	}{
+		{
+			name:     "secrets are redacted",
+			body:     `{"name":"admin","password":"hunter2","token":"abc","nested":[{"secretKey":"s"}]}`,
+			expected: `{"name":"admin","nested":[{"secretKey":"REDACTED"}],"password":"REDACTED","token":"REDACTED"}`,
+		},
+		{
+			name:     "non string values are walked",
+			body:     `{"keys":{"accessKey":"a"},"enabled":true}`,
+			expected: `{"enabled":true,"keys":{"accessKey":"REDACTED"}}`,
+		},
+		{
+			name:     "custom patterns",
+			body:     `{"dsn":"postgres://u:p@db","password":"hunter2"}`,
+			patterns: "dsn",
+			expected: `{"dsn":"REDACTED","password":"hunter2"}`,
+		},
+		{
+			name:     "non json bodies are kept",
+			body:     "plain text",
+			expected: "plain text",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			patterns := tc.patterns
+			if patterns == "" {
+				patterns = DefaultSecretPatterns
+			}
+			// secrets are never logged, even when they are shown
+			masking := newSecretMasking(true, patterns)
+			assert.Equal(t, tc.expected, redactBody([]byte(tc.body), masking))
+		})
+	}
+}
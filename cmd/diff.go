@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/urfave/cli"
+)
+
+// ignoredDiffKeys are top level fields that are never compared.
+var ignoredDiffKeys = []string{"actions", "links"}
+
+func DiffCommand() cli.Command {
+	return cli.Command{
+		Name:  "diff",
+		Usage: "Show differences between a local resource file and the live resource",
+		Description: `
+Compares a resource described in a JSON or YAML file, as written by 'rancher inspect', with
+the resource on the server. The file must contain the 'type' and the 'id' or 'name' of the
+resource. Only the fields present in the file are compared, fields set by the server are ignored.
+
+Lines starting with '~' show changed values, '+' fields only in the file and '-' fields only
+in the live resource.
+
+Examples:
+	# Compare a cluster with a local copy
+	$ rancher inspect --type cluster --format yaml mycluster > cluster.yaml
+	$ rancher diff -f cluster.yaml
+`,
+		ArgsUsage: "None",
+		Action:    diffResource,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "file,f",
+				Usage: "The location of the resource file",
+			},
+		},
+	}
+}
+
+func diffResource(ctx *cli.Context) error {
+	filePath := ctx.String("file")
+	if filePath == "" {
+		return showCommandUsage(ctx, "diff")
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	local := map[string]interface{}{}
+	if err := yaml.Unmarshal(content, &local); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+
+	resourceType, _ := local["type"].(string)
+	if resourceType == "" {
+		return NewUsageError(errors.New("the resource file must contain a 'type'"))
+	}
+	name, _ := local["id"].(string)
+	if name == "" {
+		name, _ = local["name"].(string)
+	}
+	if name == "" {
+		return NewUsageError(errors.New("the resource file must contain an 'id' or a 'name'"))
+	}
+
+	c, err := GetClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	resource, err := Lookup(c, name, resourceType)
+	if err != nil {
+		return err
+	}
+
+	live := map[string]interface{}{}
+	if err := c.ByID(resource, &live); err != nil {
+		return err
+	}
+
+	for _, key := range ignoredDiffKeys {
+		delete(local, key)
+	}
+
+	return printDiff(os.Stdout, diffMaps("", live, local, true))
+}
+
+// resourceDiff is a single difference between the live and the local value
+// of a field.
+type resourceDiff struct {
+	Path  string
+	Live  interface{}
+	Local interface{}
+	// Op is '~' for a changed value, '+' for a field only set locally and '-'
+	// for a field only set on the server.
+	Op string
+}
+
+// diffMaps compares the fields of local with live. Fields only set on the
+// server are reported except at the top level, where they are expected.
+func diffMaps(prefix string, live, local map[string]interface{}, topLevel bool) []resourceDiff {
+	var diffs []resourceDiff
+	for key, localValue := range local {
+		path := joinPath(prefix, key)
+		liveValue, ok := live[key]
+		if !ok || liveValue == nil {
+			if localValue != nil {
+				diffs = append(diffs, resourceDiff{Path: path, Local: localValue, Op: "+"})
+			}
+			continue
+		}
+
+		liveMap, liveIsMap := liveValue.(map[string]interface{})
+		localMap, localIsMap := localValue.(map[string]interface{})
+		if liveIsMap && localIsMap {
+			diffs = append(diffs, diffMaps(path, liveMap, localMap, false)...)
+			continue
+		}
+
+		if !reflect.DeepEqual(normalizeValue(liveValue), normalizeValue(localValue)) {
+			diffs = append(diffs, resourceDiff{Path: path, Live: liveValue, Local: localValue, Op: "~"})
+		}
+	}
+
+	if !topLevel {
+		for key, liveValue := range live {
+			if _, ok := local[key]; !ok && liveValue != nil {
+				diffs = append(diffs, resourceDiff{Path: joinPath(prefix, key), Live: liveValue, Op: "-"})
+			}
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Path < diffs[j].Path
+	})
+	return diffs
+}
+
+// normalizeValue round trips v through JSON so that numbers decoded from YAML
+// and JSON compare equal.
+func normalizeValue(v interface{}) interface{} {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(content, &normalized); err != nil {
+		return v
+	}
+	return normalized
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func printDiff(out io.Writer, diffs []resourceDiff) error {
+	if len(diffs) == 0 {
+		_, err := fmt.Fprintln(out, "No differences found")
+		return err
+	}
+
+	for _, d := range diffs {
+		var line string
+		live, local := answerMasking.diffValue(d.Path, d.Live), answerMasking.diffValue(d.Path, d.Local)
+		switch d.Op {
+		case "+":
+			line = fmt.Sprintf("+ %s: %s", d.Path, diffValue(local))
+		case "-":
+			line = fmt.Sprintf("- %s: %s", d.Path, diffValue(live))
+		default:
+			line = fmt.Sprintf("~ %s: %s -> %s", d.Path, diffValue(live), diffValue(local))
+		}
+		if _, err := fmt.Fprintln(out, strings.TrimSpace(line)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func diffValue(v interface{}) string {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(content)
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffMaps(t *testing.T) {
+	live := map[string]interface{}{
+		"id":          "c-1",
+		"name":        "prod",
+		"description": "old",
+		"nodeCount":   float64(3),
+		"created":     "2024-01-01T00:00:00Z",
+		"labels": map[string]interface{}{
+			"env":  "prod",
+			"team": "a",
+		},
+	}
+	local := map[string]interface{}{
+		"id":          "c-1",
+		"name":        "prod",
+		"description": "new",
+		"nodeCount":   3,
+		"labels": map[string]interface{}{
+			"env":  "prod",
+			"tier": "web",
+		},
+	}
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, printDiff(out, diffMaps("", live, local, true)))
+	assert.Equal(t, `~ description: "old" -> "new"
+- labels.team: "a"
++ labels.tier: "web"
+`, out.String())
+}
+
+func TestDiffMapsNoDifferences(t *testing.T) {
+	resource := map[string]interface{}{"id": "c-1", "name": "prod"}
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, printDiff(out, diffMaps("", resource, resource, true)))
+	assert.Equal(t, "No differences found\n", out.String())
+}
@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rancher/cli/cliclient"
+	projectClient "github.com/rancher/rancher/pkg/client/generated/project/v3"
+	"github.com/urfave/cli"
+)
+
+const dnsRecordCreateDescription = `
+Creates a service discovery record in a namespace of the current project. A record resolves to
+one of:
+
+	--ip          external IP addresses
+	--hostname    an external host name, as a CNAME
+	--alias       other records of the project
+	--workload    the pods of workloads of the project
+	--selector    the pods matching labels
+
+Example:
+	$ rancher dnsrecord create --namespace team-a --ip 10.0.0.10 --ip 10.0.0.11 legacy-db
+	$ rancher dnsrecord create --namespace team-a --hostname db.example.com database
+	$ rancher dnsrecord create --namespace team-a --workload web frontend
+	$ rancher dnsrecord create --namespace team-a --selector app=api api
+`
+
+type DNSRecordData struct {
+	ID        string
+	DNSRecord projectClient.DNSRecord
+	Type      string
+	Target    string
+}
+
+func DNSRecordCommand() cli.Command {
+	return cli.Command{
+		Name:    "dnsrecords",
+		Aliases: []string{"dnsrecord"},
+		Usage:   "Operations on the service discovery records of a project",
+		Action:  defaultAction(dnsRecordLs),
+		Flags: []cli.Flag{
+			quietFlag,
+		},
+		Subcommands: []cli.Command{
+			{
+				Name:        "ls",
+				Usage:       "List the service discovery records of the current project",
+				Description: "\nLists the service discovery records of the current project with what they resolve to.",
+				ArgsUsage:   "None",
+				Action:      dnsRecordLs,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "namespace, n",
+						Usage: "List the records of a namespace only",
+					},
+					formatFlag,
+					quietFlag,
+					filterFlag,
+					labelSelectorFlag,
+					sortByFlag,
+					noHeadersFlag,
+					limitFlag,
+					pageSizeFlag,
+				},
+			},
+			{
+				Name:        "create",
+				Usage:       "Create a service discovery record",
+				Description: dnsRecordCreateDescription,
+				ArgsUsage:   "NAME",
+				Action:      dnsRecordCreate,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "namespace, n",
+						Usage: "Namespace of the record",
+					},
+					cli.StringSliceFlag{
+						Name:  "ip",
+						Usage: "External IP address the record resolves to, can be used multiple times",
+					},
+					cli.StringFlag{
+						Name:  "hostname",
+						Usage: "External host name the record is a CNAME of",
+					},
+					cli.StringSliceFlag{
+						Name:  "alias",
+						Usage: "Record of the project the record is an alias of, by name or ID, can be used multiple times",
+					},
+					cli.StringSliceFlag{
+						Name:  "workload",
+						Usage: "Workload whose pods the record resolves to, by name or ID, can be used multiple times",
+					},
+					cli.StringSliceFlag{
+						Name:  "selector",
+						Usage: "Label KEY=VALUE of the pods the record resolves to, can be used multiple times",
+					},
+				},
+			},
+			{
+				Name:      "delete",
+				Aliases:   []string{"rm"},
+				Usage:     "Delete service discovery records by name or ID",
+				ArgsUsage: "[RECORDNAME/RECORDID...]",
+				Action:    dnsRecordDelete,
+				Flags:     deleteFlags,
+			},
+		},
+	}
+}
+
+func dnsRecordLs(ctx *cli.Context) error {
+	c, err := GetClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	opts := filteredListOpts(ctx)
+	if namespace := ctx.String("namespace"); namespace != "" {
+		opts.Filters["namespaceId"] = namespace
+	}
+	collection, err := c.ProjectClient.DNSRecord.List(opts)
+	if err != nil {
+		return err
+	}
+	collection.Data, err = listAll(ctx, collection, func(c *projectClient.DNSRecordCollection) []projectClient.DNSRecord { return c.Data })
+	if err != nil {
+		return err
+	}
+
+	writer := NewTableWriter([][]string{
+		{"ID", "ID"},
+		{"NAMESPACE", "DNSRecord.NamespaceId"},
+		{"NAME", "DNSRecord.Name"},
+		{"TYPE", "Type"},
+		{"TARGET", "Target"},
+		{"STATE", "DNSRecord.State"},
+	}, ctx)
+
+	defer writer.Close()
+
+	for _, record := range collection.Data {
+		recordType, target := dnsRecordTarget(&record)
+		writer.Write(&DNSRecordData{
+			ID:        record.ID,
+			DNSRecord: record,
+			Type:      recordType,
+			Target:    target,
+		})
+	}
+	return writer.Err()
+}
+
+func dnsRecordCreate(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+	if ctx.String("namespace") == "" {
+		return NewUsageError(errors.New("--namespace is required"))
+	}
+
+	targets := 0
+	for _, set := range []bool{
+		len(ctx.StringSlice("ip")) > 0,
+		ctx.String("hostname") != "",
+		len(ctx.StringSlice("alias")) > 0,
+		len(ctx.StringSlice("workload")) > 0,
+		len(ctx.StringSlice("selector")) > 0,
+	} {
+		if set {
+			targets++
+		}
+	}
+	if targets != 1 {
+		return NewUsageError(errors.New("give what the record resolves to with one of --ip, --hostname, --alias, --workload or --selector"))
+	}
+	selector, err := parseKeyValues("selector", ctx.StringSlice("selector"))
+	if err != nil {
+		return err
+	}
+
+	c, err := GetClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	record := &projectClient.DNSRecord{
+		Name:        ctx.Args().First(),
+		NamespaceId: ctx.String("namespace"),
+		IPAddresses: ctx.StringSlice("ip"),
+		Hostname:    ctx.String("hostname"),
+		Selector:    selector,
+	}
+	if record.TargetDNSRecordIDs, err = lookupIDs(c, ctx.StringSlice("alias"), projectClient.DNSRecordType); err != nil {
+		return err
+	}
+	if record.TargetWorkloadIDs, err = lookupIDs(c, ctx.StringSlice("workload"), projectClient.WorkloadType); err != nil {
+		return err
+	}
+
+	if err := checkPermissions(ctx, projectPermission(c, projectClient.DNSRecordType, verbCreate)); err != nil {
+		return err
+	}
+	created, err := c.ProjectClient.DNSRecord.Create(record)
+	if err != nil {
+		return err
+	}
+	fmt.Println(created.ID)
+	return nil
+}
+
+func dnsRecordDelete(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+
+	c, err := GetClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return bulkDelete{
+		kind: "DNS records",
+		resolve: func(arg string) (*bulkTarget, error) {
+			resource, err := Lookup(c, arg, projectClient.DNSRecordType)
+			if err != nil {
+				return nil, err
+			}
+
+			record, err := c.ProjectClient.DNSRecord.ByID(resource.ID)
+			if err != nil {
+				return nil, err
+			}
+			return &bulkTarget{
+				resource:     record.Resource,
+				descriptions: []string{record.NamespaceId + "/" + record.Name},
+				delete: func() error {
+					return c.ProjectClient.DNSRecord.Delete(record)
+				},
+			}, nil
+		},
+	}.run(ctx, c)
+}
+
+// lookupIDs returns the IDs of the resources of schemaType named by names
+func lookupIDs(c *cliclient.MasterClient, names []string, schemaType string) ([]string, error) {
+	var ids []string
+	for _, name := range names {
+		resource, err := Lookup(c, name, schemaType)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, resource.ID)
+	}
+	return ids, nil
+}
+
+// dnsRecordTarget returns the type of a record, from what it resolves to, and
+// its targets.
+func dnsRecordTarget(record *projectClient.DNSRecord) (string, string) {
+	switch {
+	case len(record.IPAddresses) > 0:
+		return "ip", strings.Join(record.IPAddresses, ",")
+	case record.Hostname != "":
+		return "cname", record.Hostname
+	case len(record.TargetDNSRecordIDs) > 0:
+		return "alias", strings.Join(record.TargetDNSRecordIDs, ",")
+	case len(record.TargetWorkloadIDs) > 0:
+		return "workload", strings.Join(record.TargetWorkloadIDs, ",")
+	case len(record.Selector) > 0:
+		var labels []string
+		for key, value := range record.Selector {
+			labels = append(labels, key+"="+value)
+		}
+		sort.Strings(labels)
+		return "selector", strings.Join(labels, ",")
+	}
+	return "", ""
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+
+	projectClient "github.com/rancher/rancher/pkg/client/generated/project/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDNSRecordTarget(t *testing.T) {
+	tests := []struct {
+		record     projectClient.DNSRecord
+		recordType string
+		target     string
+	}{
+		{projectClient.DNSRecord{IPAddresses: []string{"10.0.0.10", "10.0.0.11"}}, "ip", "10.0.0.10,10.0.0.11"},
+		{projectClient.DNSRecord{Hostname: "db.example.com"}, "cname", "db.example.com"},
+		{projectClient.DNSRecord{TargetDNSRecordIDs: []string{"team-a:database"}}, "alias", "team-a:database"},
+		{projectClient.DNSRecord{TargetWorkloadIDs: []string{"deployment:team-a:web"}}, "workload", "deployment:team-a:web"},
+		{projectClient.DNSRecord{Selector: map[string]string{"tier": "api", "app": "shop"}}, "selector", "app=shop,tier=api"},
+		{projectClient.DNSRecord{}, "", ""},
+	}
+	for _, tt := range tests {
+		recordType, target := dnsRecordTarget(&tt.record)
+		assert.Equal(t, tt.recordType, recordType)
+		assert.Equal(t, tt.target, target)
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// dryRunTransport prints mutating API requests instead of sending them.
+// Reads are passed through so that names can still be resolved to IDs.
+type dryRunTransport struct {
+	next   http.RoundTripper
+	out    io.Writer
+	format string
+}
+
+func newDryRunTransport(next http.RoundTripper, out io.Writer, format string) http.RoundTripper {
+	return &dryRunTransport{
+		next:   next,
+		out:    out,
+		format: format,
+	}
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return t.next.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+
+	if err := t.print(req, body); err != nil {
+		return nil, err
+	}
+
+	// Echo the payload back as if the server accepted it, actions and deletes
+	// get an empty response as their output doesn't match the request.
+	response := body
+	status := http.StatusOK
+	if req.Method == http.MethodDelete || req.URL.Query().Get("action") != "" {
+		response = nil
+		status = http.StatusNoContent
+	}
+
+	return &http.Response{
+		Status:        http.StatusText(status),
+		StatusCode:    status,
+		Proto:         req.Proto,
+		ProtoMajor:    req.ProtoMajor,
+		ProtoMinor:    req.ProtoMinor,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(response)),
+		ContentLength: int64(len(response)),
+		Request:       req,
+	}, nil
+}
+
+func (t *dryRunTransport) print(req *http.Request, body []byte) error {
+	fmt.Fprintf(t.out, "# %s %s\n", req.Method, req.URL)
+	if len(body) == 0 {
+		return nil
+	}
+
+	// secret answers are masked, the request is sent unchanged
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err == nil {
+		if masked, err := json.Marshal(answerMasking.body(data)); err == nil {
+			body = masked
+		}
+	}
+
+	var content []byte
+	var err error
+	if strings.EqualFold(t.format, "yaml") {
+		content, err = yaml.JSONToYAML(body)
+	} else {
+		indented := &bytes.Buffer{}
+		err = json.Indent(indented, body, "", "  ")
+		content = append(indented.Bytes(), '\n')
+	}
+	if err != nil {
+		return err
+	}
+	_, err = t.out.Write(content)
+	return err
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestDryRunTransport(t *testing.T) {
+	var sent []string
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		sent = append(sent, req.Method)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+	})
+
+	tt := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		format         string
+		expectedOutput string
+		expectedBody   string
+		expectedSent   []string
+	}{
+		{
+			name:         "reads are sent",
+			method:       http.MethodGet,
+			url:          "https://rancher/v3/clusters",
+			expectedBody: "{}",
+			expectedSent: []string{http.MethodGet},
+		},
+		{
+			name:           "create is printed as json",
+			method:         http.MethodPost,
+			url:            "https://rancher/v3/multiclusterapps",
+			body:           `{"name":"app"}`,
+			expectedOutput: "# POST https://rancher/v3/multiclusterapps\n{\n  \"name\": \"app\"\n}\n",
+			expectedBody:   `{"name":"app"}`,
+		},
+		{
+			name:           "update is printed as yaml",
+			method:         http.MethodPut,
+			url:            "https://rancher/v3/multiclusterapps/app",
+			body:           `{"name":"app"}`,
+			format:         "yaml",
+			expectedOutput: "# PUT https://rancher/v3/multiclusterapps/app\nname: app\n",
+			expectedBody:   `{"name":"app"}`,
+		},
+		{
+			name:           "actions get an empty response",
+			method:         http.MethodPost,
+			url:            "https://rancher/v3/catalogs/library?action=refresh",
+			body:           `{}`,
+			expectedOutput: "# POST https://rancher/v3/catalogs/library?action=refresh\n{}\n",
+		},
+		{
+			name:           "delete",
+			method:         http.MethodDelete,
+			url:            "https://rancher/v3/clusters/c-1",
+			expectedOutput: "# DELETE https://rancher/v3/clusters/c-1\n",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			sent = nil
+			out := &bytes.Buffer{}
+			transport := newDryRunTransport(next, out, tc.format)
+
+			var body io.Reader
+			if tc.body != "" {
+				body = strings.NewReader(tc.body)
+			}
+			req, err := http.NewRequest(tc.method, tc.url, body)
+			assert.NoError(t, err)
+
+			resp, err := transport.RoundTrip(req)
+			assert.NoError(t, err)
+			content, err := io.ReadAll(resp.Body)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.expectedOutput, out.String())
+			assert.Equal(t, tc.expectedBody, string(content))
+			assert.Equal(t, tc.expectedSent, sent)
+		})
+	}
+}
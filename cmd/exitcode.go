@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/rancher/cli/config"
+	"github.com/rancher/norman/clientbase"
+	"github.com/urfave/cli"
+)
+
+// Exit codes of the CLI, they allow scripts to branch on the kind of failure
+// instead of parsing error messages.
+const (
+	// ExitCodeError is used for any failure not covered below
+	ExitCodeError = 1
+	// ExitCodeUsage is used for invalid flags or arguments
+	ExitCodeUsage = 2
+	// ExitCodeNotFound is used when a requested resource doesn't exist
+	ExitCodeNotFound = 3
+	// ExitCodeAuth is used when credentials are missing or were rejected
+	ExitCodeAuth = 4
+	// ExitCodeTimeout is used when waiting for a resource or the server timed out
+	ExitCodeTimeout = 5
+	// ExitCodeServer is used when the server failed to handle a request
+	ExitCodeServer = 6
+	// ExitCodePartial is used when an operation succeeded for only some of
+	// its targets
+	ExitCodePartial = 7
+	// ExitCodeCancelled is used when the user declined a confirmation
+	// prompt, so that nothing was changed
+	ExitCodeCancelled = 8
+	// ExitCodeInterrupted is used when the command was interrupted with
+	// Ctrl-C, like shells do for commands killed by SIGINT
+	ExitCodeInterrupted = 130
+)
+
+// codedError attaches an exit code to an error. It deliberately doesn't
+// implement cli.ExitCoder so that urfave/cli doesn't exit on its own.
+type codedError struct {
+	code int
+	err  error
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+// NewUsageError marks err as caused by invalid flags or arguments.
+func NewUsageError(err error) error {
+	return &codedError{code: ExitCodeUsage, err: err}
+}
+
+func notFoundErrorf(format string, args ...interface{}) error {
+	return &codedError{code: ExitCodeNotFound, err: fmt.Errorf(format, args...)}
+}
+
+func permissionErrorf(format string, args ...interface{}) error {
+	return &codedError{code: ExitCodeAuth, err: fmt.Errorf(format, args...)}
+}
+
+func timeoutErrorf(format string, args ...interface{}) error {
+	return &codedError{code: ExitCodeTimeout, err: fmt.Errorf(format, args...)}
+}
+
+func interruptedErrorf(format string, args ...interface{}) error {
+	return &codedError{code: ExitCodeInterrupted, err: fmt.Errorf(format, args...)}
+}
+
+// errCancelled is returned when the user declined a confirmation prompt
+var errCancelled = &codedError{code: ExitCodeCancelled, err: errors.New("cancelled, nothing was changed")}
+
+func partialErrorf(format string, args ...interface{}) error {
+	return &codedError{code: ExitCodePartial, err: fmt.Errorf(format, args...)}
+}
+
+// ExitCode returns the exit code matching err.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var coded *codedError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+
+	if errors.Is(err, config.ErrNoConfigurationFound) {
+		return ExitCodeAuth
+	}
+
+	var apiErr *clientbase.APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.StatusCode == http.StatusUnauthorized, apiErr.StatusCode == http.StatusForbidden:
+			return ExitCodeAuth
+		case apiErr.StatusCode == http.StatusNotFound:
+			return ExitCodeNotFound
+		case apiErr.StatusCode >= http.StatusInternalServerError:
+			return ExitCodeServer
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ExitCodeTimeout
+	}
+	if errors.Is(err, context.Canceled) {
+		return ExitCodeInterrupted
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ExitCodeTimeout
+	}
+
+	return ExitCodeError
+}
+
+// OnUsageError reports invalid flags like urfave/cli does by default and
+// marks the error so that the CLI exits with ExitCodeUsage.
+func OnUsageError(ctx *cli.Context, err error, _ bool) error {
+	fmt.Fprintln(ctx.App.Writer, "Incorrect Usage:", err.Error())
+	fmt.Fprintln(ctx.App.Writer)
+	if ctx.Command.Name != "" {
+		_ = cli.ShowCommandHelp(ctx, ctx.Command.Name)
+	} else {
+		_ = cli.ShowAppHelp(ctx)
+	}
+	return NewUsageError(err)
+}
+
+// errInvalidArguments is returned by commands run with missing or unexpected
+// arguments, after their help is printed.
+var errInvalidArguments = errors.New("invalid arguments, see the usage above")
+
+// showSubcommandUsage prints the help of the command and returns a usage
+// error, for commands run with missing or unexpected arguments.
+func showSubcommandUsage(ctx *cli.Context) error {
+	if err := cli.ShowSubcommandHelp(ctx); err != nil {
+		return err
+	}
+	return NewUsageError(errInvalidArguments)
+}
+
+// showCommandUsage is showSubcommandUsage for the top level command named
+// command.
+func showCommandUsage(ctx *cli.Context, command string) error {
+	if err := cli.ShowCommandHelp(ctx, command); err != nil {
+		return err
+	}
+	return NewUsageError(errInvalidArguments)
+}
+
+// SetUsageErrorHandler installs OnUsageError on commands and all of their
+// subcommands.
+func SetUsageErrorHandler(commands []cli.Command) {
+	for i := range commands {
+		commands[i].OnUsageError = OnUsageError
+		SetUsageErrorHandler(commands[i].Subcommands)
+	}
+}
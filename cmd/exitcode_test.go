@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/rancher/cli/config"
+	"github.com/rancher/norman/clientbase"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli"
+)
+
+func TestExitCode(t *testing.T) {
+	tt := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "no error", err: nil, expected: 0},
+		{name: "generic error", err: errors.New("boom"), expected: ExitCodeError},
+		{name: "usage error", err: NewUsageError(errors.New("flag provided but not defined")), expected: ExitCodeUsage},
+		{name: "lookup not found", err: notFoundErrorf("Not found: %s", "foo"), expected: ExitCodeNotFound},
+		{name: "wrapped not found", err: pkgerrors.Wrap(notFoundErrorf("Not found"), "lookup"), expected: ExitCodeNotFound},
+		{name: "api not found", err: &clientbase.APIError{StatusCode: 404}, expected: ExitCodeNotFound},
+		{name: "api unauthorized", err: &clientbase.APIError{StatusCode: 401}, expected: ExitCodeAuth},
+		{name: "api forbidden", err: fmt.Errorf("listing: %w", &clientbase.APIError{StatusCode: 403}), expected: ExitCodeAuth},
+		{name: "no configuration", err: config.ErrNoConfigurationFound, expected: ExitCodeAuth},
+		{name: "timeout", err: timeoutErrorf("Timeout reached"), expected: ExitCodeTimeout},
+		{name: "partial", err: partialErrorf("1 of 3 targets failed"), expected: ExitCodePartial},
+		{name: "cancelled", err: errCancelled, expected: ExitCodeCancelled},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, expected: ExitCodeTimeout},
+		{name: "interrupted", err: interruptedErrorf("interrupted waiting"), expected: ExitCodeInterrupted},
+		{name: "missing permission", err: permissionErrorf("the current token is missing permissions"), expected: ExitCodeAuth},
+		{name: "canceled", err: fmt.Errorf("get: %w", context.Canceled), expected: ExitCodeInterrupted},
+		{name: "server error", err: &clientbase.APIError{StatusCode: 503}, expected: ExitCodeServer},
+		{name: "api conflict", err: &clientbase.APIError{StatusCode: 409}, expected: ExitCodeError},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ExitCode(tc.err))
+		})
+	}
+}
+
+func TestShowSubcommandUsage(t *testing.T) {
+	out := &bytes.Buffer{}
+	app := cli.NewApp()
+	app.Writer = out
+	app.Commands = []cli.Command{{Name: "ls", Usage: "List the things"}}
+	ctx := cli.NewContext(app, flag.NewFlagSet("ls", flag.ContinueOnError), nil)
+	ctx.Command = app.Commands[0]
+
+	err := showSubcommandUsage(ctx)
+	assert.EqualError(t, err, "invalid arguments, see the usage above")
+	assert.Equal(t, ExitCodeUsage, ExitCode(err))
+	assert.Contains(t, out.String(), "List the things")
+}
@@ -0,0 +1,561 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/rancher/cli/cliclient"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	projectClient "github.com/rancher/rancher/pkg/client/generated/project/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+const exportTerraformDescription = `
+Writes configuration for the rancher2 Terraform provider describing existing
+resources, together with import blocks (Terraform 1.5+) so that they can be
+adopted without being recreated.
+
+Clusters are exported with their node pools, catalogs and projects, and
+projects with their catalogs and apps. Without a flag every cluster, global
+catalog and multi-cluster app visible to the user is exported.
+
+Only the fields that identify a resource and the common settings are written;
+run 'terraform plan' after importing to review any remaining differences.
+Catalog credentials are never exported.
+
+Example:
+	# Export a cluster and everything in it
+	$ rancher export terraform --cluster mycluster > mycluster.tf
+`
+
+func ExportCommand() cli.Command {
+	return cli.Command{
+		Name:  "export",
+		Usage: "Export resources for use with other tools",
+		Subcommands: []cli.Command{
+			{
+				Name:        "terraform",
+				Usage:       "Write rancher2 Terraform provider configuration for existing resources",
+				Description: exportTerraformDescription,
+				ArgsUsage:   "None",
+				Action:      exportTerraform,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "cluster",
+						Usage: "Export only this cluster (name or ID) and its resources",
+					},
+					cli.StringFlag{
+						Name:  "project",
+						Usage: "Export only this project (name or ID) and its resources",
+					},
+					cli.StringFlag{
+						Name:  "mcapp",
+						Usage: "Export only this multi-cluster app (name or ID)",
+					},
+				},
+			},
+		},
+	}
+}
+
+func exportTerraform(ctx *cli.Context) error {
+	var set []string
+	for _, name := range []string{"cluster", "project", "mcapp"} {
+		if ctx.String(name) != "" {
+			set = append(set, "--"+name)
+		}
+	}
+	if len(set) > 1 {
+		return fmt.Errorf("only one of %s can be used", strings.Join(set, ", "))
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	e := newTerraformExporter(c, os.Stdout)
+
+	switch {
+	case ctx.String("cluster") != "":
+		resource, err := Lookup(c, ctx.String("cluster"), "cluster")
+		if err != nil {
+			return err
+		}
+		cluster, err := c.ManagementClient.Cluster.ByID(resource.ID)
+		if err != nil {
+			return err
+		}
+		return e.finish(e.cluster(cluster))
+	case ctx.String("project") != "":
+		resource, err := Lookup(c, ctx.String("project"), "project")
+		if err != nil {
+			return err
+		}
+		project, err := c.ManagementClient.Project.ByID(resource.ID)
+		if err != nil {
+			return err
+		}
+		return e.finish(e.project(project))
+	case ctx.String("mcapp") != "":
+		resource, err := Lookup(c, ctx.String("mcapp"), managementClient.MultiClusterAppType)
+		if err != nil {
+			return err
+		}
+		app, err := c.ManagementClient.MultiClusterApp.ByID(resource.ID)
+		if err != nil {
+			return err
+		}
+		return e.finish(e.multiClusterApp(app))
+	default:
+		return e.finish(e.all())
+	}
+}
+
+// terraformExporter writes rancher2 resources, remembering the Terraform
+// address of every cluster and project so that resources inside them can
+// reference them instead of repeating IDs.
+type terraformExporter struct {
+	c        *cliclient.MasterClient
+	w        *hclWriter
+	clusters map[string]string
+	projects map[string]string
+}
+
+func newTerraformExporter(c *cliclient.MasterClient, out io.Writer) *terraformExporter {
+	return &terraformExporter{
+		c:        c,
+		w:        newHCLWriter(out),
+		clusters: map[string]string{},
+		projects: map[string]string{},
+	}
+}
+
+// finish returns err, or the first error hit while writing the output
+func (e *terraformExporter) finish(err error) error {
+	if err != nil {
+		return err
+	}
+	return e.w.err
+}
+
+func (e *terraformExporter) all() error {
+	catalogs, err := e.c.ManagementClient.Catalog.List(defaultListOpts(nil))
+	if err != nil {
+		return err
+	}
+	catalogData, err := listAll(nil, catalogs, func(c *managementClient.CatalogCollection) []managementClient.Catalog { return c.Data })
+	if err != nil {
+		return err
+	}
+	for _, catalog := range catalogData {
+		e.catalog(catalog.ID, catalog.Name, "global", "", &catalog)
+	}
+
+	clusters, err := listAllClusters(nil, e.c.ManagementClient)
+	if err != nil {
+		return err
+	}
+	for i := range clusters {
+		if err := e.cluster(&clusters[i]); err != nil {
+			return err
+		}
+	}
+
+	apps, err := e.c.ManagementClient.MultiClusterApp.List(defaultListOpts(nil))
+	if err != nil {
+		return err
+	}
+	appData, err := listAll(nil, apps, func(c *managementClient.MultiClusterAppCollection) []managementClient.MultiClusterApp { return c.Data })
+	if err != nil {
+		return err
+	}
+	for i := range appData {
+		if err := e.multiClusterApp(&appData[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *terraformExporter) cluster(cluster *managementClient.Cluster) error {
+	// The local cluster is created by Rancher itself so it is left out, its
+	// projects refer to it by ID instead.
+	if cluster.ID != "local" {
+		address := e.w.resource("rancher2_cluster", cluster.Name, cluster.ID, func() {
+			e.w.attr("name", cluster.Name)
+			e.w.attr("description", cluster.Description)
+		})
+		e.clusters[cluster.ID] = address
+	}
+
+	filter := defaultListOpts(nil)
+	filter.Filters["clusterId"] = cluster.ID
+
+	pools, err := e.c.ManagementClient.NodePool.List(filter)
+	if err != nil {
+		return err
+	}
+	poolData, err := listAll(nil, pools, func(c *managementClient.NodePoolCollection) []managementClient.NodePool { return c.Data })
+	if err != nil {
+		return err
+	}
+	for _, pool := range poolData {
+		e.w.resource("rancher2_node_pool", cluster.Name+"_"+pool.Name, pool.ID, func() {
+			e.w.attr("cluster_id", e.clusterRef(pool.ClusterID))
+			e.w.attr("name", pool.Name)
+			e.w.attr("hostname_prefix", pool.HostnamePrefix)
+			e.w.attr("node_template_id", pool.NodeTemplateID)
+			e.w.attr("quantity", pool.Quantity)
+			e.w.attr("control_plane", pool.ControlPlane)
+			e.w.attr("etcd", pool.Etcd)
+			e.w.attr("worker", pool.Worker)
+		})
+	}
+
+	catalogs, err := e.c.ManagementClient.ClusterCatalog.List(filter)
+	if err != nil {
+		return err
+	}
+	catalogData, err := listAll(nil, catalogs, func(c *managementClient.ClusterCatalogCollection) []managementClient.ClusterCatalog { return c.Data })
+	if err != nil {
+		return err
+	}
+	for _, catalog := range catalogData {
+		e.catalog(catalog.ID, catalog.Name, "cluster", e.clusterRef(catalog.ClusterID), &managementClient.Catalog{
+			Branch:      catalog.Branch,
+			Description: catalog.Description,
+			HelmVersion: catalog.HelmVersion,
+			Kind:        catalog.Kind,
+			URL:         catalog.URL,
+			Username:    catalog.Username,
+		})
+	}
+
+	projects, err := e.c.ManagementClient.Project.List(filter)
+	if err != nil {
+		return err
+	}
+	projectData, err := listAll(nil, projects, projectPageData)
+	if err != nil {
+		return err
+	}
+	for i := range projectData {
+		if err := e.project(&projectData[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *terraformExporter) project(project *managementClient.Project) error {
+	address := e.w.resource("rancher2_project", project.Name, project.ID, func() {
+		e.w.attr("name", project.Name)
+		e.w.attr("cluster_id", e.clusterRef(project.ClusterID))
+		e.w.attr("description", project.Description)
+	})
+	e.projects[project.ID] = address
+
+	filter := defaultListOpts(nil)
+	filter.Filters["projectId"] = project.ID
+
+	catalogs, err := e.c.ManagementClient.ProjectCatalog.List(filter)
+	if err != nil {
+		return err
+	}
+	catalogData, err := listAll(nil, catalogs, func(c *managementClient.ProjectCatalogCollection) []managementClient.ProjectCatalog { return c.Data })
+	if err != nil {
+		return err
+	}
+	for _, catalog := range catalogData {
+		e.catalog(catalog.ID, catalog.Name, "project", e.projectRef(catalog.ProjectID), &managementClient.Catalog{
+			Branch:      catalog.Branch,
+			Description: catalog.Description,
+			HelmVersion: catalog.HelmVersion,
+			Kind:        catalog.Kind,
+			URL:         catalog.URL,
+			Username:    catalog.Username,
+		})
+	}
+
+	return e.apps(project)
+}
+
+// apps exports the apps of project. Apps are served by the project API, so a
+// client for that project is created on the way.
+func (e *terraformExporter) apps(project *managementClient.Project) error {
+	sc := *e.c.UserConfig
+	sc.Project = project.ID
+	pc, err := cliclient.NewProjectClient(&sc)
+	if err != nil {
+		logrus.Warnf("Skipping apps of project %s: %v", project.Name, err)
+		return nil
+	}
+
+	apps, err := pc.ProjectClient.App.List(defaultListOpts(nil))
+	if err != nil {
+		return err
+	}
+	appData, err := listAll(nil, apps, func(c *projectClient.AppCollection) []projectClient.App { return c.Data })
+	if err != nil {
+		return err
+	}
+	for _, app := range appData {
+		// Apps deployed by a multi-cluster app are managed through it
+		if app.MultiClusterAppID != "" {
+			continue
+		}
+		externalInfo, err := parseExternalID(app.ExternalID)
+		if err != nil {
+			return err
+		}
+		answers := map[string]string{}
+		for k, v := range app.Answers {
+			answers[k] = v
+		}
+		for k, v := range app.AnswersSetString {
+			answers[k] = v
+		}
+		e.w.resource("rancher2_app", project.Name+"_"+app.Name, app.ID, func() {
+			e.w.attr("name", app.Name)
+			e.w.attr("project_id", e.projectRef(app.ProjectID))
+			e.w.attr("catalog_name", externalInfo["catalog"])
+			e.w.attr("template_name", externalInfo["template"])
+			e.w.attr("template_version", externalInfo["version"])
+			e.w.attr("target_namespace", app.TargetNamespace)
+			e.w.attr("answers", answers)
+			if app.ValuesYaml != "" {
+				e.w.attr("values_yaml", hclExpr("base64encode("+hclString(app.ValuesYaml)+")"))
+			}
+		})
+	}
+	return nil
+}
+
+func (e *terraformExporter) catalog(id, name, scope string, parent interface{}, catalog *managementClient.Catalog) {
+	e.w.resource("rancher2_catalog", name, scope+"."+id, func() {
+		e.w.attr("name", name)
+		e.w.attr("scope", scope)
+		switch scope {
+		case "cluster":
+			e.w.attr("cluster_id", parent)
+		case "project":
+			e.w.attr("project_id", parent)
+		}
+		e.w.attr("url", catalog.URL)
+		e.w.attr("branch", catalog.Branch)
+		e.w.attr("kind", catalog.Kind)
+		e.w.attr("version", catalog.HelmVersion)
+		e.w.attr("description", catalog.Description)
+		e.w.attr("username", catalog.Username)
+	})
+}
+
+func (e *terraformExporter) multiClusterApp(app *managementClient.MultiClusterApp) error {
+	templateVersion, err := e.c.ManagementClient.TemplateVersion.ByID(app.TemplateVersionID)
+	if err != nil {
+		return err
+	}
+	externalInfo, err := parseExternalID(templateVersion.ExternalID)
+	if err != nil {
+		return err
+	}
+
+	e.w.resource("rancher2_multi_cluster_app", app.Name, app.ID, func() {
+		e.w.attr("name", app.Name)
+		e.w.attr("catalog_name", externalInfo["catalog"])
+		e.w.attr("template_name", externalInfo["template"])
+		e.w.attr("template_version", externalInfo["version"])
+		e.w.attr("roles", app.Roles)
+		for _, target := range app.Targets {
+			e.w.block("targets", func() {
+				e.w.attr("project_id", e.projectRef(target.ProjectID))
+			})
+		}
+		for _, answer := range app.Answers {
+			e.w.block("answers", func() {
+				e.w.attr("cluster_id", answer.ClusterID)
+				e.w.attr("project_id", answer.ProjectID)
+				values := map[string]string{}
+				for k, v := range answer.Values {
+					values[k] = v
+				}
+				for k, v := range answer.ValuesSetString {
+					values[k] = v
+				}
+				e.w.attr("values", values)
+			})
+		}
+	})
+	return nil
+}
+
+// clusterRef refers to an exported cluster by address, falling back to its ID
+func (e *terraformExporter) clusterRef(id string) interface{} {
+	if address, ok := e.clusters[id]; ok {
+		return hclExpr(address + ".id")
+	}
+	return id
+}
+
+// projectRef refers to an exported project by address, falling back to its ID
+func (e *terraformExporter) projectRef(id string) interface{} {
+	if address, ok := e.projects[id]; ok {
+		return hclExpr(address + ".id")
+	}
+	return id
+}
+
+// hclExpr is written as-is instead of as a quoted string
+type hclExpr string
+
+var invalidHCLNameChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// hclWriter writes HCL blocks, tracking the resource names used so far so
+// that every resource gets a unique, valid name.
+type hclWriter struct {
+	out    io.Writer
+	indent int
+	names  map[string]bool
+	err    error
+}
+
+func newHCLWriter(out io.Writer) *hclWriter {
+	return &hclWriter{out: out, names: map[string]bool{}}
+}
+
+func (w *hclWriter) printf(format string, args ...interface{}) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = fmt.Fprintf(w.out, strings.Repeat("  ", w.indent)+format, args...)
+}
+
+// resource writes a resource block followed by the import block adopting id,
+// and returns the address of the resource.
+func (w *hclWriter) resource(resourceType, name, id string, body func()) string {
+	address := resourceType + "." + w.name(resourceType, name)
+	w.printf("resource %q %q {\n", resourceType, strings.TrimPrefix(address, resourceType+"."))
+	w.indent++
+	body()
+	w.indent--
+	w.printf("}\n\n")
+
+	w.printf("import {\n")
+	w.indent++
+	w.attr("to", hclExpr(address))
+	w.attr("id", id)
+	w.indent--
+	w.printf("}\n\n")
+	return address
+}
+
+// name turns name into a resource name unique for resourceType
+func (w *hclWriter) name(resourceType, name string) string {
+	base := strings.Trim(invalidHCLNameChars.ReplaceAllString(name, "_"), "_")
+	if base == "" || (base[0] >= '0' && base[0] <= '9') || base[0] == '-' {
+		base = "r_" + base
+	}
+	candidate := base
+	for i := 2; w.names[resourceType+"."+candidate]; i++ {
+		candidate = base + "_" + strconv.Itoa(i)
+	}
+	w.names[resourceType+"."+candidate] = true
+	return candidate
+}
+
+func (w *hclWriter) block(name string, body func()) {
+	w.printf("%s {\n", name)
+	w.indent++
+	body()
+	w.indent--
+	w.printf("}\n")
+}
+
+// attr writes an attribute, skipping zero values so that the provider
+// defaults apply.
+func (w *hclWriter) attr(key string, value interface{}) {
+	switch v := value.(type) {
+	case hclExpr:
+		w.printf("%s = %s\n", key, v)
+	case string:
+		if v != "" {
+			w.printf("%s = %s\n", key, hclString(v))
+		}
+	case int64:
+		if v != 0 {
+			w.printf("%s = %d\n", key, v)
+		}
+	case bool:
+		if v {
+			w.printf("%s = true\n", key)
+		}
+	case []string:
+		if len(v) > 0 {
+			quoted := make([]string, len(v))
+			for i, s := range v {
+				quoted[i] = hclString(s)
+			}
+			w.printf("%s = [%s]\n", key, strings.Join(quoted, ", "))
+		}
+	case map[string]string:
+		if len(v) == 0 {
+			return
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		w.printf("%s = {\n", key)
+		w.indent++
+		for _, k := range keys {
+			w.printf("%s = %s\n", hclString(k), hclString(v[k]))
+		}
+		w.indent--
+		w.printf("}\n")
+	}
+}
+
+// hclString quotes s as an HCL string, escaping template sequences. HCL only
+// takes the \n, \r, \t, \", \\ and \u escapes, so other characters that
+// aren't printable are written as \u or \U escapes.
+func hclString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i, r := range s {
+		switch {
+		case r == '"':
+			b.WriteString(`\"`)
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case (r == '$' || r == '%') && strings.HasPrefix(s[i+1:], "{"):
+			// doubled so it isn't read as an interpolation or a directive
+			b.WriteRune(r)
+			b.WriteRune(r)
+		case !unicode.IsPrint(r):
+			if r > 0xffff {
+				fmt.Fprintf(&b, `\U%08x`, r)
+			} else {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
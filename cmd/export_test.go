@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHCLWriterResource(t *testing.T) {
+	var buf bytes.Buffer
+	w := newHCLWriter(&buf)
+
+	first := w.resource("rancher2_project", "My Project", "c-1:p-1", func() {
+		w.attr("name", "My Project")
+		w.attr("cluster_id", hclExpr("rancher2_cluster.prod.id"))
+		w.attr("description", "")
+		w.attr("answers", map[string]string{"b": "${x}", "a": "1"})
+	})
+	second := w.resource("rancher2_project", "my-project!", "c-1:p-2", func() {})
+	third := w.resource("rancher2_project", "1st", "c-1:p-3", func() {})
+
+	assert.NoError(t, w.err)
+	assert.Equal(t, "rancher2_project.My_Project", first)
+	assert.Equal(t, "rancher2_project.my-project", second)
+	assert.Equal(t, "rancher2_project.r_1st", third)
+	assert.Equal(t, `resource "rancher2_project" "My_Project" {
+  name = "My Project"
+  cluster_id = rancher2_cluster.prod.id
+  answers = {
+    "a" = "1"
+    "b" = "$${x}"
+  }
+}
+
+import {
+  to = rancher2_project.My_Project
+  id = "c-1:p-1"
+}
+
+`, buf.String()[:bytes.Index(buf.Bytes(), []byte(`resource "rancher2_project" "my-project"`))])
+}
+
+func TestHCLWriterUniqueNames(t *testing.T) {
+	w := newHCLWriter(&bytes.Buffer{})
+
+	assert.Equal(t, "web", w.name("rancher2_app", "web"))
+	assert.Equal(t, "web_2", w.name("rancher2_app", "web"))
+	assert.Equal(t, "web", w.name("rancher2_project", "web"))
+}
+
+func TestHCLString(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "plain", s: "prod", want: `"prod"`},
+		{name: "quotes and backslashes", s: `a "b" \c`, want: `"a \"b\" \\c"`},
+		{name: "whitespace", s: "a\nb\tc\r", want: `"a\nb\tc\r"`},
+		{name: "control characters", s: "bell\a vt\v del\x7f", want: `"bell\u0007 vt\u000b del\u007f"`},
+		{name: "non printable outside the BMP", s: "tag\U000e0041", want: `"tag\U000e0041"`},
+		{name: "unicode", s: "café", want: `"café"`},
+		{name: "template sequences", s: "${var} %{if} $5 100%", want: `"$${var} %%{if} $5 100%"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, hclString(tt.s))
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// rowFilter narrows the rows handed to a TableWriter using the --filter and
+// --label-selector flags.
+type rowFilter struct {
+	fields   []fieldRequirement
+	selector []labelRequirement
+}
+
+type fieldRequirement struct {
+	key   string
+	value string
+}
+
+type labelRequirement struct {
+	key      string
+	value    string
+	operator string
+}
+
+// newRowFilter parses filters in the form key=value and a label selector such
+// as "app=web,tier!=db,!canary". It returns nil when there is nothing to filter.
+func newRowFilter(filters []string, selector string) (*rowFilter, error) {
+	f := &rowFilter{}
+	for _, filter := range filters {
+		key, value, ok := strings.Cut(filter, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid filter %q, expected key=value", filter)
+		}
+		f.fields = append(f.fields, fieldRequirement{
+			key:   strings.ToLower(key),
+			value: value,
+		})
+	}
+
+	requirements, err := parseLabelSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	f.selector = requirements
+
+	if len(f.fields) == 0 && len(f.selector) == 0 {
+		return nil, nil
+	}
+	return f, nil
+}
+
+func parseLabelSelector(selector string) ([]labelRequirement, error) {
+	var requirements []labelRequirement
+	for _, term := range strings.Split(selector, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		var r labelRequirement
+		switch {
+		case strings.Contains(term, "!="):
+			r.key, r.value, _ = strings.Cut(term, "!=")
+			r.operator = "!="
+		case strings.Contains(term, "=="):
+			r.key, r.value, _ = strings.Cut(term, "==")
+			r.operator = "="
+		case strings.Contains(term, "="):
+			r.key, r.value, _ = strings.Cut(term, "=")
+			r.operator = "="
+		case strings.HasPrefix(term, "!"):
+			r.key = strings.TrimPrefix(term, "!")
+			r.operator = "!"
+		default:
+			r.key = term
+			r.operator = "exists"
+		}
+
+		r.key = strings.TrimSpace(r.key)
+		r.value = strings.TrimSpace(r.value)
+		if r.key == "" {
+			return nil, fmt.Errorf("invalid label selector %q", selector)
+		}
+		requirements = append(requirements, r)
+	}
+	return requirements, nil
+}
+
+// Match reports whether obj satisfies every filter and label requirement.
+// Filter keys are compared case-insensitively against the dotted JSON path of
+// each field, either exactly or as a suffix, so "state" matches "App.State".
+func (f *rowFilter) Match(obj interface{}) (bool, error) {
+	content, err := json.Marshal(obj)
+	if err != nil {
+		return false, err
+	}
+	var data interface{}
+	if err := json.Unmarshal(content, &data); err != nil {
+		return false, err
+	}
+
+	fields := map[string][]string{}
+	labels := map[string]string{}
+	flattenRow("", data, fields, labels)
+
+	for _, r := range f.fields {
+		if !matchField(fields, r) {
+			return false, nil
+		}
+	}
+
+	for _, r := range f.selector {
+		value, ok := labels[r.key]
+		switch r.operator {
+		case "=":
+			if !ok || value != r.value {
+				return false, nil
+			}
+		case "!=":
+			if ok && value == r.value {
+				return false, nil
+			}
+		case "!":
+			if ok {
+				return false, nil
+			}
+		case "exists":
+			if !ok {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+func matchField(fields map[string][]string, r fieldRequirement) bool {
+	for path, values := range fields {
+		if path != r.key && !strings.HasSuffix(path, "."+r.key) {
+			continue
+		}
+		for _, value := range values {
+			if value == r.value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// flattenRow collects the scalar values of data keyed by their lower cased
+// dotted path. Elements of a list share the path of the list itself. Any
+// "labels" maps found along the way are merged into labels.
+func flattenRow(path string, data interface{}, fields map[string][]string, labels map[string]string) {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			childPath := strings.ToLower(key)
+			if path != "" {
+				childPath = path + "." + childPath
+			}
+			if strings.EqualFold(key, "labels") {
+				if m, ok := value.(map[string]interface{}); ok {
+					for labelKey, labelValue := range m {
+						labels[labelKey] = fmt.Sprint(labelValue)
+					}
+				}
+			}
+			flattenRow(childPath, value, fields, labels)
+		}
+	case []interface{}:
+		for _, value := range v {
+			flattenRow(path, value, fields, labels)
+		}
+	case nil:
+	default:
+		fields[path] = append(fields[path], fmt.Sprint(v))
+	}
+}
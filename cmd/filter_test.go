@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type filterTestRow struct {
+	ID      string
+	Cluster struct {
+		Name   string            `json:"name"`
+		State  string            `json:"state"`
+		Labels map[string]string `json:"labels"`
+	}
+}
+
+func TestRowFilterMatch(t *testing.T) {
+	row := filterTestRow{ID: "c-1"}
+	row.Cluster.Name = "prod-east"
+	row.Cluster.State = "active"
+	row.Cluster.Labels = map[string]string{"env": "prod", "tier": "web"}
+
+	tt := []struct {
+		name     string
+		filters  []string
+		selector string
+		expected bool
+	}{
+		{name: "suffix match", filters: []string{"state=active"}, expected: true},
+		{name: "full path match", filters: []string{"Cluster.Name=prod-east"}, expected: true},
+		{name: "value mismatch", filters: []string{"state=error"}, expected: false},
+		{name: "all filters must match", filters: []string{"state=active", "name=other"}, expected: false},
+		{name: "label equality", selector: "env=prod", expected: true},
+		{name: "label double equals", selector: "env==prod,tier=web", expected: true},
+		{name: "label inequality", selector: "env!=prod", expected: false},
+		{name: "label exists", selector: "tier", expected: true},
+		{name: "label does not exist", selector: "!canary", expected: true},
+		{name: "label missing", selector: "canary", expected: false},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := newRowFilter(tc.filters, tc.selector)
+			assert.NoError(t, err)
+
+			ok, err := f.Match(row)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, ok)
+		})
+	}
+}
+
+func TestNewRowFilterErrors(t *testing.T) {
+	_, err := newRowFilter([]string{"state"}, "")
+	assert.EqualError(t, err, `invalid filter "state", expected key=value`)
+
+	_, err = newRowFilter(nil, "!")
+	assert.EqualError(t, err, `invalid label selector "!"`)
+
+	f, err := newRowFilter(nil, "")
+	assert.NoError(t, err)
+	assert.Nil(t, f)
+}
@@ -0,0 +1,500 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rancher/cli/cliclient"
+	"github.com/rancher/norman/types"
+	"github.com/urfave/cli"
+)
+
+const (
+	fleetGitRepoType      = "fleet.cattle.io.gitrepo"
+	fleetBundleType       = "fleet.cattle.io.bundle"
+	fleetClusterGroupType = "fleet.cattle.io.clustergroup"
+
+	// fleetRepoNameLabel is set by fleet on the bundles created from a GitRepo
+	fleetRepoNameLabel = "fleet.cattle.io/repo-name"
+)
+
+type fleetSummary struct {
+	DesiredReady      int `json:"desiredReady"`
+	Ready             int `json:"ready"`
+	NonReadyResources []struct {
+		Name    string `json:"name,omitempty"`
+		Bundle  string `json:"bundleState,omitempty"`
+		Message string `json:"message,omitempty"`
+	} `json:"nonReadyResources,omitempty"`
+}
+
+type GitRepoTarget struct {
+	Name            string `json:"name,omitempty"`
+	ClusterName     string `json:"clusterName,omitempty"`
+	ClusterGroup    string `json:"clusterGroup,omitempty"`
+	ClusterSelector *struct {
+		MatchLabels map[string]string `json:"matchLabels,omitempty"`
+	} `json:"clusterSelector,omitempty"`
+}
+
+type GitRepo struct {
+	types.Resource
+	Metadata objectMeta `json:"metadata,omitempty"`
+	Spec     struct {
+		Repo             string          `json:"repo,omitempty"`
+		Branch           string          `json:"branch,omitempty"`
+		Revision         string          `json:"revision,omitempty"`
+		Paths            []string        `json:"paths,omitempty"`
+		ClientSecretName string          `json:"clientSecretName,omitempty"`
+		Targets          []GitRepoTarget `json:"targets,omitempty"`
+	} `json:"spec,omitempty"`
+	Status struct {
+		Commit     string            `json:"commit,omitempty"`
+		Summary    fleetSummary      `json:"summary,omitempty"`
+		Conditions []objectCondition `json:"conditions,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+type GitRepoCollection struct {
+	types.Collection
+	Data []GitRepo `json:"data,omitempty"`
+}
+
+type Bundle struct {
+	types.Resource
+	Metadata objectMeta `json:"metadata,omitempty"`
+	Status   struct {
+		Summary fleetSummary `json:"summary,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+type BundleCollection struct {
+	types.Collection
+	Data []Bundle `json:"data,omitempty"`
+}
+
+type ClusterGroup struct {
+	types.Resource
+	Metadata objectMeta `json:"metadata,omitempty"`
+	Spec     struct {
+		Selector *struct {
+			MatchLabels map[string]string `json:"matchLabels,omitempty"`
+		} `json:"selector,omitempty"`
+	} `json:"spec,omitempty"`
+	Status struct {
+		ClusterCount         int `json:"clusterCount"`
+		NonReadyClusterCount int `json:"nonReadyClusterCount"`
+	} `json:"status,omitempty"`
+}
+
+type ClusterGroupCollection struct {
+	types.Collection
+	Data []ClusterGroup `json:"data,omitempty"`
+}
+
+type GitRepoData struct {
+	ID      string
+	GitRepo GitRepo
+	Name    string
+	Commit  string
+	Ready   string
+	State   string
+}
+
+type BundleData struct {
+	ID      string
+	Bundle  Bundle
+	Name    string
+	GitRepo string
+	Ready   string
+	State   string
+}
+
+type ClusterGroupData struct {
+	ID           string
+	ClusterGroup ClusterGroup
+	Name         string
+	Clusters     string
+	Selector     string
+}
+
+var fleetWorkspaceFlag = cli.StringFlag{
+	Name:  "workspace,w",
+	Usage: "Fleet workspace (namespace) to use",
+	Value: "fleet-default",
+}
+
+func FleetCommand() cli.Command {
+	fleetLsFlags := []cli.Flag{
+		fleetWorkspaceFlag,
+		formatFlag,
+		quietFlag,
+		filterFlag,
+		labelSelectorFlag,
+		sortByFlag,
+		noHeadersFlag,
+	}
+
+	return cli.Command{
+		Name:  "fleet",
+		Usage: "Operations on Fleet continuous delivery",
+		Description: `
+Manages the Fleet GitOps resources of the local cluster. Resources are read from the
+'fleet-default' workspace, which targets downstream clusters, unless --workspace is given.
+Fleet is available in Rancher 2.5 and later.
+`,
+		Subcommands: []cli.Command{
+			{
+				Name:    "gitrepo",
+				Aliases: []string{"gitrepos"},
+				Usage:   "Operations on git repositories watched by Fleet",
+				Action:  defaultAction(fleetGitRepoLs),
+				Flags:   fleetLsFlags,
+				Subcommands: []cli.Command{
+					{
+						Name:      "ls",
+						Usage:     "List git repositories",
+						ArgsUsage: "None",
+						Action:    fleetGitRepoLs,
+						Flags:     fleetLsFlags,
+					},
+					{
+						Name:  "add",
+						Usage: "Add a git repository for Fleet to deploy",
+						Description: `
+Without --cluster or --cluster-group the repository is deployed to every cluster in the workspace.
+
+Example:
+	$ rancher fleet gitrepo add --branch main --path guestbook --cluster-group prod \
+		guestbook https://github.com/rancher/fleet-examples
+`,
+						ArgsUsage: "[NAME, REPO_URL]",
+						Action:    fleetGitRepoAdd,
+						Flags: []cli.Flag{
+							fleetWorkspaceFlag,
+							cli.StringFlag{
+								Name:  "branch",
+								Usage: "Branch to watch, defaults to master",
+							},
+							cli.StringFlag{
+								Name:  "revision",
+								Usage: "Commit or tag to deploy instead of following a branch",
+							},
+							cli.StringSliceFlag{
+								Name:  "path",
+								Usage: "Directory in the repository to deploy, can be used multiple times",
+							},
+							cli.StringFlag{
+								Name:  "client-secret",
+								Usage: "Name of the secret holding the credentials for the repository",
+							},
+							cli.StringSliceFlag{
+								Name:  "cluster",
+								Usage: "Name of a Fleet cluster to deploy to, can be used multiple times",
+							},
+							cli.StringSliceFlag{
+								Name:  "cluster-group",
+								Usage: "Name of a cluster group to deploy to, can be used multiple times",
+							},
+						},
+					},
+					{
+						Name:      "status",
+						Usage:     "Show the deployment status of a git repository",
+						ArgsUsage: "[NAME]",
+						Action:    fleetGitRepoStatus,
+						Flags: []cli.Flag{
+							fleetWorkspaceFlag,
+						},
+					},
+				},
+			},
+			{
+				Name:    "bundle",
+				Aliases: []string{"bundles"},
+				Usage:   "Operations on Fleet bundles",
+				Action:  defaultAction(fleetBundleLs),
+				Flags:   fleetLsFlags,
+				Subcommands: []cli.Command{
+					{
+						Name:      "ls",
+						Usage:     "List bundles",
+						ArgsUsage: "None",
+						Action:    fleetBundleLs,
+						Flags:     fleetLsFlags,
+					},
+				},
+			},
+			{
+				Name:    "cluster-group",
+				Aliases: []string{"cluster-groups"},
+				Usage:   "Operations on Fleet cluster groups",
+				Action:  defaultAction(fleetClusterGroupLs),
+				Flags:   fleetLsFlags,
+				Subcommands: []cli.Command{
+					{
+						Name:      "ls",
+						Usage:     "List cluster groups",
+						ArgsUsage: "None",
+						Action:    fleetClusterGroupLs,
+						Flags:     fleetLsFlags,
+					},
+				},
+			},
+		},
+	}
+}
+
+func fleetGitRepoLs(ctx *cli.Context) error {
+	c, err := getFleetClient(ctx, fleetGitRepoType)
+	if err != nil {
+		return err
+	}
+
+	collection := &GitRepoCollection{}
+	if err := c.CAPIClient.List(fleetGitRepoType, fleetListOpts(ctx), collection); err != nil {
+		return err
+	}
+
+	writer := NewTableWriter([][]string{
+		{"NAME", "Name"},
+		{"REPO", "GitRepo.Spec.Repo"},
+		{"COMMIT", "Commit"},
+		{"READY", "Ready"},
+		{"STATE", "State"},
+	}, ctx)
+
+	defer writer.Close()
+
+	for _, item := range collection.Data {
+		writer.Write(&GitRepoData{
+			ID:      item.ID,
+			GitRepo: item,
+			Name:    item.Metadata.Name,
+			Commit:  shortCommit(item.Status.Commit),
+			Ready:   fleetReady(item.Status.Summary),
+			State:   objectState(item.Metadata),
+		})
+	}
+
+	return writer.Err()
+}
+
+func fleetGitRepoAdd(ctx *cli.Context) error {
+	if ctx.NArg() < 2 {
+		return showSubcommandUsage(ctx)
+	}
+
+	c, err := getFleetClient(ctx, fleetGitRepoType)
+	if err != nil {
+		return err
+	}
+
+	repo := &GitRepo{}
+	repo.Type = fleetGitRepoType
+	repo.Metadata.Name = ctx.Args().First()
+	repo.Metadata.Namespace = ctx.String("workspace")
+	repo.Spec.Repo = ctx.Args().Get(1)
+	repo.Spec.Branch = ctx.String("branch")
+	repo.Spec.Revision = ctx.String("revision")
+	repo.Spec.Paths = ctx.StringSlice("path")
+	repo.Spec.ClientSecretName = ctx.String("client-secret")
+	for _, name := range ctx.StringSlice("cluster") {
+		repo.Spec.Targets = append(repo.Spec.Targets, GitRepoTarget{ClusterName: name})
+	}
+	for _, name := range ctx.StringSlice("cluster-group") {
+		repo.Spec.Targets = append(repo.Spec.Targets, GitRepoTarget{ClusterGroup: name})
+	}
+
+	created := &GitRepo{}
+	if err := c.CAPIClient.Create(fleetGitRepoType, repo, created); err != nil {
+		return err
+	}
+
+	fmt.Printf("Added git repository %s/%s\n", created.Metadata.Namespace, created.Metadata.Name)
+	return nil
+}
+
+func fleetGitRepoStatus(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+
+	c, err := getFleetClient(ctx, fleetGitRepoType)
+	if err != nil {
+		return err
+	}
+
+	repo := &GitRepo{}
+	id := ctx.String("workspace") + "/" + ctx.Args().First()
+	if err := c.CAPIClient.ByID(fleetGitRepoType, id, repo); err != nil {
+		return err
+	}
+
+	fmt.Printf("Name:     %s\n", repo.Metadata.Name)
+	fmt.Printf("Repo:     %s\n", repo.Spec.Repo)
+	if repo.Spec.Revision != "" {
+		fmt.Printf("Revision: %s\n", repo.Spec.Revision)
+	} else {
+		fmt.Printf("Branch:   %s\n", valueOrDefault(repo.Spec.Branch, "master"))
+	}
+	fmt.Printf("Commit:   %s\n", repo.Status.Commit)
+	fmt.Printf("Targets:  %s\n", fleetTargets(repo.Spec.Targets))
+	fmt.Printf("Ready:    %s\n", fleetReady(repo.Status.Summary))
+	fmt.Printf("State:    %s\n", objectState(repo.Metadata))
+	if repo.Metadata.State != nil && repo.Metadata.State.Message != "" {
+		fmt.Printf("Message:  %s\n", repo.Metadata.State.Message)
+	}
+
+	if len(repo.Status.Conditions) > 0 {
+		fmt.Println("\nConditions:")
+		for _, condition := range repo.Status.Conditions {
+			fmt.Printf("  %s=%s %s\n", condition.Type, condition.Status, condition.Message)
+		}
+	}
+
+	if len(repo.Status.Summary.NonReadyResources) > 0 {
+		fmt.Println("\nNot ready:")
+		for _, resource := range repo.Status.Summary.NonReadyResources {
+			fmt.Printf("  %s %s %s\n", resource.Name, resource.Bundle, resource.Message)
+		}
+	}
+
+	return nil
+}
+
+func fleetBundleLs(ctx *cli.Context) error {
+	c, err := getFleetClient(ctx, fleetBundleType)
+	if err != nil {
+		return err
+	}
+
+	collection := &BundleCollection{}
+	if err := c.CAPIClient.List(fleetBundleType, fleetListOpts(ctx), collection); err != nil {
+		return err
+	}
+
+	writer := NewTableWriter([][]string{
+		{"NAME", "Name"},
+		{"GITREPO", "GitRepo"},
+		{"READY", "Ready"},
+		{"STATE", "State"},
+	}, ctx)
+
+	defer writer.Close()
+
+	for _, item := range collection.Data {
+		writer.Write(&BundleData{
+			ID:      item.ID,
+			Bundle:  item,
+			Name:    item.Metadata.Name,
+			GitRepo: item.Metadata.Labels[fleetRepoNameLabel],
+			Ready:   fleetReady(item.Status.Summary),
+			State:   objectState(item.Metadata),
+		})
+	}
+
+	return writer.Err()
+}
+
+func fleetClusterGroupLs(ctx *cli.Context) error {
+	c, err := getFleetClient(ctx, fleetClusterGroupType)
+	if err != nil {
+		return err
+	}
+
+	collection := &ClusterGroupCollection{}
+	if err := c.CAPIClient.List(fleetClusterGroupType, fleetListOpts(ctx), collection); err != nil {
+		return err
+	}
+
+	writer := NewTableWriter([][]string{
+		{"NAME", "Name"},
+		{"CLUSTERS-READY", "Clusters"},
+		{"SELECTOR", "Selector"},
+	}, ctx)
+
+	defer writer.Close()
+
+	for _, item := range collection.Data {
+		var selector map[string]string
+		if item.Spec.Selector != nil {
+			selector = item.Spec.Selector.MatchLabels
+		}
+		writer.Write(&ClusterGroupData{
+			ID:           item.ID,
+			ClusterGroup: item,
+			Name:         item.Metadata.Name,
+			Clusters: fmt.Sprintf("%d/%d",
+				item.Status.ClusterCount-item.Status.NonReadyClusterCount, item.Status.ClusterCount),
+			Selector: formatLabels(selector),
+		})
+	}
+
+	return writer.Err()
+}
+
+// getFleetClient returns a client for the /v1 API, checking that the server
+// serves schemaType so that older servers get a clear error.
+func getFleetClient(ctx *cli.Context, schemaType string) (*cliclient.MasterClient, error) {
+	return getLocalV1Client(ctx, schemaType, "fleet is not available on this server")
+}
+
+// fleetListOpts lists the resources of the --workspace namespace
+func fleetListOpts(ctx *cli.Context) *types.ListOpts {
+	return &types.ListOpts{
+		Filters: map[string]interface{}{
+			"filter": "metadata.namespace=" + ctx.String("workspace"),
+		},
+	}
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
+
+func fleetReady(summary fleetSummary) string {
+	return fmt.Sprintf("%d/%d", summary.Ready, summary.DesiredReady)
+}
+
+// fleetTargets describes where a GitRepo is deployed
+func fleetTargets(targets []GitRepoTarget) string {
+	if len(targets) == 0 {
+		return "all clusters"
+	}
+	var names []string
+	for _, target := range targets {
+		switch {
+		case target.ClusterName != "":
+			names = append(names, "cluster="+target.ClusterName)
+		case target.ClusterGroup != "":
+			names = append(names, "group="+target.ClusterGroup)
+		case target.ClusterSelector != nil:
+			names = append(names, "selector="+formatLabels(target.ClusterSelector.MatchLabels))
+		case target.Name != "":
+			names = append(names, target.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatLabels renders labels as a sorted, comma separated selector
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func valueOrDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
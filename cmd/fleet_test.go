@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFleetTargets(t *testing.T) {
+	assert.Equal(t, "all clusters", fleetTargets(nil))
+
+	selector := GitRepoTarget{}
+	selector.ClusterSelector = &struct {
+		MatchLabels map[string]string `json:"matchLabels,omitempty"`
+	}{MatchLabels: map[string]string{"env": "prod", "app": "web"}}
+
+	targets := []GitRepoTarget{
+		{ClusterName: "c1"},
+		{ClusterGroup: "prod"},
+		selector,
+	}
+	assert.Equal(t, "cluster=c1, group=prod, selector=app=web,env=prod", fleetTargets(targets))
+}
+
+func TestShortCommit(t *testing.T) {
+	assert.Equal(t, "0123456", shortCommit("0123456789abcdef"))
+	assert.Equal(t, "abc", shortCommit("abc"))
+}
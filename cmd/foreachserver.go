@@ -0,0 +1,317 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/urfave/cli"
+)
+
+// foreachServerEnv is set for the commands run by foreach-server, their
+// tables are written as CSV so that they can be merged.
+const foreachServerEnv = "RANCHER_FOREACH_SERVER"
+
+const foreachServerDescription = `
+Runs a rancher command against every configured server concurrently and merges the output in
+one table, with a SERVER column in front. Only read-only commands such as ls and inspect can
+be run, and they are run with --dry-run too so that they can't change anything. The command
+goes after --, so that its flags aren't taken as flags of foreach-server.
+
+Example:
+	# List the multi-cluster apps of every server
+	$ rancher foreach-server -- mcapp ls
+
+	# Only some servers
+	$ rancher foreach-server --servers prod-eu,prod-us -- clusters ls
+`
+
+// readOnlyCommands are the commands that change nothing, by the name of the
+// command or of the subcommand that is run. foreach-server only runs them and
+// only their output is cached for --cached.
+var readOnlyCommands = map[string]bool{
+	"get":            true,
+	"id":             true,
+	"inspect":        true,
+	"list-members":   true,
+	"list-roles":     true,
+	"list-templates": true,
+	"ls":             true,
+	"ps":             true,
+	"show":           true,
+	"show-app":       true,
+	"show-template":  true,
+	"status":         true,
+	"version":        true,
+}
+
+// serverOutput is the output of a command run against one server
+type serverOutput struct {
+	Server string
+	Stdout []byte
+	Stderr []byte
+	Err    error
+}
+
+func ForeachServerCommand() cli.Command {
+	return cli.Command{
+		Name:        "foreach-server",
+		Usage:       "Run a read-only command against every configured server",
+		Description: foreachServerDescription,
+		ArgsUsage:   "-- COMMAND [ARGS...]",
+		Action:      foreachServer,
+		Flags: []cli.Flag{
+			cli.StringSliceFlag{
+				Name:  "servers",
+				Usage: "Only run the command against these servers",
+			},
+		},
+	}
+}
+
+func foreachServer(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+	if err := checkReadOnlyCommand(ctx.App.Commands, ctx.Args()); err != nil {
+		return NewUsageError(err)
+	}
+
+	cf, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	servers := ctx.StringSlice("servers")
+	if len(servers) == 0 {
+		for name := range cf.Servers {
+			servers = append(servers, name)
+		}
+	}
+	for _, name := range servers {
+		if _, ok := cf.Servers[name]; !ok {
+			return fmt.Errorf("server %s not found, run `rancher server ls` to see the configured servers", name)
+		}
+	}
+	if len(servers) == 0 {
+		return errors.New("no servers are configured, run `rancher login` first")
+	}
+	sort.Strings(servers)
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	stop := commandInterrupt.watch()
+	defer stop()
+	outputs := make([]serverOutput, len(servers))
+	var wg sync.WaitGroup
+	for i, name := range servers {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			args := append(foreachServerGlobalArgs(ctx, name), ctx.Args()...)
+			outputs[i] = runForServer(commandInterrupt.context(), executable, name, args)
+		}(i, name)
+	}
+	wg.Wait()
+
+	if err := writeServerOutputs(os.Stdout, outputs, !hasNoHeaders(ctx.Args())); err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, output := range outputs {
+		if output.Err == nil {
+			continue
+		}
+		failed = append(failed, output.Server)
+		message := strings.TrimSpace(string(output.Stderr))
+		if message == "" {
+			message = output.Err.Error()
+		}
+		fmt.Fprintf(os.Stderr, "%s: %s\n", output.Server, message)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("the command failed on %d of %d servers: %s", len(failed), len(outputs), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// checkReadOnlyCommand returns an error unless args run a read-only command,
+// looked up in commands by the leading arguments up to the first flag.
+func checkReadOnlyCommand(commands cli.Commands, args []string) error {
+	command := leafCommand(commands, args)
+	switch {
+	case command == nil:
+		return fmt.Errorf("unknown command %q", strings.Join(args, " "))
+	case !isReadOnlyCommand(command):
+		return fmt.Errorf("foreach-server only runs read-only commands such as ls and inspect, not %q", strings.Join(args, " "))
+	}
+	return nil
+}
+
+// leafCommand returns the command run by args, looked up in commands by the
+// leading arguments up to the first flag, nil when there is none.
+func leafCommand(commands cli.Commands, args []string) *cli.Command {
+	var command *cli.Command
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		found := findCommand(commands, arg)
+		if found == nil {
+			break
+		}
+		command = found
+		if commands = found.Subcommands; len(commands) == 0 {
+			break
+		}
+	}
+	return command
+}
+
+func isReadOnlyCommand(command *cli.Command) bool {
+	return len(command.Subcommands) == 0 && readOnlyCommands[command.Name]
+}
+
+func findCommand(commands cli.Commands, name string) *cli.Command {
+	for i := range commands {
+		if commands[i].HasName(name) {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+// hasNoHeaders reports whether args ask for tables without headers.
+func hasNoHeaders(args []string) bool {
+	for _, arg := range args {
+		name, value, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if strings.HasPrefix(arg, "-") && name == "no-headers" && value != "false" {
+			return true
+		}
+	}
+	return false
+}
+
+// foreachServerGlobalArgs returns the global flags the command is run with
+// against server: the same config and inherited flags as foreach-server, and
+// --dry-run so that nothing is changed.
+func foreachServerGlobalArgs(ctx *cli.Context, server string) []string {
+	args := []string{"--config", GetConfigPath(ctx), "--server", server, "--dry-run"}
+	return append(args, inheritedGlobalArgs(ctx)...)
+}
+
+// inheritedGlobalArgs returns the global flags set for this command that the
+// rancher commands it runs inherit: the impersonated user, how requests are
+// sent and how secrets are shown. The project isn't, as it depends on the
+// server.
+func inheritedGlobalArgs(ctx *cli.Context) []string {
+	var args []string
+	if user := ctx.GlobalString("as"); user != "" {
+		args = append(args, "--as", user)
+	}
+	if ctx.GlobalIsSet("retries") {
+		args = append(args, "--retries", strconv.Itoa(ctx.GlobalInt("retries")))
+	}
+	for _, name := range []string{"request-timeout", "cache-ttl"} {
+		if ctx.GlobalIsSet(name) {
+			args = append(args, "--"+name, ctx.GlobalDuration(name).String())
+		}
+	}
+	if ctx.GlobalIsSet("secret-patterns") {
+		args = append(args, "--secret-patterns", ctx.GlobalString("secret-patterns"))
+	}
+	if ctx.GlobalBool("show-secrets") {
+		args = append(args, "--show-secrets")
+	}
+	return args
+}
+
+func runForServer(ctx context.Context, executable, server string, args []string) serverOutput {
+	cmd := exec.CommandContext(ctx, executable, args...)
+	// the project of the environment is of one server, --server picks another
+	cmd.Env = append(withoutEnv(os.Environ(), "RANCHER_PROJECT"), foreachServerEnv+"=1")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return serverOutput{
+		Server: server,
+		Stdout: stdout.Bytes(),
+		Stderr: stderr.Bytes(),
+		Err:    err,
+	}
+}
+
+// writeServerOutputs writes the tables printed for every server as one table
+// with a SERVER column. The header is written once, when headers says the
+// tables have one. Output that isn't a table, such as JSON, is written line by
+// line prefixed with the server.
+func writeServerOutputs(out io.Writer, outputs []serverOutput, headers bool) error {
+	writer := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+	headerWritten := false
+	for _, output := range outputs {
+		records, ok := parseTable(output.Stdout)
+		if !ok {
+			for _, line := range strings.Split(strings.TrimRight(string(output.Stdout), "\n"), "\n") {
+				if line != "" {
+					fmt.Fprintf(writer, "%s\t%s\n", output.Server, line)
+				}
+			}
+			continue
+		}
+		if headers && len(records) > 0 && isTableHeader(records[0]) {
+			if !headerWritten {
+				fmt.Fprintf(writer, "SERVER\t%s\n", strings.Join(records[0], "\t"))
+				headerWritten = true
+			}
+			records = records[1:]
+		}
+		for _, record := range records {
+			fmt.Fprintf(writer, "%s\t%s\n", output.Server, strings.Join(record, "\t"))
+		}
+	}
+	return writer.Flush()
+}
+
+// parseTable parses output written as CSV, with the same number of fields on
+// every line.
+func parseTable(content []byte) ([][]string, bool) {
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		return nil, false
+	}
+	return records, true
+}
+
+// isTableHeader reports whether a record is a header of the table writer,
+// which has upper case column names.
+func isTableHeader(record []string) bool {
+	for _, field := range record {
+		if field == "" || field != strings.ToUpper(field) || strings.ToLower(field) == field {
+			return false
+		}
+	}
+	return true
+}
+
+// withoutEnv returns env without the variable name.
+func withoutEnv(env []string, name string) []string {
+	return slices.DeleteFunc(slices.Clone(env), func(variable string) bool {
+		return strings.HasPrefix(variable, name+"=")
+	})
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli"
+)
+
+func TestWriteServerOutputs(t *testing.T) {
+	outputs := []serverOutput{
+		{Server: "eu", Stdout: []byte("ID,NAME\nc-1,prod\n")},
+		{Server: "us", Stdout: []byte("ID,NAME\nc-2,staging\nc-3,dev\n")},
+		{Server: "lab", Stdout: []byte("{\"id\":\"c-4\",\"name\":\"lab\"}\n")},
+	}
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, writeServerOutputs(out, outputs, true))
+	assert.Equal(t, "SERVER    ID        NAME\n"+
+		"eu        c-1       prod\n"+
+		"us        c-2       staging\n"+
+		"us        c-3       dev\n"+
+		"lab       {\"id\":\"c-4\",\"name\":\"lab\"}\n", out.String())
+}
+
+func TestWriteServerOutputsNoHeaders(t *testing.T) {
+	outputs := []serverOutput{
+		{Server: "eu", Stdout: []byte("ID,STATE\nc-1,ACTIVE\n")},
+	}
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, writeServerOutputs(out, outputs, false))
+	assert.Equal(t, "eu        ID        STATE\n"+
+		"eu        c-1       ACTIVE\n", out.String())
+}
+
+func TestCheckReadOnlyCommand(t *testing.T) {
+	commands := cli.Commands{
+		{Name: "clusters", Aliases: []string{"cluster"}, Subcommands: cli.Commands{{Name: "ls"}, {Name: "delete"}}},
+		{Name: "inspect"},
+		{Name: "kubectl"},
+	}
+
+	tt := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{name: "list", args: []string{"cluster", "ls", "--format", "json"}},
+		{name: "inspect", args: []string{"inspect", "c-1"}},
+		{name: "delete", args: []string{"clusters", "delete", "ls"}, expectedErr: `foreach-server only runs read-only commands such as ls and inspect, not "clusters delete ls"`},
+		{name: "kubectl", args: []string{"kubectl", "get", "pods"}, expectedErr: `foreach-server only runs read-only commands such as ls and inspect, not "kubectl get pods"`},
+		{name: "no subcommand", args: []string{"clusters"}, expectedErr: `foreach-server only runs read-only commands such as ls and inspect, not "clusters"`},
+		{name: "unknown", args: []string{"ls"}, expectedErr: `unknown command "ls"`},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkReadOnlyCommand(commands, tc.args)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestHasNoHeaders(t *testing.T) {
+	assert.True(t, hasNoHeaders([]string{"clusters", "ls", "--no-headers"}))
+	assert.True(t, hasNoHeaders([]string{"clusters", "ls", "--no-headers=true"}))
+	assert.False(t, hasNoHeaders([]string{"clusters", "ls", "--no-headers=false"}))
+	assert.False(t, hasNoHeaders([]string{"inspect", "no-headers"}))
+}
+
+func TestIsTableHeader(t *testing.T) {
+	assert.True(t, isTableHeader([]string{"ID", "NAME", "CPU %"}))
+	assert.False(t, isTableHeader([]string{"c-1", "prod"}))
+	assert.False(t, isTableHeader([]string{"123"}))
+}
+
+func TestForeachServerGlobalArgs(t *testing.T) {
+	global := flag.NewFlagSet("rancher", flag.ContinueOnError)
+	global.String("config", "/tmp/cli2.json", "")
+	global.String("as", "", "")
+	global.String("project", "", "")
+	global.Int("retries", 3, "")
+	global.Duration("request-timeout", 0, "")
+	global.Duration("cache-ttl", 5*time.Minute, "")
+	global.String("secret-patterns", "", "")
+	global.Bool("show-secrets", false, "")
+	assert.NoError(t, global.Parse([]string{"--as", "bob", "--project", "c-1:p-1", "--retries", "5", "--request-timeout", "30s"}))
+	ctx := cli.NewContext(nil, flag.NewFlagSet("foreach-server", flag.ContinueOnError), cli.NewContext(nil, global, nil))
+
+	assert.Equal(t, []string{
+		"--config", "/tmp/cli2.json", "--server", "eu", "--dry-run",
+		"--as", "bob", "--retries", "5", "--request-timeout", "30s",
+	}, foreachServerGlobalArgs(ctx, "eu"))
+}
+
+func TestWithoutEnv(t *testing.T) {
+	env := []string{"HOME=/root", "RANCHER_PROJECT=c-1:p-1", "RANCHER_PROJECTS=x"}
+	assert.Equal(t, []string{"HOME=/root", "RANCHER_PROJECTS=x"}, withoutEnv(env, "RANCHER_PROJECT"))
+	assert.Len(t, env, 3)
+}
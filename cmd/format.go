@@ -4,8 +4,15 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
+// FormatMessage puts a transitioning message on a single line so that it
+// fits in a column of a listing.
+func FormatMessage(message string) string {
+	return strings.Join(strings.Fields(message), " ")
+}
+
 func FormatEndpoint(data interface{}) string {
 	dataSlice, ok := data.([]interface{})
 	if !ok {
@@ -0,0 +1,13 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatMessage(t *testing.T) {
+	assert.Equal(t, "", FormatMessage(""))
+	assert.Equal(t, "Error: failed to install app: timed out waiting for the condition",
+		FormatMessage("Error: failed to install app:\n  timed out waiting for the condition\n"))
+}
@@ -0,0 +1,419 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/rancher/norman/clientbase"
+	"github.com/rancher/norman/types"
+	"github.com/urfave/cli"
+)
+
+const (
+	clusterRepoType        = "catalog.cattle.io.clusterrepo"
+	constraintTemplateType = "templates.gatekeeper.sh.constrainttemplate"
+	// every constraint kind is served as a type with this prefix
+	constraintTypePrefix = "constraints.gatekeeper.sh."
+
+	gatekeeperChart     = "rancher-gatekeeper"
+	gatekeeperNamespace = "cattle-gatekeeper-system"
+	gatekeeperFeature   = "OPA Gatekeeper"
+)
+
+type ClusterRepo struct {
+	types.Resource
+	Metadata objectMeta `json:"metadata,omitempty"`
+}
+
+// chartIndex is the part of a Helm repository index needed to find chart versions
+type chartIndex struct {
+	Entries map[string][]struct {
+		Version string `json:"version"`
+	} `json:"entries"`
+}
+
+type chartInstall struct {
+	ChartName   string                 `json:"chartName"`
+	Version     string                 `json:"version"`
+	ReleaseName string                 `json:"releaseName"`
+	Values      map[string]interface{} `json:"values,omitempty"`
+}
+
+type chartInstallAction struct {
+	Namespace string         `json:"namespace"`
+	Wait      bool           `json:"wait"`
+	Charts    []chartInstall `json:"charts"`
+}
+
+type chartOperation struct {
+	OperationName      string `json:"operationName"`
+	OperationNamespace string `json:"operationNamespace"`
+}
+
+type ConstraintTemplate struct {
+	types.Resource
+	Metadata objectMeta `json:"metadata,omitempty"`
+}
+
+type GatekeeperViolation struct {
+	EnforcementAction string `json:"enforcementAction,omitempty"`
+	Kind              string `json:"kind,omitempty"`
+	Name              string `json:"name,omitempty"`
+	Namespace         string `json:"namespace,omitempty"`
+	Message           string `json:"message,omitempty"`
+}
+
+type Constraint struct {
+	types.Resource
+	Kind     string     `json:"kind,omitempty"`
+	Metadata objectMeta `json:"metadata,omitempty"`
+	Spec     struct {
+		EnforcementAction string `json:"enforcementAction,omitempty"`
+	} `json:"spec,omitempty"`
+	Status struct {
+		AuditTimestamp  string                `json:"auditTimestamp,omitempty"`
+		TotalViolations *int64                `json:"totalViolations,omitempty"`
+		Violations      []GatekeeperViolation `json:"violations,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+type ConstraintCollection struct {
+	types.Collection
+	Data []Constraint `json:"data,omitempty"`
+}
+
+type ConstraintData struct {
+	ID          string
+	Constraint  Constraint
+	Kind        string
+	Name        string
+	Enforcement string
+	Violations  string
+	LastAudit   string
+}
+
+type ViolationData struct {
+	Kind       string
+	Constraint string
+	Action     string
+	Resource   string
+	Message    string
+}
+
+var gatekeeperClusterFlag = cli.StringFlag{
+	Name:  "cluster",
+	Usage: "Cluster to use, by default the cluster in the current context",
+}
+
+func GatekeeperCommand() cli.Command {
+	return cli.Command{
+		Name:  "gatekeeper",
+		Usage: "Operations on OPA Gatekeeper policies",
+		Description: `
+Manages policies enforced by OPA Gatekeeper. Gatekeeper is installed in a cluster with
+'rancher gatekeeper enable', after which constraint templates and constraints can be managed.
+`,
+		Subcommands: []cli.Command{
+			{
+				Name:  "enable",
+				Usage: "Install OPA Gatekeeper in a cluster",
+				Description: `
+Installs the rancher-gatekeeper chart from the rancher-charts repository in the
+` + gatekeeperNamespace + ` namespace.
+
+Example:
+	$ rancher gatekeeper enable mycluster
+`,
+				ArgsUsage: "[CLUSTERNAME/CLUSTERID]",
+				Action:    gatekeeperEnable,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "version",
+						Usage: "Version of the chart, by default the latest version",
+					},
+				},
+			},
+			{
+				Name:    "constraint-template",
+				Aliases: []string{"constraint-templates"},
+				Usage:   "Operations on constraint templates",
+				Subcommands: []cli.Command{
+					{
+						Name:  "apply",
+						Usage: "Create or update constraint templates from a file",
+						Description: `
+Creates the ConstraintTemplates in a YAML or JSON file, updating those that already exist.
+The file may hold several templates separated by "---".
+
+Example:
+	$ rancher gatekeeper constraint-template apply --cluster mycluster -f k8srequiredlabels.yaml
+`,
+						ArgsUsage: "None",
+						Action:    gatekeeperTemplateApply,
+						Flags: []cli.Flag{
+							gatekeeperClusterFlag,
+							cli.StringFlag{
+								Name:  "file,f",
+								Usage: "File holding the constraint templates",
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:    "constraint",
+				Aliases: []string{"constraints"},
+				Usage:   "Operations on constraints",
+				Subcommands: []cli.Command{
+					{
+						Name:  "ls",
+						Usage: "List constraints",
+						Description: `
+Lists the constraints of every kind with the number of violations found by the last audit.
+With --violations the violations themselves are listed instead.
+`,
+						ArgsUsage: "None",
+						Action:    gatekeeperConstraintLs,
+						Flags: []cli.Flag{
+							gatekeeperClusterFlag,
+							cli.BoolFlag{
+								Name:  "violations",
+								Usage: "List the violations of the constraints",
+							},
+							formatFlag,
+							filterFlag,
+							labelSelectorFlag,
+							noHeadersFlag,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func gatekeeperEnable(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+
+	client, err := getClusterV1Client(ctx, ctx.Args().First(), clusterRepoType, "Apps & Marketplace")
+	if err != nil {
+		return err
+	}
+	if _, ok := client.Types[constraintTemplateType]; ok {
+		fmt.Printf("%s is already enabled in cluster %s\n", gatekeeperFeature, ctx.Args().First())
+		return nil
+	}
+
+	repo := &ClusterRepo{}
+	if err := client.ByID(clusterRepoType, "rancher-charts", repo); err != nil {
+		return err
+	}
+
+	version := ctx.String("version")
+	if version == "" {
+		version, err = latestChartVersion(client, repo, gatekeeperChart)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the CRD chart is released with the same versions as the chart
+	input := &chartInstallAction{
+		Namespace: gatekeeperNamespace,
+		Wait:      true,
+		Charts: []chartInstall{
+			{ChartName: gatekeeperChart + "-crd", Version: version, ReleaseName: gatekeeperChart + "-crd"},
+			{ChartName: gatekeeperChart, Version: version, ReleaseName: gatekeeperChart},
+		},
+	}
+	operation := &chartOperation{}
+	if err := client.Action(clusterRepoType, "install", &repo.Resource, input, operation); err != nil {
+		return err
+	}
+
+	fmt.Printf("Installing %s %s in cluster %s, operation %s/%s\n", gatekeeperChart, version,
+		ctx.Args().First(), operation.OperationNamespace, operation.OperationName)
+	return nil
+}
+
+// latestChartVersion returns the newest version of chart in repo
+func latestChartVersion(client *clientbase.APIBaseClient, repo *ClusterRepo, chart string) (string, error) {
+	indexURL, ok := repo.Links["index"]
+	if !ok {
+		return "", fmt.Errorf("unable to read the index of repository %s", repo.Metadata.Name)
+	}
+	index := &chartIndex{}
+	if err := client.Ops.DoGet(indexURL, nil, index); err != nil {
+		return "", err
+	}
+	// the index lists the versions of a chart newest first
+	if versions := index.Entries[chart]; len(versions) > 0 {
+		return versions[0].Version, nil
+	}
+	return "", notFoundErrorf("chart %s not found in repository %s", chart, repo.Metadata.Name)
+}
+
+func gatekeeperTemplateApply(ctx *cli.Context) error {
+	if ctx.String("file") == "" {
+		return showSubcommandUsage(ctx)
+	}
+
+	content, err := os.ReadFile(ctx.String("file"))
+	if err != nil {
+		return err
+	}
+	templates, err := decodeManifests(content)
+	if err != nil {
+		return fmt.Errorf("unable to parse %s: %w", ctx.String("file"), err)
+	}
+	for _, template := range templates {
+		if kind, _ := template["kind"].(string); kind != "ConstraintTemplate" {
+			return fmt.Errorf("%s holds a %s, only ConstraintTemplates can be applied", ctx.String("file"), kind)
+		}
+	}
+
+	client, err := getClusterV1Client(ctx, ctx.String("cluster"), constraintTemplateType, gatekeeperFeature)
+	if err != nil {
+		return err
+	}
+
+	for _, template := range templates {
+		metadata, _ := template["metadata"].(map[string]interface{})
+		name, _ := metadata["name"].(string)
+		if name == "" {
+			return fmt.Errorf("a constraint template in %s has no name", ctx.String("file"))
+		}
+		template["type"] = constraintTemplateType
+
+		existing := &ConstraintTemplate{}
+		err := client.ByID(constraintTemplateType, name, existing)
+		switch {
+		case clientbase.IsNotFound(err):
+			if err := client.Create(constraintTemplateType, template, &ConstraintTemplate{}); err != nil {
+				return err
+			}
+			fmt.Printf("constrainttemplate %s created\n", name)
+		case err != nil:
+			return err
+		default:
+			metadata["resourceVersion"] = existing.Metadata.ResourceVersion
+			if err := client.Update(constraintTemplateType, &existing.Resource, template, existing); err != nil {
+				return err
+			}
+			fmt.Printf("constrainttemplate %s configured\n", name)
+		}
+	}
+	return nil
+}
+
+func gatekeeperConstraintLs(ctx *cli.Context) error {
+	client, err := getClusterV1Client(ctx, ctx.String("cluster"), constraintTemplateType, gatekeeperFeature)
+	if err != nil {
+		return err
+	}
+
+	constraints, err := listConstraints(client)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool("violations") {
+		return writeViolations(ctx, constraints)
+	}
+
+	writer := NewTableWriter([][]string{
+		{"KIND", "Kind"},
+		{"NAME", "Name"},
+		{"ENFORCEMENT", "Enforcement"},
+		{"VIOLATIONS", "Violations"},
+		{"LAST-AUDIT", "LastAudit"},
+	}, ctx)
+
+	defer writer.Close()
+
+	for _, item := range constraints {
+		data := &ConstraintData{
+			ID:          item.ID,
+			Constraint:  item,
+			Kind:        item.Kind,
+			Name:        item.Metadata.Name,
+			Enforcement: valueOrDefault(item.Spec.EnforcementAction, "deny"),
+			LastAudit:   item.Status.AuditTimestamp,
+		}
+		if item.Status.TotalViolations != nil {
+			data.Violations = fmt.Sprint(*item.Status.TotalViolations)
+		}
+		writer.Write(data)
+	}
+
+	return writer.Err()
+}
+
+func writeViolations(ctx *cli.Context, constraints []Constraint) error {
+	writer := NewTableWriter([][]string{
+		{"KIND", "Kind"},
+		{"CONSTRAINT", "Constraint"},
+		{"ACTION", "Action"},
+		{"RESOURCE", "Resource"},
+		{"MESSAGE", "Message"},
+	}, ctx)
+
+	defer writer.Close()
+
+	for _, item := range constraints {
+		for _, violation := range item.Status.Violations {
+			writer.Write(&ViolationData{
+				Kind:       item.Kind,
+				Constraint: item.Metadata.Name,
+				Action:     violation.EnforcementAction,
+				Resource:   violationResource(violation),
+				Message:    violation.Message,
+			})
+		}
+	}
+
+	return writer.Err()
+}
+
+// violationResource formats the violating resource as kind/namespace/name
+func violationResource(violation GatekeeperViolation) string {
+	parts := []string{violation.Kind, violation.Namespace, violation.Name}
+	if violation.Namespace == "" {
+		parts = []string{violation.Kind, violation.Name}
+	}
+	return strings.Join(parts, "/")
+}
+
+// constraintTypes returns the types of constraints in schemas, one per
+// constraint template.
+func constraintTypes(schemas map[string]types.Schema) []string {
+	var result []string
+	for schemaType := range schemas {
+		if strings.HasPrefix(schemaType, constraintTypePrefix) {
+			result = append(result, schemaType)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func listConstraints(client *clientbase.APIBaseClient) ([]Constraint, error) {
+	var result []Constraint
+	for _, schemaType := range constraintTypes(client.Types) {
+		collection := &ConstraintCollection{}
+		if err := client.List(schemaType, &types.ListOpts{}, collection); err != nil {
+			return nil, err
+		}
+		for _, item := range collection.Data {
+			if item.Kind == "" {
+				item.Kind = strings.TrimPrefix(schemaType, constraintTypePrefix)
+			}
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
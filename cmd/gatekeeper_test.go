@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/rancher/norman/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeManifests(t *testing.T) {
+	content := []byte(`---
+apiVersion: templates.gatekeeper.sh/v1
+kind: ConstraintTemplate
+metadata:
+  name: k8srequiredlabels
+---
+# only a comment
+---
+{"apiVersion": "templates.gatekeeper.sh/v1", "kind": "ConstraintTemplate", "metadata": {"name": "k8sallowedrepos"}}
+`)
+
+	objects, err := decodeManifests(content)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, "k8srequiredlabels", objects[0]["metadata"].(map[string]interface{})["name"])
+	assert.Equal(t, "k8sallowedrepos", objects[1]["metadata"].(map[string]interface{})["name"])
+
+	_, err = decodeManifests([]byte("kind: [unterminated"))
+	assert.Error(t, err)
+}
+
+func TestConstraintTypes(t *testing.T) {
+	schemas := map[string]types.Schema{
+		"constraints.gatekeeper.sh.k8srequiredlabels": {},
+		"constraints.gatekeeper.sh.k8sallowedrepos":   {},
+		constraintTemplateType:                        {},
+		"pod":                                         {},
+	}
+	assert.Equal(t, []string{
+		"constraints.gatekeeper.sh.k8sallowedrepos",
+		"constraints.gatekeeper.sh.k8srequiredlabels",
+	}, constraintTypes(schemas))
+}
+
+func TestViolationResource(t *testing.T) {
+	assert.Equal(t, "Pod/default/nginx", violationResource(GatekeeperViolation{Kind: "Pod", Namespace: "default", Name: "nginx"}))
+	assert.Equal(t, "Namespace/dev", violationResource(GatekeeperViolation{Kind: "Namespace", Name: "dev"}))
+}
@@ -99,6 +99,12 @@ func GlobalDNSCommand() cli.Command {
 						Flags: []cli.Flag{
 							formatFlag,
 							quietFlag,
+							filterFlag,
+							labelSelectorFlag,
+							sortByFlag,
+							noHeadersFlag,
+							limitFlag,
+							pageSizeFlag,
 						},
 					},
 					{
@@ -177,6 +183,12 @@ func GlobalDNSCommand() cli.Command {
 						Flags: []cli.Flag{
 							formatFlag,
 							quietFlag,
+							filterFlag,
+							labelSelectorFlag,
+							sortByFlag,
+							noHeadersFlag,
+							limitFlag,
+							pageSizeFlag,
 						},
 					},
 					{
@@ -286,12 +298,18 @@ func GlobalDNSCommand() cli.Command {
 }
 
 func globalDNSProviderLs(ctx *cli.Context) error {
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	providers, err := c.ManagementClient.GlobalDnsProvider.List(defaultListOpts(ctx))
+	providers, err := c.ManagementClient.GlobalDnsProvider.List(filteredListOpts(ctx))
+	if err != nil {
+		return err
+	}
+	providers.Data, err = listAll(ctx, providers, func(c *managementClient.GlobalDnsProviderCollection) []managementClient.GlobalDnsProvider {
+		return c.Data
+	})
 	if err != nil {
 		return err
 	}
@@ -316,10 +334,10 @@ func globalDNSProviderLs(ctx *cli.Context) error {
 
 func listGlobalDNSProviderMembers(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -334,10 +352,10 @@ func listGlobalDNSProviderMembers(ctx *cli.Context) error {
 
 func globalDNSProviderCreate(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -414,10 +432,10 @@ func globalDNSProviderCreate(ctx *cli.Context) error {
 
 func globalDNSProviderUpdate(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -481,10 +499,10 @@ func globalDNSProviderUpdate(ctx *cli.Context) error {
 
 func globalDNSProviderDelete(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -505,10 +523,10 @@ func globalDNSProviderDelete(ctx *cli.Context) error {
 
 func addGlobalDNSProviderMembers(ctx *cli.Context) error {
 	if ctx.NArg() < 2 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -532,10 +550,10 @@ func addGlobalDNSProviderMembers(ctx *cli.Context) error {
 
 func deleteGlobalDNSProviderMembers(ctx *cli.Context) error {
 	if ctx.NArg() < 2 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -558,12 +576,16 @@ func deleteGlobalDNSProviderMembers(ctx *cli.Context) error {
 }
 
 func globalDNSLs(ctx *cli.Context) error {
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	entries, err := c.ManagementClient.GlobalDns.List(defaultListOpts(ctx))
+	entries, err := c.ManagementClient.GlobalDns.List(filteredListOpts(ctx))
+	if err != nil {
+		return err
+	}
+	entries.Data, err = listAll(ctx, entries, func(c *managementClient.GlobalDnsCollection) []managementClient.GlobalDns { return c.Data })
 	if err != nil {
 		return err
 	}
@@ -620,10 +642,10 @@ func getEntryTarget(c *cliclient.MasterClient, clusterCache map[string]managemen
 
 func listGlobalDNSMembers(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -637,7 +659,7 @@ func listGlobalDNSMembers(ctx *cli.Context) error {
 }
 
 func globalDNSCreate(ctx *cli.Context) error {
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -701,10 +723,10 @@ func globalDNSCreate(ctx *cli.Context) error {
 
 func globalDNSUpdate(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -765,10 +787,10 @@ func globalDNSUpdate(ctx *cli.Context) error {
 
 func globalDNSDelete(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -789,10 +811,10 @@ func globalDNSDelete(ctx *cli.Context) error {
 
 func addGlobalDNSMembers(ctx *cli.Context) error {
 	if ctx.NArg() < 2 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -815,10 +837,10 @@ func addGlobalDNSMembers(ctx *cli.Context) error {
 
 func deleteGlobalDNSMembers(ctx *cli.Context) error {
 	if ctx.NArg() < 2 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -842,10 +864,10 @@ func deleteGlobalDNSMembers(ctx *cli.Context) error {
 
 func deleteGlobalDNSProjects(ctx *cli.Context) error {
 	if len(ctx.Args()) < 2 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -870,10 +892,10 @@ func deleteGlobalDNSProjects(ctx *cli.Context) error {
 
 func addGlobalDNSProjects(ctx *cli.Context) error {
 	if len(ctx.Args()) < 2 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
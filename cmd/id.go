@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/urfave/cli"
+)
+
+const idDescription = `
+Prints the ID of the resource of TYPE named NAME, found like every command finds the resources
+given by name. A resource that doesn't exist exits with code 3, so that scripts can resolve names
+to IDs without parsing the output of listings.
+
+The resource is looked up in the current context, TYPE is a type of the API such as cluster,
+project, user, app or namespace. A NAME that is already an ID is printed as is.
+
+Example:
+	$ rancher id cluster production
+	c-abc12
+
+	$ rancher --project "$(rancher id project frontend)" apps
+`
+
+func IDCommand() cli.Command {
+	return cli.Command{
+		Name:        "id",
+		Usage:       "Print the ID of a resource given by name",
+		Description: idDescription,
+		ArgsUsage:   "TYPE NAME",
+		Action:      resolveID,
+	}
+}
+
+func resolveID(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return NewUsageError(errors.New("expected the type and the name of the resource"))
+	}
+	resourceType, name := ctx.Args().Get(0), ctx.Args().Get(1)
+
+	// most lookups are of management types, which don't need the schemas of
+	// the cluster and the project
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+	schemaType, err := GetResourceType(c, resourceType)
+	if err != nil {
+		if c, err = GetClient(ctx); err != nil {
+			return err
+		}
+		if schemaType, err = GetResourceType(c, resourceType); err != nil {
+			return NewUsageError(err)
+		}
+	}
+
+	resource, err := Lookup(c, name, schemaType)
+	if err != nil {
+		return err
+	}
+	fmt.Println(resource.ID)
+	return nil
+}
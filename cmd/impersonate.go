@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const impersonateUserHeader = "Impersonate-User"
+
+// userIDPattern matches the IDs of the users of Rancher, the default admin is
+// user-xxxxx and the others u-xxxxx.
+var userIDPattern = regexp.MustCompile(`^u(ser)?-[a-z0-9]+$`)
+
+// impersonation is the user the API requests are sent as with the global --as
+// flag. It is shared by the transports of all clients so that the user is
+// resolved once.
+type impersonation struct {
+	user   string
+	once   sync.Once
+	userID string
+	err    error
+}
+
+// impersonateTransport sends the API requests as another user, so that admins
+// see what the user would see. Before the first request the user is resolved
+// to its ID and the server is checked to honor the impersonation, as a server
+// that doesn't would silently answer as the admin.
+type impersonateTransport struct {
+	next          http.RoundTripper
+	impersonation *impersonation
+}
+
+func newImpersonateTransport(next http.RoundTripper, impersonation *impersonation) http.RoundTripper {
+	return &impersonateTransport{
+		next:          next,
+		impersonation: impersonation,
+	}
+}
+
+func (t *impersonateTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	i := t.impersonation
+	i.once.Do(func() {
+		i.userID, i.err = t.resolve(req)
+	})
+	if i.err != nil {
+		return nil, i.err
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set(impersonateUserHeader, i.userID)
+	return t.next.RoundTrip(req)
+}
+
+// resolve returns the ID of the impersonated user, given by ID or username,
+// once the server has answered a request as that user.
+func (t *impersonateTransport) resolve(req *http.Request) (string, error) {
+	user := t.impersonation.user
+	usersURL := &url.URL{Scheme: req.URL.Scheme, Host: req.URL.Host, Path: "/v3/users"}
+
+	userID := user
+	if !userIDPattern.MatchString(user) {
+		usersURL.RawQuery = url.Values{"username": []string{user}}.Encode()
+		users, err := t.listUsers(req, usersURL, "")
+		if err != nil {
+			return "", fmt.Errorf("looking up user %s: %w", user, err)
+		}
+		if len(users) == 0 {
+			return "", notFoundErrorf("user %s not found", user)
+		}
+		userID = users[0]
+	}
+
+	usersURL.RawQuery = url.Values{"me": []string{"true"}}.Encode()
+	me, err := t.listUsers(req, usersURL, userID)
+	if err != nil {
+		return "", fmt.Errorf("impersonating user %s: %w", user, err)
+	}
+	if len(me) != 1 || me[0] != userID {
+		return "", fmt.Errorf("the server doesn't support impersonating user %s", user)
+	}
+	return userID, nil
+}
+
+// listUsers returns the IDs of the users listed by u with the credentials of
+// req, as the user impersonated if set.
+func (t *impersonateTransport) listUsers(req *http.Request, u *url.URL, impersonated string) ([]string, error) {
+	list, err := http.NewRequestWithContext(req.Context(), http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	list.Header.Set("Accept", "application/json")
+	if auth := req.Header.Get("Authorization"); auth != "" {
+		list.Header.Set("Authorization", auth)
+	}
+	if impersonated != "" {
+		list.Header.Set(impersonateUserHeader, impersonated)
+	}
+
+	resp, err := t.next.RoundTrip(list)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var users struct {
+		Message string `json:"message"`
+		Data    []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	decodeErr := json.Unmarshal(body, &users)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", resp.Status, valueOrDefault(users.Message, strings.TrimSpace(string(body))))
+	}
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+	var ids []string
+	for _, user := range users.Data {
+		ids = append(ids, user.ID)
+	}
+	return ids, nil
+}
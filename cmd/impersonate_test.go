@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImpersonateTransport(t *testing.T) {
+	tt := []struct {
+		name             string
+		user             string
+		honored          bool
+		expectedRequests []string
+		expectedErr      string
+	}{
+		{
+			name:    "user by ID",
+			user:    "u-abc12",
+			honored: true,
+			expectedRequests: []string{
+				"/v3/users?me=true as u-abc12",
+				"/v3/clusters as u-abc12",
+			},
+		},
+		{
+			name:    "user by username",
+			user:    "alice",
+			honored: true,
+			expectedRequests: []string{
+				"/v3/users?username=alice as ",
+				"/v3/users?me=true as u-abc12",
+				"/v3/clusters as u-abc12",
+			},
+		},
+		{
+			name: "unknown user",
+			user: "bob",
+			expectedRequests: []string{
+				"/v3/users?username=bob as ",
+			},
+			expectedErr: "user bob not found",
+		},
+		{
+			name: "impersonation not honored",
+			user: "u-abc12",
+			expectedRequests: []string{
+				"/v3/users?me=true as u-abc12",
+			},
+			expectedErr: "the server doesn't support impersonating user u-abc12",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests []string
+			next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				impersonated := req.Header.Get(impersonateUserHeader)
+				requests = append(requests, req.URL.RequestURI()+" as "+impersonated)
+				assert.Equal(t, "Basic secret", req.Header.Get("Authorization"))
+
+				body := `{"data":[]}`
+				switch {
+				case req.URL.Query().Get("username") == "alice":
+					body = `{"data":[{"id":"u-abc12"}]}`
+				case req.URL.Query().Get("me") == "true" && tc.honored:
+					body = `{"data":[{"id":"` + impersonated + `"}]}`
+				case req.URL.Query().Get("me") == "true":
+					body = `{"data":[{"id":"user-admin"}]}`
+				}
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+			})
+			transport := newImpersonateTransport(next, &impersonation{user: tc.user})
+
+			req, err := http.NewRequest(http.MethodGet, "https://rancher/v3/clusters", nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Basic secret")
+			_, err = transport.RoundTrip(req)
+
+			assert.Equal(t, tc.expectedRequests, requests)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Empty(t, req.Header.Get(impersonateUserHeader))
+		})
+	}
+}
@@ -1,11 +1,20 @@
 package cmd
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
 	"strings"
 
+	"github.com/rancher/cli/cliclient"
 	"github.com/urfave/cli"
+	"k8s.io/client-go/util/jsonpath"
 )
 
+// unfollowedLinks are links that don't lead to related resources
+var unfollowedLinks = map[string]bool{"self": true, "remove": true, "update": true}
+
 func InspectCommand() cli.Command {
 	return cli.Command{
 		Name:  "inspect",
@@ -21,6 +30,12 @@ Examples:
 
 	# Inspect a project and get the output in yaml format with the projects links
 	$ rancher inspect --type project --format yaml --links projectFoo
+
+	# Print a single field
+	$ rancher inspect --type cluster --jsonpath '{.conditions[?(@.type=="Ready")].status}' clusterFoo
+
+	# Embed the nodes of a cluster, or with '*' every linked resource
+	$ rancher inspect --type cluster --embed nodes clusterFoo
 `,
 		ArgsUsage: "[RESOURCEID RESOURCENAME]",
 		Action:    inspectResources,
@@ -38,13 +53,21 @@ Examples:
 				Usage: "'json', 'yaml' or Custom format: '{{.kind}}'",
 				Value: "json",
 			},
+			cli.StringFlag{
+				Name:  "jsonpath",
+				Usage: "Only print the fields selected by a JSONPath expression, e.g. '{.state}'",
+			},
+			cli.StringSliceFlag{
+				Name:  "embed",
+				Usage: "Follow a link of the resource and embed the linked resources under _embedded, '*' for every link",
+			},
 		},
 	}
 }
 
 func inspectResources(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowCommandHelp(ctx, "inspect")
+		return showCommandUsage(ctx, "inspect")
 	}
 
 	c, err := GetClient(ctx)
@@ -75,13 +98,78 @@ func inspectResources(ctx *cli.Context) error {
 		return err
 	}
 
+	if embed := ctx.StringSlice("embed"); len(embed) > 0 {
+		if err := embedLinks(c, mapResource, embed); err != nil {
+			return err
+		}
+	}
+
 	if !ctx.Bool("links") {
 		delete(mapResource, "links")
 		delete(mapResource, "actions")
 	}
+
+	if ctx.String("jsonpath") != "" {
+		return printJSONPath(os.Stdout, ctx.String("jsonpath"), mapResource)
+	}
+
 	writer := NewTableWriter(nil, ctx)
 	writer.Write(mapResource)
 	writer.Close()
 
 	return writer.Err()
 }
+
+// embedLinks follows the named links of a resource, or all of them for "*",
+// and adds the linked resources to it under _embedded. The items of linked
+// collections are embedded as a list.
+func embedLinks(c *cliclient.MasterClient, resource map[string]interface{}, names []string) error {
+	links, _ := resource["links"].(map[string]interface{})
+	if len(names) == 1 && names[0] == "*" {
+		names = nil
+		for name := range links {
+			if !unfollowedLinks[name] {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+	}
+
+	embedded := map[string]interface{}{}
+	for _, name := range names {
+		url, _ := links[name].(string)
+		if url == "" {
+			return fmt.Errorf("the resource has no link %q", name)
+		}
+		linked := map[string]interface{}{}
+		if err := c.ManagementClient.Ops.DoGet(url, nil, &linked); err != nil {
+			return fmt.Errorf("following link %s: %w", name, err)
+		}
+		if data, ok := linked["data"]; ok && linked["type"] == "collection" {
+			embedded[name] = data
+			continue
+		}
+		delete(linked, "links")
+		delete(linked, "actions")
+		embedded[name] = linked
+	}
+	resource["_embedded"] = embedded
+	return nil
+}
+
+// printJSONPath prints the fields of a resource selected by a kubectl style
+// JSONPath expression, the braces around it are optional.
+func printJSONPath(out io.Writer, expression string, resource map[string]interface{}) error {
+	if !strings.HasPrefix(expression, "{") {
+		expression = "{" + expression + "}"
+	}
+	path := jsonpath.New("inspect")
+	if err := path.Parse(expression); err != nil {
+		return NewUsageError(fmt.Errorf("invalid JSONPath %q: %w", expression, err))
+	}
+	if err := path.Execute(out, resource); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(out)
+	return err
+}
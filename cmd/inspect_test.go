@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintJSONPath(t *testing.T) {
+	resource := map[string]interface{}{
+		"name": "prod",
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Provisioned", "status": "True"},
+			map[string]interface{}{"type": "Ready", "status": "False"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		want       string
+		wantErr    bool
+	}{
+		{name: "field", expression: "{.name}", want: "prod\n"},
+		{name: "without braces", expression: ".name", want: "prod\n"},
+		{name: "filter", expression: `{.conditions[?(@.type=="Ready")].status}`, want: "False\n"},
+		{name: "missing field", expression: "{.missing}", wantErr: true},
+		{name: "invalid expression", expression: "{.conditions[", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := printJSONPath(out, tt.expression, resource)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rancher/cli/cliclient"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	projectClient "github.com/rancher/rancher/pkg/client/generated/project/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+var installOrUpgradeFlag = cli.BoolFlag{
+	Name:  "install-or-upgrade",
+	Usage: "Upgrade the app with the version and answers given when an app with the name is already installed, instead of failing",
+}
+
+// errInstallOrUpgradeName is returned when --install-or-upgrade is set without
+// an app name, as the app to upgrade is found by name.
+var errInstallOrUpgradeName = NewUsageError(errors.New("--install-or-upgrade needs the name of the app"))
+
+// checkSameTemplate fails when an installed app of name is asked to be
+// upgraded to a version of another template than its own.
+func checkSameTemplate(name, currentExternalID, externalID string) error {
+	if currentExternalID == "" || externalID == "" {
+		return nil
+	}
+	current, err := parseExternalID(currentExternalID)
+	if err != nil {
+		return err
+	}
+	target, err := parseExternalID(externalID)
+	if err != nil {
+		return err
+	}
+	if current["catalog"] != target["catalog"] || current["template"] != target["template"] {
+		return fmt.Errorf("app %s is installed from template %s/%s, not %s/%s", name,
+			current["catalog"], current["template"], target["catalog"], target["template"])
+	}
+	return nil
+}
+
+// findApp returns the app of the current project named name, or nil
+func findApp(c *cliclient.MasterClient, name string) (*projectClient.App, error) {
+	filter := defaultListOpts(nil)
+	filter.Filters["name"] = name
+	apps, err := c.ProjectClient.App.List(filter)
+	if err != nil || len(apps.Data) == 0 {
+		return nil, err
+	}
+	return &apps.Data[0], nil
+}
+
+// upgradeInstalledApp upgrades the existing app with the template version,
+// answers and values app would have been installed with.
+func upgradeInstalledApp(ctx *cli.Context, c *cliclient.MasterClient, existing, app *projectClient.App, output string) error {
+	if namespace := ctx.String("namespace"); namespace != "" && namespace != existing.TargetNamespace {
+		return NewUsageError(fmt.Errorf("app %s is installed in namespace %s, not %s", existing.Name, existing.TargetNamespace, namespace))
+	}
+	if app.ExternalID != "" && existing.ExternalID != "" {
+		if err := checkSameTemplate(existing.Name, existing.ExternalID, app.ExternalID); err != nil {
+			return err
+		}
+		current, err := templateVersionByExternalID(c, existing.ExternalID)
+		if err != nil {
+			return err
+		}
+		target, err := templateVersionByExternalID(c, app.ExternalID)
+		if err != nil {
+			return err
+		}
+		if err := checkUpgradeVersion(current, target, false); err != nil {
+			return err
+		}
+	}
+
+	if err := checkPermissions(ctx, projectPermission(c, projectClient.AppType, verbUpdate)); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	err := c.ProjectClient.App.ActionUpgrade(existing, &projectClient.AppUpgradeConfig{
+		Answers:          app.Answers,
+		AnswersSetString: app.AnswersSetString,
+		ValuesYaml:       app.ValuesYaml,
+		ExternalID:       app.ExternalID,
+		Files:            app.Files,
+	})
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		fmt.Printf("App %q is already installed, upgrading it...\n", existing.Name)
+	}
+
+	if ctx.Bool("wait") {
+		err = waitForResource(c, &existing.Resource, time.Duration(ctx.Int("wait-timeout"))*time.Second)
+	}
+	if output != "" {
+		return writeInstallResult(os.Stdout, c, output, &existing.Resource, start, err)
+	}
+	return err
+}
+
+// findMultiClusterApp returns the multi-cluster app named name, or nil
+func findMultiClusterApp(c *cliclient.MasterClient, name string) (*managementClient.MultiClusterApp, error) {
+	filter := defaultListOpts(nil)
+	filter.Filters["name"] = name
+	apps, err := c.ManagementClient.MultiClusterApp.List(filter)
+	if err != nil || len(apps.Data) == 0 {
+		return nil, err
+	}
+	return &apps.Data[0], nil
+}
+
+// upgradeInstalledMultiClusterApp upgrades the existing multi-cluster app with
+// the template version and answers app would have been installed with. Its
+// roles and upgrade strategy are changed only when given, and its targets are
+// kept.
+func upgradeInstalledMultiClusterApp(ctx *cli.Context, c *cliclient.MasterClient, existing, app *managementClient.MultiClusterApp) (*managementClient.MultiClusterApp, error) {
+	current, err := c.ManagementClient.TemplateVersion.ByID(existing.TemplateVersionID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := c.ManagementClient.TemplateVersion.ByID(app.TemplateVersionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSameTemplate(existing.Name, current.ExternalID, target.ExternalID); err != nil {
+		return nil, err
+	}
+	if err := checkUpgradeVersion(current, target, false); err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, requested := range app.Targets {
+		found := false
+		for _, target := range existing.Targets {
+			found = found || target.ProjectID == requested.ProjectID
+		}
+		if !found {
+			missing = append(missing, requested.ProjectID)
+		}
+	}
+	if len(missing) > 0 {
+		logrus.Warnf("%s aren't targets of multi-cluster app %s, add them with 'rancher mcapp add-project'", strings.Join(missing, ", "), existing.Name)
+	}
+
+	update := map[string]interface{}{
+		"templateVersionId": app.TemplateVersionID,
+		"answers":           app.Answers,
+		"roles":             existing.Roles,
+	}
+	if ctx.IsSet("role") {
+		update["roles"] = app.Roles
+	}
+	if ctx.IsSet(argUpgradeStrategy) {
+		update["upgradeStrategy"] = app.UpgradeStrategy
+	}
+	return c.ManagementClient.MultiClusterApp.Update(existing, update)
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckSameTemplate(t *testing.T) {
+	tt := []struct {
+		name              string
+		currentExternalID string
+		externalID        string
+		expectedErr       string
+	}{
+		{
+			name:              "newer version of the template",
+			currentExternalID: "catalog://?catalog=library&template=redis&version=1.0.1",
+			externalID:        "catalog://?catalog=library&template=redis&version=1.0.2",
+		},
+		{
+			name:              "other template",
+			currentExternalID: "catalog://?catalog=library&template=redis&version=1.0.1",
+			externalID:        "catalog://?catalog=library&template=mysql&version=1.0.2",
+			expectedErr:       "app appFoo is installed from template library/redis, not library/mysql",
+		},
+		{
+			name:              "other catalog",
+			currentExternalID: "catalog://?catalog=library&template=redis&version=1.0.1",
+			externalID:        "catalog://?catalog=custom&template=redis&version=1.0.1",
+			expectedErr:       "app appFoo is installed from template library/redis, not custom/redis",
+		},
+		{
+			name:       "installed from a local chart",
+			externalID: "catalog://?catalog=library&template=redis&version=1.0.2",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkSameTemplate("appFoo", tc.currentExternalID, tc.externalID)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
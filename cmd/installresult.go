@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/rancher/cli/cliclient"
+	ntypes "github.com/rancher/norman/types"
+	"github.com/urfave/cli"
+)
+
+var installOutputFlag = cli.StringFlag{
+	Name:  "output",
+	Usage: "Print the result of the install as 'json' or 'yaml' once it finishes, for CI systems to archive",
+}
+
+// InstallResult is printed with --output when an install finishes, whether
+// the app became active, failed or the wait timed out.
+type InstallResult struct {
+	ID             string         `json:"id"`
+	Type           string         `json:"type"`
+	Name           string         `json:"name,omitempty"`
+	State          string         `json:"state,omitempty"`
+	Message        string         `json:"message,omitempty"`
+	Targets        []TargetResult `json:"targets,omitempty"`
+	ElapsedSeconds float64        `json:"elapsedSeconds"`
+	Error          string         `json:"error,omitempty"`
+}
+
+// TargetResult is the status of a target project of a multi-cluster app
+type TargetResult struct {
+	ProjectID   string `json:"projectId"`
+	AppID       string `json:"appId,omitempty"`
+	State       string `json:"state,omitempty"`
+	HealthState string `json:"healthState,omitempty"`
+}
+
+// installOutputFormat returns the --output format of an install command
+func installOutputFormat(ctx *cli.Context) (string, error) {
+	switch format := ctx.String("output"); format {
+	case "", "json", "yaml":
+		return format, nil
+	default:
+		return "", NewUsageError(fmt.Errorf("invalid output %q, expected json or yaml", format))
+	}
+}
+
+// writeInstallResult prints the result of the install of resource, started
+// at start, in format. waitErr is the error of waiting for the install and is
+// returned once the result is printed so that the exit code reflects it.
+func writeInstallResult(out io.Writer, c *cliclient.MasterClient, format string, resource *ntypes.Resource, start time.Time, waitErr error) error {
+	data := map[string]interface{}{}
+	// nothing was created with --dry-run
+	if !c.DryRun {
+		if err := c.ByID(resource, &data); err != nil && waitErr == nil {
+			waitErr = err
+		}
+	}
+
+	result := newInstallResult(resource, data, time.Since(start), waitErr)
+	var content []byte
+	var err error
+	if format == "yaml" {
+		content, err = yaml.Marshal(result)
+	} else {
+		content, err = json.MarshalIndent(result, "", "  ")
+		content = append(content, '\n')
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := out.Write(content); err != nil {
+		return err
+	}
+	return waitErr
+}
+
+// newInstallResult returns the result of an install from the decoded
+// resource.
+func newInstallResult(resource *ntypes.Resource, data map[string]interface{}, elapsed time.Duration, waitErr error) InstallResult {
+	result := InstallResult{
+		ID:             resource.ID,
+		Type:           resource.Type,
+		ElapsedSeconds: elapsed.Round(time.Millisecond).Seconds(),
+	}
+	result.Name, _ = data["name"].(string)
+	result.State, _ = data["state"].(string)
+	result.Message = transitioningMessage(data)
+	if waitErr != nil {
+		result.Error = waitErr.Error()
+	}
+
+	targets, _ := data["targets"].([]interface{})
+	for _, t := range targets {
+		target, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var tr TargetResult
+		tr.ProjectID, _ = target["projectId"].(string)
+		tr.AppID, _ = target["appId"].(string)
+		tr.State, _ = target["state"].(string)
+		tr.HealthState, _ = target["healthState"].(string)
+		result.Targets = append(result.Targets, tr)
+	}
+	sort.Slice(result.Targets, func(i, j int) bool {
+		return result.Targets[i].ProjectID < result.Targets[j].ProjectID
+	})
+	return result
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	ntypes "github.com/rancher/norman/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewInstallResult(t *testing.T) {
+	resource := &ntypes.Resource{ID: "cattle-global-data:mcapp-redis", Type: "multiClusterApp"}
+	data := map[string]interface{}{
+		"name":                 "redis",
+		"state":                "deploying",
+		"transitioningMessage": "waiting for targets",
+		"targets": []interface{}{
+			map[string]interface{}{"projectId": "c-2:p-2", "appId": "p-2:redis", "state": "error"},
+			map[string]interface{}{"projectId": "c-1:p-1", "appId": "p-1:redis", "state": "active", "healthState": "healthy"},
+		},
+	}
+
+	result := newInstallResult(resource, data, 1500*time.Millisecond, partialErrorf("1 of 2 targets failed: c-2:p-2"))
+
+	assert.Equal(t, InstallResult{
+		ID:      "cattle-global-data:mcapp-redis",
+		Type:    "multiClusterApp",
+		Name:    "redis",
+		State:   "deploying",
+		Message: "waiting for targets",
+		Targets: []TargetResult{
+			{ProjectID: "c-1:p-1", AppID: "p-1:redis", State: "active", HealthState: "healthy"},
+			{ProjectID: "c-2:p-2", AppID: "p-2:redis", State: "error"},
+		},
+		ElapsedSeconds: 1.5,
+		Error:          "1 of 2 targets failed: c-2:p-2",
+	}, result)
+
+	result = newInstallResult(&ntypes.Resource{ID: "p-1:redis", Type: "app"}, map[string]interface{}{"name": "redis", "state": "active"}, time.Second, nil)
+	assert.Empty(t, result.Targets)
+	assert.Empty(t, result.Error)
+	assert.Equal(t, "active", result.State)
+}
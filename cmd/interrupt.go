@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/urfave/cli"
+)
+
+var cleanupOnCancelFlag = cli.BoolFlag{
+	Name:  "cleanup-on-cancel",
+	Usage: "Delete the resources created by the command when it's interrupted with Ctrl-C before it completes",
+}
+
+// interrupt cancels the API requests and the waits of a command on Ctrl-C
+// while it's watched, around the long running operations such as installs,
+// waits and shells. A second Ctrl-C exits right away, as does a Ctrl-C while
+// it isn't watched, at prompts for example.
+type interrupt struct {
+	lock     sync.Mutex
+	ctx      context.Context
+	cancel   context.CancelFunc
+	watching int
+	signals  chan os.Signal
+}
+
+var commandInterrupt = newInterrupt()
+
+func newInterrupt() *interrupt {
+	i := &interrupt{}
+	i.reset()
+	return i
+}
+
+// watch cancels the context of the command on the first Ctrl-C until stop is
+// called. Watches nest, Ctrl-C is handled until the outermost one stops.
+func (i *interrupt) watch() (stop func()) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.watching++
+	if i.watching == 1 {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt)
+		i.signals = signals
+		go func() {
+			if _, ok := <-signals; !ok {
+				return
+			}
+			// restores the default handling, which exits
+			signal.Stop(signals)
+			fmt.Fprintln(os.Stderr, "Interrupted, press Ctrl-C again to exit immediately")
+			i.lock.Lock()
+			defer i.lock.Unlock()
+			i.cancel()
+		}()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			i.lock.Lock()
+			defer i.lock.Unlock()
+			if i.watching--; i.watching == 0 {
+				signal.Stop(i.signals)
+				close(i.signals)
+			}
+		})
+	}
+}
+
+// context returns the context of the command, canceled on Ctrl-C.
+func (i *interrupt) context() context.Context {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return i.ctx
+}
+
+// reset replaces a canceled context so that the requests cleaning up after
+// an interruption can be sent.
+func (i *interrupt) reset() {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.ctx, i.cancel = context.WithCancel(context.Background())
+}
+
+// interruptTransport aborts the API requests in flight when the command is
+// interrupted.
+type interruptTransport struct {
+	next      http.RoundTripper
+	interrupt *interrupt
+}
+
+func newInterruptTransport(next http.RoundTripper, interrupt *interrupt) http.RoundTripper {
+	return &interruptTransport{
+		next:      next,
+		interrupt: interrupt,
+	}
+}
+
+func (t *interruptTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// the context of the request holds the timeout of the client
+	ctx, cancel := context.WithCancel(req.Context())
+	stop := context.AfterFunc(t.interrupt.context(), cancel)
+	release := func() {
+		stop()
+		cancel()
+	}
+
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil || resp.Body == nil {
+		release()
+		return resp, err
+	}
+	// the request is done once its response is read
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// isInterrupted reports whether err is caused by a Ctrl-C.
+func isInterrupted(err error) bool {
+	var coded *codedError
+	if errors.As(err, &coded) && coded.code == ExitCodeInterrupted {
+		return true
+	}
+	return errors.Is(err, context.Canceled)
+}
+
+// createdResources records the resources created by a command, to report
+// them, or delete them with --cleanup-on-cancel, when the command is
+// interrupted before it completes.
+type createdResources struct {
+	resources []createdResource
+}
+
+type createdResource struct {
+	kind   string
+	name   string
+	delete func() error
+}
+
+func (r *createdResources) add(kind, name string, delete func() error) {
+	r.resources = append(r.resources, createdResource{kind: kind, name: name, delete: delete})
+}
+
+// interrupted handles err, the error of the command: when it's an
+// interruption the created resources are printed, or deleted in the reverse
+// order of their creation with cleanup, and the requests of the command can
+// be sent again. err is returned.
+func (r *createdResources) interrupted(out io.Writer, err error, cleanup bool) error {
+	if !isInterrupted(err) || len(r.resources) == 0 {
+		return err
+	}
+
+	// a second Ctrl-C exits while the requests below are sent
+	commandInterrupt.reset()
+	if !cleanup {
+		fmt.Fprintln(out, "The command was interrupted after creating:")
+		for _, resource := range r.resources {
+			fmt.Fprintf(out, "  %s %s\n", resource.kind, resource.name)
+		}
+		fmt.Fprintln(out, "Use --cleanup-on-cancel to delete them on Ctrl-C")
+		return err
+	}
+
+	for i := len(r.resources) - 1; i >= 0; i-- {
+		resource := r.resources[i]
+		if deleteErr := resource.delete(); deleteErr != nil {
+			fmt.Fprintf(out, "Failed to delete %s %s: %v\n", resource.kind, resource.name, deleteErr)
+			continue
+		}
+		fmt.Fprintf(out, "Deleted %s %s\n", resource.kind, resource.name)
+	}
+	return err
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterruptTransport(t *testing.T) {
+	i := newInterrupt()
+	started := make(chan struct{})
+	transport := newInterruptTransport(fakeRoundTripper(func(req *http.Request) (*http.Response, error) {
+		close(started)
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}), i)
+
+	go func() {
+		<-started
+		i.cancel()
+	}()
+	req, err := http.NewRequest(http.MethodGet, "https://rancher/v3/clusters", nil)
+	require.NoError(t, err)
+	_, err = transport.RoundTrip(req)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.True(t, isInterrupted(err))
+}
+
+func TestCreatedResourcesInterrupted(t *testing.T) {
+	var deleted []string
+	created := &createdResources{}
+	for _, name := range []string{"wordpress-abcde", "wordpress"} {
+		created.add("resource", name, func() error {
+			deleted = append(deleted, name)
+			return nil
+		})
+	}
+
+	var out bytes.Buffer
+	other := errors.New("boom")
+	assert.Equal(t, other, created.interrupted(&out, other, true))
+	assert.Empty(t, out.String())
+	assert.Empty(t, deleted)
+
+	interrupted := interruptedErrorf("interrupted waiting")
+	assert.Equal(t, interrupted, created.interrupted(&out, interrupted, false))
+	assert.Equal(t, `The command was interrupted after creating:
+  resource wordpress-abcde
+  resource wordpress
+Use --cleanup-on-cancel to delete them on Ctrl-C
+`, out.String())
+	assert.Empty(t, deleted)
+
+	out.Reset()
+	assert.Equal(t, interrupted, created.interrupted(&out, interrupted, true))
+	assert.Equal(t, []string{"wordpress", "wordpress-abcde"}, deleted)
+	assert.Equal(t, "Deleted resource wordpress\nDeleted resource wordpress-abcde\n", out.String())
+}
+
+func TestInterruptWatch(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("interrupts can't be sent to the process on Windows")
+	}
+	i := newInterrupt()
+	stopOuter := i.watch()
+	stopInner := i.watch()
+	stopInner()
+	stopInner()
+	assert.Equal(t, 1, i.watching, "stopping a nested watch keeps handling Ctrl-C")
+
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, process.Signal(os.Interrupt))
+	select {
+	case <-i.context().Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Ctrl-C didn't cancel the context")
+	}
+
+	stopOuter()
+	assert.Equal(t, 0, i.watching)
+}
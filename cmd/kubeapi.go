@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/rancher/norman/clientbase"
+	"github.com/urfave/cli"
+)
+
+const kubeAPIDescription = `
+Sends a request to the Kubernetes API of a cluster through the proxy of the Rancher server,
+authenticated with the token of the CLI, and prints the response. No kubeconfig is generated,
+so that the API can be read where generating kubeconfigs isn't allowed. The permissions are
+those of the user in the cluster.
+
+The method defaults to GET. The body of the request is read from --file, YAML is sent as
+JSON; PATCH requests are merge patches unless --content-type says otherwise. A response
+other than 2xx is an error, with exit code 3 for 404 and 4 for 401 and 403.
+
+Example:
+	$ rancher kubeapi production -- GET /api/v1/nodes
+	$ rancher kubeapi production /apis/apps/v1/namespaces/default/deployments/web | jq .status
+	$ rancher kubeapi --file patch.yaml production -- PATCH /apis/apps/v1/namespaces/default/deployments/web
+`
+
+// kubeAPIMethods are the methods of requests kubeapi sends
+var kubeAPIMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+func KubeAPICommand() cli.Command {
+	return cli.Command{
+		Name:        "kubeapi",
+		Usage:       "Send a request to the Kubernetes API of a cluster through Rancher",
+		Description: kubeAPIDescription,
+		ArgsUsage:   "CLUSTER [--] [METHOD] PATH",
+		Action:      kubeAPIRequest,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "file,f",
+				Usage: "Path to the body of the request, JSON or YAML. Use - to read it from stdin",
+			},
+			cli.StringFlag{
+				Name:  "content-type",
+				Usage: "Content type of the body, defaults to application/json or application/merge-patch+json for PATCH",
+			},
+		},
+	}
+}
+
+// parseKubeAPIArgs returns the cluster, the method and the path of the
+// arguments of kubeapi, which may hold a -- before the method.
+func parseKubeAPIArgs(args []string) (string, string, string, error) {
+	var rest []string
+	for i, arg := range args {
+		if arg == "--" {
+			rest = append(rest, args[i+1:]...)
+			break
+		}
+		rest = append(rest, arg)
+	}
+
+	var cluster, method, path string
+	switch len(rest) {
+	case 2:
+		cluster, method, path = rest[0], http.MethodGet, rest[1]
+	case 3:
+		cluster, method, path = rest[0], strings.ToUpper(rest[1]), rest[2]
+	default:
+		return "", "", "", errors.New("expected the cluster, the method and the path of the request")
+	}
+	if !slices.Contains(kubeAPIMethods, method) {
+		return "", "", "", fmt.Errorf("invalid method %q, expected one of %s", method, strings.Join(kubeAPIMethods, ", "))
+	}
+	if !strings.HasPrefix(path, "/") {
+		return "", "", "", fmt.Errorf("invalid path %q, expected an absolute path such as /api/v1/nodes", path)
+	}
+	return cluster, method, path, nil
+}
+
+func kubeAPIRequest(ctx *cli.Context) error {
+	cluster, method, path, err := parseKubeAPIArgs(ctx.Args())
+	if err != nil {
+		return NewUsageError(err)
+	}
+
+	var body io.Reader
+	contentType := ctx.String("content-type")
+	if file := ctx.String("file"); file != "" {
+		var content []byte
+		if file == "-" {
+			content, err = io.ReadAll(os.Stdin)
+		} else {
+			content, err = os.ReadFile(file)
+		}
+		if err != nil {
+			return err
+		}
+		if contentType == "" {
+			// YAML is a superset of JSON
+			if content, err = yaml.YAMLToJSON(content); err != nil {
+				return NewUsageError(fmt.Errorf("invalid body in %s: %w", file, err))
+			}
+		}
+		body = bytes.NewReader(content)
+	}
+	if contentType == "" {
+		contentType = "application/json"
+		if method == http.MethodPatch {
+			contentType = "application/merge-patch+json"
+		}
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+	clusterID, err := resolveClusterID(c, cluster)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/k8s/clusters/%s%s", strings.TrimSuffix(c.UserConfig.URL, "/v3"), clusterID, path)
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	c.ManagementClient.Ops.SetupRequest(req)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.ManagementClient.Ops.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		content, _ := io.ReadAll(resp.Body)
+		return kubeAPIError(method, url, resp.StatusCode, resp.Status, content)
+	}
+	_, err = io.Copy(os.Stdout, resp.Body)
+	return err
+}
+
+// kubeAPIError returns the error of a failed request, with the message of the
+// Status the Kubernetes API answers with when there is one.
+func kubeAPIError(method, url string, statusCode int, status string, content []byte) error {
+	message := strings.TrimSpace(string(content))
+	var kubeStatus struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(content, &kubeStatus) == nil && kubeStatus.Message != "" {
+		message = kubeStatus.Message
+	}
+	return &clientbase.APIError{
+		StatusCode: statusCode,
+		URL:        url,
+		Msg:        fmt.Sprintf("%s %s: %s", method, status, valueOrDefault(message, "no message")),
+		Status:     status,
+		Body:       string(content),
+	}
+}
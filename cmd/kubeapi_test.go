@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKubeAPIArgs(t *testing.T) {
+	tt := []struct {
+		name            string
+		args            []string
+		expectedCluster string
+		expectedMethod  string
+		expectedPath    string
+		expectedErr     string
+	}{
+		{
+			name:            "method after --",
+			args:            []string{"production", "--", "GET", "/api/v1/nodes"},
+			expectedCluster: "production",
+			expectedMethod:  http.MethodGet,
+			expectedPath:    "/api/v1/nodes",
+		},
+		{
+			name:            "lowercase method",
+			args:            []string{"c-abc12", "delete", "/api/v1/namespaces/default/pods/web-0"},
+			expectedCluster: "c-abc12",
+			expectedMethod:  http.MethodDelete,
+			expectedPath:    "/api/v1/namespaces/default/pods/web-0",
+		},
+		{
+			name:            "method defaults to GET",
+			args:            []string{"production", "/version"},
+			expectedCluster: "production",
+			expectedMethod:  http.MethodGet,
+			expectedPath:    "/version",
+		},
+		{
+			name:        "invalid method",
+			args:        []string{"production", "--", "FETCH", "/api/v1/nodes"},
+			expectedErr: `invalid method "FETCH", expected one of GET, HEAD, POST, PUT, PATCH, DELETE`,
+		},
+		{
+			name:        "relative path",
+			args:        []string{"production", "api/v1/nodes"},
+			expectedErr: `invalid path "api/v1/nodes", expected an absolute path such as /api/v1/nodes`,
+		},
+		{
+			name:        "missing path",
+			args:        []string{"production"},
+			expectedErr: "expected the cluster, the method and the path of the request",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster, method, path, err := parseKubeAPIArgs(tc.args)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedCluster, cluster)
+			assert.Equal(t, tc.expectedMethod, method)
+			assert.Equal(t, tc.expectedPath, path)
+		})
+	}
+}
+
+func TestKubeAPIError(t *testing.T) {
+	err := kubeAPIError(http.MethodGet, "https://rancher/k8s/clusters/c-abc12/api/v1/nodes/n1", http.StatusNotFound, "404 Not Found",
+		[]byte(`{"kind":"Status","status":"Failure","message":"nodes \"n1\" not found","code":404}`))
+
+	assert.EqualError(t, err, `GET 404 Not Found: nodes "n1" not found`)
+	assert.Equal(t, ExitCodeNotFound, ExitCode(err))
+}
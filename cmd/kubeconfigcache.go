@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+const kubeconfigCacheDir = "kubeconfigs"
+
+// kubeconfigCacheMaxTTL is how long a kubeconfig is used without checking its
+// token, so that a revoked token is noticed even when it never expires.
+const kubeconfigCacheMaxTTL = time.Hour
+
+// kubeconfigCacheExpiryMargin keeps a kubeconfig from being used right until
+// its token expires, as kubectl may run for a while.
+const kubeconfigCacheExpiryMargin = time.Minute
+
+type kubeconfigCacheEntry struct {
+	Expires time.Time `json:"expires"`
+}
+
+// kubeconfigCache keeps on disk the kubeconfigs generated for kubectl, one per
+// server, user and cluster, so that consecutive kubectl commands run without
+// any request to the server until their token expires.
+type kubeconfigCache struct {
+	dir string
+	now func() time.Time
+}
+
+func kubeconfigCachePath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), kubeconfigCacheDir)
+}
+
+func newKubeconfigCache(configPath string) *kubeconfigCache {
+	return &kubeconfigCache{dir: kubeconfigCachePath(configPath), now: time.Now}
+}
+
+// kubeconfigCacheKey identifies the kubeconfig of a cluster for the access key
+// of a server and the user impersonated with --as, if any, without putting the
+// access key in a file name.
+func kubeconfigCacheKey(server, accessKey, impersonated, cluster string) string {
+	sum := sha256.Sum256([]byte(server + lookupCacheKeySep + accessKey + lookupCacheKeySep + impersonated + lookupCacheKeySep + cluster))
+	return hex.EncodeToString(sum[:16])
+}
+
+func (k *kubeconfigCache) paths(key string) (string, string) {
+	return filepath.Join(k.dir, key+".yaml"), filepath.Join(k.dir, key+".json")
+}
+
+// get returns the path of the cached kubeconfig of key, if it hasn't expired.
+func (k *kubeconfigCache) get(key string) (string, bool) {
+	kubeconfigPath, entryPath := k.paths(key)
+	content, err := os.ReadFile(entryPath)
+	if err != nil {
+		return "", false
+	}
+	entry := kubeconfigCacheEntry{}
+	if err := json.Unmarshal(content, &entry); err != nil || !k.now().Before(entry.Expires) {
+		return "", false
+	}
+	if _, err := os.Stat(kubeconfigPath); err != nil {
+		return "", false
+	}
+	return kubeconfigPath, true
+}
+
+// put caches the kubeconfig of key until expires and returns its path.
+// Failing to cache it isn't fatal, a temporary file is used instead.
+func (k *kubeconfigCache) put(key string, kubeConfig api.Config, expires time.Time) (string, error) {
+	kubeconfigPath, entryPath := k.paths(key)
+	if err := os.MkdirAll(k.dir, 0700); err != nil {
+		return "", err
+	}
+	if err := writeFileAtomic(kubeconfigPath, func(path string) error {
+		return clientcmd.WriteToFile(kubeConfig, path)
+	}); err != nil {
+		return "", err
+	}
+	content, err := json.Marshal(kubeconfigCacheEntry{Expires: expires})
+	if err != nil {
+		return "", err
+	}
+	if err := writeFileAtomic(entryPath, func(path string) error {
+		return os.WriteFile(path, content, 0600)
+	}); err != nil {
+		return "", err
+	}
+	return kubeconfigPath, nil
+}
+
+// expiry returns until when a kubeconfig whose token is token may be used
+func (k *kubeconfigCache) expiry(token *client.Token) time.Time {
+	expires := k.now().Add(kubeconfigCacheMaxTTL)
+	if token.ExpiresAt == "" {
+		return expires
+	}
+	tokenExpires, err := time.Parse(time.RFC3339, token.ExpiresAt)
+	if err != nil {
+		logrus.Debugf("Ignoring the invalid expiry %q of token %s: %v", token.ExpiresAt, token.ID, err)
+		return expires
+	}
+	if tokenExpires = tokenExpires.Add(-kubeconfigCacheExpiryMargin); tokenExpires.Before(expires) {
+		return tokenExpires
+	}
+	return expires
+}
+
+func (k *kubeconfigCache) clear() error {
+	return os.RemoveAll(k.dir)
+}
+
+// writeFileAtomic writes path with write through a temporary file, so that
+// concurrent readers never see it partially written. The file is private.
+func writeFileAtomic(path string, write func(path string) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := write(tmp.Name()); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
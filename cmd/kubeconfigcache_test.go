@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestKubeconfigCache(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cache := &kubeconfigCache{dir: filepath.Join(t.TempDir(), kubeconfigCacheDir), now: func() time.Time { return now }}
+	key := kubeconfigCacheKey("https://rancher.example.com", "token-abc", "", "c-1")
+
+	_, ok := cache.get(key)
+	assert.False(t, ok)
+
+	kubeConfig := api.Config{
+		Clusters:  map[string]*api.Cluster{"c-1": {Server: "https://rancher.example.com/k8s/clusters/c-1"}},
+		AuthInfos: map[string]*api.AuthInfo{"user": {Token: "kubeconfig-u-1:secret"}},
+	}
+	path, err := cache.put(key, kubeConfig, now.Add(time.Minute))
+	require.NoError(t, err)
+
+	cached, ok := cache.get(key)
+	assert.True(t, ok)
+	assert.Equal(t, path, cached)
+	loaded, err := clientcmd.LoadFromFile(cached)
+	require.NoError(t, err)
+	assert.Equal(t, "kubeconfig-u-1:secret", loaded.AuthInfos["user"].Token)
+
+	_, ok = cache.get(kubeconfigCacheKey("https://rancher.example.com", "token-abc", "", "c-2"))
+	assert.False(t, ok)
+
+	_, ok = cache.get(kubeconfigCacheKey("https://rancher.example.com", "token-abc", "bob", "c-1"))
+	assert.False(t, ok, "the kubeconfig of the real user must not be used with --as")
+
+	now = now.Add(time.Minute)
+	_, ok = cache.get(key)
+	assert.False(t, ok)
+
+	require.NoError(t, cache.clear())
+	assert.NoDirExists(t, cache.dir)
+}
+
+func TestKubeconfigCacheExpiry(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cache := &kubeconfigCache{now: func() time.Time { return now }}
+
+	assert.Equal(t, now.Add(kubeconfigCacheMaxTTL), cache.expiry(&client.Token{}))
+	assert.Equal(t, now.Add(kubeconfigCacheMaxTTL), cache.expiry(&client.Token{ExpiresAt: "2024-06-01T00:00:00Z"}))
+	assert.Equal(t, now.Add(9*time.Minute), cache.expiry(&client.Token{ExpiresAt: "2024-05-01T12:10:00Z"}))
+	assert.Equal(t, now.Add(kubeconfigCacheMaxTTL), cache.expiry(&client.Token{ExpiresAt: "soon"}))
+}
@@ -8,16 +8,25 @@ import (
 
 	"github.com/rancher/norman/clientbase"
 	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 )
 
+const kubectlDescription = `
+Use the current cluster context to run kubectl commands in the cluster.
+
+The kubeconfig of the cluster is cached on disk until its token expires, and for an hour at
+most, so that consecutive commands don't make any request to the Rancher server. Run
+'rancher cache clear' to drop it.
+`
+
 func KubectlCommand() cli.Command {
 	return cli.Command{
 		Name:            "kubectl",
 		Usage:           "Run kubectl commands",
-		Description:     "Use the current cluster context to run kubectl commands in the cluster",
+		Description:     kubectlDescription,
 		Action:          runKubectl,
 		SkipFlagParsing: true,
 	}
@@ -36,90 +45,127 @@ func runKubectl(ctx *cli.Context) error {
 			"for more info. Error: %s", err.Error())
 	}
 
-	c, err := GetClient(ctx)
+	sc, err := lookupConfig(ctx)
 	if err != nil {
 		return err
 	}
-
-	config, err := loadConfig(ctx)
-	if err != nil {
-		return err
+	cache := newKubeconfigCache(GetConfigPath(ctx))
+	cacheKey := kubeconfigCacheKey(sc.URL, sc.AccessKey, ctx.GlobalString("as"), sc.FocusedCluster())
+	kubeconfigPath, ok := cache.get(cacheKey)
+	if !ok {
+		var cleanup func()
+		kubeconfigPath, cleanup, err = generateKubectlConfig(ctx, cache, cacheKey)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
 	}
 
-	currentRancherServer, err := config.FocusedServer()
+	cmd := exec.Command(path, ctx.Args()...)
+	cmd.Env = append(os.Environ(), "KUBECONFIG="+kubeconfigPath)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	err = cmd.Run()
 	if err != nil {
 		return err
 	}
+	return nil
+}
 
-	currentToken := currentRancherServer.AccessKey
-	t, err := c.ManagementClient.Token.ByID(currentToken)
+// generateKubectlConfig returns the path of the kubeconfig of the current
+// cluster, generated unless the config holds one whose token is still valid.
+// The kubeconfig is cached until its token expires, or written to a temporary
+// file removed by cleanup when it can't be cached.
+func generateKubectlConfig(ctx *cli.Context, cache *kubeconfigCache, cacheKey string) (string, func(), error) {
+	c, err := GetManagementClient(ctx)
 	if err != nil {
-		return err
+		return "", nil, err
 	}
 
-	currentUser := t.UserID
-	kubeConfig, err := getKubeConfigForUser(ctx, currentUser)
-	if err != nil {
-		return err
+	// the kubeconfigs kept in the config belong to the owner of the access key,
+	// they are neither used nor replaced when impersonating another user
+	impersonating := ctx.GlobalString("as") != ""
+	var currentUser string
+	var kubeConfig *api.Config
+	if !impersonating {
+		t, err := c.ManagementClient.Token.ByID(c.UserConfig.AccessKey)
+		if err != nil {
+			return "", nil, err
+		}
+		currentUser = t.UserID
+		if kubeConfig, err = getKubeConfigForUser(ctx, currentUser); err != nil {
+			return "", nil, err
+		}
 	}
 
-	var isTokenValid bool
+	var token *client.Token
 	if kubeConfig != nil {
 		tokenID, err := extractKubeconfigTokenID(*kubeConfig)
 		if err != nil {
-			return err
+			return "", nil, err
 		}
-		isTokenValid, err = validateToken(tokenID, c.ManagementClient.Token)
+		token, err = lookupValidToken(tokenID, c.ManagementClient.Token)
 		if err != nil {
-			return err
+			return "", nil, err
 		}
 	}
 
-	if kubeConfig == nil || !isTokenValid {
+	if token == nil {
 		cluster, err := getClusterByID(c, c.UserConfig.FocusedCluster())
 		if err != nil {
-			return err
+			return "", nil, err
 		}
 
 		config, err := c.ManagementClient.Cluster.ActionGenerateKubeconfig(cluster)
 		if err != nil {
-			return err
+			return "", nil, err
 		}
 
 		kubeConfigBytes := []byte(config.Config)
 		kubeConfig, err = clientcmd.Load(kubeConfigBytes)
 		if err != nil {
-			return err
+			return "", nil, err
 		}
 
-		if err := setKubeConfigForUser(ctx, currentUser, kubeConfig); err != nil {
-			return err
+		if !impersonating {
+			if err := setKubeConfigForUser(ctx, currentUser, kubeConfig); err != nil {
+				return "", nil, err
+			}
+		}
+
+		tokenID, err := extractKubeconfigTokenID(*kubeConfig)
+		if err != nil {
+			return "", nil, err
 		}
+		if token, err = lookupValidToken(tokenID, c.ManagementClient.Token); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if token != nil {
+		path, err := cache.put(cacheKey, *kubeConfig, cache.expiry(token))
+		if err == nil {
+			return path, func() {}, nil
+		}
+		logrus.Debugf("Unable to cache the kubeconfig: %v", err)
 	}
 
 	tmpfile, err := os.CreateTemp("", "rancher-")
 	if err != nil {
-		return err
+		return "", nil, err
 	}
-	defer os.Remove(tmpfile.Name())
+	cleanup := func() { os.Remove(tmpfile.Name()) }
 
 	if err := clientcmd.WriteToFile(*kubeConfig, tmpfile.Name()); err != nil {
-		return err
+		cleanup()
+		return "", nil, err
 	}
 	if err := tmpfile.Close(); err != nil {
-		return err
-	}
-
-	cmd := exec.Command(path, ctx.Args()...)
-	cmd.Env = append(os.Environ(), "KUBECONFIG="+tmpfile.Name())
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-	err = cmd.Run()
-	if err != nil {
-		return err
+		cleanup()
+		return "", nil, err
 	}
-	return nil
+	return tmpfile.Name(), cleanup, nil
 }
 
 func extractKubeconfigTokenID(kubeconfig api.Config) (string, error) {
@@ -137,13 +183,18 @@ func extractKubeconfigTokenID(kubeconfig api.Config) (string, error) {
 	return parts[0], nil
 }
 
-func validateToken(tokenID string, tokenClient client.TokenOperations) (bool, error) {
+// lookupValidToken returns the token tokenID, nil when it doesn't exist or
+// expired.
+func lookupValidToken(tokenID string, tokenClient client.TokenOperations) (*client.Token, error) {
 	token, err := tokenClient.ByID(tokenID)
 	if err != nil {
 		if !clientbase.IsNotFound(err) {
-			return false, err
+			return nil, err
 		}
-		return false, nil
+		return nil, nil
+	}
+	if token.Expired {
+		return nil, nil
 	}
-	return !token.Expired, nil
+	return token, nil
 }
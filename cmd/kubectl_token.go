@@ -125,6 +125,7 @@ func CredentialCommand() cli.Command {
 			},
 		},
 		Subcommands: []cli.Command{
+			createTokenCommand(),
 			{
 				Name:   "delete",
 				Usage:  fmt.Sprintf("Delete cached token used for kubectl login at [%s] \n %s", configDir, deleteExample),
@@ -187,7 +188,7 @@ func runCredential(ctx *cli.Context) error {
 
 func deleteCachedCredential(ctx *cli.Context) error {
 	if len(ctx.Args()) == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
 	cf, err := loadConfig(ctx)
@@ -195,30 +196,34 @@ func deleteCachedCredential(ctx *cli.Context) error {
 		return err
 	}
 
-	// dir is always set by global default.
-	dir := ctx.GlobalString("config")
-
 	if len(cf.Servers) == 0 {
-		customPrint(fmt.Sprintf("there are no cached tokens in [%s]", dir))
+		customPrint(fmt.Sprintf("there are no cached tokens in [%s]", cf.Path))
 		return nil
 	}
 
 	if ctx.Args().First() == "all" {
-		customPrint(fmt.Sprintf("removing cached tokens in [%s]", dir))
-		for _, server := range cf.Servers {
-			server.KubeCredentials = make(map[string]*config.ExecCredential)
-		}
-		return cf.Write()
+		customPrint(fmt.Sprintf("removing cached tokens in [%s]", cf.Path))
+		return updateConfig(ctx, func(cf *config.Config) error {
+			for _, server := range cf.Servers {
+				server.KubeCredentials = make(map[string]*config.ExecCredential)
+			}
+			return nil
+		})
 	}
 
 	for _, key := range ctx.Args() {
 		customPrint(fmt.Sprintf("removing [%s]", key))
-		for _, server := range cf.Servers {
-			server.KubeCredentials[key] = nil
-		}
 	}
-
-	return cf.Write()
+	return updateConfig(ctx, func(cf *config.Config) error {
+		for _, key := range ctx.Args() {
+			for _, server := range cf.Servers {
+				if server.KubeCredentials != nil {
+					server.KubeCredentials[key] = nil
+				}
+			}
+		}
+		return nil
+	})
 }
 
 func loadCachedCredential(ctx *cli.Context, key string) (*config.ExecCredential, error) {
@@ -233,14 +238,15 @@ func loadCachedCredential(ctx *cli.Context, key string) (*config.ExecCredential,
 	}
 	ts := cred.Status.ExpirationTimestamp
 	if ts != nil && ts.Time.Before(time.Now()) {
-		cf, err := loadConfig(ctx)
+		err := updateConfig(ctx, func(cf *config.Config) error {
+			if server := cf.Servers[ctx.String("server")]; server != nil && server.KubeCredentials != nil {
+				server.KubeCredentials[key] = nil
+			}
+			return nil
+		})
 		if err != nil {
 			return nil, err
 		}
-		cf.Servers[ctx.String("server")].KubeCredentials[key] = nil
-		if err := cf.Write(); err != nil {
-			return nil, err
-		}
 		return nil, nil
 	}
 
@@ -267,8 +273,13 @@ func lookupServerConfig(ctx *cli.Context) (*config.ServerConfig, error) {
 		sc = &config.ServerConfig{
 			KubeCredentials: make(map[string]*config.ExecCredential),
 		}
-		cf.Servers[server] = sc
-		if err := cf.Write(); err != nil {
+		err := updateConfig(ctx, func(cf *config.Config) error {
+			if cf.Servers[server] == nil {
+				cf.Servers[server] = sc
+			}
+			return nil
+		})
+		if err != nil {
 			return nil, err
 		}
 	}
@@ -286,22 +297,18 @@ func cacheCredential(ctx *cli.Context, cred *config.ExecCredential, id string) e
 		return errors.New("name of rancher server is required")
 	}
 
-	cf, err := loadConfig(ctx)
-	if err != nil {
-		return err
-	}
-
-	sc, err := lookupServerConfig(ctx)
-	if err != nil {
-		return err
-	}
-
-	if sc.KubeCredentials[id] == nil {
-		sc.KubeCredentials = make(map[string]*config.ExecCredential)
-	}
-	sc.KubeCredentials[id] = cred
-	cf.Servers[server] = sc
-	return cf.Write()
+	return updateConfig(ctx, func(cf *config.Config) error {
+		sc := cf.Servers[server]
+		if sc == nil {
+			sc = &config.ServerConfig{}
+			cf.Servers[server] = sc
+		}
+		if sc.KubeCredentials[id] == nil {
+			sc.KubeCredentials = make(map[string]*config.ExecCredential)
+		}
+		sc.KubeCredentials[id] = cred
+		return nil
+	})
 }
 
 func loginAndGenerateCred(input *LoginInput) (*config.ExecCredential, error) {
@@ -582,7 +589,7 @@ func selectAuthProvider(authProviders []TypedProvider, providerType string) (Typ
 				return p, nil
 			}
 		}
-		return nil, fmt.Errorf("provider %s not found", providerType)
+		return nil, notFoundErrorf("provider %s not found", providerType)
 	}
 
 	// otherwise ask to the user (if more than one)
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/rancher/cli/cliclient"
+	"github.com/rancher/norman/clientbase"
+	"github.com/rancher/norman/types"
+	"github.com/urfave/cli"
+)
+
+// objectMeta is the part of the Kubernetes object metadata the commands
+// working with the /v1 API use. State is the summary that API adds to every
+// object.
+type objectMeta struct {
+	Name              string            `json:"name,omitempty"`
+	GenerateName      string            `json:"generateName,omitempty"`
+	Namespace         string            `json:"namespace,omitempty"`
+	ResourceVersion   string            `json:"resourceVersion,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	CreationTimestamp string            `json:"creationTimestamp,omitempty"`
+	OwnerReferences   []struct {
+		Kind string `json:"kind,omitempty"`
+		Name string `json:"name,omitempty"`
+	} `json:"ownerReferences,omitempty"`
+	State *struct {
+		Name          string `json:"name,omitempty"`
+		Error         bool   `json:"error,omitempty"`
+		Transitioning bool   `json:"transitioning,omitempty"`
+		Message       string `json:"message,omitempty"`
+	} `json:"state,omitempty"`
+}
+
+type objectCondition struct {
+	Type    string `json:"type,omitempty"`
+	Status  string `json:"status,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// findCondition returns the condition of type conditionType, nil if unset
+func findCondition(conditions []objectCondition, conditionType string) *objectCondition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// objectState returns the state summary of an object, empty when unknown
+func objectState(metadata objectMeta) string {
+	if metadata.State == nil {
+		return ""
+	}
+	return metadata.State.Name
+}
+
+// ownedBy reports whether an object of kind named name owns metadata
+func ownedBy(metadata objectMeta, kind, name string) bool {
+	for _, ref := range metadata.OwnerReferences {
+		if ref.Kind == kind && ref.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+var manifestSeparator = regexp.MustCompile(`(?m)^---[ \t]*$`)
+
+// decodeManifests decodes the objects of a YAML or JSON manifest, which may
+// hold several documents separated by "---".
+func decodeManifests(content []byte) ([]map[string]interface{}, error) {
+	var objects []map[string]interface{}
+	for i, doc := range manifestSeparator.Split(string(content), -1) {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		object := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(doc), &object); err != nil {
+			return nil, fmt.Errorf("document %d: %w", i+1, err)
+		}
+		if len(object) > 0 {
+			objects = append(objects, object)
+		}
+	}
+	return objects, nil
+}
+
+// getClusterV1Client returns a client for the /v1 API of the cluster named or
+// identified by cluster, or of the cluster in the current context when empty.
+// The client must serve schemaType, so that a missing feature is reported
+// clearly.
+func getClusterV1Client(ctx *cli.Context, cluster, schemaType, feature string) (*clientbase.APIBaseClient, error) {
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterID, err := resolveClusterID(c, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := cliclient.NewClusterV1Client(c.UserConfig, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := client.Types[schemaType]; !ok {
+		return nil, fmt.Errorf("%s is not installed in cluster %s or you don't have access to it", feature, clusterID)
+	}
+	return client, nil
+}
+
+// resolveClusterID returns the ID of the cluster named or identified by
+// cluster, or of the cluster in the current context when empty.
+func resolveClusterID(c *cliclient.MasterClient, cluster string) (string, error) {
+	if cluster == "" {
+		return c.UserConfig.FocusedCluster(), nil
+	}
+	resource, err := Lookup(c, cluster, "cluster")
+	if err != nil {
+		return "", err
+	}
+	return resource.ID, nil
+}
+
+// clusterServiceURL returns the URL of a service in a cluster, reached through
+// the Kubernetes API proxy Rancher serves for every cluster. port may be a
+// port name or number.
+func clusterServiceURL(c *cliclient.MasterClient, clusterID, namespace, scheme, service, port string) string {
+	return fmt.Sprintf("%s/k8s/clusters/%s/api/v1/namespaces/%s/services/%s:%s:%s/proxy",
+		strings.TrimSuffix(c.UserConfig.URL, "/v3"), clusterID, namespace, scheme, service, port)
+}
+
+// getLocalV1Client returns a client for the /v1 API of the local cluster, which
+// must serve schemaType. missing describes the error otherwise.
+func getLocalV1Client(ctx *cli.Context, schemaType, missing string) (*cliclient.MasterClient, error) {
+	c, err := GetCAPIClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := c.CAPIClient.Types[schemaType]; !ok {
+		return nil, fmt.Errorf("%s or you don't have access to %s resources", missing, schemaType)
+	}
+	return c, nil
+}
+
+// childMap returns the map under key in m, adding an empty one when missing
+func childMap(m map[string]interface{}, key string) map[string]interface{} {
+	child, ok := m[key].(map[string]interface{})
+	if !ok {
+		child = map[string]interface{}{}
+		m[key] = child
+	}
+	return child
+}
+
+// getRawObject returns an object of the /v1 API as a map, so that fields
+// this CLI doesn't know are kept when it is updated, and its links.
+func getRawObject(client *clientbase.APIBaseClient, schemaType, id string) (map[string]interface{}, *types.Resource, error) {
+	var raw json.RawMessage
+	if err := client.ByID(schemaType, id, &raw); err != nil {
+		return nil, nil, err
+	}
+	object := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, nil, err
+	}
+	resource := &types.Resource{}
+	if err := json.Unmarshal(raw, resource); err != nil {
+		return nil, nil, err
+	}
+	return object, resource, nil
+}
@@ -49,6 +49,10 @@ func LoginCommand() cli.Command {
 				Name:  "token,t",
 				Usage: "Token from the Rancher UI",
 			},
+			cli.StringFlag{
+				Name:  "token-source",
+				Usage: "Read the token when needed instead of storing it, from 'vault:PATH[#FIELD]', 'command:COMMAND' or 'env:NAME'",
+			},
 			cli.StringFlag{
 				Name:  "cacert",
 				Usage: "Location of the CACerts to use",
@@ -67,12 +71,7 @@ func LoginCommand() cli.Command {
 
 func loginSetup(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowCommandHelp(ctx, "login")
-	}
-
-	cf, err := loadConfig(ctx)
-	if err != nil {
-		return err
+		return showCommandUsage(ctx, "login")
 	}
 
 	serverName := ctx.String("name")
@@ -91,7 +90,12 @@ func loginSetup(ctx *cli.Context) error {
 	u.Path = ""
 	serverConfig.URL = u.String()
 
-	if ctx.String("token") != "" {
+	if ctx.String("token-source") != "" {
+		serverConfig.TokenSource = ctx.String("token-source")
+		if err := serverConfig.ResolveToken(); err != nil {
+			return err
+		}
+	} else if ctx.String("token") != "" {
 		auth := SplitOnColon(ctx.String("token"))
 		if len(auth) != 2 {
 			return errors.New("invalid token")
@@ -101,7 +105,7 @@ func loginSetup(ctx *cli.Context) error {
 		serverConfig.TokenKey = ctx.String("token")
 	} else {
 		// This can be removed once username and password is accepted
-		return errors.New("token flag is required")
+		return errors.New("token or token-source flag is required")
 	}
 
 	if ctx.String("cacert") != "" {
@@ -132,12 +136,20 @@ func loginSetup(ctx *cli.Context) error {
 		return err
 	}
 
+	serverVersion, err := getServerVersion(c)
+	if err != nil {
+		logrus.Debugf("Unable to get the server version: %v", err)
+	}
+
 	// Set the default server and proj for the user
 	serverConfig.Project = proj
-	cf.CurrentServer = serverName
-	cf.Servers[serverName] = serverConfig
-
-	err = cf.Write()
+	serverConfig.ServerVersion = serverVersion
+	warnIncompatibleServer(serverConfig)
+	err = updateConfig(ctx, func(cf *config.Config) error {
+		cf.CurrentServer = serverName
+		cf.Servers[serverName] = serverConfig
+		return nil
+	})
 	if err != nil {
 		return err
 	}
@@ -340,7 +352,7 @@ func processServerChain(res *http.Response) ([]string, error) {
 }
 
 func loginContext(ctx *cli.Context) error {
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
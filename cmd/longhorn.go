@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rancher/cli/cliclient"
+	"github.com/urfave/cli"
+)
+
+type LonghornReplica struct {
+	Name     string `json:"name"`
+	HostID   string `json:"hostId"`
+	Mode     string `json:"mode"`
+	Running  bool   `json:"running"`
+	FailedAt string `json:"failedAt"`
+}
+
+type LonghornController struct {
+	HostID string `json:"hostId"`
+}
+
+type LonghornVolume struct {
+	Name             string               `json:"name"`
+	Size             string               `json:"size"`
+	ActualSize       int64                `json:"actualSize"`
+	State            string               `json:"state"`
+	Robustness       string               `json:"robustness"`
+	NumberOfReplicas int                  `json:"numberOfReplicas"`
+	Frontend         string               `json:"frontend"`
+	Controllers      []LonghornController `json:"controllers"`
+	Replicas         []LonghornReplica    `json:"replicas"`
+	KubernetesStatus struct {
+		Namespace string `json:"namespace"`
+		PVCName   string `json:"pvcName"`
+	} `json:"kubernetesStatus"`
+}
+
+type LonghornVolumeCollection struct {
+	Data []LonghornVolume `json:"data"`
+}
+
+type LonghornVolumeData struct {
+	Volume   LonghornVolume
+	Name     string
+	State    string
+	Health   string
+	Size     string
+	Replicas string
+	Nodes    string
+	Attached string
+	PVC      string
+}
+
+type LonghornReplicaData struct {
+	Replica LonghornReplica
+	Name    string
+	Node    string
+	Mode    string
+	State   string
+}
+
+var longhornClusterFlag = cli.StringFlag{
+	Name:  "cluster",
+	Usage: "Cluster to use, by default the cluster in the current context",
+}
+
+func LonghornCommand() cli.Command {
+	return cli.Command{
+		Name:  "longhorn",
+		Usage: "Operations on Longhorn storage",
+		Description: `
+Reads the Longhorn API of a cluster through Rancher. Longhorn must be installed in the
+longhorn-system namespace of the cluster.
+`,
+		Subcommands: []cli.Command{
+			{
+				Name:    "volumes",
+				Aliases: []string{"volume"},
+				Usage:   "Operations on Longhorn volumes",
+				Action:  defaultAction(longhornVolumeLs),
+				Flags:   []cli.Flag{longhornClusterFlag},
+				Subcommands: []cli.Command{
+					{
+						Name:  "ls",
+						Usage: "List volumes",
+						Description: `
+Lists the volumes with their health and the nodes their replicas are placed on. A volume is
+degraded when fewer replicas than requested are healthy.
+
+Example:
+	$ rancher longhorn volumes ls --cluster mycluster
+`,
+						ArgsUsage: "None",
+						Action:    longhornVolumeLs,
+						Flags: []cli.Flag{
+							longhornClusterFlag,
+							formatFlag,
+							quietFlag,
+							filterFlag,
+							labelSelectorFlag,
+							sortByFlag,
+							noHeadersFlag,
+						},
+					},
+					{
+						Name:      "show",
+						Usage:     "Show a volume and its replicas",
+						ArgsUsage: "[VOLUME_NAME]",
+						Action:    longhornVolumeShow,
+						Flags: []cli.Flag{
+							longhornClusterFlag,
+							formatFlag,
+							noHeadersFlag,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func longhornVolumeLs(ctx *cli.Context) error {
+	c, url, err := getLonghornURL(ctx)
+	if err != nil {
+		return err
+	}
+
+	collection := &LonghornVolumeCollection{}
+	if err := c.ManagementClient.Ops.DoGet(url+"/v1/volumes", nil, collection); err != nil {
+		return longhornError(err)
+	}
+
+	writer := NewTableWriter([][]string{
+		{"NAME", "Name"},
+		{"STATE", "State"},
+		{"HEALTH", "Health"},
+		{"SIZE", "Size"},
+		{"REPLICAS", "Replicas"},
+		{"NODES", "Nodes"},
+		{"ATTACHED", "Attached", wideFormat},
+		{"PVC", "PVC"},
+	}, ctx)
+
+	defer writer.Close()
+
+	for _, volume := range collection.Data {
+		writer.Write(newLonghornVolumeData(volume))
+	}
+
+	return writer.Err()
+}
+
+func longhornVolumeShow(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+
+	c, url, err := getLonghornURL(ctx)
+	if err != nil {
+		return err
+	}
+
+	volume := &LonghornVolume{}
+	if err := c.ManagementClient.Ops.DoGet(url+"/v1/volumes/"+ctx.Args().First(), nil, volume); err != nil {
+		return longhornError(err)
+	}
+
+	format := ctx.String("format")
+	if format == "" || format == wideFormat {
+		data := newLonghornVolumeData(*volume)
+		fmt.Printf("Name:       %s\n", data.Name)
+		fmt.Printf("State:      %s\n", data.State)
+		fmt.Printf("Health:     %s\n", data.Health)
+		fmt.Printf("Size:       %s (%s used)\n", data.Size, formatBytes(volume.ActualSize))
+		fmt.Printf("Replicas:   %s\n", data.Replicas)
+		fmt.Printf("Attached:   %s\n", valueOrDefault(data.Attached, "-"))
+		fmt.Printf("PVC:        %s\n\n", valueOrDefault(data.PVC, "-"))
+	}
+
+	writer := NewTableWriter([][]string{
+		{"REPLICA", "Name"},
+		{"NODE", "Node"},
+		{"MODE", "Mode"},
+		{"STATE", "State"},
+	}, ctx)
+
+	defer writer.Close()
+
+	for _, replica := range volume.Replicas {
+		writer.Write(&LonghornReplicaData{
+			Replica: replica,
+			Name:    replica.Name,
+			Node:    replica.HostID,
+			Mode:    replica.Mode,
+			State:   replicaState(replica),
+		})
+	}
+
+	return writer.Err()
+}
+
+// getLonghornURL returns the URL of the Longhorn API of the cluster given
+// with --cluster.
+func getLonghornURL(ctx *cli.Context) (*cliclient.MasterClient, string, error) {
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	clusterID, err := resolveClusterID(c, ctx.String("cluster"))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return c, clusterServiceURL(c, clusterID, "longhorn-system", "http", "longhorn-backend", "9500"), nil
+}
+
+func longhornError(err error) error {
+	return fmt.Errorf("unable to reach the Longhorn API, is Longhorn installed? %w", err)
+}
+
+func newLonghornVolumeData(volume LonghornVolume) *LonghornVolumeData {
+	data := &LonghornVolumeData{
+		Volume: volume,
+		Name:   volume.Name,
+		State:  volume.State,
+		Health: volume.Robustness,
+		Size:   volume.Size,
+	}
+	if size, err := strconv.ParseInt(volume.Size, 10, 64); err == nil {
+		data.Size = formatBytes(size)
+	}
+
+	healthy := 0
+	var nodes []string
+	for _, replica := range volume.Replicas {
+		if replicaState(replica) == "healthy" {
+			healthy++
+		}
+		if replica.HostID != "" {
+			nodes = append(nodes, replica.HostID)
+		}
+	}
+	sort.Strings(nodes)
+	data.Replicas = fmt.Sprintf("%d/%d", healthy, volume.NumberOfReplicas)
+	data.Nodes = strings.Join(nodes, ",")
+
+	for _, controller := range volume.Controllers {
+		if controller.HostID != "" {
+			data.Attached = controller.HostID
+		}
+	}
+	if volume.KubernetesStatus.PVCName != "" {
+		data.PVC = volume.KubernetesStatus.Namespace + "/" + volume.KubernetesStatus.PVCName
+	}
+	return data
+}
+
+// replicaState summarizes a replica the way the Longhorn UI does
+func replicaState(replica LonghornReplica) string {
+	switch {
+	case replica.FailedAt != "":
+		return "failed"
+	case !replica.Running:
+		return "stopped"
+	case replica.Mode == "WO":
+		return "rebuilding"
+	case replica.Mode == "ERR":
+		return "failed"
+	default:
+		return "healthy"
+	}
+}
+
+// formatBytes formats n bytes with binary units, e.g. 10Gi
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	value := strconv.FormatFloat(float64(n)/float64(div), 'f', 1, 64)
+	return strings.TrimSuffix(value, ".0") + string("KMGTPE"[exp]) + "i"
+}
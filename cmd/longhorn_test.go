@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLonghornVolumeData(t *testing.T) {
+	volume := LonghornVolume{
+		Name:             "pvc-1",
+		Size:             "10737418240",
+		State:            "attached",
+		Robustness:       "degraded",
+		NumberOfReplicas: 3,
+		Controllers:      []LonghornController{{HostID: "node2"}},
+		Replicas: []LonghornReplica{
+			{Name: "r1", HostID: "node2", Mode: "RW", Running: true},
+			{Name: "r2", HostID: "node1", Mode: "WO", Running: true},
+			{Name: "r3", HostID: "node3", Running: false, FailedAt: "2024-01-01T00:00:00Z"},
+		},
+	}
+	volume.KubernetesStatus.Namespace = "default"
+	volume.KubernetesStatus.PVCName = "data"
+
+	data := newLonghornVolumeData(volume)
+	assert.Equal(t, "10Gi", data.Size)
+	assert.Equal(t, "1/3", data.Replicas)
+	assert.Equal(t, "node1,node2,node3", data.Nodes)
+	assert.Equal(t, "node2", data.Attached)
+	assert.Equal(t, "default/data", data.PVC)
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		512:                    "512",
+		2048:                   "2Ki",
+		1536 * 1024 * 1024:     "1.5Gi",
+		5 * 1024 * 1024 * 1024: "5Gi",
+	}
+	for n, want := range tests {
+		assert.Equal(t, want, formatBytes(n))
+	}
+}
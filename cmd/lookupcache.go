@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultLookupCacheTTL is how long names resolved to IDs are cached by default
+const DefaultLookupCacheTTL = 5 * time.Minute
+
+const (
+	lookupCacheFile   = "lookup-cache.json"
+	lookupCacheKeySep = "|"
+)
+
+// cacheableLookupTypes are the types whose name to ID mappings are kept in the
+// on-disk lookup cache.
+var cacheableLookupTypes = []string{
+	managementClient.ClusterType,
+	managementClient.ProjectType,
+	managementClient.TemplateType,
+}
+
+type lookupCacheEntry struct {
+	ID      string    `json:"id"`
+	Expires time.Time `json:"expires"`
+}
+
+// lookupCache is an on-disk cache of name to ID mappings shared by consecutive
+// invocations, so resolving a name doesn't have to list a whole collection.
+// It's disabled until configured with a path and a positive TTL.
+type lookupCache struct {
+	mu      sync.Mutex
+	path    string
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[string]lookupCacheEntry
+}
+
+var nameCache = &lookupCache{now: time.Now}
+
+func lookupCachePath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), lookupCacheFile)
+}
+
+func lookupCacheKey(server, schemaType, name string) string {
+	return server + lookupCacheKeySep + schemaType + lookupCacheKeySep + name
+}
+
+func isCacheableLookupType(schemaType string) bool {
+	return slices.Contains(cacheableLookupTypes, schemaType)
+}
+
+func (l *lookupCache) configure(path string, ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.path = path
+	l.ttl = ttl
+	l.entries = nil
+}
+
+func (l *lookupCache) enabled() bool {
+	return l.path != "" && l.ttl > 0
+}
+
+// get returns the cached ID for key, if it hasn't expired.
+func (l *lookupCache) get(key string) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.enabled() {
+		return "", false
+	}
+	l.load()
+
+	entry, ok := l.entries[key]
+	if !ok || l.now().After(entry.Expires) {
+		return "", false
+	}
+	return entry.ID, true
+}
+
+func (l *lookupCache) set(key, id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.enabled() {
+		return
+	}
+	l.load()
+	l.entries[key] = lookupCacheEntry{ID: id, Expires: l.now().Add(l.ttl)}
+	l.save()
+}
+
+func (l *lookupCache) delete(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.enabled() {
+		return
+	}
+	l.load()
+	if _, ok := l.entries[key]; ok {
+		delete(l.entries, key)
+		l.save()
+	}
+}
+
+// load reads the cache file the first time it's needed, a missing or
+// unreadable cache is treated as empty.
+func (l *lookupCache) load() {
+	if l.entries != nil {
+		return
+	}
+	l.entries = make(map[string]lookupCacheEntry)
+
+	content, err := os.ReadFile(l.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logrus.Debugf("Unable to read lookup cache %s: %v", l.path, err)
+		}
+		return
+	}
+	if err := json.Unmarshal(content, &l.entries); err != nil {
+		logrus.Debugf("Ignoring invalid lookup cache %s: %v", l.path, err)
+		l.entries = make(map[string]lookupCacheEntry)
+	}
+}
+
+// save drops expired entries and replaces the cache file. Failing to save the
+// cache isn't fatal, the next invocation will resolve the names again.
+func (l *lookupCache) save() {
+	now := l.now()
+	for key, entry := range l.entries {
+		if now.After(entry.Expires) {
+			delete(l.entries, key)
+		}
+	}
+
+	content, err := json.Marshal(l.entries)
+	if err != nil {
+		logrus.Debugf("Unable to encode lookup cache: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0700); err != nil {
+		logrus.Debugf("Unable to save lookup cache: %v", err)
+		return
+	}
+
+	output, err := os.CreateTemp(filepath.Dir(l.path), lookupCacheFile+".tmp-*")
+	if err != nil {
+		logrus.Debugf("Unable to save lookup cache: %v", err)
+		return
+	}
+	defer os.Remove(output.Name())
+	_, err = output.Write(content)
+	if closeErr := output.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(output.Name(), l.path)
+	}
+	if err != nil {
+		logrus.Debugf("Unable to save lookup cache: %v", err)
+	}
+}
+
+// clear removes the cache file.
+func (l *lookupCache) clear() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = nil
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupCache(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), lookupCacheFile)
+	key := lookupCacheKey("https://rancher.example.com", "cluster", "prod")
+
+	cache := &lookupCache{now: func() time.Time { return now }}
+	cache.configure(path, time.Minute)
+	cache.set(key, "c-1")
+
+	// a new invocation reads the mapping from disk
+	reloaded := &lookupCache{now: func() time.Time { return now.Add(30 * time.Second) }}
+	reloaded.configure(path, time.Minute)
+	id, ok := reloaded.get(key)
+	assert.True(ok)
+	assert.Equal("c-1", id)
+
+	reloaded.now = func() time.Time { return now.Add(2 * time.Minute) }
+	_, ok = reloaded.get(key)
+	assert.False(ok, "expired entries must not be returned")
+
+	assert.NoError(reloaded.clear())
+	assert.NoFileExists(path)
+	cache.configure(path, time.Minute)
+	_, ok = cache.get(key)
+	assert.False(ok)
+}
+
+func TestLookupCacheDisabled(t *testing.T) {
+	cache := &lookupCache{now: time.Now}
+	cache.configure(filepath.Join(t.TempDir(), lookupCacheFile), 0)
+	cache.set("key", "c-1")
+
+	_, ok := cache.get("key")
+	assert.False(t, ok)
+}
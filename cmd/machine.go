@@ -33,6 +33,12 @@ func MachineCommand() cli.Command {
 						Usage: "'json', 'yaml' or Custom format: '{{.Machine.ID}} {{.Machine.Name}}'",
 					},
 					quietFlag,
+					filterFlag,
+					labelSelectorFlag,
+					sortByFlag,
+					noHeadersFlag,
+					limitFlag,
+					pageSizeFlag,
 				},
 			},
 		},
@@ -73,8 +79,16 @@ func getMachinesList(
 	ctx *cli.Context,
 	c *cliclient.MasterClient,
 ) (*capiClient.MachineCollection, error) {
-	filter := defaultListOpts(ctx)
-	return c.CAPIClient.Machine.List(filter)
+	filter := filteredListOpts(ctx)
+	collection, err := c.CAPIClient.Machine.List(filter)
+	if err != nil {
+		return nil, err
+	}
+	collection.Data, err = listAll(ctx, collection, func(c *capiClient.MachineCollection) []capiClient.Machine { return c.Data })
+	if err != nil {
+		return nil, err
+	}
+	return collection, nil
 }
 
 func getMachineByNodeName(
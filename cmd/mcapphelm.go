@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/rancher/cli/cliclient"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/urfave/cli"
+)
+
+const exportHelmDescription = `
+Prints the 'helm upgrade --install' command that deploys the same chart, version and
+answers as a multi-cluster app does in one of its target projects, and writes the answers
+that apply to that project as a values file. Answers are merged in global, cluster and
+project scope order, as Rancher does.
+
+Example:
+	$ rancher mcapp export-helm --target mycluster:myproject --values-file wordpress.yaml wordpress
+`
+
+func exportMultiClusterAppHelm(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+	if ctx.String("target") == "" {
+		return errors.New("--target is required")
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, app, err := searchForMcapp(c, ctx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	projectID, err := lookupProjectIDFromProjectScope(c, ctx.String("target"))
+	if err != nil {
+		return err
+	}
+	if !mcappTargetsProject(app, projectID) {
+		return fmt.Errorf("project %s is not a target of multi-cluster app %s", ctx.String("target"), app.Name)
+	}
+	clusterID, _, err := parseClusterAndProjectID(projectID)
+	if err != nil {
+		return err
+	}
+
+	templateVersion, err := c.ManagementClient.TemplateVersion.ByID(app.TemplateVersionID)
+	if err != nil {
+		return err
+	}
+	externalInfo, err := parseExternalID(templateVersion.ExternalID)
+	if err != nil {
+		return err
+	}
+
+	cluster, err := getClusterByID(c, clusterID)
+	if err != nil {
+		return err
+	}
+
+	values, setFlags := answersToHelmValues(scopedAnswers(app.Answers, clusterID, projectID))
+
+	valuesFile := ctx.String("values-file")
+	if _, err := os.Stat(valuesFile); err == nil && !ctx.Bool("force") {
+		return fmt.Errorf("%s already exists, use --force to overwrite it", valuesFile)
+	}
+	content, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(valuesFile, content, 0600); err != nil {
+		return err
+	}
+
+	catalogURL := catalogRepoURL(c, externalInfo["catalog"])
+	fmt.Printf("helm repo add %s %s\n", externalInfo["catalog"], catalogURL)
+	fmt.Println(helmInstallCommand(app.Name, externalInfo, cluster.Name, valuesFile, setFlags))
+	return nil
+}
+
+// mcappTargetsProject reports whether app is deployed to projectID
+func mcappTargetsProject(app *managementClient.MultiClusterApp, projectID string) bool {
+	for _, target := range app.Targets {
+		if target.ProjectID == projectID {
+			return true
+		}
+	}
+	return false
+}
+
+// scopedAnswers merges the answers that apply to projectID, global answers
+// first so that cluster and then project answers override them.
+func scopedAnswers(answers []managementClient.Answer, clusterID, projectID string) (map[string]string, map[string]string) {
+	values, setString := map[string]string{}, map[string]string{}
+	for _, scope := range []func(managementClient.Answer) bool{
+		func(a managementClient.Answer) bool { return a.ClusterID == "" && a.ProjectID == "" },
+		func(a managementClient.Answer) bool { return a.ClusterID == clusterID && a.ProjectID == "" },
+		func(a managementClient.Answer) bool { return a.ProjectID == projectID },
+	} {
+		for _, answer := range answers {
+			if !scope(answer) {
+				continue
+			}
+			for k, v := range answer.Values {
+				values[k] = v
+				delete(setString, k)
+			}
+			for k, v := range answer.ValuesSetString {
+				setString[k] = v
+				delete(values, k)
+			}
+		}
+	}
+	return values, setString
+}
+
+// answersToHelmValues turns flat answers such as "image.tag=1.0" into nested
+// values. Values are typed like 'helm --set' does and setString values are
+// kept as strings. Keys indexing lists can't be expressed as a nested map
+// without the chart's defaults, so they are returned as --set flags.
+func answersToHelmValues(answers, setString map[string]string) (map[string]interface{}, []string) {
+	values := map[string]interface{}{}
+	var setFlags []string
+
+	add := func(m map[string]string, typed bool, flag string) {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if strings.Contains(key, "[") {
+				setFlags = append(setFlags, flag+" "+shellQuote(key+"="+m[key]))
+				continue
+			}
+			var value interface{} = m[key]
+			if typed {
+				value = helmTypedValue(m[key])
+			}
+			setNestedValue(values, strings.Split(key, "."), value)
+		}
+	}
+	add(answers, true, "--set")
+	add(setString, false, "--set-string")
+
+	return values, setFlags
+}
+
+func setNestedValue(values map[string]interface{}, path []string, value interface{}) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := values[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			values[key] = next
+		}
+		values = next
+	}
+	values[path[len(path)-1]] = value
+}
+
+// helmTypedValue converts a --set value the way helm does
+func helmTypedValue(s string) interface{} {
+	switch strings.ToLower(s) {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	// helm keeps values with leading zeros such as "0123" as strings
+	if s == "0" || !strings.HasPrefix(s, "0") {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i
+		}
+	}
+	return s
+}
+
+// catalogRepoURL returns the URL of a global catalog, or a placeholder when
+// it can't be read.
+func catalogRepoURL(c *cliclient.MasterClient, name string) string {
+	catalog, err := c.ManagementClient.Catalog.ByID(name)
+	if err != nil || catalog.URL == "" {
+		return "<" + name + "-repository-url>"
+	}
+	return catalog.URL
+}
+
+func helmInstallCommand(release string, externalInfo map[string]string, kubeContext, valuesFile string, setFlags []string) string {
+	args := []string{
+		"helm", "upgrade", "--install", shellQuote(release),
+		shellQuote(externalInfo["catalog"] + "/" + externalInfo["template"]),
+		"--version", shellQuote(externalInfo["version"]),
+		// multi-cluster apps are deployed to a namespace named after the app
+		"--namespace", shellQuote(release), "--create-namespace",
+		"--kube-context", shellQuote(kubeContext),
+		"-f", shellQuote(valuesFile),
+	}
+	args = append(args, setFlags...)
+	return strings.Join(args, " ")
+}
+
+// shellQuote quotes s for a POSIX shell when it contains special characters
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=,@") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"testing"
+
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopedAnswers(t *testing.T) {
+	answers := []managementClient.Answer{
+		{ProjectID: "c-1:p-1", Values: map[string]string{"replicas": "3"}},
+		{Values: map[string]string{"replicas": "1", "image.tag": "1.0"}, ValuesSetString: map[string]string{"port": "80"}},
+		{ClusterID: "c-1", Values: map[string]string{"image.tag": "2.0", "port": "8080"}},
+		{ClusterID: "c-2", Values: map[string]string{"image.tag": "3.0"}},
+		{ProjectID: "c-1:p-2", Values: map[string]string{"replicas": "5"}},
+	}
+
+	values, setString := scopedAnswers(answers, "c-1", "c-1:p-1")
+	assert.Equal(t, map[string]string{"replicas": "3", "image.tag": "2.0", "port": "8080"}, values)
+	assert.Empty(t, setString)
+}
+
+func TestAnswersToHelmValues(t *testing.T) {
+	values, setFlags := answersToHelmValues(
+		map[string]string{
+			"image.tag":        "1.0",
+			"replicas":         "3",
+			"persistence.size": "08",
+			"ingress.enabled":  "true",
+			"hosts[0].name":    "a.example.com",
+		},
+		map[string]string{"image.pullPolicy": "true"},
+	)
+
+	assert.Equal(t, map[string]interface{}{
+		"image": map[string]interface{}{
+			"tag":        "1.0",
+			"pullPolicy": "true",
+		},
+		"replicas":    int64(3),
+		"persistence": map[string]interface{}{"size": "08"},
+		"ingress":     map[string]interface{}{"enabled": true},
+	}, values)
+	assert.Equal(t, []string{"--set 'hosts[0].name=a.example.com'"}, setFlags)
+}
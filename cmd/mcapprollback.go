@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rancher/cli/cliclient"
+	"github.com/rancher/norman/clientbase"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+)
+
+// mcappRollbackPrecheck prints what rolling back app to revision changes: its
+// template version, with a warning when the catalog no longer has it, and its
+// answers.
+func mcappRollbackPrecheck(out io.Writer, c *cliclient.MasterClient, app *managementClient.MultiClusterApp, revision *managementClient.MultiClusterAppRevision) error {
+	if revision.TemplateVersionID != app.TemplateVersionID {
+		fmt.Fprintf(out, "Template version: %s -> %s\n", app.TemplateVersionID, revision.TemplateVersionID)
+	} else {
+		fmt.Fprintf(out, "Template version: %s (unchanged)\n", app.TemplateVersionID)
+	}
+	if _, err := c.ManagementClient.TemplateVersion.ByID(revision.TemplateVersionID); err != nil {
+		if !clientbase.IsNotFound(err) {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Warning: template version %s of revision %s was removed from its catalog, the rollback is likely to fail\n",
+			revision.TemplateVersionID, revision.Name)
+	}
+
+	diffs := rollbackAnswerChanges(app.Answers, revision.Answers)
+	if len(diffs) == 0 {
+		fmt.Fprintln(out, "Answers: unchanged")
+		return nil
+	}
+	fmt.Fprintln(out, "Answers:")
+	return printDiff(out, diffs)
+}
+
+// rollbackAnswerChanges returns the changes from the current answers of an
+// app to the answers of a revision. The string answers are prefixed with
+// answersSetString.
+func rollbackAnswerChanges(current, revision []managementClient.Answer) []resourceDiff {
+	currentAnswers, currentSetString := fromMultiClusterAppAnswers(current)
+	revisionAnswers, revisionSetString := fromMultiClusterAppAnswers(revision)
+	diffs := diffMaps("", answerValues(currentAnswers), answerValues(revisionAnswers), false)
+	return append(diffs, diffMaps("answersSetString", answerValues(currentSetString), answerValues(revisionSetString), false)...)
+}
+
+func answerValues(answers map[string]string) map[string]interface{} {
+	values := make(map[string]interface{}, len(answers))
+	for key, value := range answers {
+		values[key] = value
+	}
+	return values
+}
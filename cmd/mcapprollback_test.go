@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollbackAnswerChanges(t *testing.T) {
+	current := []managementClient.Answer{
+		{Values: map[string]string{"replicas": "3", "image.tag": "1.27"}},
+		{ProjectID: "c-1:p-1", Values: map[string]string{"replicas": "5"}, ValuesSetString: map[string]string{"region": "01"}},
+	}
+	revision := []managementClient.Answer{
+		{Values: map[string]string{"replicas": "2", "image.tag": "1.27", "debug": "true"}},
+	}
+
+	var out bytes.Buffer
+	assert.NoError(t, printDiff(&out, rollbackAnswerChanges(current, revision)))
+	assert.Equal(t, `- c-1:p-1:replicas: "5"
++ debug: "true"
+~ replicas: "3" -> "2"
+- answersSetString.c-1:p-1:region: "01"
+`, out.String())
+
+	assert.Empty(t, rollbackAnswerChanges(current, current))
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/ghodss/yaml"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/urfave/cli"
+)
+
+var targetsFileFlag = cli.StringFlag{
+	Name:  "targets-file",
+	Usage: "Path to a YAML or JSON file listing the target projects, with optional answers for each of them",
+}
+
+const targetsFileDescription = `
+A targets file lists the target projects under 'targets', as cluster:project names or project
+IDs. A target can override answers for its project, those answers override --answers and --set:
+
+	targets:
+	- prod-east:default
+	- project: prod-west:default
+	  answers:
+	    replicas: 5
+	  answersSetString:
+	    region: "01"
+`
+
+// TargetsFile is the content of the --targets-file of the mcapp commands
+type TargetsFile struct {
+	Targets []TargetsFileTarget `json:"targets"`
+}
+
+// TargetsFileTarget is a target project of a targets file, given either as
+// its cluster:project scope or as an object with the answers of the target.
+type TargetsFileTarget struct {
+	Project          string                 `json:"project"`
+	Answers          map[string]interface{} `json:"answers,omitempty"`
+	AnswersSetString map[string]interface{} `json:"answersSetString,omitempty"`
+}
+
+func (t *TargetsFileTarget) UnmarshalJSON(data []byte) error {
+	var project string
+	if err := json.Unmarshal(data, &project); err == nil {
+		*t = TargetsFileTarget{Project: project}
+		return nil
+	}
+	// the alias has no UnmarshalJSON, avoiding the recursion
+	type target TargetsFileTarget
+	return json.Unmarshal(data, (*target)(t))
+}
+
+func readTargetsFile(path string) ([]TargetsFileTarget, error) {
+	content, err := readFileOrStdin(path)
+	if err != nil {
+		return nil, err
+	}
+	file := &TargetsFile{}
+	if err := yaml.Unmarshal(content, file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(file.Targets) == 0 {
+		return nil, fmt.Errorf("%s: no targets listed", path)
+	}
+	var projects []string
+	for i, target := range file.Targets {
+		if target.Project == "" {
+			return nil, fmt.Errorf("%s: target %d has no project", path, i+1)
+		}
+		if slices.Contains(projects, target.Project) {
+			return nil, fmt.Errorf("%s: target %s is listed twice", path, target.Project)
+		}
+		projects = append(projects, target.Project)
+	}
+	return file.Targets, nil
+}
+
+// mcappTargets returns the target projects given with --target and
+// --targets-file, and the targets of the file.
+func mcappTargets(ctx *cli.Context) ([]string, []TargetsFileTarget, error) {
+	projects := ctx.StringSlice("target")
+	if ctx.String("targets-file") == "" {
+		return projects, nil, nil
+	}
+	fileTargets, err := readTargetsFile(ctx.String("targets-file"))
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, target := range fileTargets {
+		if !slices.Contains(projects, target.Project) {
+			projects = append(projects, target.Project)
+		}
+	}
+	return projects, fileTargets, nil
+}
+
+// applyTargetAnswers sets the answers of the targets in answers and
+// answersSetString, scoped to their project.
+func applyTargetAnswers(targets []TargetsFileTarget, answers, answersSetString map[string]string) {
+	for _, target := range targets {
+		for key, value := range target.Answers {
+			answers[concatScope(target.Project, key)] = answerString(value)
+		}
+		for key, value := range target.AnswersSetString {
+			answersSetString[concatScope(target.Project, key)] = answerString(value)
+		}
+	}
+}
+
+// answerString formats a value of an answers file as an answer
+func answerString(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// mcappTargetChanges returns the projects to add to the targets of an app and
+// the projects to remove from them, so that its targets are projectIDs.
+func mcappTargetChanges(current []managementClient.Target, projectIDs []string) (add, remove []string) {
+	var currentIDs []string
+	for _, target := range current {
+		currentIDs = append(currentIDs, target.ProjectID)
+		if !slices.Contains(projectIDs, target.ProjectID) {
+			remove = append(remove, target.ProjectID)
+		}
+	}
+	for _, projectID := range projectIDs {
+		if !slices.Contains(currentIDs, projectID) {
+			add = append(add, projectID)
+		}
+	}
+	return add, remove
+}
+
+// writeTargetChanges prints the projects added to and removed from the
+// targets of an app.
+func writeTargetChanges(app string, add, remove []string) {
+	for _, projectID := range remove {
+		fmt.Fprintf(os.Stderr, "Removing target project %s from multi-cluster app %s\n", projectID, app)
+	}
+	for _, projectID := range add {
+		fmt.Fprintf(os.Stderr, "Adding target project %s to multi-cluster app %s\n", projectID, app)
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadTargetsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "targets.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`targets:
+- prod-east:default
+- project: prod-west:default
+  answers:
+    replicas: 5
+  answersSetString:
+    region: "01"
+`), 0600))
+
+	targets, err := readTargetsFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []TargetsFileTarget{
+		{Project: "prod-east:default"},
+		{
+			Project:          "prod-west:default",
+			Answers:          map[string]interface{}{"replicas": float64(5)},
+			AnswersSetString: map[string]interface{}{"region": "01"},
+		},
+	}, targets)
+
+	answers, answersSetString := map[string]string{"replicas": "2"}, map[string]string{}
+	applyTargetAnswers(targets, answers, answersSetString)
+	assert.Equal(t, map[string]string{"replicas": "2", "prod-west:default:replicas": "5"}, answers)
+	assert.Equal(t, map[string]string{"prod-west:default:region": "01"}, answersSetString)
+
+	assert.NoError(t, os.WriteFile(path, []byte("targets:\n- a:b\n- project: a:b\n"), 0600))
+	_, err = readTargetsFile(path)
+	assert.EqualError(t, err, path+": target a:b is listed twice")
+}
+
+func TestMcappTargetChanges(t *testing.T) {
+	current := []managementClient.Target{{ProjectID: "c-1:p-1"}, {ProjectID: "c-1:p-2"}}
+
+	add, remove := mcappTargetChanges(current, []string{"c-1:p-2", "c-2:p-3"})
+	assert.Equal(t, []string{"c-2:p-3"}, add)
+	assert.Equal(t, []string{"c-1:p-1"}, remove)
+
+	add, remove = mcappTargetChanges(current, []string{"c-1:p-1", "c-1:p-2"})
+	assert.Empty(t, add)
+	assert.Empty(t, remove)
+}
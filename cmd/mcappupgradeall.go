@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	gover "github.com/hashicorp/go-version"
+	"github.com/rancher/cli/cliclient"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+const upgradeAllMultiClusterAppDescription = `
+Upgrades every multi-cluster app whose template has a newer version matching --constraint to the
+newest such version, keeping its answers, roles and upgrade strategy: an app with a rolling
+update strategy is rolled out to its targets in batches as usual.
+
+--filter narrows the apps by the fields of the table, such as catalog, template or name. Versions
+the chart doesn't support upgrading to are passed over unless --force is set.
+
+The constraint is a list of comparisons such as ">= 1.2, < 2.0", or one of:
+
+	^1.2.3   the versions of major 1 from 1.2.3, ^1.x has all of major 1
+	~1.2.3   the versions of minor 1.2 from 1.2.3
+	1.2.x    the versions of minor 1.2
+
+Example:
+	# Print the upgrades without applying them
+	$ rancher mcapp upgrade-all --filter catalog=library --constraint '^1.x' --dry-run
+
+	$ rancher mcapp upgrade-all --filter catalog=library --constraint '~2.4'
+`
+
+// MultiClusterAppUpgrade is the upgrade of a multi-cluster app to the newest
+// version matching the constraint of upgrade-all.
+type MultiClusterAppUpgrade struct {
+	ID       string
+	Name     string
+	Catalog  string
+	Template string
+	Current  string
+	Target   string
+	Strategy string
+	Status   string
+	app      *managementClient.MultiClusterApp
+	targetID string
+}
+
+var caretOrTildeConstraint = regexp.MustCompile(`^([\^~])\s*v?(\d+)(?:\.(\d+|[xX*]))?(?:\.(\d+|[xX*]))?$`)
+
+var wildcardConstraint = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?\.[xX*]$`)
+
+// parseVersionConstraint parses the --constraint of upgrade-all: caret,
+// tilde and wildcard ranges are turned into the comparisons go-version
+// understands.
+func parseVersionConstraint(constraint string) (gover.Constraints, error) {
+	constraint = strings.TrimSpace(constraint)
+	if match := caretOrTildeConstraint.FindStringSubmatch(constraint); match != nil {
+		major, _ := strconv.Atoi(match[2])
+		minor, patch := 0, 0
+		minorSet := match[3] != "" && !isVersionWildcard(match[3])
+		if minorSet {
+			minor, _ = strconv.Atoi(match[3])
+		}
+		if match[4] != "" && !isVersionWildcard(match[4]) {
+			patch, _ = strconv.Atoi(match[4])
+		}
+		lower := fmt.Sprintf("%d.%d.%d", major, minor, patch)
+		var upper string
+		switch {
+		case match[1] == "~" && minorSet, match[1] == "^" && major == 0 && minorSet:
+			upper = fmt.Sprintf("%d.%d.0", major, minor+1)
+		default:
+			upper = fmt.Sprintf("%d.0.0", major+1)
+		}
+		constraint = fmt.Sprintf(">= %s, < %s", lower, upper)
+	} else if match := wildcardConstraint.FindStringSubmatch(constraint); match != nil {
+		major, _ := strconv.Atoi(match[1])
+		if match[2] == "" {
+			constraint = fmt.Sprintf(">= %d.0.0, < %d.0.0", major, major+1)
+		} else {
+			minor, _ := strconv.Atoi(match[2])
+			constraint = fmt.Sprintf(">= %d.%d.0, < %d.%d.0", major, minor, major, minor+1)
+		}
+	}
+	constraints, err := gover.NewConstraint(constraint)
+	if err != nil {
+		return nil, NewUsageError(fmt.Errorf("invalid --constraint %q: %w", constraint, err))
+	}
+	return constraints, nil
+}
+
+func isVersionWildcard(part string) bool {
+	return part == "x" || part == "X" || part == "*"
+}
+
+// newestUpgradeVersion returns the newest of versions, sorted oldest first,
+// newer than current and matching constraints, or nil. Unless force is set
+// the versions the chart doesn't support upgrading to are passed over.
+func newestUpgradeVersion(current *managementClient.TemplateVersion, versions []*gover.Version, constraints gover.Constraints, force bool) *gover.Version {
+	from, err := gover.NewVersion(current.Version)
+	if err != nil {
+		return nil
+	}
+	for i := len(versions) - 1; i >= 0; i-- {
+		version := versions[i]
+		if !version.GreaterThan(from) {
+			return nil
+		}
+		if constraints != nil && !constraints.Check(version) {
+			continue
+		}
+		if !force && upgradeConstraint(current, version.Original()) != "" {
+			continue
+		}
+		return version
+	}
+	return nil
+}
+
+func multiClusterAppUpgradeAll(ctx *cli.Context) error {
+	var constraints gover.Constraints
+	if ctx.String("constraint") != "" {
+		var err error
+		if constraints, err = parseVersionConstraint(ctx.String("constraint")); err != nil {
+			return err
+		}
+	}
+	filter, err := newRowFilter(ctx.StringSlice("filter"), "")
+	if err != nil {
+		return NewUsageError(err)
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	collection, err := c.ManagementClient.MultiClusterApp.List(defaultListOpts(ctx))
+	if err != nil {
+		return err
+	}
+	apps, err := listAll(ctx, collection, func(c *managementClient.MultiClusterAppCollection) []managementClient.MultiClusterApp { return c.Data })
+	if err != nil {
+		return err
+	}
+	sort.Slice(apps, func(i, j int) bool { return apps[i].Name < apps[j].Name })
+
+	upgrades, err := planMultiClusterAppUpgrades(c, apps, constraints, ctx.Bool("force"))
+	if err != nil {
+		return err
+	}
+	var selected []*MultiClusterAppUpgrade
+	for _, upgrade := range upgrades {
+		if filter != nil {
+			matched, err := filter.Match(upgrade)
+			if err != nil {
+				return err
+			}
+			if !matched {
+				continue
+			}
+		}
+		selected = append(selected, upgrade)
+	}
+
+	var failed, pending int
+	for _, upgrade := range selected {
+		if upgrade.targetID != "" {
+			pending++
+		}
+	}
+	if pending > 0 && !ctx.Bool("dry-run") {
+		if err := checkPermissions(ctx, managementPermission(c, managementClient.MultiClusterAppType, verbUpdate)); err != nil {
+			return err
+		}
+	}
+	for _, upgrade := range selected {
+		if upgrade.targetID == "" {
+			continue
+		}
+		if ctx.Bool("dry-run") {
+			upgrade.Status = "would upgrade"
+			continue
+		}
+		_, err := c.ManagementClient.MultiClusterApp.Update(upgrade.app, map[string]interface{}{
+			"templateVersionId": upgrade.targetID,
+			"answers":           upgrade.app.Answers,
+			"roles":             upgrade.app.Roles,
+		})
+		if err != nil {
+			logrus.Errorf("Upgrading %s to %s: %v", upgrade.Name, upgrade.Target, err)
+			upgrade.Status = "failed"
+			failed++
+			continue
+		}
+		upgrade.Status = "upgrading"
+	}
+
+	writer := NewTableWriter([][]string{
+		{"NAME", "Name"},
+		{"CATALOG", "Catalog"},
+		{"TEMPLATE", "Template"},
+		{"CURRENT", "Current"},
+		{"TARGET", "Target"},
+		{"STRATEGY", "Strategy"},
+		{"STATUS", "Status"},
+	}, ctx)
+	for _, upgrade := range selected {
+		writer.Write(upgrade)
+	}
+	writer.Close()
+	if err := writer.Err(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return partialErrorf("failed to upgrade %d of %d multi-cluster apps", failed, pending)
+	}
+	return nil
+}
+
+// planMultiClusterAppUpgrades returns the upgrade of each app, with no target
+// when it's up to date. The templates are fetched once, filtered by the
+// version of the server like show-template does.
+func planMultiClusterAppUpgrades(c *cliclient.MasterClient, apps []managementClient.MultiClusterApp, constraints gover.Constraints, force bool) ([]*MultiClusterAppUpgrade, error) {
+	serverVersion, err := getRancherServerVersion(c)
+	if err != nil {
+		return nil, err
+	}
+	templates := map[string]*managementClient.Template{}
+
+	var upgrades []*MultiClusterAppUpgrade
+	for i := range apps {
+		app := &apps[i]
+		current, err := c.ManagementClient.TemplateVersion.ByID(app.TemplateVersionID)
+		if err != nil {
+			return nil, err
+		}
+		upgrade := &MultiClusterAppUpgrade{
+			ID:       app.ID,
+			Name:     app.Name,
+			Current:  current.Version,
+			Strategy: multiClusterAppStrategy(app),
+			Status:   "up to date",
+			app:      app,
+		}
+		if current.ExternalID != "" {
+			externalInfo, err := parseExternalID(current.ExternalID)
+			if err != nil {
+				return nil, err
+			}
+			upgrade.Catalog = externalInfo["catalog"]
+			upgrade.Template = externalInfo["template"]
+		}
+		upgrades = append(upgrades, upgrade)
+
+		link := current.Links["template"]
+		template, ok := templates[link]
+		if !ok {
+			opts := defaultListOpts(nil)
+			opts.Filters["rancherVersion"] = serverVersion
+			template = &managementClient.Template{}
+			if err := c.ManagementClient.Ops.DoGet(link, opts, template); err != nil {
+				return nil, err
+			}
+			templates[link] = template
+		}
+		list, err := getTemplateVersionList(template)
+		if err != nil {
+			return nil, err
+		}
+		version := newestUpgradeVersion(current, list.versions, constraints, force)
+		if version == nil {
+			continue
+		}
+		upgrade.Target = version.Original()
+		upgrade.targetID = templateVersionIDFromVersionLink(list.links[version])
+		upgrade.Status = "pending"
+	}
+	return upgrades, nil
+}
+
+// multiClusterAppStrategy describes how an app is rolled out to its targets
+func multiClusterAppStrategy(app *managementClient.MultiClusterApp) string {
+	if app.UpgradeStrategy == nil || app.UpgradeStrategy.RollingUpdate == nil {
+		return upgradeStrategySimultaneously
+	}
+	rolling := app.UpgradeStrategy.RollingUpdate
+	return fmt.Sprintf("%s (%d every %ds)", upgradeStrategyRollingUpdate, rolling.BatchSize, rolling.Interval)
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"testing"
+
+	gover "github.com/hashicorp/go-version"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersionConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		matching   []string
+		other      []string
+	}{
+		{constraint: "^1.x", matching: []string{"1.0.0", "1.9.3"}, other: []string{"0.9.0", "2.0.0"}},
+		{constraint: "^1.2.3", matching: []string{"1.2.3", "1.8.0"}, other: []string{"1.2.2", "2.0.0"}},
+		{constraint: "^0.3.1", matching: []string{"0.3.1", "0.3.9"}, other: []string{"0.4.0"}},
+		{constraint: "~2.4", matching: []string{"2.4.0", "2.4.7"}, other: []string{"2.5.0", "2.3.9"}},
+		{constraint: "1.2.x", matching: []string{"1.2.0", "1.2.11"}, other: []string{"1.3.0"}},
+		{constraint: "3.x", matching: []string{"3.0.0", "3.7.1"}, other: []string{"4.0.0"}},
+		{constraint: ">= 1.2, < 1.4", matching: []string{"1.3.5"}, other: []string{"1.4.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			constraints, err := parseVersionConstraint(tt.constraint)
+			require.NoError(t, err)
+			for _, version := range tt.matching {
+				assert.True(t, constraints.Check(gover.Must(gover.NewVersion(version))), version)
+			}
+			for _, version := range tt.other {
+				assert.False(t, constraints.Check(gover.Must(gover.NewVersion(version))), version)
+			}
+		})
+	}
+
+	_, err := parseVersionConstraint("latest")
+	assert.Equal(t, ExitCodeUsage, ExitCode(err))
+}
+
+func TestNewestUpgradeVersion(t *testing.T) {
+	var versions []*gover.Version
+	for _, version := range []string{"1.0.0", "1.1.0", "1.2.0", "2.0.0"} {
+		versions = append(versions, gover.Must(gover.NewVersion(version)))
+	}
+	caret, err := parseVersionConstraint("^1.x")
+	require.NoError(t, err)
+
+	current := &managementClient.TemplateVersion{Version: "1.0.0"}
+	assert.Equal(t, "2.0.0", newestUpgradeVersion(current, versions, nil, false).Original())
+	assert.Equal(t, "1.2.0", newestUpgradeVersion(current, versions, caret, false).Original())
+	assert.Nil(t, newestUpgradeVersion(&managementClient.TemplateVersion{Version: "1.2.0"}, versions, caret, false))
+
+	// only 1.1.0 is an upgrade the chart supports
+	current.UpgradeVersionLinks = map[string]string{"1.1.0": "link"}
+	assert.Equal(t, "1.1.0", newestUpgradeVersion(current, versions, caret, false).Original())
+	assert.Equal(t, "1.2.0", newestUpgradeVersion(current, versions, caret, true).Original())
+}
+
+func TestMultiClusterAppStrategy(t *testing.T) {
+	assert.Equal(t, "simultaneously", multiClusterAppStrategy(&managementClient.MultiClusterApp{}))
+	assert.Equal(t, "rolling-update (2 every 30s)", multiClusterAppStrategy(&managementClient.MultiClusterApp{
+		UpgradeStrategy: &managementClient.UpgradeStrategy{
+			RollingUpdate: &managementClient.RollingUpdate{BatchSize: 2, Interval: 30},
+		},
+	}))
+}
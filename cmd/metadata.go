@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli"
+)
+
+var labelFlag = cli.StringSliceFlag{
+	Name:  "label",
+	Usage: "Set a label on the app, can be used multiple times. Example: --label team=payments",
+}
+
+var annotationFlag = cli.StringSliceFlag{
+	Name:  "annotation",
+	Usage: "Set an annotation on the app, can be used multiple times. Example: --annotation source=git@example.com:org/deploy.git",
+}
+
+// appMetadata returns the labels and annotations given with --label and
+// --annotation.
+func appMetadata(ctx *cli.Context) (map[string]string, map[string]string, error) {
+	labels, err := parseKeyValues("label", ctx.StringSlice("label"))
+	if err != nil {
+		return nil, nil, err
+	}
+	annotations, err := parseKeyValues("annotation", ctx.StringSlice("annotation"))
+	if err != nil {
+		return nil, nil, err
+	}
+	return labels, annotations, nil
+}
+
+// parseKeyValues parses the KEY=VALUE entries of a flag, nil is returned
+// without entries so that nothing is sent to the server.
+func parseKeyValues(flag string, entries []string) (map[string]string, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	values := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok || key == "" {
+			return nil, NewUsageError(fmt.Errorf("invalid --%s %q, expected KEY=VALUE", flag, entry))
+		}
+		values[key] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeyValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "no entries",
+		},
+		{
+			name:    "values",
+			entries: []string{"team=payments", "source=git@example.com:org/deploy.git?ref=main", "empty="},
+			want: map[string]string{
+				"team":   "payments",
+				"source": "git@example.com:org/deploy.git?ref=main",
+				"empty":  "",
+			},
+		},
+		{
+			name:    "missing value",
+			entries: []string{"team"},
+			wantErr: `invalid --label "team", expected KEY=VALUE`,
+		},
+		{
+			name:    "missing key",
+			entries: []string{"=payments"},
+			wantErr: `invalid --label "=payments", expected KEY=VALUE`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := parseKeyValues("label", tt.entries)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, values)
+		})
+	}
+}
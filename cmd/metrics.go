@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rancher/norman/types"
+	"github.com/urfave/cli"
+)
+
+// metricsShortcuts are the canned queries; %s is replaced with the PromQL
+// string of a regular expression matching node names.
+var metricsShortcuts = map[string]string{
+	"cpu": `100 * (1 - avg by (nodename) (rate(node_cpu_seconds_total{mode="idle"}[5m])` +
+		` * on(instance) group_left(nodename) node_uname_info{nodename=~%s}))`,
+	"memory": `max by (nodename) (100 * (1 - node_memory_MemAvailable_bytes / node_memory_MemTotal_bytes)` +
+		` * on(instance) group_left(nodename) node_uname_info{nodename=~%s})`,
+}
+
+type promResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+type promSeries struct {
+	Metric map[string]string `json:"metric"`
+	Value  []interface{}     `json:"value"`
+	Values [][]interface{}   `json:"values"`
+}
+
+type MetricData struct {
+	Metric string
+	Labels map[string]string
+	Time   string
+	Value  string
+}
+
+func MetricsCommand() cli.Command {
+	rangeFlags := []cli.Flag{
+		cli.DurationFlag{
+			Name:  "range",
+			Usage: "Query the given time range up to now, e.g. 1h, instead of the current value",
+		},
+		cli.DurationFlag{
+			Name:  "step",
+			Usage: "Resolution of a --range query, by default the range is split in 100 steps",
+		},
+		formatFlag,
+		noHeadersFlag,
+	}
+
+	shortcutFlags := append([]cli.Flag{
+		cli.StringFlag{
+			Name:  "cluster",
+			Usage: "Cluster to query, by default the cluster in the current context",
+		},
+		cli.StringFlag{
+			Name:  "node",
+			Usage: "Only show the node with this name, regular expressions are allowed",
+		},
+	}, rangeFlags...)
+
+	return cli.Command{
+		Name:  "metrics",
+		Usage: "Query the monitoring of a cluster",
+		Description: `
+Queries the Prometheus of the rancher-monitoring app through Rancher. Monitoring must be
+installed in the queried cluster.
+`,
+		Subcommands: []cli.Command{
+			{
+				Name:  "query",
+				Usage: "Run a PromQL query",
+				Description: `
+Example:
+	$ rancher metrics query mycluster 'sum(rate(container_cpu_usage_seconds_total[5m])) by (namespace)'
+	$ rancher metrics query --range 1h --step 30s mycluster 'up'
+`,
+				ArgsUsage: "[CLUSTERNAME/CLUSTERID, QUERY]",
+				Action:    metricsQuery,
+				Flags:     rangeFlags,
+			},
+			{
+				Name:      "cpu",
+				Usage:     "Show the CPU usage of nodes in percent",
+				ArgsUsage: "None",
+				Action:    metricsShortcut("cpu"),
+				Flags:     shortcutFlags,
+			},
+			{
+				Name:      "memory",
+				Usage:     "Show the memory usage of nodes in percent",
+				ArgsUsage: "None",
+				Action:    metricsShortcut("memory"),
+				Flags:     shortcutFlags,
+			},
+		},
+	}
+}
+
+func metricsQuery(ctx *cli.Context) error {
+	if ctx.NArg() < 2 {
+		return showSubcommandUsage(ctx)
+	}
+	return runPrometheusQuery(ctx, ctx.Args().First(), ctx.Args().Get(1))
+}
+
+func metricsShortcut(name string) func(*cli.Context) error {
+	return func(ctx *cli.Context) error {
+		node := ctx.String("node")
+		if node == "" {
+			node = ".+"
+		}
+		return runPrometheusQuery(ctx, ctx.String("cluster"), shortcutQuery(name, node))
+	}
+}
+
+// shortcutQuery returns the canned query name for the nodes matching node.
+// PromQL strings take the escapes of Go strings, so the regular expression is
+// quoted as one.
+func shortcutQuery(name, node string) string {
+	return fmt.Sprintf(metricsShortcuts[name], strconv.Quote(node))
+}
+
+func runPrometheusQuery(ctx *cli.Context, cluster, query string) error {
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	clusterID, err := resolveClusterID(c, cluster)
+	if err != nil {
+		return err
+	}
+
+	url := clusterServiceURL(c, clusterID, "cattle-monitoring-system", "http", "rancher-monitoring-prometheus", "9090")
+	opts := &types.ListOpts{Filters: map[string]interface{}{"query": query}}
+	if span := ctx.Duration("range"); span > 0 {
+		step := ctx.Duration("step")
+		if step <= 0 {
+			step = (span / 100).Round(time.Second)
+			if step < time.Second {
+				step = time.Second
+			}
+		}
+		end := time.Now()
+		url += "/api/v1/query_range"
+		opts.Filters["start"] = end.Add(-span).Unix()
+		opts.Filters["end"] = end.Unix()
+		opts.Filters["step"] = strconv.FormatFloat(step.Seconds(), 'f', -1, 64)
+	} else {
+		url += "/api/v1/query"
+	}
+
+	resp := &promResponse{}
+	if err := c.ManagementClient.Ops.DoGet(url, opts, resp); err != nil {
+		return fmt.Errorf("querying Prometheus of cluster %s, is monitoring installed? %w", clusterID, err)
+	}
+	if resp.Status != "success" {
+		return fmt.Errorf("query failed: %s", resp.Error)
+	}
+
+	rows, err := promRows(resp)
+	if err != nil {
+		return err
+	}
+
+	writer := NewTableWriter([][]string{
+		{"METRIC", "Metric"},
+		{"TIME", "Time"},
+		{"VALUE", "Value"},
+	}, ctx)
+
+	defer writer.Close()
+
+	for _, row := range rows {
+		writer.Write(row)
+	}
+
+	return writer.Err()
+}
+
+// promRows flattens a query result into one row per sample
+func promRows(resp *promResponse) ([]*MetricData, error) {
+	var rows []*MetricData
+
+	switch resp.Data.ResultType {
+	case "scalar", "string":
+		var sample []interface{}
+		if err := json.Unmarshal(resp.Data.Result, &sample); err != nil {
+			return nil, err
+		}
+		return append(rows, promRow(nil, sample)), nil
+	}
+
+	var series []promSeries
+	if err := json.Unmarshal(resp.Data.Result, &series); err != nil {
+		return nil, err
+	}
+	for _, s := range series {
+		if s.Value != nil {
+			rows = append(rows, promRow(s.Metric, s.Value))
+		}
+		for _, sample := range s.Values {
+			rows = append(rows, promRow(s.Metric, sample))
+		}
+	}
+	return rows, nil
+}
+
+// promRow converts a [timestamp, "value"] sample
+func promRow(metric map[string]string, sample []interface{}) *MetricData {
+	row := &MetricData{
+		Metric: formatMetric(metric),
+		Labels: metric,
+	}
+	if len(sample) == 2 {
+		if ts, ok := sample[0].(float64); ok {
+			row.Time = time.Unix(0, int64(ts*float64(time.Second))).UTC().Format(time.RFC3339)
+		}
+		row.Value = fmt.Sprint(sample[1])
+	}
+	return row
+}
+
+// formatMetric renders a metric the way Prometheus does, name{label="value"}
+func formatMetric(metric map[string]string) string {
+	var labels []string
+	for k, v := range metric {
+		if k != "__name__" {
+			labels = append(labels, fmt.Sprintf("%s=%q", k, v))
+		}
+	}
+	sort.Strings(labels)
+	if len(labels) == 0 {
+		return metric["__name__"]
+	}
+	return metric["__name__"] + "{" + strings.Join(labels, ",") + "}"
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromRows(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []MetricData
+	}{
+		{
+			name: "vector",
+			body: `{"status":"success","data":{"resultType":"vector","result":[` +
+				`{"metric":{"__name__":"up","job":"node","instance":"10.0.0.1:9100"},"value":[1700000000,"1"]}]}}`,
+			want: []MetricData{{Metric: `up{instance="10.0.0.1:9100",job="node"}`, Time: "2023-11-14T22:13:20Z", Value: "1"}},
+		},
+		{
+			name: "matrix",
+			body: `{"status":"success","data":{"resultType":"matrix","result":[` +
+				`{"metric":{"nodename":"n1"},"values":[[1700000000,"10.5"],[1700000030.5,"11"]]}]}}`,
+			want: []MetricData{
+				{Metric: `{nodename="n1"}`, Time: "2023-11-14T22:13:20Z", Value: "10.5"},
+				{Metric: `{nodename="n1"}`, Time: "2023-11-14T22:13:50Z", Value: "11"},
+			},
+		},
+		{
+			name: "scalar",
+			body: `{"status":"success","data":{"resultType":"scalar","result":[1700000000,"42"]}}`,
+			want: []MetricData{{Time: "2023-11-14T22:13:20Z", Value: "42"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &promResponse{}
+			require.NoError(t, json.Unmarshal([]byte(tt.body), resp))
+
+			rows, err := promRows(resp)
+			require.NoError(t, err)
+			require.Len(t, rows, len(tt.want))
+			for i, row := range rows {
+				assert.Equal(t, tt.want[i].Metric, row.Metric)
+				assert.Equal(t, tt.want[i].Time, row.Time)
+				assert.Equal(t, tt.want[i].Value, row.Value)
+			}
+		})
+	}
+}
+
+func TestShortcutQuery(t *testing.T) {
+	tests := []struct {
+		node string
+		want string
+	}{
+		{node: ".+", want: `node_uname_info{nodename=~".+"}`},
+		{node: `worker\.1`, want: `node_uname_info{nodename=~"worker\\.1"}`},
+		{node: `a"b`, want: `node_uname_info{nodename=~"a\"b"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.node, func(t *testing.T) {
+			assert.Contains(t, shortcutQuery("memory", tt.node), tt.want)
+		})
+	}
+}
@@ -0,0 +1,394 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rancher/cli/cliclient"
+	"github.com/rancher/norman/clientbase"
+	"github.com/rancher/norman/types"
+	"github.com/urfave/cli"
+)
+
+const (
+	catalogAppType           = "catalog.cattle.io.app"
+	projectHelmChartType     = "helm.cattle.io.projecthelmchart"
+	projectMonitoringName    = "project-monitoring"
+	projectMonitoringAPI     = "monitoring.cattle.io/v1alpha1"
+	prometheusFederator      = "Prometheus Federator"
+	registrationNamespaceTTL = 2 * time.Minute
+
+	monitoringChart     = "rancher-monitoring"
+	monitoringNamespace = "cattle-monitoring-system"
+)
+
+type ProjectHelmChart struct {
+	types.Resource
+	Metadata objectMeta `json:"metadata,omitempty"`
+	Status   struct {
+		Status        string `json:"status,omitempty"`
+		StatusMessage string `json:"statusMessage,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+type CatalogApp struct {
+	types.Resource
+	Metadata objectMeta `json:"metadata,omitempty"`
+	Spec     struct {
+		Chart struct {
+			Metadata struct {
+				Version string `json:"version,omitempty"`
+			} `json:"metadata,omitempty"`
+		} `json:"chart,omitempty"`
+	} `json:"spec,omitempty"`
+	Status struct {
+		Summary struct {
+			State string `json:"state,omitempty"`
+		} `json:"summary,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+var monitoringAnswersFlag = cli.StringFlag{
+	Name:  "answers,a",
+	Usage: "YAML or JSON file with the values of the monitoring chart. Use - to read it from stdin",
+}
+
+func monitoringCommand(enable, disable, status func(*cli.Context) error, scope, description string, enableFlags ...cli.Flag) cli.Command {
+	argsUsage := "[" + strings.ToUpper(scope) + "NAME/" + strings.ToUpper(scope) + "ID]"
+	return cli.Command{
+		Name:        "monitoring",
+		Usage:       "Operations on the monitoring of a " + scope,
+		Description: description,
+		Subcommands: []cli.Command{
+			{
+				Name:      "enable",
+				Usage:     "Deploy monitoring in a " + scope,
+				ArgsUsage: argsUsage,
+				Action:    enable,
+				Flags:     append([]cli.Flag{monitoringAnswersFlag, expandEnvFlag}, enableFlags...),
+			},
+			{
+				Name:      "disable",
+				Usage:     "Remove the monitoring of a " + scope,
+				ArgsUsage: argsUsage,
+				Action:    disable,
+			},
+			{
+				Name:      "status",
+				Usage:     "Show the status of the monitoring of a " + scope,
+				ArgsUsage: argsUsage,
+				Action:    status,
+			},
+		},
+	}
+}
+
+// ProjectMonitoringCommand is the 'project monitoring' sub-command
+func ProjectMonitoringCommand() cli.Command {
+	return monitoringCommand(projectMonitoringEnable, projectMonitoringDisable, projectMonitoringStatus, "project", `
+Manages the monitoring stack of a project, which Prometheus Federator deploys in the
+cattle-project-<PROJECTID> namespace. Prometheus Federator must be installed in the cluster.
+
+Example:
+	# Enable the monitoring of every project of the current cluster
+	$ for p in $(rancher project ls -q); do rancher project monitoring enable $p; done
+`)
+}
+
+// ClusterMonitoringCommand is the 'cluster monitoring' sub-command
+func ClusterMonitoringCommand() cli.Command {
+	return monitoringCommand(clusterMonitoringEnable, clusterMonitoringDisable, clusterMonitoringStatus, "cluster", `
+Manages the rancher-monitoring app of a cluster, installed from the rancher-charts repository
+in the `+monitoringNamespace+` namespace.
+
+Example:
+	$ rancher cluster monitoring enable --answers monitoring-values.yaml mycluster
+`, cli.StringFlag{
+		Name:  "version",
+		Usage: "Version of the chart, by default the latest version",
+	})
+}
+
+// monitoringValues returns the chart values of the --answers file
+func monitoringValues(ctx *cli.Context) (map[string]interface{}, error) {
+	if ctx.String("answers") == "" {
+		return nil, nil
+	}
+	values, err := parseFile(ctx, ctx.String("answers"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", ctx.String("answers"), err)
+	}
+	return values, nil
+}
+
+// projectV1Client returns the project named or identified by the first
+// argument and a client for the /v1 API of its cluster.
+func projectV1Client(ctx *cli.Context) (string, *clientbase.APIBaseClient, error) {
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	resource, err := Lookup(c, ctx.Args().First(), "project")
+	if err != nil {
+		return "", nil, err
+	}
+	clusterID, _, _ := strings.Cut(resource.ID, ":")
+	client, err := cliclient.NewClusterV1Client(c.UserConfig, clusterID)
+	if err != nil {
+		return "", nil, err
+	}
+	if _, ok := client.Types[projectHelmChartType]; !ok {
+		return "", nil, fmt.Errorf("%s is not installed in cluster %s or you don't have access to it", prometheusFederator, clusterID)
+	}
+	return resource.ID, client, nil
+}
+
+func projectMonitoringEnable(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+	values, err := monitoringValues(ctx)
+	if err != nil {
+		return err
+	}
+	projectID, client, err := projectV1Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = client.ByID(projectHelmChartType, projectMonitoringID(projectID), &ProjectHelmChart{})
+	if err == nil {
+		fmt.Printf("Monitoring is already enabled in project %s\n", ctx.Args().First())
+		return nil
+	}
+	if !clientbase.IsNotFound(err) {
+		return err
+	}
+
+	if err := enableProjectMonitoring(client, projectID, values); err != nil {
+		return err
+	}
+	fmt.Printf("Enabled monitoring of project %s\n", ctx.Args().First())
+	return nil
+}
+
+func projectMonitoringDisable(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+	projectID, client, err := projectV1Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	chart := &ProjectHelmChart{}
+	err = client.ByID(projectHelmChartType, projectMonitoringID(projectID), chart)
+	if clientbase.IsNotFound(err) {
+		fmt.Printf("Monitoring is not enabled in project %s\n", ctx.Args().First())
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := client.Delete(&chart.Resource); err != nil {
+		return err
+	}
+	fmt.Printf("Disabled monitoring of project %s\n", ctx.Args().First())
+	return nil
+}
+
+func projectMonitoringStatus(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+	projectID, client, err := projectV1Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	chart := &ProjectHelmChart{}
+	err = client.ByID(projectHelmChartType, projectMonitoringID(projectID), chart)
+	if clientbase.IsNotFound(err) {
+		fmt.Printf("%s: disabled\n", ctx.Args().First())
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: %s\n", ctx.Args().First(), monitoringState(valueOrDefault(chart.Status.Status, "Pending"), chart.Status.StatusMessage))
+	return nil
+}
+
+func clusterMonitoringEnable(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+	values, err := monitoringValues(ctx)
+	if err != nil {
+		return err
+	}
+
+	client, err := getClusterV1Client(ctx, ctx.Args().First(), clusterRepoType, "Apps & Marketplace")
+	if err != nil {
+		return err
+	}
+	err = client.ByID(catalogAppType, monitoringNamespace+"/"+monitoringChart, &CatalogApp{})
+	if err == nil {
+		fmt.Printf("Monitoring is already enabled in cluster %s\n", ctx.Args().First())
+		return nil
+	}
+	if !clientbase.IsNotFound(err) {
+		return err
+	}
+
+	repo := &ClusterRepo{}
+	if err := client.ByID(clusterRepoType, "rancher-charts", repo); err != nil {
+		return err
+	}
+	version := ctx.String("version")
+	if version == "" {
+		if version, err = latestChartVersion(client, repo, monitoringChart); err != nil {
+			return err
+		}
+	}
+
+	input := &chartInstallAction{
+		Namespace: monitoringNamespace,
+		Wait:      true,
+		Charts: []chartInstall{
+			{ChartName: monitoringChart + "-crd", Version: version, ReleaseName: monitoringChart + "-crd"},
+			{ChartName: monitoringChart, Version: version, ReleaseName: monitoringChart, Values: values},
+		},
+	}
+	operation := &chartOperation{}
+	if err := client.Action(clusterRepoType, "install", &repo.Resource, input, operation); err != nil {
+		return err
+	}
+
+	fmt.Printf("Installing %s %s in cluster %s, operation %s/%s\n", monitoringChart, version,
+		ctx.Args().First(), operation.OperationNamespace, operation.OperationName)
+	return nil
+}
+
+func clusterMonitoringDisable(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+	client, err := getClusterV1Client(ctx, ctx.Args().First(), catalogAppType, "Apps & Marketplace")
+	if err != nil {
+		return err
+	}
+
+	// the CRDs are removed last, the monitoring app still uses them
+	uninstalled := false
+	for _, release := range []string{monitoringChart, monitoringChart + "-crd"} {
+		app := &CatalogApp{}
+		err := client.ByID(catalogAppType, monitoringNamespace+"/"+release, app)
+		if clientbase.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := client.Action(catalogAppType, "uninstall", &app.Resource, map[string]interface{}{}, &chartOperation{}); err != nil {
+			return err
+		}
+		uninstalled = true
+	}
+
+	if !uninstalled {
+		fmt.Printf("Monitoring is not enabled in cluster %s\n", ctx.Args().First())
+		return nil
+	}
+	fmt.Printf("Uninstalling %s from cluster %s\n", monitoringChart, ctx.Args().First())
+	return nil
+}
+
+func clusterMonitoringStatus(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+	client, err := getClusterV1Client(ctx, ctx.Args().First(), catalogAppType, "Apps & Marketplace")
+	if err != nil {
+		return err
+	}
+
+	app := &CatalogApp{}
+	err = client.ByID(catalogAppType, monitoringNamespace+"/"+monitoringChart, app)
+	if clientbase.IsNotFound(err) {
+		fmt.Printf("%s: disabled\n", ctx.Args().First())
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: %s %s\n", ctx.Args().First(), valueOrDefault(app.Status.Summary.State, "unknown"),
+		app.Spec.Chart.Metadata.Version)
+	return nil
+}
+
+func monitoringState(status, message string) string {
+	if message == "" {
+		return status
+	}
+	return status + " (" + message + ")"
+}
+
+// projectRegistrationNamespace returns the namespace Prometheus Federator
+// creates for a project, where the ProjectHelmChart of the project goes.
+func projectRegistrationNamespace(projectID string) string {
+	_, name, _ := strings.Cut(projectID, ":")
+	return "cattle-project-" + name
+}
+
+// projectMonitoringID returns the /v1 ID of the ProjectHelmChart of a project
+func projectMonitoringID(projectID string) string {
+	return projectRegistrationNamespace(projectID) + "/" + projectMonitoringName
+}
+
+// enableProjectMonitoring deploys the monitoring stack of a project by
+// creating its ProjectHelmChart, which Prometheus Federator installs. Its
+// namespace is created by Prometheus Federator once the project has a
+// namespace, so it is waited for.
+func enableProjectMonitoring(client *clientbase.APIBaseClient, projectID string, values map[string]interface{}) error {
+	if _, ok := client.Types[projectHelmChartType]; !ok {
+		return fmt.Errorf("%s is not installed in the cluster of project %s or you don't have access to it", prometheusFederator, projectID)
+	}
+
+	namespace := projectRegistrationNamespace(projectID)
+	waitCtx, cancel := waitContext(registrationNamespaceTTL)
+	defer cancel()
+
+	err := pollUntil(waitCtx, newBackoff(pollInitialInterval, pollMaxInterval), func() (bool, error) {
+		err := client.ByID("namespace", namespace, &types.Resource{})
+		if clientbase.IsNotFound(err) {
+			return false, nil
+		}
+		return err == nil, err
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return timeoutErrorf("timed out waiting for namespace %s, the project needs a namespace to be monitored", namespace)
+	}
+	if err != nil {
+		return err
+	}
+
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	chart := map[string]interface{}{
+		"type": projectHelmChartType,
+		"metadata": map[string]interface{}{
+			"name":      projectMonitoringName,
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"helmApiVersion": projectMonitoringAPI,
+			"values":         values,
+		},
+	}
+	return client.Create(projectHelmChartType, chart, &types.Resource{})
+}
@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectMonitoringID(t *testing.T) {
+	assert.Equal(t, "cattle-project-p-x7k2p/project-monitoring", projectMonitoringID("c-abc12:p-x7k2p"))
+}
+
+func TestMonitoringState(t *testing.T) {
+	assert.Equal(t, "Deployed", monitoringState("Deployed", ""))
+	assert.Equal(t, "Failed (values are invalid)", monitoringState("Failed", "values are invalid"))
+}
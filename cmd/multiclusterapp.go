@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"reflect"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/rancher/cli/cliclient"
@@ -13,6 +15,7 @@ import (
 	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
@@ -33,14 +36,69 @@ Example:
 	# Install the redis template and set target projects to install
 	$ rancher multiclusterapp install --target mycluster:Default --target c-98pjr:p-w6c5f redis appFoo
 
+	# Install the redis template with labels and annotations on the multi-cluster app
+	$ rancher multiclusterapp install --label team=payments --annotation cost-center=cc-123 redis appFoo
+
 	# Block cli until installation has finished or encountered an error. Use after multiclusterapp install.
 	$ rancher wait <multiclusterapp-id>
+
+	# Install into many projects and wait until every target is active, reporting the failed ones
+	$ rancher multiclusterapp install --target c-98pjr:p-w6c5f --target c-x7kq2:p-4lm9d --wait-policy all --continue-on-error redis appFoo
+
+	# Wait until the apps of every target and their workloads are active, not only installed
+	$ rancher multiclusterapp install --wait-for healthy --target c-98pjr:p-w6c5f redis appFoo
+
+	# Wait for every target and print the result as JSON for a CI system to archive
+	$ rancher multiclusterapp install --wait-policy all --output json redis appFoo > result.json
+
+	# Fail right away when a cluster of the targets is unavailable or has no node accepting workloads
+	$ rancher multiclusterapp install --validate-targets --target c-98pjr:p-w6c5f --target c-x7kq2:p-4lm9d redis appFoo
+
+	# Install into the target projects listed in a file, see below
+	$ rancher multiclusterapp install --targets-file targets.yaml redis appFoo
+
+	# Install the redis template starting from the answers of another multi-cluster app. A
+	# multi-cluster app reinstalled within a week of being deleted with
+	# 'rancher multiclusterapp delete' starts from the answers it had.
+	$ rancher multiclusterapp install --from-app appBar redis appFoo
+
+	# Install the multi-cluster app, or upgrade it with the version and answers given when it's
+	# already installed. Its targets are kept, its roles and upgrade strategy are changed if given.
+	$ rancher multiclusterapp install --install-or-upgrade --version 1.0.2 redis appFoo
+`
+	upgradeMultiClusterAppDescription = `
+Upgrade a multi-cluster app to another version of its template.
+
+With --targets-file the targets of the app become the projects listed in the file: the projects
+no longer listed are removed before the upgrade and the new ones are added after it.
+
+Example:
+	# Upgrade the 'appFoo' app to the 0.2.0 version
+	$ rancher multiclusterapp upgrade appFoo 0.2.0
+
+	# Upgrade the 'appFoo' app and sync its targets with a file, see below
+	$ rancher multiclusterapp upgrade --targets-file targets.yaml appFoo 0.2.0
+`
+	rollbackMultiClusterAppDescription = `
+Roll back a multi-cluster app to one of its revisions.
+
+Before rolling back, the template version and the answers the app gets from the revision are
+printed, with a warning when the catalog no longer has the template version, and the rollback
+is confirmed unless --force is given.
+
+Example:
+	# Show the revisions of the 'appFoo' app
+	$ rancher multiclusterapp rollback --show-revisions appFoo
+
+	# Roll back the 'appFoo' app to a revision without asking for confirmation
+	$ rancher multiclusterapp rollback --force appFoo mcapprevision-8kx2d
 `
 	upgradeStrategySimultaneously = "simultaneously"
 	upgradeStrategyRollingUpdate  = "rolling-update"
 	argUpgradeStrategy            = "upgrade-strategy"
 	argUpgradeBatchSize           = "upgrade-batch-size"
 	argUpgradeBatchInterval       = "upgrade-batch-interval"
+	templateVersionWorkers        = 8
 )
 
 var (
@@ -49,10 +107,13 @@ var (
 )
 
 type MultiClusterAppData struct {
-	ID      string
-	App     managementClient.MultiClusterApp
-	Version string
-	Targets string
+	ID        string
+	App       managementClient.MultiClusterApp
+	Version   string
+	Targets   string
+	TargetIDs string
+	Age       string
+	Message   string
 }
 
 type scopeAnswers struct {
@@ -89,22 +150,25 @@ func MultiClusterAppCommand() cli.Command {
 				Usage:     "Delete a multi-cluster app",
 				Action:    multiClusterAppDelete,
 				ArgsUsage: "[APP_NAME]",
+				Flags:     deleteFlags,
 			},
 			{
-				Name:        "install",
-				Usage:       "Install a multi-cluster app",
-				Description: installMultiClusterAppDescription,
-				Action:      multiClusterAppTemplateInstall,
-				ArgsUsage:   "[TEMPLATE_NAME, APP_NAME]...",
+				Name:         "install",
+				Usage:        "Install a multi-cluster app",
+				Description:  installMultiClusterAppDescription + targetsFileDescription,
+				Action:       multiClusterAppTemplateInstall,
+				BashComplete: templateVersionCompletion,
+				ArgsUsage:    "[TEMPLATE_NAME, APP_NAME]...",
 				Flags: []cli.Flag{
 					cli.StringFlag{
 						Name:  "answers,a",
-						Usage: "Path to an answers file, the format of the file is a map with key:value. This supports JSON and YAML.",
+						Usage: "Path to an answers file, the format of the file is a map with key:value. This supports JSON and YAML. Use - to read it from stdin",
 					},
 					cli.StringFlag{
 						Name:  "values",
-						Usage: "Path to a helm values file.",
+						Usage: "Path to a helm values file. Use - to read it from stdin",
 					},
+					expandEnvFlag,
 					cli.StringSliceFlag{
 						Name: "set",
 						Usage: "Set answers for the template, can be used multiple times. You can set overriding answers for specific clusters or projects " +
@@ -115,6 +179,8 @@ func MultiClusterAppCommand() cli.Command {
 						Usage: "Set string answers for the template (Skips Helm's type conversion), can be used multiple times. You can set overriding answers for specific clusters or projects " +
 							"by providing cluster ID or project ID as the prefix. Example: --set-string foo=bar --set-string c-rvcrl:foo=bar --set-string c-rvcrl:p-8w2x8:foo=bar",
 					},
+					labelFlag,
+					annotationFlag,
 					cli.StringFlag{
 						Name:  "version",
 						Usage: "Version of the template to use",
@@ -127,6 +193,7 @@ func MultiClusterAppCommand() cli.Command {
 						Name:  "target,t",
 						Usage: "Target project names/ids to install the app into",
 					},
+					targetsFileFlag,
 					cli.StringSliceFlag{
 						Name: "role",
 						Usage: "Set roles required to launch/manage the apps in target projects. For example, set \"project-member\" role when the app needs to manage resources " +
@@ -166,34 +233,73 @@ func MultiClusterAppCommand() cli.Command {
 						Name:  "helm-wait",
 						Usage: "Helm will wait for as long as timeout value, for installed resources to be ready (pods, PVCs, deployments, etc.). Example: --helm-wait",
 					},
+					cli.BoolFlag{
+						Name:  "wait",
+						Usage: "Wait for the multi-cluster app to become active, showing the progress of each target",
+					},
+					waitForFlag,
+					cli.StringFlag{
+						Name:  "wait-policy",
+						Usage: "Which targets to wait for: 'all' of them to be active, 'any' one of them or 'none'. Implies --wait",
+					},
+					cli.BoolFlag{
+						Name:  "continue-on-error",
+						Usage: "Keep waiting for the other targets when a target fails, exiting with code 7 when only some targets are active",
+					},
+					cli.IntFlag{
+						Name:  "wait-timeout",
+						Usage: "Time in seconds to wait for the multi-cluster app with --wait",
+						Value: 600,
+					},
+					installOutputFlag,
+					cleanupOnCancelFlag,
+					fromAppFlag,
+					installOrUpgradeFlag,
+					cli.BoolFlag{
+						Name:  "validate-targets",
+						Usage: "Check that the clusters of the targets are active, connected and have a node accepting workloads before creating the multi-cluster app",
+					},
 				},
 			},
 			{
-				Name:      "rollback",
-				Usage:     "Rollback a multi-cluster app to a previous version",
-				Action:    multiClusterAppRollback,
-				ArgsUsage: "[APP_NAME/APP_ID, REVISION_ID/REVISION_NAME]",
+				Name:        "rollback",
+				Usage:       "Rollback a multi-cluster app to a previous version",
+				Description: rollbackMultiClusterAppDescription,
+				Action:      multiClusterAppRollback,
+				ArgsUsage:   "[APP_NAME/APP_ID, REVISION_ID/REVISION_NAME]",
 				Flags: []cli.Flag{
 					cli.BoolFlag{
 						Name:  "show-revisions,r",
 						Usage: "Show revisions available to rollback to",
 					},
+					timestampsFlag,
+					forceFlag,
 				},
 			},
 			{
-				Name:      "upgrade",
-				Usage:     "Upgrade an app to a newer version",
-				Action:    multiClusterAppUpgrade,
-				ArgsUsage: "[APP_NAME/APP_ID VERSION]",
+				Name:        "prune-revisions",
+				Usage:       "Delete the old revisions of a multi-cluster app",
+				Description: pruneRevisionsDescription,
+				ArgsUsage:   "[APP_NAME/APP_ID]",
+				Action:      multiClusterAppPruneRevisions,
+				Flags:       pruneRevisionsFlags,
+			},
+			{
+				Name:        "upgrade",
+				Usage:       "Upgrade an app to a newer version",
+				Description: upgradeMultiClusterAppDescription + targetsFileDescription,
+				Action:      multiClusterAppUpgrade,
+				ArgsUsage:   "[APP_NAME/APP_ID VERSION]",
 				Flags: []cli.Flag{
 					cli.StringFlag{
 						Name:  "answers,a",
-						Usage: "Path to an answers file, the format of the file is a map with key:value. Supports JSON and YAML",
+						Usage: "Path to an answers file, the format of the file is a map with key:value. Supports JSON and YAML. Use - to read it from stdin",
 					},
 					cli.StringFlag{
 						Name:  "values",
-						Usage: "Path to a helm values file.",
+						Usage: "Path to a helm values file. Use - to read it from stdin",
 					},
+					expandEnvFlag,
 					cli.StringSliceFlag{
 						Name: "set",
 						Usage: "Set answers for the template, can be used multiple times. You can set overriding answers for specific clusters or projects " +
@@ -208,15 +314,22 @@ func MultiClusterAppCommand() cli.Command {
 						Name:  "reset",
 						Usage: "Reset all catalog app answers",
 					},
+					mergeAnswersFlag,
+					showAnswersFlag,
 					cli.StringSliceFlag{
 						Name: "role,r",
 						Usage: "Set roles required to launch/manage the apps in target projects. Specified roles on upgrade will override all the original roles. " +
 							"For example, provide all existing roles if you want to add additional roles. Leave it empty to keep current roles",
 					},
+					targetsFileFlag,
 					cli.BoolFlag{
 						Name:  "show-versions,v",
 						Usage: "Display versions available to upgrade to",
 					},
+					cli.BoolFlag{
+						Name:  "force",
+						Usage: "Upgrade to a version the chart doesn't support upgrading to",
+					},
 					cli.StringFlag{
 						Name:  argUpgradeStrategy,
 						Usage: "Strategy for upgrade. Valid options are \"rolling-update\" and \"simultaneously\"",
@@ -231,6 +344,30 @@ func MultiClusterAppCommand() cli.Command {
 					},
 				},
 			},
+			{
+				Name:        "upgrade-all",
+				Usage:       "Upgrade every app to the newest version matching a constraint",
+				Description: upgradeAllMultiClusterAppDescription,
+				Action:      multiClusterAppUpgradeAll,
+				ArgsUsage:   "None",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "constraint",
+						Usage: "Only upgrade to versions matching a constraint, e.g. '^1.x' or '>= 1.2, < 2.0'",
+					},
+					filterFlag,
+					cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Print the upgrades without applying them",
+					},
+					cli.BoolFlag{
+						Name:  "force",
+						Usage: "Upgrade to versions the chart doesn't support upgrading to",
+					},
+					formatFlag,
+					noHeadersFlag,
+				},
+			},
 			{
 				Name:        "add-project",
 				Usage:       "Add target projects to a multi-cluster app",
@@ -240,12 +377,13 @@ func MultiClusterAppCommand() cli.Command {
 				Flags: []cli.Flag{
 					cli.StringFlag{
 						Name:  "answers,a",
-						Usage: "Path to an answers file that provides overriding answers for the new target projects, the format of the file is a map with key:value. Supports JSON and YAML",
+						Usage: "Path to an answers file that provides overriding answers for the new target projects, the format of the file is a map with key:value. Supports JSON and YAML. Use - to read it from stdin",
 					},
 					cli.StringFlag{
 						Name:  "values",
-						Usage: "Path to a helm values file that provides overriding answers for the new target projects",
+						Usage: "Path to a helm values file that provides overriding answers for the new target projects. Use - to read it from stdin",
 					},
+					expandEnvFlag,
 					cli.StringSliceFlag{
 						Name:  "set",
 						Usage: "Set overriding answers for the new target projects",
@@ -297,6 +435,25 @@ func MultiClusterAppCommand() cli.Command {
 					formatFlag,
 				},
 			},
+			{
+				Name:        "export-helm",
+				Usage:       "Print the helm command and values file equivalent to a multi-cluster app in one target",
+				Description: exportHelmDescription,
+				ArgsUsage:   "[APP_NAME/APP_ID]",
+				Action:      exportMultiClusterAppHelm,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "target,t",
+						Usage: "Target project to export, as cluster:project names or a project ID",
+					},
+					cli.StringFlag{
+						Name:  "values-file",
+						Usage: "File the values are written to",
+						Value: "values.yaml",
+					},
+					forceFlag,
+				},
+			},
 			{
 				Name:        "list-templates",
 				Aliases:     []string{"lt"},
@@ -316,9 +473,10 @@ func MultiClusterAppCommand() cli.Command {
 				Name:        "show-template",
 				Aliases:     []string{"st"},
 				Usage:       "Show versions available to install for an app template",
-				Description: "\nShow all available versions of an app template",
+				Description: templateShowDescription,
 				ArgsUsage:   "[TEMPLATE_ID]",
 				Action:      templateShow,
+				Flags:       templateShowFlags,
 			},
 			{
 				Name:      "show-app",
@@ -328,6 +486,7 @@ func MultiClusterAppCommand() cli.Command {
 				Action:    showMultiClusterApp,
 				Flags: []cli.Flag{
 					formatFlag,
+					timestampsFlag,
 					cli.BoolFlag{
 						Name:  "show-roles",
 						Usage: "Show roles required to manage the app",
@@ -339,12 +498,16 @@ func MultiClusterAppCommand() cli.Command {
 }
 
 func multiClusterAppLs(ctx *cli.Context) error {
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	collection, err := c.ManagementClient.MultiClusterApp.List(defaultListOpts(ctx))
+	collection, err := c.ManagementClient.MultiClusterApp.List(filteredListOpts(ctx))
+	if err != nil {
+		return err
+	}
+	collection.Data, err = listAll(ctx, collection, func(c *managementClient.MultiClusterAppCollection) []managementClient.MultiClusterApp { return c.Data })
 	if err != nil {
 		return err
 	}
@@ -355,6 +518,9 @@ func multiClusterAppLs(ctx *cli.Context) error {
 		{"STATE", "App.State"},
 		{"VERSION", "Version"},
 		{"TARGET_PROJECTS", "Targets"},
+		{"AGE", "Age"},
+		{"TARGET_IDS", "TargetIDs", wideFormat},
+		{"MESSAGE", "Message", wideFormat},
 	}, ctx)
 
 	defer writer.Close()
@@ -364,56 +530,129 @@ func multiClusterAppLs(ctx *cli.Context) error {
 		return err
 	}
 
-	templateVersionCache := make(map[string]string)
+	templateVersionIDs := make([]string, 0, len(collection.Data))
 	for _, item := range collection.Data {
-		version, err := getTemplateVersion(c.ManagementClient, templateVersionCache, item.TemplateVersionID)
-		if err != nil {
-			return err
-		}
+		templateVersionIDs = append(templateVersionIDs, item.TemplateVersionID)
+	}
+	versions, err := getTemplateVersions(c.ManagementClient, templateVersionIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range collection.Data {
+		version := versions[item.TemplateVersionID]
 		targetNames := getReadableTargetNames(clusterCache, projectCache, item.Targets)
+		var targetIDs []string
+		for _, target := range item.Targets {
+			targetIDs = append(targetIDs, target.ProjectID)
+		}
 		writer.Write(&MultiClusterAppData{
-			ID:      item.ID,
-			App:     item,
-			Version: version,
-			Targets: strings.Join(targetNames, ","),
+			ID:        item.ID,
+			App:       item,
+			Version:   version,
+			Targets:   strings.Join(targetNames, ","),
+			TargetIDs: strings.Join(targetIDs, ","),
+			Age:       formatAge(ctx, item.Created),
+			Message:   FormatMessage(item.TransitioningMessage),
 		})
 	}
 	return writer.Err()
 }
 
-func getTemplateVersion(client *managementClient.Client, templateVersionCache map[string]string, ID string) (string, error) {
-	var version string
-	if cachedVersion, ok := templateVersionCache[ID]; ok {
-		version = cachedVersion
-	} else {
-		templateVersion, err := client.TemplateVersion.ByID(ID)
-		if err != nil {
-			return "", err
+// getTemplateVersions maps each distinct template version ID to its version,
+// fetching them concurrently with at most templateVersionWorkers requests in
+// flight.
+func getTemplateVersions(client *managementClient.Client, IDs []string) (map[string]string, error) {
+	versions := make(map[string]string)
+	var mu sync.Mutex
+	var g errgroup.Group
+	g.SetLimit(templateVersionWorkers)
+
+	seen := make(map[string]bool)
+	for _, ID := range IDs {
+		if ID == "" || seen[ID] {
+			continue
 		}
-		templateVersionCache[templateVersion.ID] = templateVersion.Version
-		version = templateVersion.Version
+		seen[ID] = true
+
+		ID := ID
+		g.Go(func() error {
+			templateVersion, err := client.TemplateVersion.ByID(ID)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			versions[ID] = templateVersion.Version
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
 	}
-	return version, nil
+	return versions, nil
 }
 
+// clusterProjectMaps holds the clusters and projects of a server by ID.
+type clusterProjectMaps struct {
+	clusters map[string]managementClient.Cluster
+	projects map[string]managementClient.Project
+}
+
+// clusterProjectCache shares the cluster and project maps, keyed by server
+// URL, between all the commands run within a single invocation.
+var (
+	clusterProjectCacheLock sync.Mutex
+	clusterProjectCache     = make(map[string]*clusterProjectMaps)
+)
+
+// getClusterProjectMap returns the clusters and projects by ID, listing both
+// concurrently the first time they're needed. The returned maps are shared and
+// must not be modified.
 func getClusterProjectMap(ctx *cli.Context, client *managementClient.Client) (map[string]managementClient.Cluster, map[string]managementClient.Project, error) {
-	clusters := make(map[string]managementClient.Cluster)
-	clusterCollectionData, err := listAllClusters(ctx, client)
-	if err != nil {
-		return nil, nil, err
+	var key string
+	if client.Opts != nil {
+		key = client.Opts.URL
 	}
-	for _, c := range clusterCollectionData {
-		clusters[c.ID] = c
+
+	clusterProjectCacheLock.Lock()
+	defer clusterProjectCacheLock.Unlock()
+	if cached, ok := clusterProjectCache[key]; ok && key != "" {
+		return cached.clusters, cached.projects, nil
 	}
-	projects := make(map[string]managementClient.Project)
-	projectCollectionData, err := listAllProjects(ctx, client)
-	if err != nil {
+
+	var clusterCollectionData []managementClient.Cluster
+	var projectCollectionData []managementClient.Project
+	var g errgroup.Group
+	g.Go(func() error {
+		var err error
+		clusterCollectionData, err = listAllClusters(ctx, client)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		projectCollectionData, err = listAllProjects(ctx, client)
+		return err
+	})
+	if err := g.Wait(); err != nil {
 		return nil, nil, err
 	}
+
+	maps := &clusterProjectMaps{
+		clusters: make(map[string]managementClient.Cluster, len(clusterCollectionData)),
+		projects: make(map[string]managementClient.Project, len(projectCollectionData)),
+	}
+	for _, c := range clusterCollectionData {
+		maps.clusters[c.ID] = c
+	}
 	for _, p := range projectCollectionData {
-		projects[p.ID] = p
+		maps.projects[p.ID] = p
 	}
-	return clusters, projects, nil
+	if key != "" {
+		clusterProjectCache[key] = maps
+	}
+	return maps.clusters, maps.projects, nil
 }
 
 func listAllClusters(ctx *cli.Context, client *managementClient.Client) ([]managementClient.Cluster, error) {
@@ -421,21 +660,8 @@ func listAllClusters(ctx *cli.Context, client *managementClient.Client) ([]manag
 	if err != nil {
 		return nil, err
 	}
-	clusterCollectionData := clusterCollection.Data
-	for {
-		clusterCollection, err = clusterCollection.Next()
-		if err != nil {
-			return nil, err
-		}
-		if clusterCollection == nil {
-			break
-		}
-		clusterCollectionData = append(clusterCollectionData, clusterCollection.Data...)
-		if !clusterCollection.Pagination.Partial {
-			break
-		}
-	}
-	return clusterCollectionData, nil
+	// the whole collection is needed to map IDs to names, ignore --limit
+	return listAll(nil, clusterCollection, clusterPageData)
 }
 
 func listAllProjects(ctx *cli.Context, client *managementClient.Client) ([]managementClient.Project, error) {
@@ -443,21 +669,8 @@ func listAllProjects(ctx *cli.Context, client *managementClient.Client) ([]manag
 	if err != nil {
 		return nil, err
 	}
-	projectCollectionData := projectCollection.Data
-	for {
-		projectCollection, err = projectCollection.Next()
-		if err != nil {
-			return nil, err
-		}
-		if projectCollection == nil {
-			break
-		}
-		projectCollectionData = append(projectCollectionData, projectCollection.Data...)
-		if !projectCollection.Pagination.Partial {
-			break
-		}
-	}
-	return projectCollectionData, nil
+	// the whole collection is needed to map IDs to names, ignore --limit
+	return listAll(nil, projectCollection, projectPageData)
 }
 
 func getReadableTargetNames(clusterCache map[string]managementClient.Cluster, projectCache map[string]managementClient.Project, targets []managementClient.Target) []string {
@@ -484,38 +697,53 @@ func getReadableTargetNames(clusterCache map[string]managementClient.Cluster, pr
 
 func multiClusterAppDelete(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	for _, name := range ctx.Args() {
-		_, app, err := searchForMcapp(c, name)
-		if err != nil {
-			return err
-		}
-
-		err = c.ManagementClient.MultiClusterApp.Delete(app)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return bulkDelete{
+		kind:    "multi-cluster apps",
+		warning: "multi-cluster apps and their apps",
+		resolve: func(name string) (*bulkTarget, error) {
+			_, app, err := searchForMcapp(c, name)
+			if err != nil {
+				return nil, err
+			}
+			descriptions := []string{fmt.Sprintf("%s (%s)", app.Name, app.ID)}
+			for _, target := range app.Targets {
+				if target.AppID != "" {
+					descriptions = append(descriptions, fmt.Sprintf("  app %s in project %s", target.AppID, target.ProjectID))
+				}
+			}
+			return &bulkTarget{
+				resource:     app.Resource,
+				descriptions: descriptions,
+				delete: func() error {
+					if err := c.ManagementClient.MultiClusterApp.Delete(app); err != nil {
+						return err
+					}
+					answers, answersSetString := fromMultiClusterAppAnswers(app.Answers)
+					recordDeletedApp(c, "", app.Name, answers, answersSetString)
+					return nil
+				},
+			}, nil
+		},
+	}.run(ctx, c)
 }
 
 func multiClusterAppUpgrade(ctx *cli.Context) error {
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
 
 	if ctx.Bool("show-versions") {
 		if ctx.NArg() == 0 {
-			return cli.ShowSubcommandHelp(ctx)
+			return showSubcommandUsage(ctx)
 		}
 
 		_, app, err := searchForMcapp(c, ctx.Args().First())
@@ -527,7 +755,7 @@ func multiClusterAppUpgrade(ctx *cli.Context) error {
 	}
 
 	if ctx.NArg() != 2 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
 	upgradeStrategy := strings.ToLower(ctx.String(argUpgradeStrategy))
@@ -535,17 +763,76 @@ func multiClusterAppUpgrade(ctx *cli.Context) error {
 		return fmt.Errorf("invalid upgrade-strategy %q, supported values are \"rolling-update\" and \"simultaneously\"", upgradeStrategy)
 	}
 
-	_, app, err := searchForMcapp(c, ctx.Args().First())
+	resource, app, err := searchForMcapp(c, ctx.Args().First())
 	if err != nil {
 		return err
 	}
 
+	needed := []permission{managementPermission(c, managementClient.MultiClusterAppType, verbUpdate)}
+	if ctx.String("targets-file") != "" {
+		needed = append(needed,
+			managementPermission(c, managementClient.MultiClusterAppType, "addProjects"),
+			managementPermission(c, managementClient.MultiClusterAppType, "removeProjects"))
+	}
+	if err := checkPermissions(ctx, needed...); err != nil {
+		return err
+	}
+
+	// the targets are synced with the targets file around the upgrade: the
+	// removed ones aren't upgraded and the added ones get the new version
+	var fileTargets []TargetsFileTarget
+	var addTargets []string
+	if ctx.String("targets-file") != "" {
+		var targets []string
+		targets, fileTargets, err = mcappTargets(ctx)
+		if err != nil {
+			return err
+		}
+		projectIDs, err := lookupProjectIDsFromTargets(c, targets)
+		if err != nil {
+			return err
+		}
+		var removeTargets []string
+		addTargets, removeTargets = mcappTargetChanges(app.Targets, projectIDs)
+		writeTargetChanges(app.Name, addTargets, removeTargets)
+		if len(removeTargets) > 0 {
+			if err := c.ManagementClient.MultiClusterApp.ActionRemoveProjects(app, &managementClient.UpdateMultiClusterAppTargetsInput{
+				Projects: removeTargets,
+			}); err != nil {
+				return err
+			}
+			// the answers of the removed targets are removed with them
+			if app, err = c.ManagementClient.MultiClusterApp.ByID(resource.ID); err != nil {
+				return err
+			}
+		}
+	}
+
 	update := make(map[string]interface{})
 	answers, answersSetString := fromMultiClusterAppAnswers(app.Answers)
 	answers, answersSetString, err = processAnswerUpdates(ctx, answers, answersSetString)
 	if err != nil {
 		return err
 	}
+	// the answers of the added targets are given when adding them
+	var addedAnswers, keptAnswers []TargetsFileTarget
+	for _, target := range fileTargets {
+		projectID, err := lookupProjectIDFromProjectScope(c, target.Project)
+		if err != nil {
+			return err
+		}
+		if slice.ContainsString(addTargets, projectID) {
+			addedAnswers = append(addedAnswers, target)
+		} else {
+			keptAnswers = append(keptAnswers, target)
+		}
+	}
+	applyTargetAnswers(keptAnswers, answers, answersSetString)
+	if ctx.Bool("show-answers") {
+		if err := printAnswers(ctx, answers, answersSetString); err != nil {
+			return err
+		}
+	}
 	update["answers"], err = toMultiClusterAppAnswers(c, answers, answersSetString)
 	if err != nil {
 		return err
@@ -558,7 +845,7 @@ func multiClusterAppUpgrade(ctx *cli.Context) error {
 	}
 	toUpgradeTemplateversionID := strings.TrimSuffix(templateVersion.ID, templateVersion.Version) + version
 	// Check if the template version is valid before applying it
-	_, err = c.ManagementClient.TemplateVersion.ByID(toUpgradeTemplateversionID)
+	toUpgradeTemplateVersion, err := c.ManagementClient.TemplateVersion.ByID(toUpgradeTemplateversionID)
 	if err != nil {
 		templateName := strings.TrimSuffix(toUpgradeTemplateversionID, "-"+version)
 		return fmt.Errorf(
@@ -568,6 +855,9 @@ func multiClusterAppUpgrade(ctx *cli.Context) error {
 			templateName,
 		)
 	}
+	if err := checkUpgradeVersion(templateVersion, toUpgradeTemplateVersion, ctx.Bool("force")); err != nil {
+		return err
+	}
 	update["templateVersionId"] = toUpgradeTemplateversionID
 
 	roles := ctx.StringSlice("role")
@@ -588,19 +878,32 @@ func multiClusterAppUpgrade(ctx *cli.Context) error {
 		update["upgradeStrategy"] = nil
 	}
 
-	if _, err := c.ManagementClient.MultiClusterApp.Update(app, update); err != nil {
+	app, err = c.ManagementClient.MultiClusterApp.Update(app, update)
+	if err != nil {
 		return err
 	}
 
+	if len(addTargets) > 0 {
+		targetAnswers, targetAnswersSetString := map[string]string{}, map[string]string{}
+		applyTargetAnswers(addedAnswers, targetAnswers, targetAnswersSetString)
+		input := &managementClient.UpdateMultiClusterAppTargetsInput{Projects: addTargets}
+		if input.Answers, err = toMultiClusterAppAnswers(c, targetAnswers, targetAnswersSetString); err != nil {
+			return err
+		}
+		if err := c.ManagementClient.MultiClusterApp.ActionAddProjects(app, input); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func multiClusterAppRollback(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -615,7 +918,7 @@ func multiClusterAppRollback(ctx *cli.Context) error {
 	}
 
 	if ctx.NArg() != 2 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
 	revisionResource, err := Lookup(c, ctx.Args().Get(1), managementClient.MultiClusterAppRevisionType)
@@ -623,6 +926,17 @@ func multiClusterAppRollback(ctx *cli.Context) error {
 		return err
 	}
 
+	revision, err := c.ManagementClient.MultiClusterAppRevision.ByID(revisionResource.ID)
+	if err != nil {
+		return err
+	}
+	if err := mcappRollbackPrecheck(os.Stdout, c, app, revision); err != nil {
+		return err
+	}
+	if !confirmAction(ctx, fmt.Sprintf("Multi-cluster app %s will be rolled back to revision %s.", app.Name, revision.Name)) {
+		return errCancelled
+	}
+
 	rr := &managementClient.MultiClusterAppRollbackInput{
 		RevisionID: revisionResource.ID,
 	}
@@ -636,13 +950,16 @@ func multiClusterAppRollback(ctx *cli.Context) error {
 
 func multiClusterAppTemplateInstall(ctx *cli.Context) error {
 	if ctx.NArg() > 2 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
 	templateName := ctx.Args().First()
 	appName := ctx.Args().Get(1)
+	if ctx.Bool("install-or-upgrade") && appName == "" {
+		return errInstallOrUpgradeName
+	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -653,9 +970,33 @@ func multiClusterAppTemplateInstall(ctx *cli.Context) error {
 		roles = []string{"project-member"}
 	}
 
+	labels, annotations, err := appMetadata(ctx)
+	if err != nil {
+		return err
+	}
+
+	policy := strings.ToLower(ctx.String("wait-policy"))
+	switch policy {
+	case "", waitPolicyAll, waitPolicyAny, waitPolicyNone:
+	default:
+		return NewUsageError(fmt.Errorf("invalid wait-policy %q, expected all, any or none", policy))
+	}
+	waitFor := strings.ToLower(ctx.String("wait-for"))
+	switch waitFor {
+	case "", waitForInstalled, waitForHealthy:
+	default:
+		return NewUsageError(fmt.Errorf("invalid wait-for %q, expected installed or healthy", waitFor))
+	}
+	output, err := installOutputFormat(ctx)
+	if err != nil {
+		return err
+	}
+
 	app := &managementClient.MultiClusterApp{
-		Name:  appName,
-		Roles: roles,
+		Name:        appName,
+		Roles:       roles,
+		Labels:      labels,
+		Annotations: annotations,
 	}
 
 	upgradeStrategy := strings.ToLower(ctx.String(argUpgradeStrategy))
@@ -680,38 +1021,43 @@ func multiClusterAppTemplateInstall(ctx *cli.Context) error {
 		return err
 	}
 
-	latestVersion, err := getTemplateLatestVersion(template)
+	templateVersionID, err := resolveTemplateVersionID(ctx, template, templateName, "mcapp")
 	if err != nil {
 		return err
 	}
 
-	templateVersionID := templateVersionIDFromVersionLink(template.VersionLinks[latestVersion])
-	userVersion := ctx.String("version")
-	if userVersion != "" {
-		if link, ok := template.VersionLinks[userVersion]; ok {
-			templateVersionID = templateVersionIDFromVersionLink(link)
-		} else {
-			return fmt.Errorf(
-				"version %s for template %s is invalid, run 'rancher mcapp show-template %s' for a list of versions",
-				userVersion,
-				templateName,
-				templateName,
-			)
-		}
+	templateVersion, err := c.ManagementClient.TemplateVersion.ByID(templateVersionID)
+	if err != nil {
+		return err
 	}
 
-	templateVersion, err := c.ManagementClient.TemplateVersion.ByID(templateVersionID)
+	// questions can't be answered when stdin holds the answers
+	interactive := !ctx.Bool("no-prompt") && !readsStdin(ctx)
+	previous, previousSetString, err := previousAppAnswers(ctx, deletedAppKey(c.UserConfig.URL, "", appName),
+		func(name string) (map[string]string, map[string]string, error) {
+			_, previousApp, err := searchForMcapp(c, name)
+			if err != nil {
+				return nil, nil, err
+			}
+			previous, previousSetString := fromMultiClusterAppAnswers(previousApp.Answers)
+			return previous, previousSetString, nil
+		})
+	if err != nil {
+		return err
+	}
+	questions, previous := withAnswerDefaults(templateVersion, previous, interactive)
+	answers, answersSetString, err := processAnswerInstall(ctx, questions, previous, previousSetString, interactive, true)
 	if err != nil {
 		return err
 	}
 
-	interactive := !ctx.Bool("no-prompt")
-	answers, answersSetString, err := processAnswerInstall(ctx, templateVersion, nil, nil, interactive, true)
+	targets, fileTargets, err := mcappTargets(ctx)
 	if err != nil {
 		return err
 	}
+	applyTargetAnswers(fileTargets, answers, answersSetString)
 
-	projectIDs, err := lookupProjectIDsFromTargets(c, ctx.StringSlice("target"))
+	projectIDs, err := lookupProjectIDsFromTargets(c, targets)
 	if err != nil {
 		return err
 	}
@@ -726,6 +1072,15 @@ func multiClusterAppTemplateInstall(ctx *cli.Context) error {
 			ProjectID: c.UserConfig.Project,
 		})
 	}
+	if ctx.Bool("validate-targets") {
+		var targetIDs []string
+		for _, target := range app.Targets {
+			targetIDs = append(targetIDs, target.ProjectID)
+		}
+		if err := validateTargets(c, targetIDs); err != nil {
+			return err
+		}
+	}
 
 	app.Answers, err = toMultiClusterAppAnswers(c, answers, answersSetString)
 	if err != nil {
@@ -747,14 +1102,59 @@ func multiClusterAppTemplateInstall(ctx *cli.Context) error {
 	app.Wait = ctx.Bool("helm-wait")
 	app.Timeout = ctx.Int64("helm-timeout")
 
-	app, err = c.ManagementClient.MultiClusterApp.Create(app)
-	if err != nil {
+	var existing *managementClient.MultiClusterApp
+	if ctx.Bool("install-or-upgrade") {
+		if existing, err = findMultiClusterApp(c, appName); err != nil {
+			return err
+		}
+	}
+	verb := verbCreate
+	if existing != nil {
+		verb = verbUpdate
+	}
+	if err := checkPermissions(ctx, managementPermission(c, managementClient.MultiClusterAppType, verb)); err != nil {
 		return err
 	}
 
-	fmt.Printf("Installing multi-cluster app %q...\n", app.Name)
+	stop := commandInterrupt.watch()
+	defer stop()
+	start := time.Now()
+	created := &createdResources{}
+	if existing != nil {
+		if app, err = upgradeInstalledMultiClusterApp(ctx, c, existing, app); err != nil {
+			return err
+		}
+		if output == "" {
+			fmt.Printf("Multi-cluster app %q is already installed, upgrading it...\n", app.Name)
+		}
+	} else {
+		if app, err = c.ManagementClient.MultiClusterApp.Create(app); err != nil {
+			return err
+		}
+		created.add("multi-cluster app", app.Name, func() error {
+			return c.ManagementClient.MultiClusterApp.Delete(app)
+		})
+		if output == "" {
+			fmt.Printf("Installing multi-cluster app %q...\n", app.Name)
+		}
+	}
 
-	return nil
+	timeout := time.Duration(ctx.Int("wait-timeout")) * time.Second
+	switch {
+	case policy != "" || ctx.Bool("continue-on-error"):
+		err = waitForTargets(c, &app.Resource, timeout, valueOrDefault(policy, waitPolicyAll), ctx.Bool("continue-on-error"))
+	case ctx.Bool("wait") || waitFor != "":
+		err = waitForResource(c, &app.Resource, timeout)
+	}
+	if err == nil && waitFor == waitForHealthy {
+		// the timeout covers both waits
+		err = waitForHealthyTargets(c, &app.Resource, timeout-time.Since(start), valueOrDefault(policy, waitPolicyAll), ctx.Bool("continue-on-error"))
+	}
+	err = created.interrupted(os.Stderr, err, ctx.Bool("cleanup-on-cancel"))
+	if output != "" {
+		return writeInstallResult(os.Stdout, c, output, &app.Resource, start, err)
+	}
+	return err
 }
 
 func lookupProjectIDsFromTargets(c *cliclient.MasterClient, targets []string) ([]string, error) {
@@ -769,35 +1169,65 @@ func lookupProjectIDsFromTargets(c *cliclient.MasterClient, targets []string) ([
 	return projectIDs, nil
 }
 
-func lookupClusterIDFromClusterScope(c *cliclient.MasterClient, clusterNameOrID string) (string, error) {
-	clusterResource, err := Lookup(c, clusterNameOrID, managementClient.ClusterType)
+// scopeLookupKey identifies a resolved scope, scopes are only shared between
+// lookups made with the same client.
+type scopeLookupKey struct {
+	client     *cliclient.MasterClient
+	schemaType string
+	scope      string
+}
+
+// scopeLookups memoizes the IDs of the scopes resolved within an invocation,
+// answers often repeat the same scope for many keys.
+var scopeLookups sync.Map
+
+// memoizeScopeLookup returns the ID resolved for the scope by an earlier call,
+// or calls lookup and remembers its result. Errors aren't remembered.
+func memoizeScopeLookup(c *cliclient.MasterClient, schemaType, scope string, lookup func() (string, error)) (string, error) {
+	key := scopeLookupKey{client: c, schemaType: schemaType, scope: scope}
+	if id, ok := scopeLookups.Load(key); ok {
+		return id.(string), nil
+	}
+	id, err := lookup()
 	if err != nil {
 		return "", err
 	}
-	return clusterResource.ID, nil
+	scopeLookups.Store(key, id)
+	return id, nil
+}
+
+func lookupClusterIDFromClusterScope(c *cliclient.MasterClient, clusterNameOrID string) (string, error) {
+	return memoizeScopeLookup(c, managementClient.ClusterType, clusterNameOrID, func() (string, error) {
+		clusterResource, err := Lookup(c, clusterNameOrID, managementClient.ClusterType)
+		if err != nil {
+			return "", err
+		}
+		return clusterResource.ID, nil
+	})
 }
 
 func lookupProjectIDFromProjectScope(c *cliclient.MasterClient, scope string) (string, error) {
-	cluster, project := parseScope(scope)
-	clusterResource, err := Lookup(c, cluster, managementClient.ClusterType)
-	if err != nil {
-		return "", err
-	}
-	if clusterResource.ID == cluster {
-		// Lookup by ID
-		projectResource, err := Lookup(c, scope, managementClient.ProjectType)
+	return memoizeScopeLookup(c, managementClient.ProjectType, scope, func() (string, error) {
+		cluster, project := parseScope(scope)
+		clusterID, err := lookupClusterIDFromClusterScope(c, cluster)
+		if err != nil {
+			return "", err
+		}
+		if clusterID == cluster {
+			// Lookup by ID
+			projectResource, err := Lookup(c, scope, managementClient.ProjectType)
+			if err != nil {
+				return "", err
+			}
+			return projectResource.ID, nil
+		}
+		// Lookup by clusterName:projectName
+		projectResource, err := Lookup(c, project, managementClient.ProjectType)
 		if err != nil {
 			return "", err
 		}
 		return projectResource.ID, nil
-	}
-	// Lookup by clusterName:projectName
-	projectResource, err := Lookup(c, project, managementClient.ProjectType)
-	if err != nil {
-		return "", err
-	}
-	return projectResource.ID, nil
-
+	})
 }
 
 func toMultiClusterAppAnswers(c *cliclient.MasterClient, answers, answersSetString map[string]string) ([]managementClient.Answer, error) {
@@ -910,10 +1340,10 @@ func getAnswerScopedKey(answer managementClient.Answer, key string) string {
 
 func addMcappTargetProject(ctx *cli.Context) error {
 	if len(ctx.Args()) < 2 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -937,10 +1367,10 @@ func addMcappTargetProject(ctx *cli.Context) error {
 
 func deleteMcappTargetProject(ctx *cli.Context) error {
 	if len(ctx.Args()) < 2 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -980,7 +1410,7 @@ func getTargetInput(ctx *cli.Context, c *cliclient.MasterClient) (*managementCli
 
 func addMcappMember(ctx *cli.Context) error {
 	if len(ctx.Args()) < 3 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
 	appName := ctx.Args().First()
@@ -991,7 +1421,7 @@ func addMcappMember(ctx *cli.Context) error {
 		return fmt.Errorf("invalid access type %q, supported values are \"owner\",\"member\" and \"read-only\"", accessType)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -1016,13 +1446,13 @@ func addMcappMember(ctx *cli.Context) error {
 
 func deleteMcappMember(ctx *cli.Context) error {
 	if len(ctx.Args()) < 2 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
 	appName := ctx.Args().First()
 	memberNames := ctx.Args()[1:]
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -1047,10 +1477,10 @@ func deleteMcappMember(ctx *cli.Context) error {
 
 func showMultiClusterApp(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -1086,10 +1516,10 @@ func showMultiClusterApp(ctx *cli.Context) error {
 
 func listMultiClusterAppMembers(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -1104,10 +1534,10 @@ func listMultiClusterAppMembers(ctx *cli.Context) error {
 
 func listMultiClusterAppAnswers(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -1153,6 +1583,7 @@ func outputMultiClusterAppVersions(ctx *cli.Context, c *cliclient.MasterClient,
 	writer := NewTableWriter([][]string{
 		{"CURRENT", "Current"},
 		{"VERSION", "Version"},
+		{"NOTES", "Notes"},
 	}, ctx)
 
 	defer writer.Close()
@@ -1170,6 +1601,7 @@ func outputMultiClusterAppVersions(ctx *cli.Context, c *cliclient.MasterClient,
 		writer.Write(&VersionData{
 			Current: current,
 			Version: version.String(),
+			Notes:   versionNotes(template, templateVersion, version.String()),
 		})
 	}
 	return writer.Err()
@@ -1196,6 +1628,7 @@ func outputMultiClusterAppRevisions(ctx *cli.Context, c *cliclient.MasterClient,
 		{"CURRENT", "Current"},
 		{"REVISION", "Name"},
 		{"CREATED", "Human"},
+		{"AGE", "Age"},
 	}, ctx)
 
 	defer writer.Close()
@@ -1205,6 +1638,7 @@ func outputMultiClusterAppRevisions(ctx *cli.Context, c *cliclient.MasterClient,
 			rev.Current = "*"
 		}
 		rev.Human = rev.Created.Format("02 Jan 2006 15:04:05 MST")
+		rev.Age = formatTimeAge(ctx, rev.Created)
 		writer.Write(rev)
 
 	}
@@ -1264,14 +1698,14 @@ func outputMultiClusterAppAnswers(ctx *cli.Context, c *cliclient.MasterClient, a
 			writer.Write(map[string]string{
 				"Scope":    scope,
 				"Question": key,
-				"Answer":   value,
+				"Answer":   answerMasking.value(key, value),
 			})
 		}
 		for key, value := range r.ValuesSetString {
 			writer.Write(map[string]string{
 				"Scope":    scope,
 				"Question": key,
-				"Answer":   fmt.Sprintf("\"%s\"", value),
+				"Answer":   fmt.Sprintf("\"%s\"", answerMasking.value(key, value)),
 			})
 		}
 	}
@@ -1279,7 +1713,7 @@ func outputMultiClusterAppAnswers(ctx *cli.Context, c *cliclient.MasterClient, a
 }
 
 func globalTemplateLs(ctx *cli.Context) error {
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -1315,6 +1749,7 @@ func globalTemplateLs(ctx *cli.Context) error {
 			ID:       item.ID,
 			Template: item,
 			Category: strings.Join(item.Categories, ","),
+			Metadata: templateMetadata(item),
 		})
 	}
 
@@ -1,8 +1,14 @@
 package cmd
 
 import (
+	"errors"
+	"strings"
+	"sync/atomic"
 	"testing"
 
+	"github.com/rancher/cli/cliclient"
+	"github.com/rancher/norman/clientbase"
+	"github.com/rancher/norman/types"
 	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	"github.com/stretchr/testify/assert"
 )
@@ -120,3 +126,99 @@ func TestGetReadableTargetNames(t *testing.T) {
 	result = getReadableTargetNames(clusters, projects, targets)
 	assert.Contains(result, "c-0:p-0")
 }
+
+type fakeTemplateVersions struct {
+	client.TemplateVersionOperations
+	calls int32
+}
+
+func (f *fakeTemplateVersions) ByID(id string) (*client.TemplateVersion, error) {
+	atomic.AddInt32(&f.calls, 1)
+	return &client.TemplateVersion{Version: strings.TrimPrefix(id, "cattle-global-data:redis-")}, nil
+}
+
+func TestGetTemplateVersions(t *testing.T) {
+	assert := assert.New(t)
+	templateVersions := &fakeTemplateVersions{}
+	mc := &client.Client{TemplateVersion: templateVersions}
+
+	versions, err := getTemplateVersions(mc, []string{
+		"cattle-global-data:redis-1.0.0",
+		"cattle-global-data:redis-1.1.0",
+		"cattle-global-data:redis-1.0.0",
+		"",
+	})
+
+	assert.NoError(err)
+	assert.Equal(map[string]string{
+		"cattle-global-data:redis-1.0.0": "1.0.0",
+		"cattle-global-data:redis-1.1.0": "1.1.0",
+	}, versions)
+	assert.Equal(int32(2), templateVersions.calls)
+}
+
+type fakeClusters struct {
+	client.ClusterOperations
+	calls int32
+}
+
+func (f *fakeClusters) List(opts *types.ListOpts) (*client.ClusterCollection, error) {
+	atomic.AddInt32(&f.calls, 1)
+	return &client.ClusterCollection{Data: []client.Cluster{{Resource: types.Resource{ID: "c-1"}, Name: "cluster1"}}}, nil
+}
+
+type fakeProjects struct {
+	client.ProjectOperations
+	calls int32
+}
+
+func (f *fakeProjects) List(opts *types.ListOpts) (*client.ProjectCollection, error) {
+	atomic.AddInt32(&f.calls, 1)
+	return &client.ProjectCollection{Data: []client.Project{{Resource: types.Resource{ID: "c-1:p-1"}, Name: "project1"}}}, nil
+}
+
+func TestGetClusterProjectMapIsShared(t *testing.T) {
+	assert := assert.New(t)
+	clusters := &fakeClusters{}
+	projects := &fakeProjects{}
+	mc := &client.Client{Cluster: clusters, Project: projects}
+	mc.Opts = &clientbase.ClientOpts{URL: "https://shared.example.com/v3"}
+
+	for i := 0; i < 2; i++ {
+		clusterMap, projectMap, err := getClusterProjectMap(nil, mc)
+		assert.NoError(err)
+		assert.Equal("cluster1", clusterMap["c-1"].Name)
+		assert.Equal("project1", projectMap["c-1:p-1"].Name)
+	}
+	assert.Equal(int32(1), clusters.calls)
+	assert.Equal(int32(1), projects.calls)
+}
+
+func TestMemoizeScopeLookup(t *testing.T) {
+	assert := assert.New(t)
+	mc := &cliclient.MasterClient{}
+	calls := 0
+	lookup := func() (string, error) {
+		calls++
+		if calls == 1 {
+			return "", errors.New("temporary failure")
+		}
+		return "c-1", nil
+	}
+
+	_, err := memoizeScopeLookup(mc, "cluster", "prod", lookup)
+	assert.Error(err)
+
+	for i := 0; i < 3; i++ {
+		id, err := memoizeScopeLookup(mc, "cluster", "prod", lookup)
+		assert.NoError(err)
+		assert.Equal("c-1", id)
+	}
+	assert.Equal(2, calls, "errors are retried, results are remembered")
+
+	id, err := memoizeScopeLookup(&cliclient.MasterClient{}, "cluster", "prod", func() (string, error) {
+		return "c-2", nil
+	})
+	assert.NoError(err)
+	assert.Equal("c-2", id, "results aren't shared between clients")
+}
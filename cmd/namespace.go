@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/pkg/errors"
 	"github.com/rancher/cli/cliclient"
@@ -10,8 +11,10 @@ import (
 )
 
 type NamespaceData struct {
-	ID        string
-	Namespace clusterClient.Namespace
+	ID          string
+	Namespace   clusterClient.Namespace
+	ProjectName string
+	Orphaned    bool
 }
 
 func NamespaceCommand() cli.Command {
@@ -25,21 +28,31 @@ func NamespaceCommand() cli.Command {
 		},
 		Subcommands: []cli.Command{
 			{
-				Name:        "ls",
-				Usage:       "List namespaces",
-				Description: "\nLists all namespaces in the current project.",
-				ArgsUsage:   "None",
-				Action:      namespaceLs,
+				Name:  "ls",
+				Usage: "List namespaces",
+				Description: `
+Lists all namespaces in the current project. With --all-projects the namespaces of the whole
+cluster are listed with the name of their project, namespaces that aren't assigned to any
+existing project are shown as orphaned.
+`,
+				ArgsUsage: "None",
+				Action:    namespaceLs,
 				Flags: []cli.Flag{
 					cli.BoolFlag{
-						Name:  "all-namespaces",
-						Usage: "List all namespaces in the current cluster",
+						Name:  "all-projects, all-namespaces",
+						Usage: "List the namespaces of all projects in the current cluster",
 					},
 					cli.StringFlag{
 						Name:  "format",
 						Usage: "'json', 'yaml' or Custom format: '{{.Namespace.ID}} {{.Namespace.Name}}'",
 					},
 					quietFlag,
+					filterFlag,
+					labelSelectorFlag,
+					sortByFlag,
+					noHeadersFlag,
+					limitFlag,
+					pageSizeFlag,
 				},
 			},
 			{
@@ -61,6 +74,7 @@ func NamespaceCommand() cli.Command {
 				Usage:     "Delete a namespace by name or ID",
 				ArgsUsage: "[NAMESPACEID NAMESPACENAME]",
 				Action:    namespaceDelete,
+				Flags:     deleteFlags,
 			},
 			{
 				Name:      "move",
@@ -68,6 +82,64 @@ func NamespaceCommand() cli.Command {
 				ArgsUsage: "[NAMESPACEID/NAMESPACENAME PROJECTID]",
 				Action:    namespaceMove,
 			},
+			{
+				Name:        "set-quota",
+				Usage:       "Set the resource quota of a namespace",
+				Description: namespaceSetQuotaDescription,
+				ArgsUsage:   "[NAMESPACEID NAMESPACENAME]",
+				Action:      namespaceSetQuota,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "cpu",
+						Usage: "CPU limit of the namespace, e.g. 2 or 500m",
+					},
+					cli.StringFlag{
+						Name:  "memory",
+						Usage: "Memory limit of the namespace, e.g. 4Gi",
+					},
+					cli.StringSliceFlag{
+						Name:  "limit",
+						Usage: "Set FIELD=VALUE of the quota, such as requestsCpu=1 or pods=20, can be used multiple times",
+					},
+					cli.BoolFlag{
+						Name:  "remove",
+						Usage: "Remove the resource quota of the namespace",
+					},
+				},
+			},
+			{
+				Name:        "set-psa",
+				Usage:       "Set the Pod Security Admission levels of namespaces",
+				Description: namespaceSetPSADescription,
+				ArgsUsage:   "[NAMESPACENAME...]",
+				Action:      namespaceSetPSA,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "enforce",
+						Usage: "Level enforced on the pods of the namespaces: privileged, baseline or restricted",
+					},
+					cli.StringFlag{
+						Name:  "warn",
+						Usage: "Level whose violations are returned as warnings to the users: privileged, baseline or restricted",
+					},
+					cli.StringFlag{
+						Name:  "audit",
+						Usage: "Level whose violations are recorded in the audit log: privileged, baseline or restricted",
+					},
+					cli.StringFlag{
+						Name:  "version",
+						Usage: "Kubernetes version of the policies of the levels, such as v1.29 or latest",
+					},
+					cli.StringFlag{
+						Name:  "project",
+						Usage: "Set the levels of all the namespaces of a project, by name or ID",
+					},
+					cli.BoolFlag{
+						Name:  "remove",
+						Usage: "Remove the Pod Security Admission labels of the namespaces",
+					},
+				},
+			},
 		},
 	}
 }
@@ -78,46 +150,76 @@ func namespaceLs(ctx *cli.Context) error {
 		return err
 	}
 
-	collection, err := getNamespaceList(ctx, c)
+	collection, err := c.ClusterClient.Namespace.List(filteredListOpts(ctx))
 	if err != nil {
 		return err
 	}
 
-	if !ctx.Bool("all-namespaces") {
-		var projectNamespaces []clusterClient.Namespace
+	allProjects := ctx.Bool("all-projects")
+	var projectNames map[string]string
+	if allProjects {
+		if projectNames, err = clusterProjectNames(c); err != nil {
+			return err
+		}
+	}
 
-		for _, namespace := range collection.Data {
-			if namespace.ProjectID == c.UserConfig.Project {
-				projectNamespaces = append(projectNamespaces, namespace)
-			}
+	writer := NewTableWriter(namespaceColumns(allProjects), ctx)
 
+	defer writer.Close()
+
+	err = forEachPage(ctx, collection, namespacePageData, func(item clusterClient.Namespace) error {
+		if !allProjects && item.ProjectID != c.UserConfig.Project {
+			return nil
 		}
-		collection.Data = projectNamespaces
+		writer.Write(newNamespaceData(item, projectNames))
+		return nil
+	}, writer.Flush)
+	if err != nil {
+		return err
 	}
 
-	writer := NewTableWriter([][]string{
+	return writer.Err()
+}
+
+// namespaceColumns returns the columns of namespace ls, the columns about the
+// project of a namespace are only shown with --all-projects.
+func namespaceColumns(allProjects bool) [][]string {
+	columns := [][]string{
 		{"ID", "ID"},
 		{"NAME", "Namespace.Name"},
 		{"STATE", "Namespace.State"},
 		{"PROJECT", "Namespace.ProjectID"},
+		{"PROJECT NAME", "ProjectName"},
+		{"ORPHANED", "Orphaned"},
 		{"DESCRIPTION", "Namespace.Description"},
-	}, ctx)
-
-	defer writer.Close()
-
-	for _, item := range collection.Data {
-		writer.Write(&NamespaceData{
-			ID:        item.ID,
-			Namespace: item,
-		})
 	}
+	if allProjects {
+		return columns
+	}
+	return slices.DeleteFunc(columns, func(column []string) bool {
+		return column[1] == "ProjectName" || column[1] == "Orphaned"
+	})
+}
 
-	return writer.Err()
+// newNamespaceData returns a namespace as printed by namespace ls. With the
+// names of the projects of the cluster by ID, a namespace not assigned to any
+// of them is orphaned.
+func newNamespaceData(namespace clusterClient.Namespace, projectNames map[string]string) *NamespaceData {
+	data := &NamespaceData{
+		ID:        namespace.ID,
+		Namespace: namespace,
+	}
+	if projectNames != nil {
+		name, ok := projectNames[namespace.ProjectID]
+		data.ProjectName = name
+		data.Orphaned = !ok
+	}
+	return data
 }
 
 func namespaceCreate(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
 	c, err := GetClient(ctx)
@@ -141,7 +243,7 @@ func namespaceCreate(ctx *cli.Context) error {
 
 func namespaceDelete(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
 	c, err := GetClient(ctx)
@@ -149,29 +251,33 @@ func namespaceDelete(ctx *cli.Context) error {
 		return err
 	}
 
-	for _, arg := range ctx.Args() {
-		resource, err := Lookup(c, arg, "namespace")
-		if err != nil {
-			return err
-		}
-
-		namespace, err := getNamespaceByID(c, resource.ID)
-		if err != nil {
-			return err
-		}
-
-		err = c.ClusterClient.Namespace.Delete(namespace)
-		if err != nil {
-			return err
-		}
-	}
+	return bulkDelete{
+		kind:    "namespaces",
+		warning: "namespaces and everything in them",
+		resolve: func(arg string) (*bulkTarget, error) {
+			resource, err := Lookup(c, arg, "namespace")
+			if err != nil {
+				return nil, err
+			}
 
-	return nil
+			namespace, err := getNamespaceByID(c, resource.ID)
+			if err != nil {
+				return nil, err
+			}
+			return &bulkTarget{
+				resource:     namespace.Resource,
+				descriptions: []string{namespace.Name},
+				delete: func() error {
+					return c.ClusterClient.Namespace.Delete(namespace)
+				},
+			}, nil
+		},
+	}.run(ctx, c)
 }
 
 func namespaceMove(ctx *cli.Context) error {
 	if ctx.NArg() < 2 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
 	c, err := GetClient(ctx)
@@ -225,13 +331,21 @@ func getNamespaceList(
 	ctx *cli.Context,
 	c *cliclient.MasterClient,
 ) (*clusterClient.NamespaceCollection, error) {
-	collection, err := c.ClusterClient.Namespace.List(defaultListOpts(ctx))
+	collection, err := c.ClusterClient.Namespace.List(filteredListOpts(ctx))
+	if err != nil {
+		return nil, err
+	}
+	collection.Data, err = listAll(ctx, collection, namespacePageData)
 	if err != nil {
 		return nil, err
 	}
 	return collection, nil
 }
 
+func namespacePageData(c *clusterClient.NamespaceCollection) []clusterClient.Namespace {
+	return c.Data
+}
+
 func getNamespaceByID(
 	c *cliclient.MasterClient,
 	namespaceID string,
@@ -243,3 +357,26 @@ func getNamespaceByID(
 	}
 	return namespace, nil
 }
+
+// clusterProjectNames returns the names of the projects of the current
+// cluster by ID.
+func clusterProjectNames(c *cliclient.MasterClient) (map[string]string, error) {
+	// every project is needed, whatever the flags filtering the namespaces,
+	// so that no namespace is taken as orphaned by mistake
+	opts := baseListOpts()
+	opts.Filters["clusterId"] = c.UserConfig.FocusedCluster()
+	collection, err := c.ManagementClient.Project.List(opts)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := listAll(nil, collection, projectPageData)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(projects))
+	for _, project := range projects {
+		names[project.ID] = project.Name
+	}
+	return names, nil
+}
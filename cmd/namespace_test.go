@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"testing"
+
+	clusterClient "github.com/rancher/rancher/pkg/client/generated/cluster/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNamespaceData(t *testing.T) {
+	projectNames := map[string]string{"c-1:p-1": "Default"}
+
+	tt := []struct {
+		name             string
+		projectID        string
+		projectNames     map[string]string
+		expectedName     string
+		expectedOrphaned bool
+	}{
+		{
+			name:         "assigned to a project",
+			projectID:    "c-1:p-1",
+			projectNames: projectNames,
+			expectedName: "Default",
+		},
+		{
+			name:             "not assigned",
+			projectNames:     projectNames,
+			expectedOrphaned: true,
+		},
+		{
+			name:             "assigned to a deleted project",
+			projectID:        "c-1:p-gone",
+			projectNames:     projectNames,
+			expectedOrphaned: true,
+		},
+		{
+			name:      "without --all-projects",
+			projectID: "c-1:p-1",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			data := newNamespaceData(clusterClient.Namespace{Name: "web", ProjectID: tc.projectID}, tc.projectNames)
+			assert.Equal(t, tc.expectedName, data.ProjectName)
+			assert.Equal(t, tc.expectedOrphaned, data.Orphaned)
+		})
+	}
+}
+
+func TestNamespaceColumns(t *testing.T) {
+	headers := func(columns [][]string) []string {
+		var names []string
+		for _, column := range columns {
+			names = append(names, column[0])
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"ID", "NAME", "STATE", "PROJECT", "DESCRIPTION"}, headers(namespaceColumns(false)))
+	assert.Equal(t, []string{"ID", "NAME", "STATE", "PROJECT", "PROJECT NAME", "ORPHANED", "DESCRIPTION"}, headers(namespaceColumns(true)))
+}
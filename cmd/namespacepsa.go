@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rancher/cli/cliclient"
+	"github.com/rancher/norman/clientbase"
+	"github.com/rancher/norman/types"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/urfave/cli"
+)
+
+const namespaceSetPSADescription = `
+Sets the Pod Security Admission levels of namespaces, through the labels the Kubernetes
+admission controller reads. --enforce rejects the pods violating its level, --warn and --audit
+only report them. Levels the command isn't given are left as they are.
+
+With --project the levels are set on every namespace of the project, to roll out a level across
+the namespaces of a team at once. --remove removes all the Pod Security Admission labels.
+
+Levels are privileged, baseline and restricted. --version pins the levels that are set to the
+policies of a Kubernetes version such as v1.29, 'latest' by default.
+
+Example:
+	$ rancher namespace set-psa --enforce restricted --warn restricted team-a-dev
+	$ rancher namespace set-psa --project team-a --enforce baseline --warn restricted
+	$ rancher namespace set-psa --remove sandbox
+`
+
+const (
+	podSecurityLabelPrefix     = "pod-security.kubernetes.io/"
+	namespaceProjectAnnotation = "field.cattle.io/projectId"
+)
+
+// podSecurityModes are the modes of Pod Security Admission, each is a label
+// of the namespace with its level.
+var podSecurityModes = []string{"enforce", "warn", "audit"}
+
+var podSecurityLevels = []string{"privileged", "baseline", "restricted"}
+
+var podSecurityVersion = regexp.MustCompile(`^(latest|v1\.\d+)$`)
+
+type Namespace struct {
+	types.Resource
+	Metadata objectMeta `json:"metadata,omitempty"`
+}
+
+type NamespaceCollection struct {
+	types.Collection
+	Data []Namespace `json:"data,omitempty"`
+}
+
+func namespaceSetPSA(ctx *cli.Context) error {
+	project := ctx.String("project")
+	if (ctx.NArg() == 0) == (project == "") {
+		return NewUsageError(errors.New("give either the namespaces or --project"))
+	}
+
+	levels := map[string]string{}
+	for _, mode := range podSecurityModes {
+		if level := ctx.String(mode); level != "" {
+			levels[mode] = level
+		}
+	}
+	remove := ctx.Bool("remove")
+	if remove == (len(levels) > 0) {
+		return NewUsageError(errors.New("use either --remove or the levels to set with --enforce, --warn and --audit"))
+	}
+	labels, err := podSecurityLabels(levels, ctx.String("version"))
+	if err != nil {
+		return err
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	clusterID := c.UserConfig.FocusedCluster()
+	var proj *managementClient.Project
+	if project != "" {
+		resource, err := Lookup(c, project, "project")
+		if err != nil {
+			return err
+		}
+		if proj, err = getProjectByID(c, resource.ID); err != nil {
+			return err
+		}
+		clusterID = proj.ClusterID
+	}
+	client, err := cliclient.NewClusterV1Client(c.UserConfig, clusterID)
+	if err != nil {
+		return err
+	}
+	if err := checkPermissions(ctx, newPermission("cluster", client.Types, "namespace", verbUpdate)); err != nil {
+		return err
+	}
+
+	namespaces := []string(ctx.Args())
+	if proj != nil {
+		if namespaces, err = projectNamespaces(client, proj.ID); err != nil {
+			return err
+		}
+		if len(namespaces) == 0 {
+			return fmt.Errorf("project %s has no namespaces", proj.Name)
+		}
+	}
+
+	for _, name := range namespaces {
+		if err := setNamespacePodSecurity(client, name, labels, remove); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if remove {
+			fmt.Printf("Removed the pod security levels of namespace %s\n", name)
+		} else {
+			fmt.Printf("Set the pod security levels of namespace %s: %s\n", name, formatPodSecurityLevels(levels))
+		}
+	}
+	return nil
+}
+
+// podSecurityLabels returns the labels setting levels, by mode, pinned to
+// version when it's given.
+func podSecurityLabels(levels map[string]string, version string) (map[string]string, error) {
+	if version != "" && !podSecurityVersion.MatchString(version) {
+		return nil, NewUsageError(fmt.Errorf("invalid --version %q, expected latest or a Kubernetes version such as v1.29", version))
+	}
+	labels := map[string]string{}
+	for mode, level := range levels {
+		if !slices.Contains(podSecurityLevels, level) {
+			return nil, NewUsageError(fmt.Errorf("invalid --%s level %q, expected one of %s", mode, level, strings.Join(podSecurityLevels, ", ")))
+		}
+		labels[podSecurityLabelPrefix+mode] = level
+		if version != "" {
+			labels[podSecurityLabelPrefix+mode+"-version"] = version
+		}
+	}
+	return labels, nil
+}
+
+// applyPodSecurityLabels sets labels in the labels of a namespace, or removes
+// all the Pod Security Admission labels. Other labels are kept.
+func applyPodSecurityLabels(namespaceLabels map[string]interface{}, labels map[string]string, remove bool) {
+	if remove {
+		for key := range namespaceLabels {
+			if strings.HasPrefix(key, podSecurityLabelPrefix) {
+				delete(namespaceLabels, key)
+			}
+		}
+		return
+	}
+	for key, value := range labels {
+		namespaceLabels[key] = value
+	}
+}
+
+func setNamespacePodSecurity(client *clientbase.APIBaseClient, name string, labels map[string]string, remove bool) error {
+	object, resource, err := getRawObject(client, "namespace", name)
+	if clientbase.IsNotFound(err) {
+		return notFoundErrorf("namespace %s not found", name)
+	}
+	if err != nil {
+		return err
+	}
+	applyPodSecurityLabels(childMap(childMap(object, "metadata"), "labels"), labels, remove)
+	return client.Update("namespace", resource, object, &Namespace{})
+}
+
+// projectNamespaces returns the names of the namespaces of a project
+func projectNamespaces(client *clientbase.APIBaseClient, projectID string) ([]string, error) {
+	collection := &NamespaceCollection{}
+	if err := client.List("namespace", &types.ListOpts{}, collection); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, namespace := range collection.Data {
+		if namespace.Metadata.Annotations[namespaceProjectAnnotation] == projectID {
+			names = append(names, namespace.Metadata.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func formatPodSecurityLevels(levels map[string]string) string {
+	var formatted []string
+	for _, mode := range podSecurityModes {
+		if level, ok := levels[mode]; ok {
+			formatted = append(formatted, mode+"="+level)
+		}
+	}
+	return strings.Join(formatted, ", ")
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPodSecurityLabels(t *testing.T) {
+	labels, err := podSecurityLabels(map[string]string{"enforce": "restricted", "warn": "baseline"}, "v1.29")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"pod-security.kubernetes.io/enforce":         "restricted",
+		"pod-security.kubernetes.io/enforce-version": "v1.29",
+		"pod-security.kubernetes.io/warn":            "baseline",
+		"pod-security.kubernetes.io/warn-version":    "v1.29",
+	}, labels)
+
+	_, err = podSecurityLabels(map[string]string{"enforce": "strict"}, "")
+	assert.Error(t, err)
+	_, err = podSecurityLabels(map[string]string{"enforce": "baseline"}, "1.29")
+	assert.Error(t, err)
+}
+
+func TestApplyPodSecurityLabels(t *testing.T) {
+	labels := map[string]interface{}{
+		"team":                               "a",
+		"pod-security.kubernetes.io/enforce": "privileged",
+		"pod-security.kubernetes.io/audit":   "baseline",
+	}
+	applyPodSecurityLabels(labels, map[string]string{"pod-security.kubernetes.io/enforce": "restricted"}, false)
+	assert.Equal(t, map[string]interface{}{
+		"team":                               "a",
+		"pod-security.kubernetes.io/enforce": "restricted",
+		"pod-security.kubernetes.io/audit":   "baseline",
+	}, labels)
+
+	applyPodSecurityLabels(labels, nil, true)
+	assert.Equal(t, map[string]interface{}{"team": "a"}, labels)
+}
@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	clusterClient "github.com/rancher/rancher/pkg/client/generated/cluster/v3"
+	"github.com/urfave/cli"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const namespaceSetQuotaDescription = `
+Edits the resource quota of a namespace. When the project of the namespace has a resource quota,
+the limits of its namespaces must fit in the limits of the project: the new quota is checked
+before it's applied and the headroom left in the project is printed.
+
+--cpu and --memory set the CPU and memory limits, --limit sets any other field of the quota such
+as requestsCpu, requestsMemory, pods or services. --remove removes the quota of a namespace whose
+project has no resource quota.
+
+Example:
+	$ rancher namespace set-quota --cpu 2 --memory 4Gi team-a-dev
+	$ rancher namespace set-quota --limit requestsCpu=500m --limit pods=20 team-a-dev
+	$ rancher namespace set-quota --remove sandbox
+`
+
+// quotaHeadroom is the room left in the project for a limit of a quota
+type quotaHeadroom struct {
+	Resource     string
+	Namespace    string
+	ProjectLimit string
+	ProjectUsed  string
+	Remaining    string
+}
+
+func namespaceSetQuota(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+
+	changes, err := quotaChanges(ctx.String("cpu"), ctx.String("memory"), ctx.StringSlice("limit"))
+	if err != nil {
+		return err
+	}
+	remove := ctx.Bool("remove")
+	if remove == (len(changes) > 0) {
+		return NewUsageError(errors.New("use either --remove or the limits to set with --cpu, --memory and --limit"))
+	}
+
+	c, err := GetClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	nsResource, err := Lookup(c, ctx.Args().First(), "namespace")
+	if err != nil {
+		return err
+	}
+	namespace, err := getNamespaceByID(c, nsResource.ID)
+	if err != nil {
+		return err
+	}
+	if namespace.ProjectID == "" {
+		return fmt.Errorf("namespace %s isn't in a project, quotas are set on the namespaces of projects", namespace.Name)
+	}
+	project, err := getProjectByID(c, namespace.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	var current *clusterClient.ResourceQuotaLimit
+	if namespace.ResourceQuota != nil {
+		current = namespace.ResourceQuota.Limit
+	}
+	oldLimits, err := quotaLimits(current)
+	if err != nil {
+		return err
+	}
+	newLimits := map[string]string{}
+	if !remove {
+		for field, value := range oldLimits {
+			newLimits[field] = value
+		}
+		for field, value := range changes {
+			newLimits[field] = value
+		}
+	}
+
+	var headroom []quotaHeadroom
+	if project.ResourceQuota != nil && project.ResourceQuota.Limit != nil {
+		if remove {
+			return fmt.Errorf("project %s has a resource quota, its namespaces must have one too", project.Name)
+		}
+		projectLimits, err := quotaLimits(project.ResourceQuota.Limit)
+		if err != nil {
+			return err
+		}
+		projectUsed, err := quotaLimits(project.ResourceQuota.UsedLimit)
+		if err != nil {
+			return err
+		}
+		headroom, err = projectQuotaHeadroom(project.Name, projectLimits, projectUsed, oldLimits, newLimits)
+		if err != nil {
+			return err
+		}
+	}
+
+	var quota interface{}
+	if !remove {
+		quota = map[string]interface{}{"limit": newLimits}
+	}
+	if _, err := c.ClusterClient.Namespace.Update(namespace, map[string]interface{}{
+		"resourceQuota": quota,
+	}); err != nil {
+		return err
+	}
+
+	if remove {
+		fmt.Printf("Removed the resource quota of namespace %s\n", namespace.Name)
+		return nil
+	}
+	fmt.Printf("Updated the resource quota of namespace %s\n", namespace.Name)
+	if len(headroom) > 0 {
+		fmt.Printf("\nHeadroom of project %s:\n", project.Name)
+		return writeQuotaHeadroom(os.Stdout, headroom)
+	}
+	return nil
+}
+
+// quotaChanges returns the fields of a quota to set, by their JSON name.
+func quotaChanges(cpu, memory string, limits []string) (map[string]string, error) {
+	changes := map[string]string{}
+	if cpu != "" {
+		changes["limitsCpu"] = cpu
+	}
+	if memory != "" {
+		changes["limitsMemory"] = memory
+	}
+	for _, limit := range limits {
+		field, value, ok := strings.Cut(limit, "=")
+		if !ok || field == "" || value == "" {
+			return nil, NewUsageError(fmt.Errorf("invalid --limit %q, expected FIELD=VALUE", limit))
+		}
+		changes[field] = value
+	}
+
+	for field, value := range changes {
+		if !slices.Contains(quotaFields, field) {
+			return nil, NewUsageError(fmt.Errorf("unknown quota field %q, expected one of %s", field, strings.Join(quotaFields, ", ")))
+		}
+		if _, err := resource.ParseQuantity(value); err != nil {
+			return nil, NewUsageError(fmt.Errorf("invalid quantity %q for %s: %v", value, field, err))
+		}
+	}
+	return changes, nil
+}
+
+// quotaFields are the JSON names of the fields of a quota limit
+var quotaFields = []string{
+	"configMaps", "limitsCpu", "limitsMemory", "persistentVolumeClaims", "pods", "replicationControllers",
+	"requestsCpu", "requestsMemory", "requestsStorage", "secrets", "services", "servicesLoadBalancers",
+	"servicesNodePorts",
+}
+
+// quotaLimits returns the fields of a quota limit that are set by their JSON
+// name. It accepts the limits of the management and of the cluster API,
+// which have the same fields.
+func quotaLimits(limit interface{}) (map[string]string, error) {
+	limits := map[string]string{}
+	content, err := json.Marshal(limit)
+	if err != nil {
+		return nil, err
+	}
+	// a nil limit is encoded as null, which leaves the map empty
+	if err := json.Unmarshal(content, &limits); err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// projectQuotaHeadroom checks that replacing the old limits of a namespace
+// with the new ones keeps the namespaces of the project within its limits,
+// and returns the room left for each limit of the project.
+func projectQuotaHeadroom(project string, projectLimits, projectUsed, oldLimits, newLimits map[string]string) ([]quotaHeadroom, error) {
+	for field := range newLimits {
+		if _, ok := projectLimits[field]; !ok {
+			return nil, fmt.Errorf("project %s has no limit of %s, set it in the project quota first", project, field)
+		}
+	}
+
+	var fields []string
+	for field := range projectLimits {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var headroom []quotaHeadroom
+	for _, field := range fields {
+		limit, err := resource.ParseQuantity(projectLimits[field])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s limit of project %s: %w", field, project, err)
+		}
+		used, err := parseOptionalQuantity(projectUsed[field])
+		if err != nil {
+			return nil, err
+		}
+		oldValue, err := parseOptionalQuantity(oldLimits[field])
+		if err != nil {
+			return nil, err
+		}
+		newValue, err := parseOptionalQuantity(newLimits[field])
+		if err != nil {
+			return nil, err
+		}
+
+		used.Sub(oldValue)
+		used.Add(newValue)
+		remaining := limit.DeepCopy()
+		remaining.Sub(used)
+		if remaining.Sign() < 0 {
+			return nil, fmt.Errorf("the %s of the namespaces of project %s would be %s, over the project limit of %s",
+				field, project, used.String(), limit.String())
+		}
+		headroom = append(headroom, quotaHeadroom{
+			Resource:     field,
+			Namespace:    valueOrDefault(newLimits[field], "-"),
+			ProjectLimit: limit.String(),
+			ProjectUsed:  used.String(),
+			Remaining:    remaining.String(),
+		})
+	}
+	return headroom, nil
+}
+
+func parseOptionalQuantity(value string) (resource.Quantity, error) {
+	if value == "" {
+		return resource.Quantity{}, nil
+	}
+	return resource.ParseQuantity(value)
+}
+
+func writeQuotaHeadroom(out io.Writer, headroom []quotaHeadroom) error {
+	writer := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+	fmt.Fprintln(writer, "RESOURCE\tNAMESPACE\tPROJECT LIMIT\tPROJECT USED\tREMAINING")
+	for _, h := range headroom {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", h.Resource, h.Namespace, h.ProjectLimit, h.ProjectUsed, h.Remaining)
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"testing"
+
+	clusterClient "github.com/rancher/rancher/pkg/client/generated/cluster/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaChanges(t *testing.T) {
+	changes, err := quotaChanges("2", "4Gi", []string{"pods=20"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"limitsCpu": "2", "limitsMemory": "4Gi", "pods": "20"}, changes)
+
+	_, err = quotaChanges("", "", []string{"gpus=1"})
+	assert.Error(t, err)
+	_, err = quotaChanges("two", "", nil)
+	assert.Error(t, err)
+	_, err = quotaChanges("", "", []string{"pods"})
+	assert.Error(t, err)
+}
+
+func TestQuotaLimits(t *testing.T) {
+	limits, err := quotaLimits(&clusterClient.ResourceQuotaLimit{LimitsCPU: "1", Pods: "10"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"limitsCpu": "1", "pods": "10"}, limits)
+
+	var limit *clusterClient.ResourceQuotaLimit
+	limits, err = quotaLimits(limit)
+	require.NoError(t, err)
+	assert.Empty(t, limits)
+}
+
+func TestProjectQuotaHeadroom(t *testing.T) {
+	projectLimits := map[string]string{"limitsCpu": "10", "limitsMemory": "20Gi"}
+	projectUsed := map[string]string{"limitsCpu": "6", "limitsMemory": "8Gi"}
+	oldLimits := map[string]string{"limitsCpu": "2", "limitsMemory": "4Gi"}
+
+	headroom, err := projectQuotaHeadroom("team-a", projectLimits, projectUsed, oldLimits,
+		map[string]string{"limitsCpu": "4", "limitsMemory": "4Gi"})
+	require.NoError(t, err)
+	assert.Equal(t, []quotaHeadroom{
+		{Resource: "limitsCpu", Namespace: "4", ProjectLimit: "10", ProjectUsed: "8", Remaining: "2"},
+		{Resource: "limitsMemory", Namespace: "4Gi", ProjectLimit: "20Gi", ProjectUsed: "8Gi", Remaining: "12Gi"},
+	}, headroom)
+
+	_, err = projectQuotaHeadroom("team-a", projectLimits, projectUsed, oldLimits,
+		map[string]string{"limitsCpu": "7", "limitsMemory": "4Gi"})
+	assert.EqualError(t, err, "the limitsCpu of the namespaces of project team-a would be 11, over the project limit of 10")
+
+	_, err = projectQuotaHeadroom("team-a", projectLimits, projectUsed, oldLimits,
+		map[string]string{"limitsCpu": "2", "pods": "10"})
+	assert.Error(t, err)
+}
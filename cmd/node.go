@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 
@@ -10,11 +11,18 @@ import (
 	"github.com/urfave/cli"
 )
 
+const (
+	osLinux   = "linux"
+	osWindows = "windows"
+)
+
 type NodeData struct {
 	ID   string
 	Node managementClient.Node
 	Name string
 	Pool string
+	OS   string
+	Age  string
 }
 
 func NodeCommand() cli.Command {
@@ -36,6 +44,17 @@ func NodeCommand() cli.Command {
 						Usage: "'json', 'yaml' or Custom format: '{{.Node.ID}} {{.Node.Name}}'",
 					},
 					quietFlag,
+					filterFlag,
+					labelSelectorFlag,
+					sortByFlag,
+					timestampsFlag,
+					noHeadersFlag,
+					limitFlag,
+					pageSizeFlag,
+					cli.StringFlag{
+						Name:  "os",
+						Usage: "Only list the nodes running this operating system, 'linux' or 'windows'",
+					},
 				},
 			},
 			{
@@ -44,13 +63,20 @@ func NodeCommand() cli.Command {
 				Usage:     "Delete a node by ID",
 				ArgsUsage: "[NODEID NODENAME]",
 				Action:    nodeDelete,
+				Flags:     deleteFlags,
 			},
+			nodeDrainCommand(),
 		},
 	}
 }
 
 func nodeLs(ctx *cli.Context) error {
-	c, err := GetClient(ctx)
+	osFilter := strings.ToLower(ctx.String("os"))
+	if osFilter != "" && osFilter != osLinux && osFilter != osWindows {
+		return NewUsageError(fmt.Errorf("invalid os %q, expected linux or windows", ctx.String("os")))
+	}
+
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -71,16 +97,27 @@ func nodeLs(ctx *cli.Context) error {
 		{"STATE", "Node.State"},
 		{"POOL", "Pool"},
 		{"DESCRIPTION", "Node.Description"},
+		{"AGE", "Age"},
+		{"HOSTNAME", "Node.Hostname", wideFormat},
+		{"IP", "Node.IPAddress", wideFormat},
+		{"CLUSTER", "Node.ClusterID", wideFormat},
+		{"OS", "OS", wideFormat},
 	}, ctx)
 
 	defer writer.Close()
 
 	for _, item := range collection.Data {
+		nodeOS := getNodeOS(item)
+		if osFilter != "" && nodeOS != osFilter {
+			continue
+		}
 		writer.Write(&NodeData{
 			ID:   item.ID,
 			Node: item,
 			Name: getNodeName(item),
 			Pool: getNodePoolName(item, nodePools),
+			OS:   nodeOS,
+			Age:  formatAge(ctx, item.Created),
 		})
 	}
 
@@ -89,37 +126,41 @@ func nodeLs(ctx *cli.Context) error {
 
 func nodeDelete(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	for _, arg := range ctx.Args() {
-		resource, err := Lookup(c, arg, "node")
-		if err != nil {
-			return err
-		}
-
-		node, err := getNodeByID(ctx, c, resource.ID)
-		if err != nil {
-			return err
-		}
-
-		if _, ok := node.Links["remove"]; !ok {
-			logrus.Warnf("node %v is externally managed and must be deleted "+
-				"through the provider", getNodeName(node))
-			continue
-		}
-
-		err = c.ManagementClient.Node.Delete(&node)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	return bulkDelete{
+		kind: "nodes",
+		resolve: func(arg string) (*bulkTarget, error) {
+			resource, err := Lookup(c, arg, "node")
+			if err != nil {
+				return nil, err
+			}
+
+			node, err := getNodeByID(ctx, c, resource.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := node.Links["remove"]; !ok {
+				logrus.Warnf("node %v is externally managed and must be deleted "+
+					"through the provider", getNodeName(node))
+				return nil, nil
+			}
+			return &bulkTarget{
+				resource:     node.Resource,
+				descriptions: []string{fmt.Sprintf("%s (%s)", getNodeName(node), node.ID)},
+				delete: func() error {
+					return c.ManagementClient.Node.Delete(&node)
+				},
+			}, nil
+		},
+	}.run(ctx, c)
 }
 
 func getNodesList(
@@ -127,13 +168,17 @@ func getNodesList(
 	c *cliclient.MasterClient,
 	clusterID string,
 ) (*managementClient.NodeCollection, error) {
-	filter := defaultListOpts(ctx)
+	filter := filteredListOpts(ctx)
 	filter.Filters["clusterId"] = clusterID
 
 	collection, err := c.ManagementClient.Node.List(filter)
 	if err != nil {
 		return nil, err
 	}
+	collection.Data, err = listAll(ctx, collection, func(c *managementClient.NodeCollection) []managementClient.Node { return c.Data })
+	if err != nil {
+		return nil, err
+	}
 	return collection, nil
 }
 
@@ -168,6 +213,21 @@ func getNodeName(node managementClient.Node) string {
 	return node.ID
 }
 
+// getNodeOS returns the operating system of a node, linux or windows, from
+// its kubernetes.io/os label or from the information reported by its agent.
+func getNodeOS(node managementClient.Node) string {
+	if label := node.Labels["kubernetes.io/os"]; label != "" {
+		return strings.ToLower(label)
+	}
+	if node.Info != nil && node.Info.OS != nil && node.Info.OS.OperatingSystem != "" {
+		if strings.Contains(strings.ToLower(node.Info.OS.OperatingSystem), osWindows) {
+			return osWindows
+		}
+		return osLinux
+	}
+	return ""
+}
+
 func getNodePools(
 	ctx *cli.Context,
 	c *cliclient.MasterClient,
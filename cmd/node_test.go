@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNodeOS(t *testing.T) {
+	tests := []struct {
+		name string
+		node managementClient.Node
+		want string
+	}{
+		{name: "label", node: managementClient.Node{Labels: map[string]string{"kubernetes.io/os": "windows"}}, want: osWindows},
+		{name: "windows info", node: managementClient.Node{Info: &managementClient.NodeInfo{OS: &managementClient.OSInfo{OperatingSystem: "Windows Server 2019 Datacenter"}}}, want: osWindows},
+		{name: "linux info", node: managementClient.Node{Info: &managementClient.NodeInfo{OS: &managementClient.OSInfo{OperatingSystem: "Ubuntu 22.04.3 LTS"}}}, want: osLinux},
+		{name: "unknown", node: managementClient.Node{}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getNodeOS(tt.node))
+		})
+	}
+}
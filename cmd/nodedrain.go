@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rancher/cli/cliclient"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/urfave/cli"
+)
+
+// drainWaitMargin is how much longer than the drain timeout given to the
+// server the CLI waits for the nodes to be reported drained.
+const drainWaitMargin = time.Minute
+
+func nodeDrainCommand() cli.Command {
+	return cli.Command{
+		Name:        "drain",
+		Usage:       "Drain nodes by ID or name",
+		Description: "\nCordons the nodes and evicts their workloads, showing the state of each node until they are drained.",
+		ArgsUsage:   "[NODEID NODENAME...]",
+		Action:      nodeDrain,
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "delete-local-data",
+				Usage: "Evict pods using emptyDir volumes, their data is lost",
+			},
+			cli.BoolFlag{
+				Name:  "force",
+				Usage: "Evict pods not managed by a controller",
+			},
+			cli.IntFlag{
+				Name:  "grace-period",
+				Usage: "Seconds given to each pod to terminate, -1 uses the grace period of the pod",
+				Value: -1,
+			},
+			cli.BoolTFlag{
+				Name:  "ignore-daemonsets",
+				Usage: "Drain the nodes even though they run pods of daemonsets, [default=true]",
+			},
+			cli.IntFlag{
+				Name:  "timeout",
+				Usage: "Time in seconds the server tries to drain each node before giving up",
+				Value: 120,
+			},
+		},
+	}
+}
+
+func nodeDrain(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	ignoreDaemonSets := ctx.BoolT("ignore-daemonsets")
+	input := &managementClient.NodeDrainInput{
+		DeleteLocalData:  ctx.Bool("delete-local-data"),
+		Force:            ctx.Bool("force"),
+		GracePeriod:      int64(ctx.Int("grace-period")),
+		IgnoreDaemonSets: &ignoreDaemonSets,
+		Timeout:          int64(ctx.Int("timeout")),
+	}
+
+	var nodes []managementClient.Node
+	for _, arg := range ctx.Args() {
+		resource, err := Lookup(c, arg, managementClient.NodeType)
+		if err != nil {
+			return err
+		}
+		node, err := c.ManagementClient.Node.ByID(resource.ID)
+		if err != nil {
+			return err
+		}
+		if err := c.ManagementClient.Node.ActionDrain(node, input); err != nil {
+			return fmt.Errorf("draining node %s: %w", getNodeName(*node), err)
+		}
+		nodes = append(nodes, *node)
+	}
+
+	return waitForDrain(c, nodes, time.Duration(ctx.Int("timeout"))*time.Second+drainWaitMargin)
+}
+
+// waitForDrain polls nodes until they are all drained, reporting the state of
+// each node until then.
+func waitForDrain(c *cliclient.MasterClient, nodes []managementClient.Node, timeout time.Duration) error {
+	if c.DryRun {
+		return nil
+	}
+
+	ctx, cancel := waitContext(timeout)
+	defer cancel()
+
+	var names []string
+	for _, node := range nodes {
+		names = append(names, getNodeName(node))
+	}
+	p := newProgress("Draining " + strings.Join(names, ", "))
+	err := pollUntil(ctx, newBackoff(pollInitialInterval, pollMaxInterval), func() (bool, error) {
+		current := make([]managementClient.Node, 0, len(nodes))
+		for _, node := range nodes {
+			latest, err := c.ManagementClient.Node.ByID(node.ID)
+			if err != nil {
+				return false, err
+			}
+			current = append(current, *latest)
+		}
+		steps, done, err := drainSteps(current)
+		p.Update("", steps)
+		return done, err
+	})
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		p.Done("Timeout reached")
+		return timeoutErrorf("Timeout reached draining %s", strings.Join(names, ", "))
+	case errors.Is(err, context.Canceled):
+		p.Done("Interrupted")
+		return interruptedErrorf("interrupted waiting for %s to be drained", strings.Join(names, ", "))
+	case err != nil:
+		p.Done("Failed")
+		return err
+	}
+	p.Done(fmt.Sprintf("Drained %s", strings.Join(names, ", ")))
+	return nil
+}
+
+// drainSteps returns the state of each node keyed by name, whether they are
+// all drained, and an error naming the nodes the server failed to drain.
+func drainSteps(nodes []managementClient.Node) (map[string]string, bool, error) {
+	steps := map[string]string{}
+	var failed []string
+	drained := 0
+	for _, node := range nodes {
+		name := getNodeName(node)
+		state := node.State
+		if message := drainFailure(node); message != "" {
+			failed = append(failed, fmt.Sprintf("%s (%s)", name, message))
+			state = "failed, " + message
+		} else if node.TransitioningMessage != "" {
+			state += ", " + firstLine(node.TransitioningMessage)
+		}
+		if node.State == "drained" {
+			drained++
+		}
+		steps[name] = state
+	}
+	sort.Strings(failed)
+
+	if len(failed) > 0 {
+		return steps, false, fmt.Errorf("failed to drain %s", strings.Join(failed, ", "))
+	}
+	return steps, drained == len(nodes), nil
+}
+
+// drainFailure returns why the server failed to drain node, or an empty string
+// while it's draining or once it's drained.
+func drainFailure(node managementClient.Node) string {
+	if node.Transitioning == "error" {
+		return firstLine(node.TransitioningMessage)
+	}
+	for _, condition := range node.Conditions {
+		if condition.Type == "Drained" && condition.Status == "False" && condition.Message != "" {
+			return firstLine(condition.Message)
+		}
+	}
+	return ""
+}
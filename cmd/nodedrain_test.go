@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"testing"
+
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrainSteps(t *testing.T) {
+	draining := managementClient.Node{NodeName: "worker-1", State: "draining", TransitioningMessage: "evicting pod web-0"}
+	drained := managementClient.Node{NodeName: "worker-2", State: "drained"}
+	failed := managementClient.Node{NodeName: "worker-3", State: "active", Conditions: []managementClient.NodeCondition{
+		{Type: "Drained", Status: "False", Message: "cannot delete pods with local storage"},
+	}}
+
+	tests := []struct {
+		name    string
+		nodes   []managementClient.Node
+		steps   map[string]string
+		done    bool
+		wantErr string
+	}{
+		{
+			name:  "draining",
+			nodes: []managementClient.Node{draining, drained},
+			steps: map[string]string{"worker-1": "draining, evicting pod web-0", "worker-2": "drained"},
+		},
+		{
+			name:  "drained",
+			nodes: []managementClient.Node{drained},
+			steps: map[string]string{"worker-2": "drained"},
+			done:  true,
+		},
+		{
+			name:    "failed",
+			nodes:   []managementClient.Node{draining, failed},
+			steps:   map[string]string{"worker-1": "draining, evicting pod web-0", "worker-3": "failed, cannot delete pods with local storage"},
+			wantErr: "failed to drain worker-3 (cannot delete pods with local storage)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps, done, err := drainSteps(tt.nodes)
+			assert.Equal(t, tt.steps, steps)
+			assert.Equal(t, tt.done, done)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,448 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rancher/cli/cliclient"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/urfave/cli"
+)
+
+const (
+	roleEtcd         = "etcd"
+	roleControlPlane = "controlplane"
+	roleWorker       = "worker"
+)
+
+const createNodePoolDescription = `
+Creates a node pool in a cluster provisioned with a node driver. The nodes of the pool are created
+from a node template, 'rancher vm template create' creates one from a VM template.
+
+Example:
+	$ rancher nodepool create --node-template my-template --role etcd --role controlplane mycluster control
+	$ rancher nodepool create --node-template my-template --quantity 3 --taint gpu=true:NoSchedule mycluster gpu
+`
+
+const editNodePoolDescription = `
+Changes a node pool, only the given flags are changed. --role and --taint replace the roles and
+taints of the pool, use --taint "" to remove all taints.
+
+Example:
+	$ rancher nodepool edit --quantity 5 mycluster worker
+	$ rancher nodepool edit --node-template larger-template mycluster worker
+`
+
+// taintEffects are the effects a taint can have
+var taintEffects = []string{"NoSchedule", "PreferNoSchedule", "NoExecute"}
+
+type NodePoolData struct {
+	ID       string
+	NodePool managementClient.NodePool
+	Template string
+	Roles    string
+	Taints   string
+}
+
+func NodePoolCommand() cli.Command {
+	poolFlags := []cli.Flag{
+		cli.StringFlag{
+			Name:  "node-template",
+			Usage: "Name or ID of the node template the nodes are created from",
+		},
+		cli.StringSliceFlag{
+			Name:  "role",
+			Usage: "Role of the nodes: etcd, controlplane or worker, can be used multiple times",
+		},
+		cli.Int64Flag{
+			Name:  "quantity",
+			Usage: "Number of nodes in the pool",
+			Value: 1,
+		},
+		cli.StringSliceFlag{
+			Name:  "taint",
+			Usage: "Taint of the nodes in the format KEY[=VALUE]:EFFECT, can be used multiple times",
+		},
+	}
+
+	return cli.Command{
+		Name:    "nodepools",
+		Aliases: []string{"nodepool"},
+		Usage:   "Operations on the node pools of a cluster",
+		Action:  defaultAction(nodePoolLs),
+		Subcommands: []cli.Command{
+			{
+				Name:        "ls",
+				Usage:       "List node pools",
+				Description: "\nLists the node pools of a cluster, the current cluster by default.",
+				ArgsUsage:   "[CLUSTERNAME/CLUSTERID]",
+				Action:      nodePoolLs,
+				Flags: []cli.Flag{
+					formatFlag,
+					quietFlag,
+					filterFlag,
+					labelSelectorFlag,
+					sortByFlag,
+					noHeadersFlag,
+				},
+			},
+			{
+				Name:        "create",
+				Usage:       "Create a node pool",
+				Description: createNodePoolDescription,
+				ArgsUsage:   "[CLUSTERNAME/CLUSTERID HOSTNAME_PREFIX]",
+				Action:      nodePoolCreate,
+				Flags:       poolFlags,
+			},
+			{
+				Name:        "edit",
+				Usage:       "Change a node pool",
+				Description: editNodePoolDescription,
+				ArgsUsage:   "[CLUSTERNAME/CLUSTERID HOSTNAME_PREFIX/NODEPOOLID]",
+				Action:      nodePoolEdit,
+				Flags:       poolFlags,
+			},
+			{
+				Name:      "delete",
+				Aliases:   []string{"rm"},
+				Usage:     "Delete node pools and their nodes",
+				ArgsUsage: "[CLUSTERNAME/CLUSTERID HOSTNAME_PREFIX/NODEPOOLID...]",
+				Action:    nodePoolDelete,
+				Flags:     deleteFlags,
+			},
+		},
+	}
+}
+
+func nodePoolLs(ctx *cli.Context) error {
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	clusterID, err := resolveClusterID(c, ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	pools, err := getClusterNodePools(c, clusterID)
+	if err != nil {
+		return err
+	}
+
+	templates, err := nodeTemplateNames(c)
+	if err != nil {
+		return err
+	}
+
+	writer := NewTableWriter([][]string{
+		{"ID", "ID"},
+		{"HOSTNAME PREFIX", "NodePool.HostnamePrefix"},
+		{"NODE TEMPLATE", "Template"},
+		{"ROLES", "Roles"},
+		{"QUANTITY", "NodePool.Quantity"},
+		{"STATE", "NodePool.State"},
+		{"TAINTS", "Taints", "wide"},
+	}, ctx)
+
+	defer writer.Close()
+
+	for _, pool := range pools {
+		writer.Write(&NodePoolData{
+			ID:       pool.ID,
+			NodePool: pool,
+			Template: valueOrDefault(templates[pool.NodeTemplateID], pool.NodeTemplateID),
+			Roles:    strings.Join(nodePoolRoles(pool), ","),
+			Taints:   formatTaints(pool.NodeTaints),
+		})
+	}
+
+	return writer.Err()
+}
+
+func nodePoolCreate(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return showSubcommandUsage(ctx)
+	}
+	if ctx.String("node-template") == "" {
+		return NewUsageError(errors.New("--node-template is required"))
+	}
+
+	roles, err := parseNodeRoles(ctx.StringSlice("role"))
+	if err != nil {
+		return err
+	}
+	if len(roles) == 0 {
+		roles = []string{roleWorker}
+	}
+	taints, err := parseTaints(ctx.StringSlice("taint"))
+	if err != nil {
+		return err
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	clusterID, err := resolveClusterID(c, ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	template, err := Lookup(c, ctx.String("node-template"), "nodeTemplate")
+	if err != nil {
+		return err
+	}
+
+	pool := &managementClient.NodePool{
+		ClusterID:      clusterID,
+		HostnamePrefix: ctx.Args().Get(1),
+		NodeTemplateID: template.ID,
+		Quantity:       ctx.Int64("quantity"),
+		NodeTaints:     taints,
+		Etcd:           slices.Contains(roles, roleEtcd),
+		ControlPlane:   slices.Contains(roles, roleControlPlane),
+		Worker:         slices.Contains(roles, roleWorker),
+	}
+
+	created, err := c.ManagementClient.NodePool.Create(pool)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created node pool %s (%s)\n", created.HostnamePrefix, created.ID)
+	return nil
+}
+
+func nodePoolEdit(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return showSubcommandUsage(ctx)
+	}
+
+	// the updates are a map so that roles can be set to false
+	updates := map[string]interface{}{}
+	if ctx.IsSet("role") {
+		roles, err := parseNodeRoles(ctx.StringSlice("role"))
+		if err != nil {
+			return err
+		}
+		if len(roles) == 0 {
+			return NewUsageError(errors.New("a node pool needs at least one role"))
+		}
+		updates["etcd"] = slices.Contains(roles, roleEtcd)
+		updates["controlPlane"] = slices.Contains(roles, roleControlPlane)
+		updates["worker"] = slices.Contains(roles, roleWorker)
+	}
+	if ctx.IsSet("taint") {
+		taints, err := parseTaints(ctx.StringSlice("taint"))
+		if err != nil {
+			return err
+		}
+		updates["nodeTaints"] = taints
+	}
+	if ctx.IsSet("quantity") {
+		if ctx.Int64("quantity") < 0 {
+			return NewUsageError(errors.New("--quantity can't be negative"))
+		}
+		updates["quantity"] = ctx.Int64("quantity")
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if ctx.String("node-template") != "" {
+		template, err := Lookup(c, ctx.String("node-template"), "nodeTemplate")
+		if err != nil {
+			return err
+		}
+		updates["nodeTemplateId"] = template.ID
+	}
+	if len(updates) == 0 {
+		return NewUsageError(errors.New("nothing to change, give at least one of --node-template, --role, --quantity and --taint"))
+	}
+
+	clusterID, err := resolveClusterID(c, ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	pools, err := getClusterNodePools(c, clusterID)
+	if err != nil {
+		return err
+	}
+	pool, err := findNodePool(pools, ctx.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.ManagementClient.NodePool.Update(pool, updates); err != nil {
+		return err
+	}
+
+	fmt.Printf("Updated node pool %s (%s)\n", pool.HostnamePrefix, pool.ID)
+	return nil
+}
+
+func nodePoolDelete(ctx *cli.Context) error {
+	if ctx.NArg() < 2 {
+		return showSubcommandUsage(ctx)
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	clusterID, err := resolveClusterID(c, ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	pools, err := getClusterNodePools(c, clusterID)
+	if err != nil {
+		return err
+	}
+
+	return bulkDelete{
+		kind: "node pools",
+		args: ctx.Args().Tail(),
+		resolve: func(arg string) (*bulkTarget, error) {
+			pool, err := findNodePool(pools, arg)
+			if err != nil {
+				return nil, err
+			}
+			return &bulkTarget{
+				resource:     pool.Resource,
+				descriptions: []string{fmt.Sprintf("%s (%s) with %d nodes", pool.HostnamePrefix, pool.ID, pool.Quantity)},
+				delete: func() error {
+					return c.ManagementClient.NodePool.Delete(pool)
+				},
+			}, nil
+		},
+	}.run(ctx, c)
+}
+
+func getClusterNodePools(c *cliclient.MasterClient, clusterID string) ([]managementClient.NodePool, error) {
+	filter := defaultListOpts(nil)
+	filter.Filters["clusterId"] = clusterID
+
+	collection, err := c.ManagementClient.NodePool.List(filter)
+	if err != nil {
+		return nil, err
+	}
+	return listAll(nil, collection, func(c *managementClient.NodePoolCollection) []managementClient.NodePool { return c.Data })
+}
+
+// nodeTemplateNames maps the IDs of the node templates to their names
+func nodeTemplateNames(c *cliclient.MasterClient) (map[string]string, error) {
+	collection, err := c.ManagementClient.NodeTemplate.List(defaultListOpts(nil))
+	if err != nil {
+		return nil, err
+	}
+	templates, err := listAll(nil, collection, func(c *managementClient.NodeTemplateCollection) []managementClient.NodeTemplate { return c.Data })
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(templates))
+	for _, template := range templates {
+		names[template.ID] = template.Name
+	}
+	return names, nil
+}
+
+// findNodePool returns the pool of pools with the ID or hostname prefix arg,
+// the cluster part of the ID can be left out.
+func findNodePool(pools []managementClient.NodePool, arg string) (*managementClient.NodePool, error) {
+	var found []managementClient.NodePool
+	for _, pool := range pools {
+		if pool.ID == arg || strings.HasSuffix(pool.ID, ":"+arg) {
+			return &pool, nil
+		}
+		if pool.HostnamePrefix == arg {
+			found = append(found, pool)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, notFoundErrorf("no node pool %s found in the cluster", arg)
+	case 1:
+		return &found[0], nil
+	default:
+		ids := make([]string, len(found))
+		for i, pool := range found {
+			ids[i] = pool.ID
+		}
+		return nil, fmt.Errorf("multiple node pools with hostname prefix %s: %s", arg, strings.Join(ids, ", "))
+	}
+}
+
+// parseNodeRoles returns the roles of --role, which may also be separated by
+// commas.
+func parseNodeRoles(values []string) ([]string, error) {
+	var roles []string
+	for _, value := range values {
+		for _, role := range strings.Split(value, ",") {
+			role = strings.ToLower(strings.TrimSpace(role))
+			switch role {
+			case "":
+			case roleEtcd, roleControlPlane, roleWorker:
+				roles = append(roles, role)
+			default:
+				return nil, NewUsageError(fmt.Errorf("invalid role %q, expected etcd, controlplane or worker", role))
+			}
+		}
+	}
+	return roles, nil
+}
+
+func nodePoolRoles(pool managementClient.NodePool) []string {
+	var roles []string
+	if pool.Etcd {
+		roles = append(roles, roleEtcd)
+	}
+	if pool.ControlPlane {
+		roles = append(roles, roleControlPlane)
+	}
+	if pool.Worker {
+		roles = append(roles, roleWorker)
+	}
+	return roles
+}
+
+// parseTaints parses taints in the format KEY[=VALUE]:EFFECT, empty values
+// are ignored so that "" removes all taints.
+func parseTaints(values []string) ([]managementClient.Taint, error) {
+	taints := []managementClient.Taint{}
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		i := strings.LastIndex(value, ":")
+		if i < 0 {
+			return nil, NewUsageError(fmt.Errorf("invalid taint %q, expected KEY[=VALUE]:EFFECT", value))
+		}
+		key, taintValue, _ := strings.Cut(value[:i], "=")
+		effect := value[i+1:]
+		if key == "" || !slices.Contains(taintEffects, effect) {
+			return nil, NewUsageError(fmt.Errorf("invalid taint %q, expected KEY[=VALUE]:EFFECT with an effect of %s",
+				value, strings.Join(taintEffects, ", ")))
+		}
+		taints = append(taints, managementClient.Taint{Key: key, Value: taintValue, Effect: effect})
+	}
+	return taints, nil
+}
+
+func formatTaints(taints []managementClient.Taint) string {
+	formatted := make([]string, len(taints))
+	for i, taint := range taints {
+		formatted[i] = taint.Key
+		if taint.Value != "" {
+			formatted[i] += "=" + taint.Value
+		}
+		formatted[i] += ":" + taint.Effect
+	}
+	return strings.Join(formatted, ",")
+}
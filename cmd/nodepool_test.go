@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/rancher/norman/types"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTaints(t *testing.T) {
+	taints, err := parseTaints([]string{"gpu=true:NoSchedule", "dedicated:NoExecute", "url=http://x:PreferNoSchedule", ""})
+	require.NoError(t, err)
+	assert.Equal(t, []managementClient.Taint{
+		{Key: "gpu", Value: "true", Effect: "NoSchedule"},
+		{Key: "dedicated", Effect: "NoExecute"},
+		{Key: "url", Value: "http://x", Effect: "PreferNoSchedule"},
+	}, taints)
+	assert.Equal(t, "gpu=true:NoSchedule,dedicated:NoExecute,url=http://x:PreferNoSchedule", formatTaints(taints))
+
+	taints, err = parseTaints([]string{""})
+	require.NoError(t, err)
+	assert.Empty(t, taints)
+
+	_, err = parseTaints([]string{"gpu=true"})
+	assert.EqualError(t, err, `invalid taint "gpu=true", expected KEY[=VALUE]:EFFECT`)
+	_, err = parseTaints([]string{"gpu=true:Never"})
+	assert.EqualError(t, err, `invalid taint "gpu=true:Never", expected KEY[=VALUE]:EFFECT with an effect of NoSchedule, PreferNoSchedule, NoExecute`)
+}
+
+func TestParseNodeRoles(t *testing.T) {
+	roles, err := parseNodeRoles([]string{"etcd,ControlPlane", "worker"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"etcd", "controlplane", "worker"}, roles)
+
+	_, err = parseNodeRoles([]string{"master"})
+	assert.EqualError(t, err, `invalid role "master", expected etcd, controlplane or worker`)
+}
+
+func TestFindNodePool(t *testing.T) {
+	pools := []managementClient.NodePool{
+		{Resource: types.Resource{ID: "c-abc:np-1"}, HostnamePrefix: "control"},
+		{Resource: types.Resource{ID: "c-abc:np-2"}, HostnamePrefix: "worker"},
+		{Resource: types.Resource{ID: "c-abc:np-3"}, HostnamePrefix: "worker"},
+	}
+
+	pool, err := findNodePool(pools, "control")
+	require.NoError(t, err)
+	assert.Equal(t, "c-abc:np-1", pool.ID)
+
+	pool, err = findNodePool(pools, "np-3")
+	require.NoError(t, err)
+	assert.Equal(t, "c-abc:np-3", pool.ID)
+
+	_, err = findNodePool(pools, "worker")
+	assert.EqualError(t, err, "multiple node pools with hostname prefix worker: c-abc:np-2, c-abc:np-3")
+
+	_, err = findNodePool(pools, "gpu")
+	assert.EqualError(t, err, "no node pool gpu found in the cluster")
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/urfave/cli"
+)
+
+const notifierType = "notifier"
+
+const notifierTestDescription = `
+Sends a test message through a notifier of the cluster alerts, so that the routing of alerts can
+be verified after configuring a notifier. The message is sent by the Rancher server with the
+configuration of the notifier, the command fails when the server can't deliver it.
+
+Notifiers are part of the legacy cluster alerting of Rancher v2.5 and older.
+
+Example:
+	$ rancher notifier test ops-slack --message "checking the route to #ops"
+`
+
+const defaultNotifierTestMessage = "This is a test message sent from the Rancher CLI"
+
+// notificationInput is the input of the send action of a notifier, the
+// notifier's own configuration is used when no configuration is given.
+type notificationInput struct {
+	Message string `json:"message,omitempty"`
+}
+
+func NotifierCommand() cli.Command {
+	return cli.Command{
+		Name:  "notifier",
+		Usage: "Operations on the notifiers of cluster alerts",
+		Subcommands: []cli.Command{
+			{
+				Name:        "test",
+				Usage:       "Send a test message through a notifier",
+				Description: notifierTestDescription,
+				ArgsUsage:   "[NOTIFIER_NAME/NOTIFIER_ID]",
+				Action:      notifierTest,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "message",
+						Usage: "Text of the test message",
+						Value: defaultNotifierTestMessage,
+					},
+				},
+			},
+		},
+	}
+}
+
+func notifierTest(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := c.ManagementClient.APIBaseClient.Types[notifierType]; !ok {
+		return errors.New("the Rancher server has no notifiers, they are only available with the legacy cluster alerting")
+	}
+
+	notifier, err := Lookup(c, ctx.Args().First(), notifierType)
+	if err != nil {
+		return err
+	}
+	if _, ok := notifier.Actions["send"]; !ok {
+		return fmt.Errorf("notifier %s can't send test messages", ctx.Args().First())
+	}
+
+	input := &notificationInput{Message: ctx.String("message")}
+	if err := c.ManagementClient.Action(notifierType, "send", notifier, input, nil); err != nil {
+		return fmt.Errorf("sending test message through notifier %s: %w", ctx.Args().First(), err)
+	}
+
+	fmt.Printf("Test message delivered through notifier %s (%s)\n", ctx.Args().First(), notifier.ID)
+	return nil
+}
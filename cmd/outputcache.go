@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rancher/norman/clientbase"
+	"github.com/sirupsen/logrus"
+)
+
+const outputCacheDir = "output-cache"
+
+// cachedOutput is the output of a listing, saved to be printed with --cached
+// when the server is unreachable.
+type cachedOutput struct {
+	Server  string    `json:"server"`
+	Command string    `json:"command"`
+	Time    time.Time `json:"time"`
+	Output  string    `json:"output"`
+}
+
+// outputCache records the tables printed by a command and saves them once the
+// command succeeds. It's disabled until configured with a directory, and for
+// commands that aren't read-only.
+type outputCache struct {
+	dir      string
+	server   string
+	command  string
+	readOnly bool
+	cached   bool
+	now      func() time.Time
+	output   bytes.Buffer
+}
+
+var listingCache = &outputCache{now: time.Now}
+
+func outputCachePath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), outputCacheDir)
+}
+
+// configure sets the server and the command whose output is recorded, served
+// instead of failing when the server is unreachable with cached. Only the
+// output of read-only commands is recorded and served.
+func (o *outputCache) configure(dir, server string, args []string, readOnly, cached bool) {
+	o.dir = dir
+	o.server = server
+	o.command = strings.Join(args, " ")
+	o.readOnly = readOnly
+	o.cached = cached
+	o.output.Reset()
+}
+
+// disable stops recording the output of the command and replaying it, for
+// commands whose output must never be saved or printed again.
+func (o *outputCache) disable() {
+	o.dir = ""
+	o.output.Reset()
+}
+
+// recorder returns the writer the tables are copied to, nil when disabled.
+func (o *outputCache) recorder() io.Writer {
+	if o.dir == "" || !o.readOnly {
+		return nil
+	}
+	return &o.output
+}
+
+func (o *outputCache) path() string {
+	sum := sha256.Sum256([]byte(o.server + "\x00" + o.command))
+	return filepath.Join(o.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// finish saves the recorded output when err, the error of the command, is
+// nil. When the server is unreachable and --cached is set the output saved
+// by a previous run is printed to out and no error is returned.
+func (o *outputCache) finish(out io.Writer, err error) error {
+	if o.dir == "" || !o.readOnly {
+		return err
+	}
+	if err == nil {
+		if o.output.Len() > 0 {
+			o.save()
+		}
+		return nil
+	}
+
+	// output already printed can't be completed with the cached one
+	if !o.cached || o.output.Len() > 0 || !serverUnreachable(err) {
+		return err
+	}
+	cached, ok := o.load()
+	if !ok {
+		return fmt.Errorf("%w, and the output of %q isn't cached", err, o.command)
+	}
+	logrus.Warnf("The server is unreachable: %v", err)
+	logrus.Warnf("Showing the output cached at %s (%s ago), it may be stale",
+		cached.Time.Local().Format(time.RFC3339), humanizeDuration(o.now().Sub(cached.Time)))
+	_, writeErr := io.WriteString(out, cached.Output)
+	return writeErr
+}
+
+// save writes the recorded output, failing to save it isn't fatal.
+func (o *outputCache) save() {
+	content, err := json.Marshal(cachedOutput{
+		Server:  o.server,
+		Command: o.command,
+		Time:    o.now().UTC(),
+		Output:  o.output.String(),
+	})
+	if err != nil {
+		logrus.Debugf("Unable to encode the output cache: %v", err)
+		return
+	}
+	if err := os.MkdirAll(o.dir, 0700); err != nil {
+		logrus.Debugf("Unable to save the output cache: %v", err)
+		return
+	}
+
+	output, err := os.CreateTemp(o.dir, "output.tmp-*")
+	if err != nil {
+		logrus.Debugf("Unable to save the output cache: %v", err)
+		return
+	}
+	defer os.Remove(output.Name())
+	_, err = output.Write(content)
+	if closeErr := output.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(output.Name(), o.path())
+	}
+	if err != nil {
+		logrus.Debugf("Unable to save the output cache: %v", err)
+	}
+}
+
+func (o *outputCache) load() (cachedOutput, bool) {
+	var cached cachedOutput
+	content, err := os.ReadFile(o.path())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logrus.Debugf("Unable to read the output cache: %v", err)
+		}
+		return cached, false
+	}
+	if err := json.Unmarshal(content, &cached); err != nil {
+		logrus.Debugf("Ignoring an invalid output cache: %v", err)
+		return cached, false
+	}
+	return cached, true
+}
+
+// FinishOutputCache saves the tables printed by a successful command, or
+// prints the cached ones with --cached when the server is unreachable. It
+// returns the error of the command, nil when the cached output was printed.
+func FinishOutputCache(err error) error {
+	return listingCache.finish(os.Stdout, err)
+}
+
+// serverUnreachable reports whether err means the server couldn't be reached,
+// as opposed to the server rejecting the request.
+func serverUnreachable(err error) bool {
+	var apiErr *clientbase.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	// covers the errors of connections, DNS and timeouts
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rancher/norman/clientbase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputCache(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	unreachable := &url.Error{Op: "Get", URL: "https://rancher/v3", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}
+
+	cache := &outputCache{now: func() time.Time { return now }}
+	cache.configure(dir, "https://rancher c-1:p-1", []string{"cluster", "ls"}, true, false)
+	_, err := cache.recorder().Write([]byte("NAME\nlocal\n"))
+	require.NoError(t, err)
+	require.NoError(t, cache.finish(&bytes.Buffer{}, nil))
+
+	// without --cached the error is returned
+	cache.configure(dir, "https://rancher c-1:p-1", []string{"cluster", "ls"}, true, false)
+	assert.Equal(t, unreachable, cache.finish(&bytes.Buffer{}, unreachable))
+
+	now = now.Add(time.Hour)
+	var out bytes.Buffer
+	cache.configure(dir, "https://rancher c-1:p-1", []string{"cluster", "ls"}, true, true)
+	require.NoError(t, cache.finish(&out, unreachable))
+	assert.Equal(t, "NAME\nlocal\n", out.String())
+
+	// errors of the server aren't hidden
+	forbidden := &clientbase.APIError{StatusCode: 403}
+	assert.Equal(t, forbidden, cache.finish(&bytes.Buffer{}, forbidden))
+
+	// other commands and servers have their own output
+	cache.configure(dir, "https://rancher c-1:p-1", []string{"project", "ls"}, true, true)
+	assert.ErrorIs(t, cache.finish(&bytes.Buffer{}, unreachable), unreachable)
+	cache.configure(dir, "https://other c-1:p-1", []string{"cluster", "ls"}, true, true)
+	assert.ErrorIs(t, cache.finish(&bytes.Buffer{}, unreachable), unreachable)
+
+	// the output of commands that change something is neither recorded nor replayed
+	cache.configure(dir, "https://rancher c-1:p-1", []string{"cluster", "ls"}, false, true)
+	assert.Nil(t, cache.recorder())
+	assert.ErrorIs(t, cache.finish(&bytes.Buffer{}, unreachable), unreachable)
+
+	// a disabled cache neither records nor replays
+	cache.configure(dir, "https://rancher c-1:p-1", []string{"cluster", "ls"}, true, true)
+	cache.disable()
+	assert.Nil(t, cache.recorder())
+	assert.ErrorIs(t, cache.finish(&bytes.Buffer{}, unreachable), unreachable)
+}
+
+func TestServerUnreachable(t *testing.T) {
+	assert.True(t, serverUnreachable(&url.Error{Op: "Get", URL: "https://rancher/v3", Err: errors.New("EOF")}))
+	assert.True(t, serverUnreachable(&clientbase.APIError{StatusCode: 503}))
+	assert.False(t, serverUnreachable(&clientbase.APIError{StatusCode: 401}))
+	assert.False(t, serverUnreachable(errors.New("boom")))
+}
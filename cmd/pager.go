@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+	"golang.org/x/term"
+)
+
+// defaultPager is the pager used when $PAGER isn't set, Windows has no less
+func defaultPager() string {
+	if runtime.GOOS == "windows" {
+		return "more"
+	}
+	return "less -R"
+}
+
+// pagerEnabled reports whether long output should go through a pager, which
+// is the case when stdout is a terminal unless --no-pager is set.
+func pagerEnabled(ctx *cli.Context) bool {
+	if ctx.GlobalBool("no-pager") {
+		return false
+	}
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// page writes content to out directly when it fits on the terminal, and
+// through $PAGER otherwise.
+func page(content []byte, out io.Writer) error {
+	_, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || bytes.Count(content, []byte("\n")) < height {
+		_, err := out.Write(content)
+		return err
+	}
+
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = defaultPager()
+	}
+	return runPager(pager, content, out)
+}
+
+// runPager writes content to out through the pager command, which is run by
+// the shell like git does with $PAGER. The content is written directly when
+// the pager can't be run.
+func runPager(pager string, content []byte, out io.Writer) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", pager)
+	} else {
+		cmd = exec.Command("sh", "-c", pager)
+	}
+	cmd.Stdin = bytes.NewReader(content)
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		logrus.Debugf("pager %q failed: %v", pager, err)
+		if _, ok := err.(*exec.ExitError); ok {
+			// the pager ran, e.g. the user quit less before the end
+			return nil
+		}
+		_, err := out.Write(content)
+		return err
+	}
+	return nil
+}
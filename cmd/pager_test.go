@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPageWithoutTerminal(t *testing.T) {
+	t.Setenv("PAGER", "false")
+
+	out := &bytes.Buffer{}
+	err := page([]byte("ID   NAME\nc-1  local\n"), out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ID   NAME\nc-1  local\n", out.String())
+}
+
+func TestRunPager(t *testing.T) {
+	// the pager command is run by the shell of the platform
+	pager := "tr a-z A-Z"
+	if runtime.GOOS == "windows" {
+		pager = "findstr c-1"
+	}
+
+	out := &bytes.Buffer{}
+	err := runPager(pager, []byte("id   name\nc-1  local\n"), out)
+
+	assert.NoError(t, err)
+	if runtime.GOOS == "windows" {
+		assert.Contains(t, out.String(), "c-1  local")
+		return
+	}
+	assert.Equal(t, "ID   NAME\nC-1  LOCAL\n", out.String())
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rancher/cli/cliclient"
+	"github.com/rancher/norman/types"
+	"github.com/urfave/cli"
+)
+
+// The verbs checked by --check-permissions, any other verb is the name of an
+// action.
+const (
+	verbCreate = "create"
+	verbUpdate = "update"
+	verbDelete = "delete"
+)
+
+// permission is a verb a command needs on a type of one of the APIs. The
+// schemas of the APIs only list the methods the current token is allowed.
+type permission struct {
+	api        string
+	schemas    map[string]types.Schema
+	schemaType string
+	verb       string
+}
+
+func managementPermission(c *cliclient.MasterClient, schemaType, verb string) permission {
+	return newPermission("management", c.ManagementClient.APIBaseClient.Types, schemaType, verb)
+}
+
+func clusterPermission(c *cliclient.MasterClient, schemaType, verb string) permission {
+	if c.ClusterClient == nil {
+		return permission{api: "cluster", schemaType: schemaType, verb: verb}
+	}
+	return newPermission("cluster", c.ClusterClient.APIBaseClient.Types, schemaType, verb)
+}
+
+func projectPermission(c *cliclient.MasterClient, schemaType, verb string) permission {
+	if c.ProjectClient == nil {
+		return permission{api: "project", schemaType: schemaType, verb: verb}
+	}
+	return newPermission("project", c.ProjectClient.APIBaseClient.Types, schemaType, verb)
+}
+
+func newPermission(api string, schemas map[string]types.Schema, schemaType, verb string) permission {
+	return permission{api: api, schemas: schemas, schemaType: schemaType, verb: verb}
+}
+
+func (p permission) allowed() bool {
+	schema, ok := p.schemas[p.schemaType]
+	if !ok {
+		return false
+	}
+	switch p.verb {
+	case verbCreate:
+		return slices.Contains(schema.CollectionMethods, "POST")
+	case verbUpdate:
+		return slices.Contains(schema.ResourceMethods, "PUT")
+	case verbDelete:
+		return slices.Contains(schema.ResourceMethods, "DELETE")
+	}
+	_, ok = schema.ResourceActions[p.verb]
+	return ok
+}
+
+func (p permission) String() string {
+	if _, ok := p.schemas[p.schemaType]; !ok {
+		return fmt.Sprintf("%s %s in the %s API: the type isn't accessible", p.verb, p.schemaType, p.api)
+	}
+	return fmt.Sprintf("%s %s in the %s API", p.verb, p.schemaType, p.api)
+}
+
+// checkPermissions fails listing the permissions the current token is
+// missing, before a command changes anything, when --check-permissions is
+// set.
+func checkPermissions(ctx *cli.Context, permissions ...permission) error {
+	if !ctx.GlobalBool("check-permissions") {
+		return nil
+	}
+	if missing := missingPermissions(permissions); len(missing) > 0 {
+		return permissionErrorf("the current token is missing permissions:\n  %s", strings.Join(missing, "\n  "))
+	}
+	return nil
+}
+
+func missingPermissions(permissions []permission) []string {
+	var missing []string
+	for _, p := range permissions {
+		if !p.allowed() && !slices.Contains(missing, p.String()) {
+			missing = append(missing, p.String())
+		}
+	}
+	return missing
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/rancher/norman/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMissingPermissions(t *testing.T) {
+	schemas := map[string]types.Schema{
+		"project": {
+			CollectionMethods: []string{"GET", "POST"},
+			ResourceMethods:   []string{"GET"},
+		},
+		"multiClusterApp": {
+			CollectionMethods: []string{"GET"},
+			ResourceMethods:   []string{"GET", "PUT", "DELETE"},
+			ResourceActions:   map[string]types.Action{"rollback": {}},
+		},
+	}
+
+	assert.Empty(t, missingPermissions([]permission{
+		newPermission("management", schemas, "project", verbCreate),
+		newPermission("management", schemas, "multiClusterApp", verbUpdate),
+		newPermission("management", schemas, "multiClusterApp", verbDelete),
+		newPermission("management", schemas, "multiClusterApp", "rollback"),
+	}))
+
+	assert.Equal(t, []string{
+		"update project in the management API",
+		"create multiClusterApp in the management API",
+		"addProjects multiClusterApp in the management API",
+		"create projectRoleTemplateBinding in the management API: the type isn't accessible",
+	}, missingPermissions([]permission{
+		newPermission("management", schemas, "project", verbUpdate),
+		newPermission("management", schemas, "multiClusterApp", verbCreate),
+		newPermission("management", schemas, "multiClusterApp", "addProjects"),
+		newPermission("management", schemas, "projectRoleTemplateBinding", verbCreate),
+		newPermission("management", schemas, "project", verbUpdate),
+	}))
+}
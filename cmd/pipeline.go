@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rancher/cli/cliclient"
+	"github.com/rancher/norman/types"
+	"github.com/urfave/cli"
+)
+
+const (
+	sourceCodeProviderConfigType = "sourceCodeProviderConfig"
+	sourceCodeRepositoryType     = "sourceCodeRepository"
+	pipelineType                 = "pipeline"
+)
+
+const pipelineDescription = `
+Configures the pipelines of the current project without the UI: the source code provider whose
+repositories the pipelines build, and the repositories with a pipeline.
+
+Pipelines are part of Rancher v2.5 and older.
+`
+
+const pipelineProviderConfigureDescription = `
+Configures and enables the source code provider of the pipelines of the current project with the
+client ID and secret of an OAuth application of the provider. --hostname is the host of a GitHub
+Enterprise, self-hosted GitLab or Bitbucket Server instance.
+
+The users of the project then authorize their own account with the provider, before repositories
+of their account can be enabled.
+
+Example:
+	$ rancher pipeline provider configure github --client-id 0123abcd --client-secret $SECRET
+	$ RANCHER_PIPELINE_CLIENT_SECRET=$SECRET rancher pipeline provider configure gitlab --client-id 0123abcd --hostname gitlab.example.com
+`
+
+const pipelineRepoDescription = `
+Enables or disables the pipeline of a repository of the current project. The repository is given
+by its URL or by its OWNER/NAME path.
+
+Example:
+	$ rancher pipeline repo enable acme/api
+	$ rancher pipeline repo disable https://github.com/acme/api.git
+`
+
+// pipelineProviders are the types of the configuration of each source code
+// provider.
+var pipelineProviders = map[string]string{
+	"github":    "githubPipelineConfig",
+	"gitlab":    "gitlabPipelineConfig",
+	"bitbucket": "bitbucketCloudPipelineConfig",
+}
+
+type SourceCodeProviderConfig struct {
+	types.Resource
+	Name     string `json:"name,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Enabled  bool   `json:"enabled,omitempty"`
+	ClientID string `json:"clientId,omitempty"`
+	Hostname string `json:"hostname,omitempty"`
+}
+
+type SourceCodeProviderConfigCollection struct {
+	types.Collection
+	Data []SourceCodeProviderConfig `json:"data,omitempty"`
+}
+
+type SourceCodeRepository struct {
+	types.Resource
+	URL                    string `json:"url,omitempty"`
+	SourceCodeType         string `json:"sourceCodeType,omitempty"`
+	SourceCodeCredentialID string `json:"sourceCodeCredentialId,omitempty"`
+}
+
+type SourceCodeRepositoryCollection struct {
+	types.Collection
+	Data []SourceCodeRepository `json:"data,omitempty"`
+}
+
+type Pipeline struct {
+	types.Resource
+	Name                   string `json:"name,omitempty"`
+	ProjectID              string `json:"projectId,omitempty"`
+	RepositoryURL          string `json:"repositoryUrl,omitempty"`
+	SourceCodeCredentialID string `json:"sourceCodeCredentialId,omitempty"`
+	TriggerWebhookPush     bool   `json:"triggerWebhookPush,omitempty"`
+	TriggerWebhookPr       bool   `json:"triggerWebhookPr,omitempty"`
+	TriggerWebhookTag      bool   `json:"triggerWebhookTag,omitempty"`
+}
+
+type PipelineCollection struct {
+	types.Collection
+	Data []Pipeline `json:"data,omitempty"`
+}
+
+func PipelineCommand() cli.Command {
+	return cli.Command{
+		Name:        "pipeline",
+		Usage:       "Operations on the pipelines of the current project",
+		Description: pipelineDescription,
+		Subcommands: []cli.Command{
+			{
+				Name:  "provider",
+				Usage: "Operations on the source code provider of the pipelines",
+				Subcommands: []cli.Command{
+					{
+						Name:        "configure",
+						Usage:       "Configure and enable a source code provider",
+						Description: pipelineProviderConfigureDescription,
+						ArgsUsage:   "[github|gitlab|bitbucket]",
+						Action:      pipelineProviderConfigure,
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:  "client-id",
+								Usage: "Client ID of the OAuth application",
+							},
+							cli.StringFlag{
+								Name:   "client-secret",
+								Usage:  "Client secret of the OAuth application",
+								EnvVar: "RANCHER_PIPELINE_CLIENT_SECRET",
+							},
+							cli.StringFlag{
+								Name:  "hostname",
+								Usage: "Host of a self-hosted instance of the provider",
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:  "repo",
+				Usage: "Enable or disable the pipelines of repositories",
+				Subcommands: []cli.Command{
+					{
+						Name:        "enable",
+						Usage:       "Enable the pipeline of a repository",
+						Description: pipelineRepoDescription,
+						ArgsUsage:   "[REPOSITORY_URL OWNER/NAME]",
+						Action:      pipelineRepoEnable,
+					},
+					{
+						Name:        "disable",
+						Usage:       "Disable the pipeline of a repository",
+						Description: pipelineRepoDescription,
+						ArgsUsage:   "[REPOSITORY_URL OWNER/NAME]",
+						Action:      pipelineRepoDisable,
+					},
+				},
+			},
+		},
+	}
+}
+
+// pipelineClient returns a client of the current project, failing when the
+// server has no pipelines.
+func pipelineClient(ctx *cli.Context) (*cliclient.MasterClient, error) {
+	c, err := GetClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := c.ProjectClient.APIBaseClient.Types[sourceCodeProviderConfigType]; !ok {
+		return nil, errors.New("the Rancher server has no pipelines, they are only available in Rancher v2.5 and older")
+	}
+	return c, nil
+}
+
+func pipelineProviderConfigure(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+	provider := ctx.Args().First()
+	configType, ok := pipelineProviders[provider]
+	if !ok {
+		var names []string
+		for name := range pipelineProviders {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return NewUsageError(fmt.Errorf("invalid provider %q, expected one of %s", provider, strings.Join(names, ", ")))
+	}
+	if ctx.String("client-id") == "" || ctx.String("client-secret") == "" {
+		return NewUsageError(errors.New("--client-id and --client-secret are required"))
+	}
+
+	c, err := pipelineClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	configs := &SourceCodeProviderConfigCollection{}
+	if err := c.ProjectClient.APIBaseClient.List(sourceCodeProviderConfigType, defaultListOpts(nil), configs); err != nil {
+		return err
+	}
+	var config *SourceCodeProviderConfig
+	for i := range configs.Data {
+		if configs.Data[i].Type == configType {
+			config = &configs.Data[i]
+			break
+		}
+	}
+	if config == nil {
+		return fmt.Errorf("the pipelines of the current project have no %s provider", provider)
+	}
+
+	updates := map[string]interface{}{
+		"enabled":      true,
+		"clientId":     ctx.String("client-id"),
+		"clientSecret": ctx.String("client-secret"),
+	}
+	if hostname := ctx.String("hostname"); hostname != "" {
+		updates["hostname"] = hostname
+		updates["tls"] = true
+	}
+	if err := c.ProjectClient.APIBaseClient.Update(configType, &config.Resource, updates, nil); err != nil {
+		return err
+	}
+	fmt.Printf("Enabled the %s provider of the pipelines of the current project\n", provider)
+	return nil
+}
+
+func pipelineRepoEnable(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+	c, err := pipelineClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	repos := &SourceCodeRepositoryCollection{}
+	if err := c.ProjectClient.APIBaseClient.List(sourceCodeRepositoryType, defaultListOpts(nil), repos); err != nil {
+		return err
+	}
+	repo, err := matchRepository(repos.Data, ctx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	pipeline, err := findPipeline(c, repo.URL)
+	if err != nil {
+		return err
+	}
+	if pipeline != nil {
+		fmt.Printf("The pipeline of %s is already enabled\n", repo.URL)
+		return nil
+	}
+
+	pipeline = &Pipeline{
+		ProjectID:              c.UserConfig.Project,
+		RepositoryURL:          repo.URL,
+		SourceCodeCredentialID: repo.SourceCodeCredentialID,
+		TriggerWebhookPush:     true,
+		TriggerWebhookPr:       true,
+		TriggerWebhookTag:      true,
+	}
+	if err := c.ProjectClient.APIBaseClient.Create(pipelineType, pipeline, nil); err != nil {
+		return err
+	}
+	fmt.Printf("Enabled the pipeline of %s\n", repo.URL)
+	return nil
+}
+
+func pipelineRepoDisable(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+	c, err := pipelineClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	pipelines := &PipelineCollection{}
+	if err := c.ProjectClient.APIBaseClient.List(pipelineType, defaultListOpts(nil), pipelines); err != nil {
+		return err
+	}
+	var repos []SourceCodeRepository
+	for _, pipeline := range pipelines.Data {
+		repos = append(repos, SourceCodeRepository{URL: pipeline.RepositoryURL})
+	}
+	repo, err := matchRepository(repos, ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("no pipeline is enabled for %s", ctx.Args().First())
+	}
+
+	for _, pipeline := range pipelines.Data {
+		if pipeline.RepositoryURL == repo.URL {
+			if err := c.ProjectClient.APIBaseClient.Delete(&pipeline.Resource); err != nil {
+				return err
+			}
+		}
+	}
+	fmt.Printf("Disabled the pipeline of %s\n", repo.URL)
+	return nil
+}
+
+// findPipeline returns the pipeline of the repository with url, nil when it
+// has none.
+func findPipeline(c *cliclient.MasterClient, url string) (*Pipeline, error) {
+	opts := defaultListOpts(nil)
+	opts.Filters["repositoryUrl"] = url
+	pipelines := &PipelineCollection{}
+	if err := c.ProjectClient.APIBaseClient.List(pipelineType, opts, pipelines); err != nil {
+		return nil, err
+	}
+	for i := range pipelines.Data {
+		if pipelines.Data[i].RepositoryURL == url {
+			return &pipelines.Data[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// matchRepository returns the repository whose URL is name, or whose path
+// is name when name is an OWNER/NAME path.
+func matchRepository(repos []SourceCodeRepository, name string) (*SourceCodeRepository, error) {
+	path := "/" + strings.Trim(strings.TrimSuffix(name, ".git"), "/")
+	var matches []*SourceCodeRepository
+	for i, repo := range repos {
+		if repo.URL == name {
+			return &repos[i], nil
+		}
+		if strings.HasSuffix(strings.TrimSuffix(repo.URL, ".git"), path) {
+			matches = append(matches, &repos[i])
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("repository %s not found, the users of the project must authorize the provider to see their repositories", name)
+	case 1:
+		return matches[0], nil
+	}
+	var urls []string
+	for _, repo := range matches {
+		urls = append(urls, repo.URL)
+	}
+	return nil, fmt.Errorf("%s matches several repositories, use the URL of one of %s", name, strings.Join(urls, ", "))
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchRepository(t *testing.T) {
+	repos := []SourceCodeRepository{
+		{URL: "https://github.com/acme/api.git"},
+		{URL: "https://github.com/acme/web.git"},
+		{URL: "https://github.com/other/api.git"},
+	}
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr string
+	}{
+		{name: "https://github.com/acme/web.git", want: "https://github.com/acme/web.git"},
+		{name: "acme/api", want: "https://github.com/acme/api.git"},
+		{name: "other/api.git", want: "https://github.com/other/api.git"},
+		{name: "api", wantErr: "api matches several repositories, use the URL of one of https://github.com/acme/api.git, https://github.com/other/api.git"},
+		{name: "acme/cli", wantErr: "repository acme/cli not found, the users of the project must authorize the provider to see their repositories"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := matchRepository(repos, tt.name)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, repo.URL)
+		})
+	}
+}
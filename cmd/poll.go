@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+const (
+	pollInitialInterval = time.Second
+	pollMaxInterval     = 15 * time.Second
+	pollFactor          = 1.5
+)
+
+// backoff computes poll intervals that grow exponentially up to max. Half of
+// each interval is random so that many clients waiting on the same server
+// don't poll in lockstep.
+type backoff struct {
+	initial time.Duration
+	max     time.Duration
+	factor  float64
+	current time.Duration
+	rand    func() float64
+}
+
+func newBackoff(initial, max time.Duration) *backoff {
+	return &backoff{
+		initial: initial,
+		max:     max,
+		factor:  pollFactor,
+		rand:    rand.Float64,
+	}
+}
+
+// Next returns how long to wait before the next attempt.
+func (b *backoff) Next() time.Duration {
+	if b.current == 0 {
+		b.current = b.initial
+	} else {
+		b.current = time.Duration(float64(b.current) * b.factor)
+	}
+	if b.current > b.max {
+		b.current = b.max
+	}
+	half := b.current / 2
+	return half + time.Duration(b.rand()*float64(half))
+}
+
+// waitContext returns a context canceled when the command is interrupted
+// with Ctrl-C and, if timeout is positive, once the timeout has passed.
+// Ctrl-C is handled until the context is canceled.
+func waitContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	stop := commandInterrupt.watch()
+	ctx, cancel := context.WithCancel(commandInterrupt.context())
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(commandInterrupt.context(), timeout)
+	}
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// pollUntil calls check until it's done or fails, waiting between the calls
+// according to b. It returns the context's error if the context ends first,
+// context.DeadlineExceeded on timeout and context.Canceled on Ctrl-C.
+func pollUntil(ctx context.Context, b *backoff, check func() (bool, error)) error {
+	for {
+		done, err := check()
+		if err != nil || done {
+			return err
+		}
+
+		timer := time.NewTimer(b.Next())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
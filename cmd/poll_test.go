@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	b := newBackoff(time.Second, 3*time.Second)
+	b.rand = func() float64 { return 1 }
+
+	var intervals []time.Duration
+	for i := 0; i < 5; i++ {
+		intervals = append(intervals, b.Next())
+	}
+
+	assert.Equal(t, []time.Duration{
+		time.Second,
+		1500 * time.Millisecond,
+		2250 * time.Millisecond,
+		3 * time.Second,
+		3 * time.Second,
+	}, intervals)
+
+	b = newBackoff(time.Second, 3*time.Second)
+	b.rand = func() float64 { return 0 }
+	assert.Equal(t, 500*time.Millisecond, b.Next(), "jitter never shortens an interval below half")
+}
+
+func TestPollUntil(t *testing.T) {
+	fast := func() *backoff { return newBackoff(time.Millisecond, time.Millisecond) }
+
+	calls := 0
+	err := pollUntil(context.Background(), fast(), func() (bool, error) {
+		calls++
+		return calls == 3, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	failure := errors.New("failed")
+	err = pollUntil(context.Background(), fast(), func() (bool, error) {
+		return false, failure
+	})
+	assert.Equal(t, failure, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = pollUntil(ctx, fast(), func() (bool, error) {
+		return false, nil
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
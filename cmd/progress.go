@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/term"
+)
+
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// progressLogInterval is how often an unchanged status is logged when the
+// output is not a terminal.
+const progressLogInterval = 10 * time.Second
+
+// progress reports on a long running wait. On a terminal it redraws a single
+// spinner line with the elapsed time and the current message, otherwise it
+// logs a line whenever the message changes and periodically while waiting.
+// Step changes, such as the state of each target of a multi-cluster app, are
+// always printed on their own line.
+type progress struct {
+	out     io.Writer
+	tty     bool
+	title   string
+	start   time.Time
+	frame   int
+	message string
+	logged  time.Time
+	steps   map[string]string
+	now     func() time.Time
+}
+
+func newProgress(title string) *progress {
+	return &progress{
+		out:   os.Stderr,
+		tty:   term.IsTerminal(int(os.Stderr.Fd())),
+		title: title,
+		start: time.Now(),
+		steps: map[string]string{},
+		now:   time.Now,
+	}
+}
+
+// Update reports the current message and the state of each step.
+func (p *progress) Update(message string, steps map[string]string) {
+	elapsed := p.now().Sub(p.start).Round(time.Second)
+
+	var changed []string
+	for name, state := range steps {
+		if p.steps[name] != state {
+			p.steps[name] = state
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+
+	if p.tty {
+		p.clearLine()
+		for _, name := range changed {
+			fmt.Fprintf(p.out, "  %s: %s\n", name, steps[name])
+		}
+		p.frame = (p.frame + 1) % len(spinnerFrames)
+		fmt.Fprintf(p.out, "%s %s (%s)", spinnerFrames[p.frame], p.title, elapsed)
+		if message != "" {
+			fmt.Fprintf(p.out, ": %s", firstLine(message))
+		}
+		return
+	}
+
+	for _, name := range changed {
+		logrus.Infof("%s: %s: %s", p.title, name, steps[name])
+	}
+	if message != p.message || p.now().Sub(p.logged) >= progressLogInterval {
+		p.logged = p.now()
+		if message != "" {
+			logrus.Infof("%s (%s): %s", p.title, elapsed, message)
+		} else {
+			logrus.Infof("%s (%s)", p.title, elapsed)
+		}
+	}
+	p.message = message
+}
+
+// Done finishes the progress output with a final message.
+func (p *progress) Done(message string) {
+	elapsed := p.now().Sub(p.start).Round(time.Second)
+	if p.tty {
+		p.clearLine()
+		fmt.Fprintf(p.out, "%s (%s)\n", message, elapsed)
+		return
+	}
+	logrus.Infof("%s (%s)", message, elapsed)
+}
+
+func (p *progress) clearLine() {
+	fmt.Fprint(p.out, "\r\x1b[K")
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
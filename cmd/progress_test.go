@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressTerminalOutput(t *testing.T) {
+	out := &bytes.Buffer{}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	p := &progress{
+		out:   out,
+		tty:   true,
+		title: "Waiting for app a-1",
+		start: start,
+		steps: map[string]string{},
+		now:   func() time.Time { return now },
+	}
+
+	now = start.Add(3 * time.Second)
+	p.Update("installing\nmore details", map[string]string{"c-1:p-1": "installing"})
+	assert.Equal(t, "\r\x1b[K  c-1:p-1: installing\n/ Waiting for app a-1 (3s): installing", out.String())
+
+	out.Reset()
+	now = start.Add(4 * time.Second)
+	p.Update("installing", map[string]string{"c-1:p-1": "installing"})
+	assert.Equal(t, "\r\x1b[K- Waiting for app a-1 (4s): installing", out.String())
+
+	out.Reset()
+	p.Done("app a-1 is active")
+	assert.Equal(t, "\r\x1b[Kapp a-1 is active (4s)\n", out.String())
+}
+
+func TestWaitSteps(t *testing.T) {
+	steps := waitSteps(map[string]interface{}{
+		"targets": []interface{}{
+			map[string]interface{}{"projectId": "c-1:p-1", "state": "active"},
+			map[string]interface{}{"projectId": "c-2:p-2"},
+		},
+	})
+	assert.Equal(t, map[string]string{"c-1:p-1": "active", "c-2:p-2": "pending"}, steps)
+	assert.Empty(t, waitSteps(map[string]interface{}{"state": "active"}))
+}
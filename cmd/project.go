@@ -32,15 +32,25 @@ func ProjectCommand() cli.Command {
 						Usage: "'json', 'yaml' or Custom format: '{{.Project.ID}} {{.Project.Name}}'",
 					},
 					quietFlag,
+					filterFlag,
+					labelSelectorFlag,
+					sortByFlag,
+					noHeadersFlag,
+					limitFlag,
+					pageSizeFlag,
 				},
 			},
 			{
 				Name:        "create",
 				Usage:       "Create a project",
-				Description: "\nCreates a project in the current cluster.",
+				Description: projectCreateDescription,
 				ArgsUsage:   "[NEWPROJECTNAME...]",
 				Action:      projectCreate,
 				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "file,f",
+						Usage: "Create the project from a YAML spec",
+					},
 					cli.StringFlag{
 						Name:  "cluster",
 						Usage: "Cluster ID to create the project in",
@@ -57,6 +67,34 @@ func ProjectCommand() cli.Command {
 				Usage:     "Delete a project by ID",
 				ArgsUsage: "[PROJECTID PROJECTNAME]",
 				Action:    projectDelete,
+				Flags:     deleteFlags,
+			},
+			{
+				Name:        "move-app",
+				Usage:       "Move an app of the current project to another project",
+				Description: projectMoveAppDescription,
+				ArgsUsage:   "[APP_NAME/APP_ID] [PROJECTNAME/PROJECTID]",
+				Action:      projectMoveApp,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "namespace,n",
+						Usage: "Namespace to install the copy of the app in",
+					},
+					cli.StringFlag{
+						Name:  "name",
+						Usage: "Name of the copy of the app, defaults to the name of the app",
+					},
+					cli.BoolFlag{
+						Name:  "delete",
+						Usage: "Delete the app once its copy is active",
+					},
+					cli.IntFlag{
+						Name:  "timeout",
+						Usage: "Time in seconds to wait for the copy to be active with --delete",
+						Value: 600,
+					},
+					forceFlag,
+				},
 			},
 			{
 				Name:        "add-member-role",
@@ -84,6 +122,8 @@ func ProjectCommand() cli.Command {
 					},
 				},
 			},
+			ProjectMonitoringCommand(),
+			ProjectSnapshotCommand(),
 			{
 				Name:   "list-roles",
 				Usage:  "List all available roles for a project",
@@ -105,12 +145,12 @@ func ProjectCommand() cli.Command {
 }
 
 func projectLs(ctx *cli.Context) error {
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	collection, err := getProjectList(ctx, c)
+	collection, err := getProjectPage(ctx, c)
 	if err != nil {
 		return err
 	}
@@ -124,26 +164,42 @@ func projectLs(ctx *cli.Context) error {
 
 	defer writer.Close()
 
-	for _, item := range collection.Data {
+	err = forEachPage(ctx, collection, projectPageData, func(item managementClient.Project) error {
 		writer.Write(&ProjectData{
 			ID:      item.ID,
 			Project: item,
 		})
+		return nil
+	}, writer.Flush)
+	if err != nil {
+		return err
 	}
 
 	return writer.Err()
 }
 
 func projectCreate(ctx *cli.Context) error {
-	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+	if ctx.NArg() == 0 && ctx.String("file") == "" {
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	var spec *ProjectSpec
+	if ctx.String("file") != "" {
+		var err error
+		if spec, err = readProjectSpec(ctx.String("file")); err != nil {
+			return err
+		}
+	}
+
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
 
+	if spec != nil {
+		return projectCreateFromSpec(ctx, c, spec)
+	}
+
 	clusterID := c.UserConfig.FocusedCluster()
 	if ctx.String("cluster") != "" {
 		resource, err := Lookup(c, ctx.String("cluster"), "cluster")
@@ -168,44 +224,47 @@ func projectCreate(ctx *cli.Context) error {
 
 func projectDelete(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	for _, arg := range ctx.Args() {
-		resource, err := Lookup(c, arg, "project")
-		if err != nil {
-			return err
-		}
-
-		project, err := getProjectByID(c, resource.ID)
-		if err != nil {
-			return err
-		}
-
-		err = c.ManagementClient.Project.Delete(project)
-		if err != nil {
-			return err
-		}
-	}
+	return bulkDelete{
+		kind: "projects",
+		resolve: func(arg string) (*bulkTarget, error) {
+			resource, err := Lookup(c, arg, "project")
+			if err != nil {
+				return nil, err
+			}
 
-	return nil
+			project, err := getProjectByID(c, resource.ID)
+			if err != nil {
+				return nil, err
+			}
+			return &bulkTarget{
+				resource:     project.Resource,
+				descriptions: []string{fmt.Sprintf("%s (%s)", project.Name, project.ID)},
+				delete: func() error {
+					return c.ManagementClient.Project.Delete(project)
+				},
+			}, nil
+		},
+	}.run(ctx, c)
 }
 
 func addProjectMemberRoles(ctx *cli.Context) error {
 	if len(ctx.Args()) < 2 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
 	memberName := ctx.Args().First()
 
 	roles := ctx.Args()[1:]
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -240,14 +299,14 @@ func addProjectMemberRoles(ctx *cli.Context) error {
 
 func deleteProjectMemberRoles(ctx *cli.Context) error {
 	if len(ctx.Args()) < 2 {
-		return cli.ShowSubcommandHelp(ctx)
+		return showSubcommandUsage(ctx)
 	}
 
 	memberName := ctx.Args().First()
 
 	roles := ctx.Args()[1:]
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -293,7 +352,7 @@ func listProjectRoles(ctx *cli.Context) error {
 }
 
 func listProjectMembers(ctx *cli.Context) error {
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -341,16 +400,33 @@ func getProjectList(
 	ctx *cli.Context,
 	c *cliclient.MasterClient,
 ) (*managementClient.ProjectCollection, error) {
-	filter := defaultListOpts(ctx)
-	filter.Filters["clusterId"] = c.UserConfig.FocusedCluster()
-
-	collection, err := c.ManagementClient.Project.List(filter)
+	collection, err := getProjectPage(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	collection.Data, err = listAll(ctx, collection, projectPageData)
 	if err != nil {
 		return nil, err
 	}
 	return collection, nil
 }
 
+// getProjectPage returns the first page of the projects in the current
+// cluster, use forEachPage to go through all of them.
+func getProjectPage(
+	ctx *cli.Context,
+	c *cliclient.MasterClient,
+) (*managementClient.ProjectCollection, error) {
+	filter := filteredListOpts(ctx)
+	filter.Filters["clusterId"] = c.UserConfig.FocusedCluster()
+
+	return c.ManagementClient.Project.List(filter)
+}
+
+func projectPageData(c *managementClient.ProjectCollection) []managementClient.Project {
+	return c.Data
+}
+
 func getProjectByID(
 	c *cliclient.MasterClient,
 	projectID string,
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rancher/cli/cliclient"
+	projectClient "github.com/rancher/rancher/pkg/client/generated/project/v3"
+	"github.com/urfave/cli"
+)
+
+const projectMoveAppDescription = `
+Moves an app of the current project to another project. Apps can't change project in place, so
+a copy of the app is installed in the target project with the same chart version, answers and
+values. The copy is a new install: its workloads and volumes start anew.
+
+In the same cluster the copy must be installed in another namespace than the app, given with
+--namespace. In another cluster the namespace of the app is used by default.
+
+With --delete the app is deleted once its copy is active, otherwise it is kept so that the copy
+can be checked first.
+
+Example:
+	# Copy wordpress to the project team-b, then delete it once the copy is active
+	$ rancher project move-app --namespace wordpress-b --delete wordpress team-b
+`
+
+func projectMoveApp(ctx *cli.Context) error {
+	if ctx.NArg() < 2 {
+		return showSubcommandUsage(ctx)
+	}
+	appName, projectName := ctx.Args().First(), ctx.Args().Get(1)
+
+	c, err := GetClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	resource, err := Lookup(c, appName, "app")
+	if err != nil {
+		return err
+	}
+	app, err := c.ProjectClient.App.ByID(resource.ID)
+	if err != nil {
+		return err
+	}
+	if app.MultiClusterAppID != "" {
+		return fmt.Errorf("app %s is deployed by a multi-cluster app, change the targets of the multi-cluster app instead", app.Name)
+	}
+
+	project, err := Lookup(c, projectName, "project")
+	if err != nil {
+		return err
+	}
+	if project.ID == c.UserConfig.Project {
+		return NewUsageError(fmt.Errorf("app %s is already in project %s", app.Name, projectName))
+	}
+
+	moved, err := movedApp(app, c.UserConfig.FocusedCluster(), project.ID, ctx.String("name"), ctx.String("namespace"))
+	if err != nil {
+		return err
+	}
+
+	// the copy is installed through the clients of the target project
+	sc := *c.UserConfig
+	sc.Project = project.ID
+	tc, err := cliclient.NewMasterClient(&sc)
+	if err != nil {
+		return err
+	}
+	tc.DryRun = c.DryRun
+
+	if _, err := createNamespace(tc, moved.TargetNamespace); err != nil {
+		return err
+	}
+	madeApp, err := tc.ProjectClient.App.Create(moved)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Installed %s in namespace %s of project %s with the chart, answers and values of %s\n",
+		madeApp.Name, moved.TargetNamespace, projectName, app.Name)
+
+	if !ctx.Bool("delete") {
+		fmt.Printf("Check the copy, then delete the app with \"rancher app delete %s\"\n", app.Name)
+		return nil
+	}
+
+	// the app is only deleted once its copy is running
+	timeout := time.Duration(ctx.Int("timeout")) * time.Second
+	if err := waitForResource(tc, &madeApp.Resource, timeout); err != nil {
+		return fmt.Errorf("app %s was kept as its copy isn't active: %w", app.Name, err)
+	}
+
+	return bulkDelete{
+		kind: "apps",
+		args: []string{app.ID},
+		resolve: func(string) (*bulkTarget, error) {
+			return &bulkTarget{
+				resource:     app.Resource,
+				descriptions: []string{fmt.Sprintf("%s (%s) in namespace %s", app.Name, app.ID, app.TargetNamespace)},
+				delete: func() error {
+					return c.ProjectClient.App.Delete(app)
+				},
+			}, nil
+		},
+	}.run(ctx, c)
+}
+
+// movedApp returns the copy of app installed in the project projectID. The
+// copy keeps the name and the namespace of the app unless given, the
+// namespace must differ in the same cluster.
+func movedApp(app *projectClient.App, clusterID, projectID, name, namespace string) (*projectClient.App, error) {
+	sameCluster := SplitOnColon(projectID)[0] == clusterID
+	if namespace == "" && !sameCluster {
+		namespace = app.TargetNamespace
+	}
+	if namespace == "" || (sameCluster && namespace == app.TargetNamespace) {
+		return nil, NewUsageError(fmt.Errorf("the copy of app %s must be installed in another namespace than %s of the same cluster, use --namespace",
+			app.Name, app.TargetNamespace))
+	}
+
+	return &projectClient.App{
+		Name:             valueOrDefault(name, app.Name),
+		ProjectID:        projectID,
+		TargetNamespace:  namespace,
+		ExternalID:       app.ExternalID,
+		Files:            app.Files,
+		Answers:          app.Answers,
+		AnswersSetString: app.AnswersSetString,
+		ValuesYaml:       app.ValuesYaml,
+		Wait:             app.Wait,
+		Timeout:          app.Timeout,
+	}, nil
+}
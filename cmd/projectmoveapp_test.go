@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"testing"
+
+	projectClient "github.com/rancher/rancher/pkg/client/generated/project/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovedApp(t *testing.T) {
+	app := &projectClient.App{
+		Name:            "wordpress",
+		ProjectID:       "c-1:p-1",
+		TargetNamespace: "wordpress",
+		ExternalID:      "catalog://?catalog=library&template=wordpress&version=10.0.1",
+		Answers:         map[string]string{"replicas": "2"},
+		ValuesYaml:      "image: wordpress\n",
+		Timeout:         300,
+	}
+
+	tests := []struct {
+		name      string
+		projectID string
+		appName   string
+		namespace string
+		want      string
+		wantName  string
+		wantErr   bool
+	}{
+		{name: "other cluster keeps the namespace", projectID: "c-2:p-2", want: "wordpress", wantName: "wordpress"},
+		{name: "same cluster needs a namespace", projectID: "c-1:p-3", wantErr: true},
+		{name: "same cluster same namespace", projectID: "c-1:p-3", namespace: "wordpress", wantErr: true},
+		{name: "same cluster other namespace", projectID: "c-1:p-3", namespace: "wordpress-b", appName: "blog", want: "wordpress-b", wantName: "blog"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moved, err := movedApp(app, "c-1", tt.projectID, tt.appName, tt.namespace)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, moved.Name)
+			assert.Equal(t, tt.want, moved.TargetNamespace)
+			assert.Equal(t, tt.projectID, moved.ProjectID)
+			assert.Equal(t, app.ExternalID, moved.ExternalID)
+			assert.Equal(t, app.Answers, moved.Answers)
+			assert.Equal(t, app.ValuesYaml, moved.ValuesYaml)
+			assert.Equal(t, app.Timeout, moved.Timeout)
+		})
+	}
+}
@@ -0,0 +1,396 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/rancher/cli/cliclient"
+	clusterClient "github.com/rancher/rancher/pkg/client/generated/cluster/v3"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	projectClient "github.com/rancher/rancher/pkg/client/generated/project/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+const projectSnapshotDescription = `
+Records the workloads, apps, secrets and resource quotas of a project in a file, and compares
+two such files, to find what changed in a project between release windows.
+
+Secrets are recorded by their keys and a digest of their data, their values aren't saved.
+
+Example:
+	$ rancher project snapshot save --file before.json
+	$ rancher project snapshot save --file after.json
+
+	# What changed between the two
+	$ rancher project snapshot compare before.json after.json
+
+	# What changed since the snapshot was saved
+	$ rancher project snapshot compare before.json
+`
+
+// ProjectSnapshot is the state of the resources of a project at a time
+type ProjectSnapshot struct {
+	Server      string                      `json:"server"`
+	ProjectID   string                      `json:"projectId"`
+	ProjectName string                      `json:"projectName"`
+	Taken       time.Time                   `json:"taken"`
+	Workloads   map[string]SnapshotWorkload `json:"workloads"`
+	Apps        map[string]SnapshotApp      `json:"apps"`
+	Secrets     map[string]SnapshotSecret   `json:"secrets"`
+	Quotas      SnapshotQuotas              `json:"quotas"`
+}
+
+type SnapshotWorkload struct {
+	Type   string            `json:"type"`
+	Images map[string]string `json:"images"`
+	Scale  *int64            `json:"scale,omitempty"`
+}
+
+type SnapshotApp struct {
+	Catalog          string            `json:"catalog,omitempty"`
+	Template         string            `json:"template,omitempty"`
+	Version          string            `json:"version,omitempty"`
+	Answers          map[string]string `json:"answers,omitempty"`
+	AnswersSetString map[string]string `json:"answersSetString,omitempty"`
+}
+
+type SnapshotSecret struct {
+	Type   string   `json:"type,omitempty"`
+	Keys   []string `json:"keys,omitempty"`
+	Digest string   `json:"digest"`
+}
+
+type SnapshotQuotas struct {
+	Project          map[string]string            `json:"project,omitempty"`
+	NamespaceDefault map[string]string            `json:"namespaceDefault,omitempty"`
+	Namespaces       map[string]map[string]string `json:"namespaces,omitempty"`
+}
+
+func ProjectSnapshotCommand() cli.Command {
+	return cli.Command{
+		Name:        "snapshot",
+		Usage:       "Save and compare snapshots of the resources of a project",
+		Description: projectSnapshotDescription,
+		Subcommands: []cli.Command{
+			{
+				Name:        "save",
+				Usage:       "Save a snapshot of a project",
+				Description: projectSnapshotDescription,
+				ArgsUsage:   "[PROJECTNAME/PROJECTID]",
+				Action:      projectSnapshotSave,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "file,f",
+						Usage: "File to save the snapshot to, defaults to stdout",
+					},
+				},
+			},
+			{
+				Name:        "compare",
+				Usage:       "Compare two snapshots, or a snapshot with the project now",
+				Description: projectSnapshotDescription,
+				ArgsUsage:   "OLD_FILE [NEW_FILE]",
+				Action:      projectSnapshotCompare,
+			},
+		},
+	}
+}
+
+func projectSnapshotSave(ctx *cli.Context) error {
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	projectID := c.UserConfig.Project
+	if ctx.NArg() > 0 {
+		resource, err := Lookup(c, ctx.Args().First(), "project")
+		if err != nil {
+			return err
+		}
+		projectID = resource.ID
+	}
+	if projectID == "" {
+		return NewUsageError(errors.New("no current project, give the project to save a snapshot of"))
+	}
+	snapshot, err := takeProjectSnapshot(c, projectID)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	content = append(content, '\n')
+	path := ctx.String("file")
+	if path == "" {
+		_, err = os.Stdout.Write(content)
+		return err
+	}
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Saved the snapshot of project %s to %s\n", snapshot.ProjectName, path)
+	return nil
+}
+
+func projectSnapshotCompare(ctx *cli.Context) error {
+	if ctx.NArg() == 0 || ctx.NArg() > 2 {
+		return showSubcommandUsage(ctx)
+	}
+
+	old, err := readProjectSnapshot(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	var current *ProjectSnapshot
+	if ctx.NArg() == 2 {
+		if current, err = readProjectSnapshot(ctx.Args().Get(1)); err != nil {
+			return err
+		}
+	} else {
+		c, err := GetManagementClient(ctx)
+		if err != nil {
+			return err
+		}
+		if old.Server != "" && old.Server != c.UserConfig.URL {
+			logrus.Warnf("The snapshot was taken on server %s, comparing with project %s of %s", old.Server, old.ProjectID, c.UserConfig.URL)
+		}
+		if current, err = takeProjectSnapshot(c, old.ProjectID); err != nil {
+			return err
+		}
+	}
+	if old.ProjectID != current.ProjectID {
+		logrus.Warnf("Comparing snapshots of different projects: %s and %s", old.ProjectID, current.ProjectID)
+	}
+
+	fmt.Printf("Changes of project %s from %s to %s:\n", current.ProjectName,
+		old.Taken.Local().Format(time.DateTime), current.Taken.Local().Format(time.DateTime))
+	return printDiff(os.Stdout, compareProjectSnapshots(old, current))
+}
+
+func readProjectSnapshot(path string) (*ProjectSnapshot, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &ProjectSnapshot{}
+	if err := json.Unmarshal(content, snapshot); err != nil {
+		return nil, NewUsageError(fmt.Errorf("invalid snapshot %s: %w", path, err))
+	}
+	return snapshot, nil
+}
+
+// compareProjectSnapshots returns the changes of the resources from old to
+// current.
+func compareProjectSnapshots(old, current *ProjectSnapshot) []resourceDiff {
+	resources := func(snapshot *ProjectSnapshot) map[string]interface{} {
+		fields, _ := normalizeValue(map[string]interface{}{
+			"workloads": snapshot.Workloads,
+			"apps":      snapshot.Apps,
+			"secrets":   snapshot.Secrets,
+			"quotas":    snapshot.Quotas,
+		}).(map[string]interface{})
+		return fields
+	}
+	return diffMaps("", resources(old), resources(current), false)
+}
+
+func takeProjectSnapshot(c *cliclient.MasterClient, projectID string) (*ProjectSnapshot, error) {
+	project, err := getProjectByID(c, projectID)
+	if err != nil {
+		return nil, err
+	}
+	sc := *c.UserConfig
+	sc.Project = project.ID
+	pc, err := cliclient.NewProjectClient(&sc)
+	if err != nil {
+		return nil, err
+	}
+	cc, err := cliclient.NewClusterClient(&sc)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &ProjectSnapshot{
+		Server:      c.UserConfig.URL,
+		ProjectID:   project.ID,
+		ProjectName: project.Name,
+		Taken:       time.Now().UTC(),
+	}
+	if snapshot.Workloads, err = snapshotWorkloads(pc.ProjectClient); err != nil {
+		return nil, err
+	}
+	if snapshot.Apps, err = snapshotApps(pc.ProjectClient); err != nil {
+		return nil, err
+	}
+	if snapshot.Secrets, err = snapshotSecrets(pc.ProjectClient); err != nil {
+		return nil, err
+	}
+	if snapshot.Quotas, err = snapshotQuotas(cc.ClusterClient, project); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+func snapshotWorkloads(client *projectClient.Client) (map[string]SnapshotWorkload, error) {
+	collection, err := client.Workload.List(defaultListOpts(nil))
+	if err != nil {
+		return nil, err
+	}
+	workloads, err := listAll(nil, collection, func(c *projectClient.WorkloadCollection) []projectClient.Workload { return c.Data })
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := map[string]SnapshotWorkload{}
+	for _, workload := range workloads {
+		entry := SnapshotWorkload{
+			Type:   workload.Type,
+			Images: map[string]string{},
+			Scale:  workload.Scale,
+		}
+		for _, container := range workload.Containers {
+			entry.Images[container.Name] = container.Image
+		}
+		snapshot[workload.NamespaceId+"/"+workload.Name] = entry
+	}
+	return snapshot, nil
+}
+
+func snapshotApps(client *projectClient.Client) (map[string]SnapshotApp, error) {
+	collection, err := client.App.List(defaultListOpts(nil))
+	if err != nil {
+		return nil, err
+	}
+	apps, err := listAll(nil, collection, func(c *projectClient.AppCollection) []projectClient.App { return c.Data })
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := map[string]SnapshotApp{}
+	for _, app := range apps {
+		entry := SnapshotApp{
+			Answers:          app.Answers,
+			AnswersSetString: app.AnswersSetString,
+		}
+		if app.ExternalID != "" {
+			externalInfo, err := parseExternalID(app.ExternalID)
+			if err != nil {
+				return nil, err
+			}
+			entry.Catalog = externalInfo["catalog"]
+			entry.Template = externalInfo["template"]
+			entry.Version = externalInfo["version"]
+		}
+		snapshot[app.TargetNamespace+"/"+app.Name] = entry
+	}
+	return snapshot, nil
+}
+
+// snapshotSecrets returns the secrets of the project and of its namespaces,
+// the former keyed by their name alone.
+func snapshotSecrets(client *projectClient.Client) (map[string]SnapshotSecret, error) {
+	snapshot := map[string]SnapshotSecret{}
+
+	collection, err := client.Secret.List(defaultListOpts(nil))
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := listAll(nil, collection, func(c *projectClient.SecretCollection) []projectClient.Secret { return c.Data })
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range secrets {
+		snapshot[secretSnapshotKey(secret.NamespaceId, secret.Name)] = newSnapshotSecret(secret.Kind, secret.Data)
+	}
+
+	namespacedCollection, err := client.NamespacedSecret.List(defaultListOpts(nil))
+	if err != nil {
+		return nil, err
+	}
+	namespaced, err := listAll(nil, namespacedCollection, func(c *projectClient.NamespacedSecretCollection) []projectClient.NamespacedSecret { return c.Data })
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range namespaced {
+		snapshot[secretSnapshotKey(secret.NamespaceId, secret.Name)] = newSnapshotSecret(secret.Kind, secret.Data)
+	}
+	return snapshot, nil
+}
+
+func secretSnapshotKey(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
+
+// newSnapshotSecret records the keys of the data of a secret and a digest of
+// it, which changes with any of its values.
+func newSnapshotSecret(kind string, data map[string]string) SnapshotSecret {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		io.WriteString(hash, key+"\x00"+data[key]+"\x00")
+	}
+	return SnapshotSecret{
+		Type:   kind,
+		Keys:   keys,
+		Digest: hex.EncodeToString(hash.Sum(nil)),
+	}
+}
+
+func snapshotQuotas(client *clusterClient.Client, project *managementClient.Project) (SnapshotQuotas, error) {
+	var snapshot SnapshotQuotas
+	var err error
+	if project.ResourceQuota != nil {
+		if snapshot.Project, err = quotaLimits(project.ResourceQuota.Limit); err != nil {
+			return snapshot, err
+		}
+	}
+	if project.NamespaceDefaultResourceQuota != nil {
+		if snapshot.NamespaceDefault, err = quotaLimits(project.NamespaceDefaultResourceQuota.Limit); err != nil {
+			return snapshot, err
+		}
+	}
+
+	opts := defaultListOpts(nil)
+	opts.Filters["projectId"] = project.ID
+	collection, err := client.Namespace.List(opts)
+	if err != nil {
+		return snapshot, err
+	}
+	namespaces, err := listAll(nil, collection, func(c *clusterClient.NamespaceCollection) []clusterClient.Namespace { return c.Data })
+	if err != nil {
+		return snapshot, err
+	}
+	for _, namespace := range namespaces {
+		if namespace.ResourceQuota == nil {
+			continue
+		}
+		limits, err := quotaLimits(namespace.ResourceQuota.Limit)
+		if err != nil {
+			return snapshot, err
+		}
+		if snapshot.Namespaces == nil {
+			snapshot.Namespaces = map[string]map[string]string{}
+		}
+		snapshot.Namespaces[namespace.Name] = limits
+	}
+	return snapshot, nil
+}
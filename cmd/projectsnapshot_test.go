@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSnapshotSecret(t *testing.T) {
+	secret := newSnapshotSecret("Opaque", map[string]string{"user": "YWRtaW4=", "password": "c2VjcmV0"})
+	assert.Equal(t, []string{"password", "user"}, secret.Keys)
+	assert.Equal(t, "Opaque", secret.Type)
+
+	same := newSnapshotSecret("Opaque", map[string]string{"password": "c2VjcmV0", "user": "YWRtaW4="})
+	changed := newSnapshotSecret("Opaque", map[string]string{"user": "YWRtaW4=", "password": "b3RoZXI="})
+	assert.Equal(t, secret.Digest, same.Digest)
+	assert.NotEqual(t, secret.Digest, changed.Digest)
+}
+
+func TestCompareProjectSnapshots(t *testing.T) {
+	scale := func(n int64) *int64 { return &n }
+	old := &ProjectSnapshot{
+		Workloads: map[string]SnapshotWorkload{
+			"team-a/web": {Type: "deployment", Images: map[string]string{"web": "nginx:1.24"}, Scale: scale(2)},
+			"team-a/old": {Type: "deployment", Images: map[string]string{"old": "busybox"}},
+		},
+		Apps: map[string]SnapshotApp{
+			"team-a/redis": {Catalog: "library", Template: "redis", Version: "1.0.0"},
+		},
+		Secrets: map[string]SnapshotSecret{"team-a/db": {Keys: []string{"password"}, Digest: "a"}},
+		Quotas:  SnapshotQuotas{Project: map[string]string{"limitsCpu": "4000m"}},
+	}
+	current := &ProjectSnapshot{
+		Workloads: map[string]SnapshotWorkload{
+			"team-a/web": {Type: "deployment", Images: map[string]string{"web": "nginx:1.25"}, Scale: scale(2)},
+		},
+		Apps: map[string]SnapshotApp{
+			"team-a/redis": {Catalog: "library", Template: "redis", Version: "1.0.0"},
+		},
+		Secrets: map[string]SnapshotSecret{"team-a/db": {Keys: []string{"password"}, Digest: "b"}},
+		Quotas:  SnapshotQuotas{Project: map[string]string{"limitsCpu": "8000m"}},
+	}
+
+	diffs := compareProjectSnapshots(old, current)
+	var paths []string
+	for _, diff := range diffs {
+		paths = append(paths, diff.Op+" "+diff.Path)
+	}
+	assert.Equal(t, []string{
+		"~ quotas.project.limitsCpu",
+		"~ secrets.team-a/db.digest",
+		"- workloads.team-a/old",
+		"~ workloads.team-a/web.images.web",
+	}, paths)
+	assert.Empty(t, compareProjectSnapshots(old, old))
+}
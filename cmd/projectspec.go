@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ghodss/yaml"
+	"github.com/rancher/cli/cliclient"
+	"github.com/rancher/norman/clientbase"
+	"github.com/rancher/norman/types"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/urfave/cli"
+)
+
+const projectCreateDescription = `
+Creates a project in the current cluster, or in the cluster given with --cluster.
+
+With --file the project is created from a spec, with its quotas, members, namespaces and
+monitoring, so that onboarding a tenant is a single reviewed file:
+
+	name: team-a
+	cluster: prod
+	description: Team A
+	resourceQuota:
+	  limit:
+	    limitsCpu: 20000m
+	    limitsMemory: 40Gi
+	namespaceDefaultResourceQuota:
+	  limit:
+	    limitsCpu: 4000m
+	    limitsMemory: 8Gi
+	members:
+	- name: alice
+	  role: project-owner
+	- name: team-a-devs
+	  role: project-member
+	namespaces:
+	- team-a-dev
+	- team-a-prod
+	monitoring: true
+
+Monitoring is deployed by Prometheus Federator, which must be installed in the cluster. Values
+for the monitoring chart are given with monitoringValues.
+
+Example:
+	$ rancher project create --file team-a.yaml
+`
+
+// ProjectSpec is the declarative spec of a project read by
+// 'project create --file'.
+type ProjectSpec struct {
+	Name                          string                                   `json:"name"`
+	Cluster                       string                                   `json:"cluster,omitempty"`
+	Description                   string                                   `json:"description,omitempty"`
+	ResourceQuota                 *managementClient.ProjectResourceQuota   `json:"resourceQuota,omitempty"`
+	NamespaceDefaultResourceQuota *managementClient.NamespaceResourceQuota `json:"namespaceDefaultResourceQuota,omitempty"`
+	ContainerDefaultResourceLimit *managementClient.ContainerResourceLimit `json:"containerDefaultResourceLimit,omitempty"`
+	Members                       []ProjectSpecMember                      `json:"members,omitempty"`
+	Namespaces                    []string                                 `json:"namespaces,omitempty"`
+	Monitoring                    bool                                     `json:"monitoring,omitempty"`
+	MonitoringValues              map[string]interface{}                   `json:"monitoringValues,omitempty"`
+}
+
+// ProjectSpecMember is a user or group given a role in the project
+type ProjectSpecMember struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+func readProjectSpec(path string) (*ProjectSpec, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec := &ProjectSpec{}
+	if err := yaml.Unmarshal(content, spec); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := spec.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return spec, nil
+}
+
+func (s *ProjectSpec) validate() error {
+	if s.Name == "" {
+		return errors.New("name is required")
+	}
+	for i, member := range s.Members {
+		if member.Name == "" || member.Role == "" {
+			return fmt.Errorf("member %d needs a name and a role", i+1)
+		}
+	}
+	if s.Monitoring && len(s.Namespaces) == 0 {
+		return errors.New("monitoring needs at least one namespace in the project")
+	}
+	return nil
+}
+
+// projectCreateFromSpec creates the project of a spec with its members,
+// namespaces and monitoring. Members are looked up before anything is created
+// so that a typo doesn't leave a half created project.
+func projectCreateFromSpec(ctx *cli.Context, c *cliclient.MasterClient, spec *ProjectSpec) error {
+	cluster := spec.Cluster
+	if ctx.String("cluster") != "" {
+		cluster = ctx.String("cluster")
+	}
+	clusterID, err := resolveClusterID(c, cluster)
+	if err != nil {
+		return err
+	}
+
+	principals := make([]*managementClient.Principal, len(spec.Members))
+	for i, member := range spec.Members {
+		if principals[i], err = searchForMember(ctx, c, member.Name); err != nil {
+			return err
+		}
+	}
+
+	needed := []permission{managementPermission(c, managementClient.ProjectType, verbCreate)}
+	if len(spec.Members) > 0 {
+		needed = append(needed, managementPermission(c, managementClient.ProjectRoleTemplateBindingType, verbCreate))
+	}
+	var client *clientbase.APIBaseClient
+	if len(spec.Namespaces) > 0 {
+		if client, err = cliclient.NewClusterV1Client(c.UserConfig, clusterID); err != nil {
+			return err
+		}
+		needed = append(needed, newPermission("cluster", client.Types, "namespace", verbCreate))
+	}
+	if err := checkPermissions(ctx, needed...); err != nil {
+		return err
+	}
+
+	project, err := c.ManagementClient.Project.Create(&managementClient.Project{
+		Name:                          spec.Name,
+		ClusterID:                     clusterID,
+		Description:                   spec.Description,
+		ResourceQuota:                 spec.ResourceQuota,
+		NamespaceDefaultResourceQuota: spec.NamespaceDefaultResourceQuota,
+		ContainerDefaultResourceLimit: spec.ContainerDefaultResourceLimit,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created project %s (%s)\n", project.Name, project.ID)
+
+	for i, member := range spec.Members {
+		binding := &managementClient.ProjectRoleTemplateBinding{
+			ProjectID:      project.ID,
+			RoleTemplateID: member.Role,
+		}
+		if principals[i].PrincipalType == "user" {
+			binding.UserPrincipalID = principals[i].ID
+		} else {
+			binding.GroupPrincipalID = principals[i].ID
+		}
+		if _, err := c.ManagementClient.ProjectRoleTemplateBinding.Create(binding); err != nil {
+			return fmt.Errorf("adding member %s: %w", member.Name, err)
+		}
+	}
+
+	if len(spec.Namespaces) == 0 {
+		return nil
+	}
+
+	for _, name := range spec.Namespaces {
+		namespace := map[string]interface{}{
+			"type": "namespace",
+			"metadata": map[string]interface{}{
+				"name": name,
+				"annotations": map[string]string{
+					"field.cattle.io/projectId": project.ID,
+				},
+			},
+		}
+		if err := client.Create("namespace", namespace, &types.Resource{}); err != nil {
+			return fmt.Errorf("creating namespace %s: %w", name, err)
+		}
+	}
+
+	if spec.Monitoring {
+		if err := enableProjectMonitoring(client, project.ID, spec.MonitoringValues); err != nil {
+			return err
+		}
+		fmt.Printf("Enabled monitoring of project %s\n", project.Name)
+	}
+	return nil
+}
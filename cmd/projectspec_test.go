@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadProjectSpec(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "project.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+name: team-a
+cluster: prod
+resourceQuota:
+  limit:
+    limitsCpu: 20000m
+members:
+- name: alice
+  role: project-owner
+namespaces:
+- team-a-dev
+monitoring: true
+`), 0600))
+
+	spec, err := readProjectSpec(path)
+	require.NoError(t, err)
+	assert.Equal(t, "team-a", spec.Name)
+	assert.Equal(t, "prod", spec.Cluster)
+	assert.Equal(t, "20000m", spec.ResourceQuota.Limit.LimitsCPU)
+	assert.Equal(t, []ProjectSpecMember{{Name: "alice", Role: "project-owner"}}, spec.Members)
+	assert.Equal(t, []string{"team-a-dev"}, spec.Namespaces)
+	assert.True(t, spec.Monitoring)
+}
+
+func TestProjectSpecValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    ProjectSpec
+		wantErr string
+	}{
+		{
+			name:    "missing name",
+			spec:    ProjectSpec{},
+			wantErr: "name is required",
+		},
+		{
+			name:    "member without a role",
+			spec:    ProjectSpec{Name: "team-a", Members: []ProjectSpecMember{{Name: "alice"}}},
+			wantErr: "member 1 needs a name and a role",
+		},
+		{
+			name:    "monitoring without namespaces",
+			spec:    ProjectSpec{Name: "team-a", Monitoring: true},
+			wantErr: "monitoring needs at least one namespace in the project",
+		},
+		{
+			name: "valid",
+			spec: ProjectSpec{Name: "team-a", Namespaces: []string{"team-a-dev"}, Monitoring: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
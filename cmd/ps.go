@@ -40,6 +40,8 @@ Examples:
 				Name:  "format",
 				Usage: "'json', 'yaml' or Custom format: '{{.Name}} {{.Image}}'",
 			},
+			filterFlag,
+			labelSelectorFlag,
 		},
 	}
 }
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/rancher/cli/cliclient"
+	"github.com/rancher/norman/clientbase"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+const provisioningClusterType = "provisioning.cattle.io.cluster"
+
+const setRegistryDescription = `
+Configures the registry mirrors of a cluster provisioned by Rancher.
+
+RKE2 and K3s clusters get a containerd mirror for every --mirror, and --ca is trusted for the
+mirror endpoints. RKE clusters only support a default registry, which is set from the mirror
+of docker.io.
+
+Example:
+	$ rancher cluster set-registry --mirror docker.io=registry.internal:5000 --ca ca.pem mycluster
+`
+
+func clusterSetRegistry(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+
+	mirrors, err := parseRegistryMirrors(ctx.StringSlice("mirror"))
+	if err != nil {
+		return err
+	}
+
+	var caBundle []byte
+	if ctx.String("ca") != "" {
+		if caBundle, err = os.ReadFile(ctx.String("ca")); err != nil {
+			return err
+		}
+	}
+
+	c, err := GetClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	resource, err := Lookup(c, ctx.Args().First(), "cluster")
+	if err != nil {
+		return err
+	}
+
+	cluster, err := getClusterByID(c, resource.ID)
+	if err != nil {
+		return err
+	}
+
+	if cluster.RancherKubernetesEngineConfig != nil {
+		return setRKERegistry(c, cluster, mirrors, caBundle)
+	}
+	return setProvisioningClusterRegistry(c, cluster, mirrors, caBundle, ctx.Bool("insecure"))
+}
+
+// parseRegistryMirrors parses REGISTRY=ENDPOINT mirrors into a map of the
+// endpoint URL by registry.
+func parseRegistryMirrors(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, errors.New("at least one --mirror is required")
+	}
+	mirrors := map[string]string{}
+	for _, value := range values {
+		registry, endpoint, ok := strings.Cut(value, "=")
+		if !ok || registry == "" || endpoint == "" {
+			return nil, fmt.Errorf("invalid mirror %q, expected REGISTRY=ENDPOINT", value)
+		}
+		if !strings.Contains(endpoint, "://") {
+			endpoint = "https://" + endpoint
+		}
+		mirrors[registry] = endpoint
+	}
+	return mirrors, nil
+}
+
+// endpointHost returns the host[:port] of an endpoint URL, which is how
+// containerd keys the TLS configuration of a registry.
+func endpointHost(endpoint string) string {
+	_, host, _ := strings.Cut(endpoint, "://")
+	host, _, _ = strings.Cut(host, "/")
+	return host
+}
+
+func setRKERegistry(c *cliclient.MasterClient, cluster *managementClient.Cluster, mirrors map[string]string, caBundle []byte) error {
+	for registry := range mirrors {
+		if registry != "docker.io" {
+			return fmt.Errorf("RKE clusters only support a default registry, %s can't be mirrored", registry)
+		}
+	}
+	if caBundle != nil {
+		logrus.Warn("RKE clusters don't support a registry CA, the CA must be trusted by the nodes")
+	}
+
+	config := cluster.RancherKubernetesEngineConfig
+	registries := withDefaultRegistry(config.PrivateRegistries, endpointHost(mirrors["docker.io"]))
+	config.PrivateRegistries = registries
+
+	_, err := c.ManagementClient.Cluster.Update(cluster, map[string]interface{}{
+		"rancherKubernetesEngineConfig": config,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Set the default registry of cluster %s to %s\n", cluster.Name, registries[0].URL)
+	return nil
+}
+
+// withDefaultRegistry returns registries with url as the default registry,
+// replacing the previous default. The credentials of the registry already
+// configured with url are kept.
+func withDefaultRegistry(registries []managementClient.PrivateRegistry, url string) []managementClient.PrivateRegistry {
+	result := []managementClient.PrivateRegistry{{URL: url}}
+	for _, registry := range registries {
+		switch {
+		case registry.URL == url:
+			result[0] = registry
+		case !registry.IsDefault:
+			result = append(result, registry)
+		}
+	}
+	result[0].IsDefault = true
+	return result
+}
+
+func setProvisioningClusterRegistry(c *cliclient.MasterClient, cluster *managementClient.Cluster, mirrors map[string]string, caBundle []byte, insecure bool) error {
+	if c.CAPIClient == nil {
+		return errors.New("unable to reach the /v1 API of the Rancher server")
+	}
+
+	id := cluster.FleetWorkspaceName + "/" + cluster.Name
+	object, resource, err := getRawObject(&c.CAPIClient.APIBaseClient, provisioningClusterType, id)
+	if clientbase.IsNotFound(err) {
+		return fmt.Errorf("the registries of cluster %s can't be configured, only clusters provisioned by Rancher are supported", cluster.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	spec, _ := object["spec"].(map[string]interface{})
+	rkeConfig, _ := spec["rkeConfig"].(map[string]interface{})
+	if rkeConfig == nil {
+		return fmt.Errorf("the registries of cluster %s can't be configured, only clusters provisioned by Rancher are supported", cluster.Name)
+	}
+	applyRegistryMirrors(rkeConfig, mirrors, caBundle, insecure)
+
+	if err := c.CAPIClient.Update(provisioningClusterType, resource, object, &map[string]interface{}{}); err != nil {
+		return err
+	}
+
+	registries := make([]string, 0, len(mirrors))
+	for registry := range mirrors {
+		registries = append(registries, registry)
+	}
+	sort.Strings(registries)
+	fmt.Printf("Configured mirrors for %s in cluster %s\n", strings.Join(registries, ", "), cluster.Name)
+	return nil
+}
+
+// applyRegistryMirrors sets the mirrors in the registries of an rkeConfig.
+// Other mirrors and registry configuration are kept.
+func applyRegistryMirrors(rkeConfig map[string]interface{}, mirrors map[string]string, caBundle []byte, insecure bool) {
+	registries := childMap(rkeConfig, "registries")
+	configuredMirrors := childMap(registries, "mirrors")
+	configs := childMap(registries, "configs")
+
+	for registry, endpoint := range mirrors {
+		configuredMirrors[registry] = map[string]interface{}{
+			"endpoint": []string{endpoint},
+		}
+		if caBundle == nil && !insecure {
+			continue
+		}
+		config := childMap(configs, endpointHost(endpoint))
+		if caBundle != nil {
+			config["caBundle"] = base64.StdEncoding.EncodeToString(caBundle)
+		}
+		if insecure {
+			config["insecureSkipVerify"] = true
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"testing"
+
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRegistryMirrors(t *testing.T) {
+	mirrors, err := parseRegistryMirrors([]string{"docker.io=registry.internal:5000", "quay.io=http://mirror.local/quay"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"docker.io": "https://registry.internal:5000",
+		"quay.io":   "http://mirror.local/quay",
+	}, mirrors)
+
+	_, err = parseRegistryMirrors([]string{"docker.io"})
+	assert.Error(t, err)
+	_, err = parseRegistryMirrors(nil)
+	assert.Error(t, err)
+}
+
+func TestApplyRegistryMirrors(t *testing.T) {
+	rkeConfig := map[string]interface{}{
+		"registries": map[string]interface{}{
+			"mirrors": map[string]interface{}{
+				"ghcr.io": map[string]interface{}{"endpoint": []interface{}{"https://ghcr.internal"}},
+			},
+		},
+	}
+
+	applyRegistryMirrors(rkeConfig, map[string]string{"docker.io": "https://registry.internal:5000"}, []byte("CA"), false)
+
+	registries := rkeConfig["registries"].(map[string]interface{})
+	mirrors := registries["mirrors"].(map[string]interface{})
+	assert.Contains(t, mirrors, "ghcr.io")
+	assert.Equal(t, map[string]interface{}{"endpoint": []string{"https://registry.internal:5000"}}, mirrors["docker.io"])
+	configs := registries["configs"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"caBundle": "Q0E="}, configs["registry.internal:5000"])
+}
+
+func TestWithDefaultRegistry(t *testing.T) {
+	existing := []managementClient.PrivateRegistry{
+		{URL: "old.internal", User: "old", Password: "old-secret", IsDefault: true},
+		{URL: "registry.internal", User: "ci", Password: "secret"},
+		{URL: "quay.internal"},
+	}
+
+	assert.Equal(t, []managementClient.PrivateRegistry{
+		{URL: "registry.internal", User: "ci", Password: "secret", IsDefault: true},
+		{URL: "quay.internal"},
+	}, withDefaultRegistry(existing, "registry.internal"))
+
+	assert.Equal(t, []managementClient.PrivateRegistry{
+		{URL: "old.internal", User: "old", Password: "old-secret", IsDefault: true},
+		{URL: "registry.internal", User: "ci", Password: "secret"},
+		{URL: "quay.internal"},
+	}, withDefaultRegistry(existing, "old.internal"), "updating the default keeps its credentials")
+
+	assert.Equal(t, []managementClient.PrivateRegistry{
+		{URL: "new.internal", IsDefault: true},
+		{URL: "registry.internal", User: "ci", Password: "secret"},
+		{URL: "quay.internal"},
+	}, withDefaultRegistry(existing, "new.internal"))
+}
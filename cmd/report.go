@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/rancher/cli/cliclient"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	projectClient "github.com/rancher/rancher/pkg/client/generated/project/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+const reportInventoryDescription = `
+Walks every cluster of the Rancher server and prints in one document its Kubernetes version, its
+nodes by kubelet version, its projects and the apps installed in them with their template
+versions, for compliance and license reporting.
+
+As JSON the inventory is nested by cluster and project. As CSV it has a row per cluster, per
+kubelet version of the nodes of a cluster, per project and per app, told apart by the TYPE column.
+Only the clusters, projects and apps the current user can see are reported.
+
+Example:
+	$ rancher report inventory > inventory.json
+	$ rancher report inventory --output csv > inventory.csv
+`
+
+var reportOutputFlag = cli.StringFlag{
+	Name:  "output,o",
+	Usage: "Print the report as 'json' or 'csv'",
+	Value: "json",
+}
+
+// Inventory is the inventory of the clusters of a Rancher server
+type Inventory struct {
+	Server    string             `json:"server"`
+	Generated time.Time          `json:"generated"`
+	Clusters  []InventoryCluster `json:"clusters"`
+}
+
+type InventoryCluster struct {
+	ID                string             `json:"id"`
+	Name              string             `json:"name"`
+	Provider          string             `json:"provider,omitempty"`
+	KubernetesVersion string             `json:"kubernetesVersion,omitempty"`
+	State             string             `json:"state,omitempty"`
+	Nodes             int                `json:"nodes"`
+	NodeVersions      map[string]int     `json:"nodeVersions,omitempty"`
+	Projects          []InventoryProject `json:"projects"`
+}
+
+type InventoryProject struct {
+	ID   string         `json:"id"`
+	Name string         `json:"name"`
+	Apps []InventoryApp `json:"apps"`
+}
+
+type InventoryApp struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace"`
+	Catalog         string `json:"catalog,omitempty"`
+	Template        string `json:"template,omitempty"`
+	Version         string `json:"version,omitempty"`
+	State           string `json:"state,omitempty"`
+	MultiClusterApp string `json:"multiClusterApp,omitempty"`
+}
+
+func ReportCommand() cli.Command {
+	return cli.Command{
+		Name:  "report",
+		Usage: "Reports across the whole Rancher server",
+		Subcommands: []cli.Command{
+			{
+				Name:        "inventory",
+				Usage:       "Print the clusters, nodes, projects and apps of the Rancher server",
+				Description: reportInventoryDescription,
+				ArgsUsage:   "None",
+				Action:      reportInventory,
+				Flags: []cli.Flag{
+					reportOutputFlag,
+				},
+			},
+			{
+				Name:        "orphans",
+				Usage:       "List the resources left behind by removed resources",
+				Description: reportOrphansDescription,
+				ArgsUsage:   "None",
+				Action:      reportOrphans,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "delete",
+						Usage: "Ask to clean up each orphan",
+					},
+					forceFlag,
+					formatFlag,
+					noHeadersFlag,
+				},
+			},
+		},
+	}
+}
+
+func reportInventory(ctx *cli.Context) error {
+	output := ctx.String("output")
+	if output != "json" && output != "csv" {
+		return NewUsageError(fmt.Errorf("invalid output %q, expected json or csv", output))
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	inventory, err := collectInventory(c)
+	if err != nil {
+		return err
+	}
+	if output == "csv" {
+		return writeInventoryCSV(os.Stdout, inventory)
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(inventory)
+}
+
+func collectInventory(c *cliclient.MasterClient) (*Inventory, error) {
+	clusters, err := listAllClusters(nil, c.ManagementClient)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := listAllProjects(nil, c.ManagementClient)
+	if err != nil {
+		return nil, err
+	}
+	nodeCollection, err := c.ManagementClient.Node.List(defaultListOpts(nil))
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := listAll(nil, nodeCollection, func(c *managementClient.NodeCollection) []managementClient.Node { return c.Data })
+	if err != nil {
+		return nil, err
+	}
+
+	inventory := &Inventory{
+		Server:    c.UserConfig.URL,
+		Generated: time.Now().UTC(),
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].Name < clusters[j].Name })
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+	for _, cluster := range clusters {
+		entry := newInventoryCluster(cluster, nodes)
+		for _, project := range projects {
+			if project.ClusterID != cluster.ID {
+				continue
+			}
+			apps, err := inventoryApps(c, project.ID)
+			if err != nil {
+				return nil, err
+			}
+			entry.Projects = append(entry.Projects, InventoryProject{ID: project.ID, Name: project.Name, Apps: apps})
+		}
+		inventory.Clusters = append(inventory.Clusters, entry)
+	}
+	return inventory, nil
+}
+
+// newInventoryCluster returns the inventory of a cluster, without its
+// projects, counting its nodes out of nodes.
+func newInventoryCluster(cluster managementClient.Cluster, nodes []managementClient.Node) InventoryCluster {
+	entry := InventoryCluster{
+		ID:       cluster.ID,
+		Name:     cluster.Name,
+		Provider: valueOrDefault(cluster.Provider, cluster.Driver),
+		State:    cluster.State,
+	}
+	if cluster.Version != nil {
+		entry.KubernetesVersion = cluster.Version.GitVersion
+	}
+	for _, node := range nodes {
+		if node.ClusterID != cluster.ID {
+			continue
+		}
+		entry.Nodes++
+		version := "unknown"
+		if node.Info != nil && node.Info.Kubernetes != nil && node.Info.Kubernetes.KubeletVersion != "" {
+			version = node.Info.Kubernetes.KubeletVersion
+		}
+		if entry.NodeVersions == nil {
+			entry.NodeVersions = map[string]int{}
+		}
+		entry.NodeVersions[version]++
+	}
+	return entry
+}
+
+// inventoryApps returns the apps of a project
+func inventoryApps(c *cliclient.MasterClient, projectID string) ([]InventoryApp, error) {
+	_, apps, err := listProjectApps(c, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var inventory []InventoryApp
+	for _, app := range apps {
+		entry := InventoryApp{
+			ID:              app.ID,
+			Name:            app.Name,
+			Namespace:       app.TargetNamespace,
+			State:           app.State,
+			MultiClusterApp: app.MultiClusterAppID,
+		}
+		if app.ExternalID != "" {
+			externalInfo, err := parseExternalID(app.ExternalID)
+			if err != nil {
+				return nil, err
+			}
+			entry.Catalog = externalInfo["catalog"]
+			entry.Template = externalInfo["template"]
+			entry.Version = externalInfo["version"]
+		}
+		inventory = append(inventory, entry)
+	}
+	sort.Slice(inventory, func(i, j int) bool { return inventory[i].Name < inventory[j].Name })
+	return inventory, nil
+}
+
+// listProjectApps returns the apps of a project with a client of the project
+// API serving them. Apps are served by the project API, a project whose API
+// can't be reached is skipped with a warning.
+func listProjectApps(c *cliclient.MasterClient, projectID string) (*projectClient.Client, []projectClient.App, error) {
+	sc := *c.UserConfig
+	sc.Project = projectID
+	pc, err := cliclient.NewProjectClient(&sc)
+	if err != nil {
+		logrus.Warnf("Skipping apps of project %s: %v", projectID, err)
+		return nil, nil, nil
+	}
+
+	collection, err := pc.ProjectClient.App.List(defaultListOpts(nil))
+	if err != nil {
+		return nil, nil, err
+	}
+	apps, err := listAll(nil, collection, func(c *projectClient.AppCollection) []projectClient.App { return c.Data })
+	if err != nil {
+		return nil, nil, err
+	}
+	return pc.ProjectClient, apps, nil
+}
+
+// inventoryRows returns the rows of the CSV of an inventory, with its header
+func inventoryRows(inventory *Inventory) [][]string {
+	rows := [][]string{{"TYPE", "CLUSTER", "PROJECT", "NAMESPACE", "NAME", "CATALOG", "TEMPLATE", "VERSION", "STATE", "COUNT"}}
+	for _, cluster := range inventory.Clusters {
+		rows = append(rows, []string{"cluster", cluster.Name, "", "", cluster.Name, "", "", cluster.KubernetesVersion, cluster.State, strconv.Itoa(cluster.Nodes)})
+
+		var versions []string
+		for version := range cluster.NodeVersions {
+			versions = append(versions, version)
+		}
+		sort.Strings(versions)
+		for _, version := range versions {
+			rows = append(rows, []string{"nodes", cluster.Name, "", "", "", "", "", version, "", strconv.Itoa(cluster.NodeVersions[version])})
+		}
+
+		for _, project := range cluster.Projects {
+			rows = append(rows, []string{"project", cluster.Name, project.Name, "", project.Name, "", "", "", "", strconv.Itoa(len(project.Apps))})
+			for _, app := range project.Apps {
+				rows = append(rows, []string{"app", cluster.Name, project.Name, app.Namespace, app.Name, app.Catalog, app.Template, app.Version, app.State, ""})
+			}
+		}
+	}
+	return rows
+}
+
+func writeInventoryCSV(out io.Writer, inventory *Inventory) error {
+	writer := csv.NewWriter(out)
+	if err := writer.WriteAll(inventoryRows(inventory)); err != nil {
+		return err
+	}
+	return writer.Error()
+}
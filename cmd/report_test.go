@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"testing"
+
+	ntypes "github.com/rancher/norman/types"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewInventoryCluster(t *testing.T) {
+	cluster := managementClient.Cluster{
+		Name:     "prod",
+		Driver:   "rke2",
+		State:    "active",
+		Version:  &managementClient.Info{GitVersion: "v1.28.9+rke2r1"},
+		Resource: ntypes.Resource{ID: "c-1"},
+	}
+	kubelet := func(version string) *managementClient.NodeInfo {
+		return &managementClient.NodeInfo{Kubernetes: &managementClient.KubernetesInfo{KubeletVersion: version}}
+	}
+	nodes := []managementClient.Node{
+		{ClusterID: "c-1", Info: kubelet("v1.28.9+rke2r1")},
+		{ClusterID: "c-1", Info: kubelet("v1.28.9+rke2r1")},
+		{ClusterID: "c-1"},
+		{ClusterID: "c-2", Info: kubelet("v1.27.1")},
+	}
+
+	entry := newInventoryCluster(cluster, nodes)
+	assert.Equal(t, InventoryCluster{
+		ID:                "c-1",
+		Name:              "prod",
+		Provider:          "rke2",
+		KubernetesVersion: "v1.28.9+rke2r1",
+		State:             "active",
+		Nodes:             3,
+		NodeVersions:      map[string]int{"v1.28.9+rke2r1": 2, "unknown": 1},
+	}, entry)
+}
+
+func TestInventoryRows(t *testing.T) {
+	inventory := &Inventory{Clusters: []InventoryCluster{{
+		Name:              "prod",
+		KubernetesVersion: "v1.28.9",
+		State:             "active",
+		Nodes:             2,
+		NodeVersions:      map[string]int{"v1.28.9": 2},
+		Projects: []InventoryProject{{
+			Name: "Default",
+			Apps: []InventoryApp{{Name: "redis", Namespace: "redis", Catalog: "library", Template: "redis", Version: "7.2.0", State: "active"}},
+		}},
+	}}}
+
+	assert.Equal(t, [][]string{
+		{"TYPE", "CLUSTER", "PROJECT", "NAMESPACE", "NAME", "CATALOG", "TEMPLATE", "VERSION", "STATE", "COUNT"},
+		{"cluster", "prod", "", "", "prod", "", "", "v1.28.9", "active", "2"},
+		{"nodes", "prod", "", "", "", "", "", "v1.28.9", "", "2"},
+		{"project", "prod", "Default", "", "Default", "", "", "", "", "1"},
+		{"app", "prod", "Default", "redis", "redis", "library", "redis", "7.2.0", "active", ""},
+	}, inventoryRows(inventory))
+}
@@ -0,0 +1,411 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/rancher/cli/cliclient"
+	"github.com/rancher/norman/clientbase"
+	"github.com/rancher/norman/types"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+	"golang.org/x/term"
+)
+
+const reportOrphansDescription = `
+Lists the resources left behind by resources that were removed:
+
+	app                     apps whose template version is no longer in any catalog
+	multiClusterAppTarget   targets of multi-cluster apps whose project was removed
+	nodeTemplate            node templates no node pool uses
+	cloudCredential         cloud credentials no node template or cluster uses
+
+The project of a target and the template version of an app are each fetched to confirm they
+were removed, so that those the user isn't allowed to list aren't reported.
+
+With --delete each orphan is cleaned up once confirmed: apps, node templates and cloud
+credentials are deleted and the targets are removed from their multi-cluster app. Deleting an app
+uninstalls its workloads, which keep running until then.
+
+Example:
+	$ rancher report orphans
+	$ rancher report orphans --delete
+`
+
+// globalDataNamespace is the namespace of the secrets of the cloud
+// credentials, which prefixes their IDs.
+const globalDataNamespace = "cattle-global-data"
+
+// Orphan is a resource left behind by a removed resource
+type Orphan struct {
+	Type   string `json:"type"`
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+	// cleanup describes what --delete does, which remove does
+	cleanup string
+	remove  func() error
+}
+
+type provisioningCluster struct {
+	types.Resource
+	Metadata objectMeta `json:"metadata,omitempty"`
+	Spec     struct {
+		CloudCredentialSecretName string `json:"cloudCredentialSecretName,omitempty"`
+	} `json:"spec,omitempty"`
+}
+
+type provisioningClusterCollection struct {
+	types.Collection
+	Data []provisioningCluster `json:"data,omitempty"`
+}
+
+func reportOrphans(ctx *cli.Context) error {
+	if ctx.Bool("delete") && !ctx.Bool("force") && !term.IsTerminal(int(os.Stdin.Fd())) {
+		return NewUsageError(errors.New("--delete asks before each cleanup, pass --force to clean up every orphan without a terminal"))
+	}
+
+	c, err := GetClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	var orphans []Orphan
+	for _, find := range []func(*cliclient.MasterClient) ([]Orphan, error){
+		findOrphanedApps,
+		findOrphanedMcappTargets,
+		findOrphanedNodeTemplates,
+		findOrphanedCloudCredentials,
+	} {
+		found, err := find(c)
+		if err != nil {
+			return err
+		}
+		orphans = append(orphans, found...)
+	}
+
+	writer := NewTableWriter([][]string{
+		{"TYPE", "Type"},
+		{"ID", "ID"},
+		{"NAME", "Name"},
+		{"REASON", "Reason"},
+	}, ctx)
+	for _, orphan := range orphans {
+		writer.Write(orphan)
+	}
+	writer.Close()
+	if err := writer.Err(); err != nil {
+		return err
+	}
+
+	if !ctx.Bool("delete") {
+		return nil
+	}
+	var failed int
+	for _, orphan := range orphans {
+		if !confirmAction(ctx, orphan.cleanup+"?") {
+			continue
+		}
+		if err := orphan.remove(); err != nil {
+			logrus.Errorf("%s: %v", orphan.cleanup, err)
+			failed++
+			continue
+		}
+		fmt.Println(orphan.cleanup + ": done")
+	}
+	if failed > 0 {
+		return partialErrorf("%d of the cleanups failed", failed)
+	}
+	return nil
+}
+
+// findOrphanedApps returns the apps whose template version can't be found,
+// those of multi-cluster apps excepted as they're managed through them.
+func findOrphanedApps(c *cliclient.MasterClient) ([]Orphan, error) {
+	projects, err := listAllProjects(nil, c.ManagementClient)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].ID < projects[j].ID })
+
+	available := map[string]bool{}
+	var orphans []Orphan
+	for _, project := range projects {
+		pc, apps, err := listProjectApps(c, project.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, app := range apps {
+			if app.ExternalID == "" || app.MultiClusterAppID != "" {
+				continue
+			}
+			externalInfo, err := parseExternalID(app.ExternalID)
+			if err != nil {
+				return nil, err
+			}
+			found, ok := available[app.ExternalID]
+			if !ok {
+				if found, err = templateVersionAvailable(c, app.ExternalID, externalInfo); err != nil {
+					return nil, err
+				}
+				available[app.ExternalID] = found
+			}
+			if found {
+				continue
+			}
+			app := app
+			orphans = append(orphans, Orphan{
+				Type: "app",
+				ID:   app.ID,
+				Name: app.Name,
+				Reason: fmt.Sprintf("template %s version %s is no longer in catalog %s",
+					externalInfo["template"], externalInfo["version"], externalInfo["catalog"]),
+				cleanup: fmt.Sprintf("Delete app %s of project %s", app.Name, project.Name),
+				remove:  func() error { return pc.App.Delete(&app) },
+			})
+		}
+	}
+	return orphans, nil
+}
+
+// templateVersionAvailable reports whether the template version of an app
+// exists. The template versions listed are limited by the permissions of the
+// user, so one that isn't listed is only reported missing once fetching it by
+// ID fails with not found.
+func templateVersionAvailable(c *cliclient.MasterClient, externalID string, externalInfo map[string]string) (bool, error) {
+	_, err := templateVersionByExternalID(c, externalID)
+	if err == nil {
+		return true, nil
+	}
+	if ExitCode(err) != ExitCodeNotFound {
+		return false, err
+	}
+	_, err = c.ManagementClient.TemplateVersion.ByID(templateVersionIDFromExternalInfo(externalInfo))
+	removed, err := confirmRemoved(err)
+	return !removed, err
+}
+
+// templateVersionIDFromExternalInfo returns the ID of the template version of
+// an external ID. The catalog of cluster and project catalogs is prefixed by
+// their namespace, global catalogs are in the global data namespace.
+func templateVersionIDFromExternalInfo(externalInfo map[string]string) string {
+	namespace, catalog, ok := strings.Cut(externalInfo["catalog"], "/")
+	if !ok {
+		namespace, catalog = globalDataNamespace, externalInfo["catalog"]
+	}
+	return namespace + ":" + catalog + "-" + externalInfo["template"] + "-" + externalInfo["version"]
+}
+
+// confirmRemoved handles err, the error of fetching a resource by ID that
+// wasn't listed: the resource was removed only when it's not found. When the
+// user isn't allowed to get it, it's not reported as removed.
+func confirmRemoved(err error) (bool, error) {
+	var apiErr *clientbase.APIError
+	switch {
+	case err == nil:
+		return false, nil
+	case clientbase.IsNotFound(err):
+		return true, nil
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden:
+		logrus.Debugf("Unable to check whether the resource was removed: %v", err)
+		return false, nil
+	}
+	return false, err
+}
+
+func findOrphanedMcappTargets(c *cliclient.MasterClient) ([]Orphan, error) {
+	projects, err := listAllProjects(nil, c.ManagementClient)
+	if err != nil {
+		return nil, err
+	}
+	collection, err := c.ManagementClient.MultiClusterApp.List(defaultListOpts(nil))
+	if err != nil {
+		return nil, err
+	}
+	apps, err := listAll(nil, collection, func(c *managementClient.MultiClusterAppCollection) []managementClient.MultiClusterApp { return c.Data })
+	if err != nil {
+		return nil, err
+	}
+	removed := func(projectID string) (bool, error) {
+		_, err := c.ManagementClient.Project.ByID(projectID)
+		return confirmRemoved(err)
+	}
+	return orphanedMcappTargets(c, apps, projects, removed)
+}
+
+// orphanedMcappTargets returns the targets of apps whose project isn't one
+// of projects. As the projects listed are limited by the permissions of the
+// user, the project of each of those targets is only reported missing once
+// removed confirms it.
+func orphanedMcappTargets(c *cliclient.MasterClient, apps []managementClient.MultiClusterApp, projects []managementClient.Project, removed func(projectID string) (bool, error)) ([]Orphan, error) {
+	existing := map[string]bool{}
+	for _, project := range projects {
+		existing[project.ID] = true
+	}
+	var orphans []Orphan
+	for i := range apps {
+		app := &apps[i]
+		for _, target := range app.Targets {
+			if existing[target.ProjectID] {
+				continue
+			}
+			gone, err := removed(target.ProjectID)
+			if err != nil {
+				return nil, err
+			}
+			if !gone {
+				continue
+			}
+			projectID := target.ProjectID
+			orphans = append(orphans, Orphan{
+				Type:    "multiClusterAppTarget",
+				ID:      app.ID + "/" + projectID,
+				Name:    app.Name,
+				Reason:  fmt.Sprintf("project %s was removed", projectID),
+				cleanup: fmt.Sprintf("Remove project %s from the targets of multi-cluster app %s", projectID, app.Name),
+				remove: func() error {
+					return c.ManagementClient.MultiClusterApp.ActionRemoveProjects(app, &managementClient.UpdateMultiClusterAppTargetsInput{
+						Projects: []string{projectID},
+					})
+				},
+			})
+		}
+	}
+	return orphans, nil
+}
+
+func findOrphanedNodeTemplates(c *cliclient.MasterClient) ([]Orphan, error) {
+	templates, err := listAllNodeTemplates(c)
+	if err != nil {
+		return nil, err
+	}
+	poolCollection, err := c.ManagementClient.NodePool.List(defaultListOpts(nil))
+	if err != nil {
+		return nil, err
+	}
+	pools, err := listAll(nil, poolCollection, func(c *managementClient.NodePoolCollection) []managementClient.NodePool { return c.Data })
+	if err != nil {
+		return nil, err
+	}
+	return orphanedNodeTemplates(c, templates, pools), nil
+}
+
+// orphanedNodeTemplates returns the templates none of pools uses
+func orphanedNodeTemplates(c *cliclient.MasterClient, templates []managementClient.NodeTemplate, pools []managementClient.NodePool) []Orphan {
+	used := map[string]bool{}
+	for _, pool := range pools {
+		used[pool.NodeTemplateID] = true
+	}
+	var orphans []Orphan
+	for i := range templates {
+		template := &templates[i]
+		if used[template.ID] {
+			continue
+		}
+		orphans = append(orphans, Orphan{
+			Type:    "nodeTemplate",
+			ID:      template.ID,
+			Name:    template.Name,
+			Reason:  "no node pool uses it",
+			cleanup: fmt.Sprintf("Delete node template %s", valueOrDefault(template.Name, template.ID)),
+			remove:  func() error { return c.ManagementClient.NodeTemplate.Delete(template) },
+		})
+	}
+	return orphans
+}
+
+// findOrphanedCloudCredentials returns the cloud credentials no node template
+// and no cluster uses. The clusters provisioned through the /v1 API hold
+// credentials too, without that API the credentials aren't checked.
+func findOrphanedCloudCredentials(c *cliclient.MasterClient) ([]Orphan, error) {
+	if c.CAPIClient == nil {
+		logrus.Warn("Skipping cloud credentials: the /v1 API of the Rancher server is unreachable, the clusters using them can't be listed")
+		return nil, nil
+	}
+
+	credentialCollection, err := c.ManagementClient.CloudCredential.List(defaultListOpts(nil))
+	if err != nil {
+		return nil, err
+	}
+	credentials, err := listAll(nil, credentialCollection, func(c *managementClient.CloudCredentialCollection) []managementClient.CloudCredential { return c.Data })
+	if err != nil {
+		return nil, err
+	}
+	templates, err := listAllNodeTemplates(c)
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := listAllClusters(nil, c.ManagementClient)
+	if err != nil {
+		return nil, err
+	}
+	provisioned := &provisioningClusterCollection{}
+	if err := c.CAPIClient.List(provisioningClusterType, &types.ListOpts{}, provisioned); err != nil {
+		return nil, err
+	}
+
+	used := usedCloudCredentials(templates, clusters, provisioned.Data)
+	var orphans []Orphan
+	for i := range credentials {
+		credential := &credentials[i]
+		if used[credential.ID] {
+			continue
+		}
+		orphans = append(orphans, Orphan{
+			Type:    "cloudCredential",
+			ID:      credential.ID,
+			Name:    credential.Name,
+			Reason:  "no node template or cluster uses it",
+			cleanup: fmt.Sprintf("Delete cloud credential %s", valueOrDefault(credential.Name, credential.ID)),
+			remove:  func() error { return c.ManagementClient.CloudCredential.Delete(credential) },
+		})
+	}
+	return orphans, nil
+}
+
+// usedCloudCredentials returns the IDs of the cloud credentials used by node
+// templates and clusters. The clusters refer to them by secret, whose name
+// may lack the namespace of the ID.
+func usedCloudCredentials(templates []managementClient.NodeTemplate, clusters []managementClient.Cluster, provisioned []provisioningCluster) map[string]bool {
+	used := map[string]bool{}
+	add := func(id string) {
+		if id == "" {
+			return
+		}
+		if !strings.Contains(id, ":") {
+			id = globalDataNamespace + ":" + id
+		}
+		used[id] = true
+	}
+	for _, template := range templates {
+		add(template.CloudCredentialID)
+	}
+	for _, cluster := range clusters {
+		if cluster.EKSConfig != nil {
+			add(cluster.EKSConfig.AmazonCredentialSecret)
+		}
+		if cluster.AKSConfig != nil {
+			add(cluster.AKSConfig.AzureCredentialSecret)
+		}
+		if cluster.GKEConfig != nil {
+			add(cluster.GKEConfig.GoogleCredentialSecret)
+		}
+	}
+	for _, cluster := range provisioned {
+		add(cluster.Spec.CloudCredentialSecretName)
+	}
+	return used
+}
+
+func listAllNodeTemplates(c *cliclient.MasterClient) ([]managementClient.NodeTemplate, error) {
+	collection, err := c.ManagementClient.NodeTemplate.List(defaultListOpts(nil))
+	if err != nil {
+		return nil, err
+	}
+	return listAll(nil, collection, func(c *managementClient.NodeTemplateCollection) []managementClient.NodeTemplate { return c.Data })
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rancher/norman/clientbase"
+	ntypes "github.com/rancher/norman/types"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func orphanIDs(orphans []Orphan) []string {
+	var ids []string
+	for _, orphan := range orphans {
+		ids = append(ids, orphan.ID)
+	}
+	return ids
+}
+
+func TestOrphanedNodeTemplates(t *testing.T) {
+	templates := []managementClient.NodeTemplate{
+		{Resource: ntypes.Resource{ID: "cattle-global-nt:nt-used"}, Name: "used"},
+		{Resource: ntypes.Resource{ID: "cattle-global-nt:nt-unused"}, Name: "unused"},
+	}
+	pools := []managementClient.NodePool{
+		{NodeTemplateID: "cattle-global-nt:nt-used"},
+	}
+
+	orphans := orphanedNodeTemplates(nil, templates, pools)
+	assert.Equal(t, []string{"cattle-global-nt:nt-unused"}, orphanIDs(orphans))
+	assert.Equal(t, "nodeTemplate", orphans[0].Type)
+}
+
+func TestOrphanedMcappTargets(t *testing.T) {
+	apps := []managementClient.MultiClusterApp{{
+		Resource: ntypes.Resource{ID: "cattle-global-data:monitoring"},
+		Name:     "monitoring",
+		Targets: []managementClient.Target{
+			{ProjectID: "c-1:p-1"},
+			{ProjectID: "c-2:p-removed"},
+			{ProjectID: "c-3:p-not-listed"},
+		},
+	}}
+	projects := []managementClient.Project{
+		{Resource: ntypes.Resource{ID: "c-1:p-1"}},
+	}
+	removed := func(projectID string) (bool, error) {
+		return projectID == "c-2:p-removed", nil
+	}
+
+	orphans, err := orphanedMcappTargets(nil, apps, projects, removed)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cattle-global-data:monitoring/c-2:p-removed"}, orphanIDs(orphans))
+	assert.Equal(t, "monitoring", orphans[0].Name)
+
+	_, err = orphanedMcappTargets(nil, apps, projects, func(string) (bool, error) { return false, errors.New("boom") })
+	assert.EqualError(t, err, "boom")
+}
+
+func TestConfirmRemoved(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		removed bool
+		wantErr bool
+	}{
+		{name: "exists", err: nil},
+		{name: "not found", err: &clientbase.APIError{StatusCode: http.StatusNotFound}, removed: true},
+		{name: "forbidden", err: &clientbase.APIError{StatusCode: http.StatusForbidden}},
+		{name: "server error", err: &clientbase.APIError{StatusCode: http.StatusInternalServerError}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			removed, err := confirmRemoved(tt.err)
+			assert.Equal(t, tt.removed, removed)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestTemplateVersionIDFromExternalInfo(t *testing.T) {
+	assert.Equal(t, "cattle-global-data:library-wordpress-1.0.0",
+		templateVersionIDFromExternalInfo(map[string]string{"catalog": "library", "template": "wordpress", "version": "1.0.0"}))
+	assert.Equal(t, "c-1:charts-nginx-2.0.0",
+		templateVersionIDFromExternalInfo(map[string]string{"catalog": "c-1/charts", "template": "nginx", "version": "2.0.0"}))
+}
+
+func TestUsedCloudCredentials(t *testing.T) {
+	templates := []managementClient.NodeTemplate{
+		{CloudCredentialID: "cattle-global-data:cc-template"},
+		{},
+	}
+	clusters := []managementClient.Cluster{
+		{EKSConfig: &managementClient.EKSClusterConfigSpec{AmazonCredentialSecret: "cattle-global-data:cc-eks"}},
+		{AKSConfig: &managementClient.AKSClusterConfigSpec{AzureCredentialSecret: "cc-aks"}},
+		{},
+	}
+	provisioned := []provisioningCluster{{}}
+	provisioned[0].Spec.CloudCredentialSecretName = "cattle-global-data:cc-v2"
+
+	assert.Equal(t, map[string]bool{
+		"cattle-global-data:cc-template": true,
+		"cattle-global-data:cc-eks":      true,
+		"cattle-global-data:cc-aks":      true,
+		"cattle-global-data:cc-v2":       true,
+	}, usedCloudCredentials(templates, clusters, provisioned))
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rancher/cli/cliclient"
+	ntypes "github.com/rancher/norman/types"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	projectClient "github.com/rancher/rancher/pkg/client/generated/project/v3"
+	"github.com/urfave/cli"
+)
+
+const pruneRevisionsDescription = `
+Deletes the revisions of an app beyond the newest --keep revisions. Long-lived apps accumulate
+revisions with every upgrade, which slows listing them. The current revision is never deleted.
+
+Example:
+	$ rancher app prune-revisions --keep 10 appFoo
+	$ rancher mcapp prune-revisions --keep 5 --force appFoo
+`
+
+var pruneRevisionsFlags = []cli.Flag{
+	cli.IntFlag{
+		Name:  "keep",
+		Usage: "Number of the newest revisions to keep",
+		Value: 10,
+	},
+	forceFlag,
+}
+
+// prunableRevision is a revision of an app or of a multi-cluster app
+type prunableRevision struct {
+	name     string
+	created  time.Time
+	resource ntypes.Resource
+	delete   func() error
+}
+
+func appPruneRevisions(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+
+	c, err := GetClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	resource, err := Lookup(c, ctx.Args().First(), "app")
+	if err != nil {
+		return err
+	}
+	app, err := c.ProjectClient.App.ByID(resource.ID)
+	if err != nil {
+		return err
+	}
+
+	collection := &projectClient.AppRevisionCollection{}
+	if err := c.ProjectClient.GetLink(*resource, "revision", collection); err != nil {
+		return err
+	}
+
+	var revisions []prunableRevision
+	for _, rev := range collection.Data {
+		created, err := time.Parse(time.RFC3339, rev.Created)
+		if err != nil {
+			return err
+		}
+		revisions = append(revisions, prunableRevision{
+			name:     rev.Name,
+			created:  created,
+			resource: rev.Resource,
+			delete: func() error {
+				return c.ProjectClient.AppRevision.Delete(&rev)
+			},
+		})
+	}
+
+	return pruneRevisions(ctx, c, app.Name, app.AppRevisionID, revisions)
+}
+
+func multiClusterAppPruneRevisions(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	resource, app, err := searchForMcapp(c, ctx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	collection := &managementClient.MultiClusterAppRevisionCollection{}
+	if err := c.ManagementClient.GetLink(*resource, "revisions", collection); err != nil {
+		return err
+	}
+
+	var revisions []prunableRevision
+	for _, rev := range collection.Data {
+		created, err := time.Parse(time.RFC3339, rev.Created)
+		if err != nil {
+			return err
+		}
+		revisions = append(revisions, prunableRevision{
+			name:     rev.Name,
+			created:  created,
+			resource: rev.Resource,
+			delete: func() error {
+				return c.ManagementClient.MultiClusterAppRevision.Delete(&rev)
+			},
+		})
+	}
+
+	var current string
+	if app.Status != nil {
+		current = app.Status.RevisionID
+	}
+	return pruneRevisions(ctx, c, app.Name, current, revisions)
+}
+
+// pruneRevisions deletes the revisions beyond the newest --keep after
+// confirming the deletion.
+func pruneRevisions(ctx *cli.Context, c *cliclient.MasterClient, appName, current string, revisions []prunableRevision) error {
+	keep := ctx.Int("keep")
+	if keep < 1 {
+		return NewUsageError(errors.New("--keep must be at least 1"))
+	}
+
+	prune := revisionsToPrune(revisions, current, keep)
+	if len(prune) == 0 {
+		fmt.Printf("Nothing to prune, %s has %d revisions\n", appName, len(revisions))
+		return nil
+	}
+
+	byName := make(map[string]prunableRevision, len(prune))
+	names := make([]string, len(prune))
+	for i, rev := range prune {
+		byName[rev.name] = rev
+		names[i] = rev.name
+	}
+
+	return bulkDelete{
+		kind: "revisions",
+		args: names,
+		resolve: func(name string) (*bulkTarget, error) {
+			rev := byName[name]
+			return &bulkTarget{
+				resource:     rev.resource,
+				descriptions: []string{fmt.Sprintf("%s created %s", rev.name, rev.created.Format("02 Jan 2006 15:04:05 MST"))},
+				delete:       rev.delete,
+			}, nil
+		},
+	}.run(ctx, c)
+}
+
+// revisionsToPrune returns the revisions older than the newest keep, leaving
+// out the current revision.
+func revisionsToPrune(revisions []prunableRevision, current string, keep int) []prunableRevision {
+	sorted := make([]prunableRevision, len(revisions))
+	copy(sorted, revisions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].created.After(sorted[j].created)
+	})
+
+	var prune []prunableRevision
+	for i, rev := range sorted {
+		if i < keep || rev.name == current {
+			continue
+		}
+		prune = append(prune, rev)
+	}
+	return prune
+}
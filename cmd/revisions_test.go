@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevisionsToPrune(t *testing.T) {
+	now := time.Now()
+	revisions := []prunableRevision{
+		{name: "r-2", created: now.Add(-2 * time.Hour)},
+		{name: "r-4", created: now},
+		{name: "r-1", created: now.Add(-3 * time.Hour)},
+		{name: "r-3", created: now.Add(-time.Hour)},
+	}
+
+	names := func(revisions []prunableRevision) []string {
+		var names []string
+		for _, rev := range revisions {
+			names = append(names, rev.name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"r-2", "r-1"}, names(revisionsToPrune(revisions, "r-4", 2)))
+	// the current revision is kept even when it's old
+	assert.Equal(t, []string{"r-1"}, names(revisionsToPrune(revisions, "r-2", 2)))
+	assert.Empty(t, revisionsToPrune(revisions, "r-4", 10))
+}
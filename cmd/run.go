@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	clusterClient "github.com/rancher/rancher/pkg/client/generated/cluster/v3"
+	projectClient "github.com/rancher/rancher/pkg/client/generated/project/v3"
+	"github.com/urfave/cli"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const runDescription = `
+Runs an image as a deployment in the current project, creating its namespace when it doesn't
+exist. The namespace defaults to the name of the deployment.
+
+--expose creates a ClusterIP service named after the deployment for its ports, and
+--ingress-host creates an ingress routing a host name to the first of them.
+
+Example:
+	$ rancher run web --image nginx:1.25 --port 80 --replicas 2 --expose
+	$ rancher run api --image registry.example.com/api:2.1 --port 8080 --env LOG_LEVEL=debug --ingress-host api.example.com
+`
+
+func RunCommand() cli.Command {
+	return cli.Command{
+		Name:        "run",
+		Usage:       "Run an image as a deployment in the current project",
+		Description: runDescription,
+		ArgsUsage:   "NAME",
+		Action:      runImage,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "image",
+				Usage: "Image to run",
+			},
+			cli.StringFlag{
+				Name:  "namespace, n",
+				Usage: "Namespace of the deployment, defaults to its name",
+			},
+			cli.StringSliceFlag{
+				Name:  "port",
+				Usage: "Port the container listens on, as PORT or PORT/PROTOCOL, can be used multiple times",
+			},
+			cli.StringSliceFlag{
+				Name:  "env, e",
+				Usage: "Set an environment variable of the container as KEY=VALUE, can be used multiple times",
+			},
+			cli.Int64Flag{
+				Name:  "replicas",
+				Usage: "Number of pods of the deployment",
+				Value: 1,
+			},
+			cli.BoolFlag{
+				Name:  "expose",
+				Usage: "Create a service for the ports of the deployment",
+			},
+			cli.StringFlag{
+				Name:  "ingress-host",
+				Usage: "Create an ingress routing a host name to the first port, implies --expose",
+			},
+		},
+	}
+}
+
+func runImage(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showCommandUsage(ctx, "run")
+	}
+	name := ctx.Args().First()
+	if ctx.String("image") == "" {
+		return NewUsageError(errors.New("--image is required"))
+	}
+	if ctx.Int64("replicas") < 0 {
+		return NewUsageError(errors.New("--replicas can't be negative"))
+	}
+	ports, err := parseContainerPorts(ctx.StringSlice("port"))
+	if err != nil {
+		return err
+	}
+	env, err := parseKeyValues("env", ctx.StringSlice("env"))
+	if err != nil {
+		return err
+	}
+	ingressHost := ctx.String("ingress-host")
+	expose := ctx.Bool("expose") || ingressHost != ""
+	if expose && len(ports) == 0 {
+		return NewUsageError(errors.New("--expose and --ingress-host need the ports to expose, given with --port"))
+	}
+
+	c, err := GetClient(ctx)
+	if err != nil {
+		return err
+	}
+	namespace := valueOrDefault(ctx.String("namespace"), name)
+
+	needed := []permission{projectPermission(c, projectClient.DeploymentType, verbCreate)}
+	if expose {
+		needed = append(needed, projectPermission(c, projectClient.ServiceType, verbCreate))
+	}
+	if ingressHost != "" {
+		needed = append(needed, projectPermission(c, projectClient.IngressType, verbCreate))
+	}
+	if ctx.GlobalBool("check-permissions") {
+		existing, err := findNamespace(c, namespace)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			needed = append(needed, clusterPermission(c, clusterClient.NamespaceType, verbCreate))
+		}
+	}
+	if err := checkPermissions(ctx, needed...); err != nil {
+		return err
+	}
+
+	ns, err := createNamespace(c, namespace)
+	if err != nil {
+		return err
+	}
+
+	replicas := ctx.Int64("replicas")
+	deployment, err := c.ProjectClient.Deployment.Create(&projectClient.Deployment{
+		Name:        name,
+		NamespaceId: ns.ID,
+		Scale:       &replicas,
+		Containers: []projectClient.Container{{
+			Name:        name,
+			Image:       ctx.String("image"),
+			Environment: env,
+			Ports:       ports,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created deployment %s in namespace %s\n", deployment.Name, ns.Name)
+
+	if !expose {
+		return nil
+	}
+	service, err := c.ProjectClient.Service.Create(runService(name, ns.ID, ports))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created service %s for the ports %s\n", service.Name, strings.Join(ctx.StringSlice("port"), ", "))
+
+	if ingressHost == "" {
+		return nil
+	}
+	ingress, err := c.ProjectClient.Ingress.Create(runIngress(name, ns.ID, ingressHost, ports[0].ContainerPort))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created ingress %s routing %s to port %d\n", ingress.Name, ingressHost, ports[0].ContainerPort)
+	return nil
+}
+
+// parseContainerPorts parses the PORT[/PROTOCOL] of --port, the protocol
+// defaults to TCP.
+func parseContainerPorts(values []string) ([]projectClient.ContainerPort, error) {
+	var ports []projectClient.ContainerPort
+	for _, value := range values {
+		number, protocol, _ := strings.Cut(value, "/")
+		port, err := strconv.ParseInt(number, 10, 64)
+		if err != nil || port < 1 || port > 65535 {
+			return nil, NewUsageError(fmt.Errorf("invalid --port %q, expected a port number between 1 and 65535", value))
+		}
+		protocol = strings.ToUpper(valueOrDefault(protocol, "TCP"))
+		if protocol != "TCP" && protocol != "UDP" && protocol != "SCTP" {
+			return nil, NewUsageError(fmt.Errorf("invalid protocol of --port %q, expected tcp, udp or sctp", value))
+		}
+		ports = append(ports, projectClient.ContainerPort{
+			Name:          fmt.Sprintf("%d%s", port, strings.ToLower(protocol)),
+			ContainerPort: port,
+			Protocol:      protocol,
+		})
+	}
+	return ports, nil
+}
+
+// runService returns the ClusterIP service of the ports of the deployment
+// created by run.
+func runService(name, namespaceID string, ports []projectClient.ContainerPort) *projectClient.Service {
+	service := &projectClient.Service{
+		Name:              name,
+		NamespaceId:       namespaceID,
+		Kind:              "ClusterIP",
+		TargetWorkloadIDs: []string{"deployment:" + namespaceID + ":" + name},
+	}
+	for _, port := range ports {
+		service.Ports = append(service.Ports, projectClient.ServicePort{
+			Name:       port.Name,
+			Port:       port.ContainerPort,
+			Protocol:   port.Protocol,
+			TargetPort: intstr.FromInt(int(port.ContainerPort)),
+		})
+	}
+	return service
+}
+
+// runIngress returns the ingress routing host to a port of the service
+// created by run.
+func runIngress(name, namespaceID, host string, port int64) *projectClient.Ingress {
+	return &projectClient.Ingress{
+		Name:        name,
+		NamespaceId: namespaceID,
+		Rules: []projectClient.IngressRule{{
+			Host: host,
+			Paths: []projectClient.HTTPIngressPath{{
+				Path:     "/",
+				PathType: "Prefix",
+				Service: &projectClient.IngressServiceBackend{
+					Name: name,
+					Port: &projectClient.ServiceBackendPort{Number: port},
+				},
+			}},
+		}},
+	}
+}
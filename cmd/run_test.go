@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+
+	projectClient "github.com/rancher/rancher/pkg/client/generated/project/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestParseContainerPorts(t *testing.T) {
+	ports, err := parseContainerPorts([]string{"80", "53/udp"})
+	require.NoError(t, err)
+	assert.Equal(t, []projectClient.ContainerPort{
+		{Name: "80tcp", ContainerPort: 80, Protocol: "TCP"},
+		{Name: "53udp", ContainerPort: 53, Protocol: "UDP"},
+	}, ports)
+
+	for _, invalid := range []string{"http", "0", "70000", "80/icmp"} {
+		_, err := parseContainerPorts([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestRunService(t *testing.T) {
+	service := runService("web", "team-a", []projectClient.ContainerPort{
+		{Name: "80tcp", ContainerPort: 80, Protocol: "TCP"},
+	})
+	assert.Equal(t, []string{"deployment:team-a:web"}, service.TargetWorkloadIDs)
+	assert.Equal(t, []projectClient.ServicePort{
+		{Name: "80tcp", Port: 80, Protocol: "TCP", TargetPort: intstr.FromInt(80)},
+	}, service.Ports)
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"strings"
+)
+
+// DefaultSecretPatterns are the substrings of keys whose values are masked
+// when answers are printed and in the bodies logged by --debug-http-bodies.
+const DefaultSecretPatterns = "password,token,secret,key,credential"
+
+// answerFields are the fields of apps and multi-cluster apps holding answers
+var answerFields = map[string]bool{
+	"answers":          true,
+	"answersSetString": true,
+	"values":           true,
+	"valuesSetString":  true,
+}
+
+// answerMasking masks the values of secret answers in the output of the
+// commands, it is configured by ConfigureClients from --show-secrets and
+// --secret-patterns.
+var answerMasking = newSecretMasking(false, DefaultSecretPatterns)
+
+type secretMasking struct {
+	show     bool
+	patterns []string
+}
+
+// newSecretMasking returns a masking of the answers whose key contains one of
+// the comma separated patterns, show disables it.
+func newSecretMasking(show bool, patterns string) secretMasking {
+	m := secretMasking{show: show}
+	for _, pattern := range strings.Split(patterns, ",") {
+		if pattern = strings.ToLower(strings.TrimSpace(pattern)); pattern != "" {
+			m.patterns = append(m.patterns, pattern)
+		}
+	}
+	return m
+}
+
+func (m secretMasking) isSecret(key string) bool {
+	return !m.show && m.matches(key)
+}
+
+// matches reports whether key contains one of the patterns, whether or not
+// secrets are shown.
+func (m secretMasking) matches(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range m.patterns {
+		if strings.Contains(key, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// value returns the value of the answer key as it can be printed
+func (m secretMasking) value(key, value string) string {
+	if value != "" && m.isSecret(key) {
+		return redacted
+	}
+	return value
+}
+
+// body masks the secret answers in a decoded API request or resource, the
+// answers are found under the answerFields at any depth.
+func (m secretMasking) body(data interface{}) interface{} {
+	if m.show {
+		return data
+	}
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if answers, ok := value.(map[string]interface{}); ok && answerFields[key] {
+				v[key] = m.answers("", answers)
+				continue
+			}
+			v[key] = m.body(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = m.body(value)
+		}
+	}
+	return data
+}
+
+// redact masks the string values of the keys matching the patterns at any
+// depth of decoded JSON. Unlike body it ignores show, as logs are often shared.
+func (m secretMasking) redact(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if _, ok := value.(string); ok && m.matches(key) {
+				v[key] = redacted
+				continue
+			}
+			v[key] = m.redact(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = m.redact(value)
+		}
+	}
+	return data
+}
+
+// answers masks the secret answers of a decoded answers map, nested maps
+// hold the values of a dotted key.
+func (m secretMasking) answers(prefix string, answers map[string]interface{}) map[string]interface{} {
+	for key, value := range answers {
+		if nested, ok := value.(map[string]interface{}); ok {
+			answers[key] = m.answers(joinPath(prefix, key), nested)
+			continue
+		}
+		if value != nil && m.isSecret(joinPath(prefix, key)) {
+			answers[key] = redacted
+		}
+	}
+	return answers
+}
+
+// diffValue masks a value printed by diff at path when it is, or holds,
+// a secret answer.
+func (m secretMasking) diffValue(path string, value interface{}) interface{} {
+	if m.show || value == nil {
+		return value
+	}
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		if !answerFields[segment] {
+			continue
+		}
+		if i == len(segments)-1 {
+			// the value holds all the answers
+			return m.body(map[string]interface{}{segment: value}).(map[string]interface{})[segment]
+		}
+		if m.isSecret(strings.Join(segments[i+1:], ".")) {
+			return redacted
+		}
+		return value
+	}
+	return value
+}
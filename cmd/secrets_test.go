@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretMaskingValue(t *testing.T) {
+	masking := newSecretMasking(false, DefaultSecretPatterns)
+	assert.Equal(t, redacted, masking.value("mysql.rootPassword", "hunter2"))
+	assert.Equal(t, redacted, masking.value("ingress.tls.SecretName", "tls"))
+	assert.Equal(t, "3", masking.value("replicas", "3"))
+	assert.Equal(t, "", masking.value("auth.token", ""))
+
+	custom := newSecretMasking(false, " dsn , ")
+	assert.Equal(t, redacted, custom.value("database.DSN", "postgres://app:pw@db"))
+	assert.Equal(t, "hunter2", custom.value("mysql.rootPassword", "hunter2"))
+
+	shown := newSecretMasking(true, DefaultSecretPatterns)
+	assert.Equal(t, "hunter2", shown.value("mysql.rootPassword", "hunter2"))
+}
+
+func TestSecretMaskingBody(t *testing.T) {
+	body := `{
+		"name": "wordpress",
+		"answers": {"wordpressPassword": "hunter2", "replicas": "2"},
+		"targets": [{"projectId": "c-1:p-1"}],
+		"answersList": [{"projectId": "c-1:p-1", "values": {"db": {"password": "pw", "host": "db"}}}]
+	}`
+	var data interface{}
+	require.NoError(t, json.Unmarshal([]byte(body), &data))
+
+	masked, err := json.Marshal(newSecretMasking(false, DefaultSecretPatterns).body(data))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name": "wordpress",
+		"answers": {"wordpressPassword": "REDACTED", "replicas": "2"},
+		"targets": [{"projectId": "c-1:p-1"}],
+		"answersList": [{"projectId": "c-1:p-1", "values": {"db": {"password": "REDACTED", "host": "db"}}}]
+	}`, string(masked))
+}
+
+func TestSecretMaskingDiffValue(t *testing.T) {
+	masking := newSecretMasking(false, DefaultSecretPatterns)
+	assert.Equal(t, redacted, masking.diffValue("answers.mysql.rootPassword", "hunter2"))
+	assert.Equal(t, "2", masking.diffValue("answers.replicas", "2"))
+	assert.Equal(t, "c-1:p-1", masking.diffValue("targetProjectKey", "c-1:p-1"))
+	assert.Equal(t,
+		map[string]interface{}{"adminToken": redacted, "replicas": "2"},
+		masking.diffValue("answers", map[string]interface{}{"adminToken": "t0ken", "replicas": "2"}))
+}
@@ -60,9 +60,18 @@ be displayed and one can be selected.
 			{
 				Name:  "ls",
 				Usage: "List all servers",
+				Flags: []cli.Flag{
+					formatFlag,
+					filterFlag,
+					labelSelectorFlag,
+				},
 				Action: func(ctx *cli.Context) error {
-					format := ctx.String("format")
-					return serverLs(ctx.App.Writer, cfg, format)
+					return serverLs(cfg, &TableWriterConfig{
+						Writer:        ctx.App.Writer,
+						Format:        ctx.String("format"),
+						Filters:       ctx.StringSlice("filter"),
+						LabelSelector: ctx.String("label-selector"),
+					})
 				},
 			},
 			{
@@ -100,17 +109,18 @@ func serverCurrent(out io.Writer, cfg *config.Config) error {
 
 // serverDelete command to delete a server from the local config
 func serverDelete(cfg *config.Config, serverName string) error {
-	_, ok := cfg.Servers[serverName]
-	if !ok {
-		return errors.New("Server not found")
-	}
-	delete(cfg.Servers, serverName)
-
-	if cfg.CurrentServer == serverName {
-		cfg.CurrentServer = ""
-	}
+	err := cfg.Update(func(cfg *config.Config) error {
+		_, ok := cfg.Servers[serverName]
+		if !ok {
+			return notFoundErrorf("Server not found")
+		}
+		delete(cfg.Servers, serverName)
 
-	err := cfg.Write()
+		if cfg.CurrentServer == serverName {
+			cfg.CurrentServer = ""
+		}
+		return nil
+	})
 	if err != nil {
 		return err
 	}
@@ -119,12 +129,7 @@ func serverDelete(cfg *config.Config, serverName string) error {
 }
 
 // serverLs command to list rancher servers from the local config
-func serverLs(out io.Writer, cfg *config.Config, format string) error {
-	writerConfig := &TableWriterConfig{
-		Writer: out,
-		Format: format,
-	}
-
+func serverLs(cfg *config.Config, writerConfig *TableWriterConfig) error {
 	writer := NewTableWriterWithConfig([][]string{
 		{"CURRENT", "Current"},
 		{"NAME", "Name"},
@@ -143,18 +148,19 @@ func serverLs(out io.Writer, cfg *config.Config, format string) error {
 
 // serverSwitch will alter and write the config to switch rancher server.
 func serverSwitch(cf *config.Config, serverName string) error {
-	_, ok := cf.Servers[serverName]
-	if !ok {
-		return errors.New("Server not found")
-	}
-
-	if len(cf.Servers[serverName].Project) == 0 {
-		logrus.Warn("No context set; some commands will not work. Run 'rancher context switch'")
-	}
+	err := cf.Update(func(cf *config.Config) error {
+		_, ok := cf.Servers[serverName]
+		if !ok {
+			return notFoundErrorf("Server not found")
+		}
 
-	cf.CurrentServer = serverName
+		if len(cf.Servers[serverName].Project) == 0 {
+			logrus.Warn("No context set; some commands will not work. Run 'rancher context switch'")
+		}
 
-	err := cf.Write()
+		cf.CurrentServer = serverName
+		return nil
+	})
 	if err != nil {
 		return err
 	}
@@ -100,6 +100,7 @@ func TestServerDelete(t *testing.T) {
 			cfg := newTestConfig()
 			cfg.Path = tmpConfig.Name()
 			cfg.CurrentServer = tc.actualCurrentServer
+			assert.NoError(t, cfg.Write())
 
 			// do test and check resulting config
 			err = serverDelete(cfg, tc.serverToDelete)
@@ -163,6 +164,7 @@ func TestServerSwitch(t *testing.T) {
 			cfg := newTestConfig()
 			cfg.Path = tmpConfig.Name()
 			cfg.CurrentServer = tc.actualCurrentServer
+			assert.NoError(t, cfg.Write())
 
 			// do test and check resulting config
 			err = serverSwitch(cfg, tc.serverName)
@@ -182,6 +184,7 @@ func TestServerLs(t *testing.T) {
 		name           string
 		config         *config.Config
 		format         string
+		filters        []string
 		expectedOutput string
 		expectedErr    bool
 	}{
@@ -233,6 +236,13 @@ URL: https://myserver-3.com
 			expectedOutput: `https://myserver-1.com
 https://myserver-2.com
 https://myserver-3.com
+`,
+		},
+		{
+			name:    "list servers matching a filter",
+			format:  "{{.URL}}",
+			filters: []string{"name=server2"},
+			expectedOutput: `https://myserver-2.com
 `,
 		},
 		{
@@ -252,7 +262,11 @@ https://myserver-3.com
 			}
 
 			// do test and check resulting config
-			err := serverLs(out, tc.config, tc.format)
+			err := serverLs(tc.config, &TableWriterConfig{
+				Writer:  out,
+				Format:  tc.format,
+				Filters: tc.filters,
+			})
 			if tc.expectedErr {
 				assert.Error(t, err)
 			} else {
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rancher/cli/cliclient"
+	"github.com/rancher/norman/clientbase"
+	"github.com/rancher/norman/types"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/urfave/cli"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	serviceAccountType     = "serviceaccount"
+	secretType             = "secret"
+	clusterRoleBindingType = "rbac.authorization.k8s.io.clusterrolebinding"
+
+	serviceAccountTokenTimeout = 30 * time.Second
+)
+
+type roleRef struct {
+	APIGroup string `json:"apiGroup"`
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+}
+
+type rbacSubject struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type ClusterRoleBinding struct {
+	types.Resource
+	Metadata objectMeta    `json:"metadata,omitempty"`
+	RoleRef  roleRef       `json:"roleRef"`
+	Subjects []rbacSubject `json:"subjects"`
+}
+
+type ServiceAccount struct {
+	types.Resource
+	Metadata objectMeta `json:"metadata,omitempty"`
+}
+
+type Secret struct {
+	types.Resource
+	Metadata objectMeta `json:"metadata,omitempty"`
+	// the /v1 API serves the type of a secret as _type, type is the schema type
+	SecretType string            `json:"_type,omitempty"`
+	Data       map[string]string `json:"data,omitempty"`
+}
+
+// serviceAccountKubeConfig returns a kubeconfig authenticating as the service
+// account given as NAMESPACE/NAME with --serviceaccount. The kubeconfig talks
+// to the API server of the cluster directly, Rancher's proxy doesn't accept
+// service account tokens. The service account and a binding to --role are
+// created with --create, the token secret is created when missing.
+func serviceAccountKubeConfig(ctx *cli.Context, c *cliclient.MasterClient, cluster *managementClient.Cluster) ([]byte, error) {
+	namespace, name, ok := strings.Cut(ctx.String("serviceaccount"), "/")
+	if !ok || namespace == "" || name == "" {
+		return nil, fmt.Errorf("invalid service account %q, expected NAMESPACE/NAME", ctx.String("serviceaccount"))
+	}
+	if cluster.APIEndpoint == "" {
+		return nil, fmt.Errorf("the API endpoint of cluster %s is unknown", cluster.Name)
+	}
+
+	client, err := cliclient.NewClusterV1Client(c.UserConfig, cluster.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx.Bool("create") {
+		if err := ensureServiceAccount(client, namespace, name); err != nil {
+			return nil, err
+		}
+		if role := ctx.String("role"); role != "" {
+			if err := ensureClusterRoleBinding(client, namespace, name, role); err != nil {
+				return nil, err
+			}
+		}
+	} else if err := client.ByID(serviceAccountType, namespace+"/"+name, &ServiceAccount{}); err != nil {
+		if clientbase.IsNotFound(err) {
+			return nil, notFoundErrorf("service account %s/%s not found, use --create to create it", namespace, name)
+		}
+		return nil, err
+	}
+
+	secret, err := serviceAccountToken(client, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := base64.StdEncoding.DecodeString(secret.Data["token"])
+	if err != nil {
+		return nil, err
+	}
+	caData, err := base64.StdEncoding.DecodeString(secret.Data["ca.crt"])
+	if err != nil {
+		return nil, err
+	}
+	if len(caData) == 0 && cluster.CACert != "" {
+		if caData, err = base64.StdEncoding.DecodeString(cluster.CACert); err != nil {
+			return nil, err
+		}
+	}
+
+	contextName := cluster.Name + "-" + name
+	config := api.NewConfig()
+	config.Clusters[cluster.Name] = &api.Cluster{
+		Server:                   cluster.APIEndpoint,
+		CertificateAuthorityData: caData,
+	}
+	config.AuthInfos[name] = &api.AuthInfo{Token: string(token)}
+	config.Contexts[contextName] = &api.Context{
+		Cluster:   cluster.Name,
+		AuthInfo:  name,
+		Namespace: namespace,
+	}
+	config.CurrentContext = contextName
+	return clientcmd.Write(*config)
+}
+
+func ensureServiceAccount(client *clientbase.APIBaseClient, namespace, name string) error {
+	err := client.ByID(serviceAccountType, namespace+"/"+name, &ServiceAccount{})
+	if !clientbase.IsNotFound(err) {
+		return err
+	}
+	account := &ServiceAccount{}
+	account.Type = serviceAccountType
+	account.Metadata.Name = name
+	account.Metadata.Namespace = namespace
+	return client.Create(serviceAccountType, account, &ServiceAccount{})
+}
+
+func ensureClusterRoleBinding(client *clientbase.APIBaseClient, namespace, name, role string) error {
+	bindingName := fmt.Sprintf("%s-%s-%s", namespace, name, role)
+	err := client.ByID(clusterRoleBindingType, bindingName, &ClusterRoleBinding{})
+	if !clientbase.IsNotFound(err) {
+		return err
+	}
+	binding := &ClusterRoleBinding{
+		RoleRef:  roleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: role},
+		Subjects: []rbacSubject{{Kind: "ServiceAccount", Name: name, Namespace: namespace}},
+	}
+	binding.Type = clusterRoleBindingType
+	binding.Metadata.Name = bindingName
+	return client.Create(clusterRoleBindingType, binding, &ClusterRoleBinding{})
+}
+
+// serviceAccountToken returns the long-lived token secret of a service
+// account, creating it when missing. Kubernetes fills in the token of a new
+// secret shortly after it is created.
+func serviceAccountToken(client *clientbase.APIBaseClient, namespace, name string) (*Secret, error) {
+	id := namespace + "/" + name + "-token"
+	secret := &Secret{}
+	err := client.ByID(secretType, id, secret)
+	if clientbase.IsNotFound(err) {
+		create := &Secret{SecretType: "kubernetes.io/service-account-token"}
+		create.Type = secretType
+		create.Metadata.Name = name + "-token"
+		create.Metadata.Namespace = namespace
+		create.Metadata.Annotations = map[string]string{"kubernetes.io/service-account.name": name}
+		err = client.Create(secretType, create, secret)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	waitCtx, cancel := waitContext(serviceAccountTokenTimeout)
+	defer cancel()
+
+	err = pollUntil(waitCtx, newBackoff(pollInitialInterval, pollMaxInterval), func() (bool, error) {
+		if secret.Data["token"] == "" {
+			if err := client.ByID(secretType, id, secret); err != nil {
+				return false, err
+			}
+		}
+		return secret.Data["token"] != "", nil
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, timeoutErrorf("timed out waiting for the token of service account %s/%s", namespace, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
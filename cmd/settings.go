@@ -31,6 +31,12 @@ func SettingsCommand() cli.Command {
 				Flags: []cli.Flag{
 					formatFlag,
 					quietFlag,
+					filterFlag,
+					labelSelectorFlag,
+					sortByFlag,
+					noHeadersFlag,
+					limitFlag,
+					pageSizeFlag,
 				},
 			},
 			{
@@ -59,12 +65,12 @@ func SettingsCommand() cli.Command {
 }
 
 func settingsLs(ctx *cli.Context) error {
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	settings, err := c.ManagementClient.Setting.List(defaultListOpts(ctx))
+	settings, err := c.ManagementClient.Setting.List(filteredListOpts(ctx))
 	if err != nil {
 		return err
 	}
@@ -77,21 +83,27 @@ func settingsLs(ctx *cli.Context) error {
 
 	defer writer.Close()
 
-	for _, setting := range settings.Data {
+	err = forEachPage(ctx, settings, func(c *managementClient.SettingCollection) []managementClient.Setting {
+		return c.Data
+	}, func(setting managementClient.Setting) error {
 		writer.Write(&settingHolder{
 			ID:      setting.ID,
 			Setting: setting,
 		})
+		return nil
+	}, writer.Flush)
+	if err != nil {
+		return err
 	}
 	return writer.Err()
 }
 
 func settingGet(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowCommandHelp(ctx, "settings")
+		return showCommandUsage(ctx, "settings")
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -126,10 +138,10 @@ func settingGet(ctx *cli.Context) error {
 
 func settingSet(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowCommandHelp(ctx, "settings")
+		return showCommandUsage(ctx, "settings")
 	}
 
-	c, err := GetClient(ctx)
+	c, err := GetManagementClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -23,6 +23,8 @@ import (
 const sshDescription = `
 For any nodes created through Rancher using docker-machine,
 you can SSH into the node. This is not supported for any custom nodes.
+Windows nodes are logged into as Administrator unless another login
+name is given, their OpenSSH server must be enabled.
 Examples:
 	# SSH into a node by ID/name
 	$ rancher ssh nodeFoo
@@ -34,6 +36,9 @@ Examples:
 	$ rancher ssh login1@nodeFoo -- netstat -p tcp
 `
 
+// windowsSSHUser is the login name used for Windows nodes without an SSH user
+const windowsSSHUser = "Administrator"
+
 func SSHCommand() cli.Command {
 	return cli.Command{
 		Name:        "ssh",
@@ -61,7 +66,7 @@ func nodeSSH(ctx *cli.Context) error {
 	}
 
 	if ctx.NArg() == 0 {
-		return cli.ShowCommandHelp(ctx, "ssh")
+		return showCommandUsage(ctx, "ssh")
 	}
 
 	user := ctx.String("login")
@@ -87,6 +92,9 @@ func nodeSSH(ctx *cli.Context) error {
 	if user == "" {
 		user = sshNode.SshUser
 	}
+	if user == "" && getNodeOS(sshNode) == osWindows {
+		user = windowsSSHUser
+	}
 	ipAddress := sshNode.IPAddress
 	if ctx.Bool("external") {
 		ipAddress = sshNode.ExternalIPAddress
@@ -112,6 +120,10 @@ func getNodeAndKey(ctx *cli.Context, c *cliclient.MasterClient, nodeName string)
 		// Get the machine and use that instead.
 		machine, err := getMachineByNodeName(ctx, c, sshNode.NodeName)
 		if err != nil {
+			if getNodeOS(sshNode) == osWindows {
+				return sshNode, nil, fmt.Errorf("windows node [%s] wasn't created by Rancher, so it has no SSH key: "+
+					"connect to it with RDP or the SSH credentials of its owner", nodeName)
+			}
 			return sshNode, nil, fmt.Errorf("failed to find SSH key for node [%s]", nodeName)
 		}
 
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// apiStats collects the calls made to each API endpoint during a command,
+// printed with --stats.
+type apiStats struct {
+	start     time.Time
+	now       func() time.Time
+	lock      sync.Mutex
+	endpoints map[string]*endpointStats
+}
+
+type endpointStats struct {
+	Calls    int
+	Bytes    int64
+	Duration time.Duration
+}
+
+// requestStats is set when --stats is used
+var requestStats *apiStats
+
+func newAPIStats(now func() time.Time) *apiStats {
+	return &apiStats{
+		start:     now(),
+		now:       now,
+		endpoints: map[string]*endpointStats{},
+	}
+}
+
+func (s *apiStats) record(endpoint string, calls int, bytes int64, duration time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	stats, ok := s.endpoints[endpoint]
+	if !ok {
+		stats = &endpointStats{}
+		s.endpoints[endpoint] = stats
+	}
+	stats.Calls += calls
+	stats.Bytes += bytes
+	stats.Duration += duration
+}
+
+// write prints the calls of each endpoint, the slowest first, and the totals.
+func (s *apiStats) write(out io.Writer) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	endpoints := make([]string, 0, len(s.endpoints))
+	var total endpointStats
+	for endpoint, stats := range s.endpoints {
+		endpoints = append(endpoints, endpoint)
+		total.Calls += stats.Calls
+		total.Bytes += stats.Bytes
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		a, b := s.endpoints[endpoints[i]], s.endpoints[endpoints[j]]
+		if a.Duration != b.Duration {
+			return a.Duration > b.Duration
+		}
+		return endpoints[i] < endpoints[j]
+	})
+
+	writer := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+	fmt.Fprintln(writer, "ENDPOINT\tCALLS\tBYTES\tTIME")
+	for _, endpoint := range endpoints {
+		stats := s.endpoints[endpoint]
+		fmt.Fprintf(writer, "%s\t%d\t%s\t%s\n", endpoint, stats.Calls, formatBytes(stats.Bytes), stats.Duration.Round(time.Millisecond))
+	}
+	fmt.Fprintf(writer, "TOTAL\t%d\t%s\t%s\n", total.Calls, formatBytes(total.Bytes), s.now().Sub(s.start).Round(time.Millisecond))
+	return writer.Flush()
+}
+
+// WriteRequestStats prints the API calls made by the command when --stats is
+// used.
+func WriteRequestStats(out io.Writer) error {
+	if requestStats == nil {
+		return nil
+	}
+	return requestStats.write(out)
+}
+
+// statsTransport records the calls, bytes sent and received and the time
+// spent of every API request. The time of a request includes reading its
+// response.
+type statsTransport struct {
+	next  http.RoundTripper
+	stats *apiStats
+}
+
+func newStatsTransport(next http.RoundTripper, stats *apiStats) http.RoundTripper {
+	return &statsTransport{
+		next:  next,
+		stats: stats,
+	}
+}
+
+func (t *statsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := statsEndpoint(req)
+	var sent int64
+	if req.ContentLength > 0 {
+		sent = req.ContentLength
+	}
+
+	start := t.stats.now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.Body == nil {
+		t.stats.record(endpoint, 1, sent, t.stats.now().Sub(start))
+		return resp, err
+	}
+	resp.Body = &statsBody{
+		ReadCloser: resp.Body,
+		done: func(received int64) {
+			t.stats.record(endpoint, 1, sent+received, t.stats.now().Sub(start))
+		},
+	}
+	return resp, nil
+}
+
+// statsBody counts the bytes read from a response body and reports them when
+// the body is closed.
+type statsBody struct {
+	io.ReadCloser
+	read int64
+	once sync.Once
+	done func(int64)
+}
+
+func (b *statsBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	return n, err
+}
+
+func (b *statsBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.done(b.read) })
+	return err
+}
+
+// statsEndpoint returns the method and path of a request with the IDs
+// replaced, so that the calls to the same kind of resource are grouped. The
+// API paths alternate types and IDs after the version, such as
+// /v3/project/c-1:p-1/apps/p-1:app. Actions are kept as they are different
+// endpoints.
+func statsEndpoint(req *http.Request) string {
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	for i := 2; i < len(segments); i += 2 {
+		segments[i] = "{id}"
+	}
+	endpoint := req.Method + " /" + strings.Join(segments, "/")
+	if action := req.URL.Query().Get("action"); action != "" {
+		endpoint += "?action=" + action
+	}
+	return endpoint
+}
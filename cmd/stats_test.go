@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsEndpoint(t *testing.T) {
+	tt := []struct {
+		method   string
+		url      string
+		expected string
+	}{
+		{http.MethodGet, "https://rancher/v3/clusters?limit=1000", "GET /v3/clusters"},
+		{http.MethodGet, "https://rancher/v3/clusters/c-abcde", "GET /v3/clusters/{id}"},
+		{http.MethodGet, "https://rancher/v3/project/c-1:p-1/apps/p-1:wordpress", "GET /v3/project/{id}/apps/{id}"},
+		{http.MethodPost, "https://rancher/v3/clusters/c-abcde?action=generateKubeconfig", "POST /v3/clusters/{id}?action=generateKubeconfig"},
+	}
+	for _, tc := range tt {
+		req, err := http.NewRequest(tc.method, tc.url, nil)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, statsEndpoint(req))
+	}
+}
+
+type fakeRoundTripper func(*http.Request) (*http.Response, error)
+
+func (f fakeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestStatsTransport(t *testing.T) {
+	now := time.Unix(0, 0)
+	stats := newAPIStats(func() time.Time { return now })
+	transport := newStatsTransport(fakeRoundTripper(func(*http.Request) (*http.Response, error) {
+		now = now.Add(100 * time.Millisecond)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(strings.Repeat("x", 2048)))}, nil
+	}), stats)
+
+	for _, url := range []string{"https://rancher/v3/clusters/c-1", "https://rancher/v3/clusters/c-2"} {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		resp, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		_, err = io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, stats.write(&out))
+	assert.Equal(t, `ENDPOINT                CALLS     BYTES     TIME
+GET /v3/clusters/{id}   2         4Ki       200ms
+TOTAL                   2         4Ki       200ms
+`, out.String())
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rancher/cli/cliclient"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+)
+
+// validateTargets checks that the clusters of the target projects of a
+// multi-cluster app can run it: they must be active, connected to Rancher
+// and have a node accepting workloads. The error lists every unhealthy
+// target.
+func validateTargets(c *cliclient.MasterClient, projectIDs []string) error {
+	problemsByCluster := map[string][]string{}
+	var unhealthy []string
+	for _, projectID := range projectIDs {
+		project, err := getProjectByID(c, projectID)
+		if err != nil {
+			return err
+		}
+
+		clusterID := project.ClusterID
+		problems, ok := problemsByCluster[clusterID]
+		if !ok {
+			cluster, err := getClusterByID(c, clusterID)
+			if err != nil {
+				return err
+			}
+			filter := defaultListOpts(nil)
+			filter.Filters["clusterId"] = clusterID
+			nodes, err := c.ManagementClient.Node.List(filter)
+			if err != nil {
+				return err
+			}
+			nodeData, err := listAll(nil, nodes, func(c *managementClient.NodeCollection) []managementClient.Node { return c.Data })
+			if err != nil {
+				return err
+			}
+			problems = clusterTargetProblems(cluster, nodeData)
+			problemsByCluster[clusterID] = problems
+		}
+
+		if project.State != "" && project.State != "active" {
+			problems = append([]string{"project is " + project.State}, problems...)
+		}
+		if len(problems) > 0 {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (project %s): %s", projectID, project.Name, strings.Join(problems, ", ")))
+		}
+	}
+
+	if len(unhealthy) > 0 {
+		return fmt.Errorf("unhealthy targets, the multi-cluster app wasn't created:\n  %s", strings.Join(unhealthy, "\n  "))
+	}
+	return nil
+}
+
+// clusterTargetProblems returns why apps can't be deployed in cluster, none
+// when it is healthy.
+func clusterTargetProblems(cluster *managementClient.Cluster, nodes []managementClient.Node) []string {
+	var problems []string
+	if cluster.State != "active" {
+		problem := fmt.Sprintf("cluster %s is %s", cluster.Name, cluster.State)
+		if cluster.TransitioningMessage != "" {
+			problem += ": " + FormatMessage(cluster.TransitioningMessage)
+		}
+		problems = append(problems, problem)
+	}
+
+	for _, condition := range cluster.Conditions {
+		if (condition.Type == "Ready" || condition.Type == "Connected") && condition.Status == "False" {
+			problem := fmt.Sprintf("cluster %s isn't %s", cluster.Name, strings.ToLower(condition.Type))
+			if message := valueOrDefault(condition.Message, condition.Reason); message != "" {
+				problem += ": " + FormatMessage(message)
+			}
+			problems = append(problems, problem)
+		}
+	}
+
+	// nodes are only checked when Rancher knows them
+	if len(nodes) == 0 {
+		return problems
+	}
+	for _, node := range nodes {
+		if schedulableNode(node) {
+			return problems
+		}
+	}
+	return append(problems, fmt.Sprintf("no node of cluster %s accepts workloads, they are cordoned or tainted", cluster.Name))
+}
+
+// schedulableNode reports whether pods without tolerations can be scheduled
+// on node.
+func schedulableNode(node managementClient.Node) bool {
+	if node.Unschedulable || (node.State != "" && node.State != "active") {
+		return false
+	}
+	for _, taint := range node.Taints {
+		if taint.Effect == "NoSchedule" || taint.Effect == "NoExecute" {
+			return false
+		}
+	}
+	return true
+}
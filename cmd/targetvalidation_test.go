@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterTargetProblems(t *testing.T) {
+	healthy := &managementClient.Cluster{Name: "prod", State: "active"}
+	schedulable := managementClient.Node{State: "active"}
+	tainted := managementClient.Node{State: "active", Taints: []managementClient.Taint{{Key: "dedicated", Effect: "NoSchedule"}}}
+	cordoned := managementClient.Node{State: "active", Unschedulable: true}
+
+	assert.Empty(t, clusterTargetProblems(healthy, []managementClient.Node{tainted, schedulable}))
+	assert.Empty(t, clusterTargetProblems(healthy, nil))
+	assert.Equal(t, []string{"no node of cluster prod accepts workloads, they are cordoned or tainted"},
+		clusterTargetProblems(healthy, []managementClient.Node{tainted, cordoned}))
+
+	disconnected := &managementClient.Cluster{
+		Name:                 "edge",
+		State:                "unavailable",
+		TransitioningMessage: "Cluster agent is not connected",
+		Conditions: []managementClient.ClusterCondition{
+			{Type: "Ready", Status: "True"},
+			{Type: "Connected", Status: "False", Reason: "Disconnected"},
+		},
+	}
+	assert.Equal(t, []string{
+		"cluster edge is unavailable: Cluster agent is not connected",
+		"cluster edge isn't connected: Disconnected",
+	}, clusterTargetProblems(disconnected, []managementClient.Node{schedulable}))
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/rancher/cli/cliclient"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/urfave/cli"
+)
+
+const templateShowDescription = `
+Show all available versions of an app template. With --format json or yaml the metadata of the
+template is shown with its versions, including the maintainers, sources, icon and project URL
+from the chart of its default version.
+
+Example:
+	$ rancher app show-template cattle-global-data:library-wordpress
+	$ rancher app show-template -o json cattle-global-data:library-wordpress
+`
+
+var templateShowFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "format,o",
+		Usage: "'json' or 'yaml' to show the metadata of the template",
+	},
+}
+
+// TemplateMetadata describes a template for catalogs built over the output of
+// list-templates and show-template.
+type TemplateMetadata struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	Catalog        string            `json:"catalog,omitempty"`
+	Categories     []string          `json:"categories,omitempty"`
+	Description    string            `json:"description,omitempty"`
+	Maintainers    []ChartMaintainer `json:"maintainers,omitempty"`
+	Sources        []string          `json:"sources,omitempty"`
+	Icon           string            `json:"icon,omitempty"`
+	ProjectURL     string            `json:"projectURL,omitempty"`
+	DefaultVersion string            `json:"defaultVersion,omitempty"`
+	Versions       []string          `json:"versions,omitempty"`
+}
+
+// ChartMaintainer is a maintainer listed in a Chart.yaml
+type ChartMaintainer struct {
+	Name  string `json:"name,omitempty" yaml:"name,omitempty"`
+	Email string `json:"email,omitempty" yaml:"email,omitempty"`
+	URL   string `json:"url,omitempty" yaml:"url,omitempty"`
+}
+
+// templateMetadata returns the metadata of a template known without reading
+// its chart.
+func templateMetadata(template managementClient.Template) TemplateMetadata {
+	metadata := TemplateMetadata{
+		ID:             template.ID,
+		Name:           template.Name,
+		Catalog:        valueOrDefault(template.CatalogID, valueOrDefault(template.ClusterCatalogID, template.ProjectCatalogID)),
+		Categories:     template.Categories,
+		Description:    template.Description,
+		Icon:           valueOrDefault(template.Icon, template.Links["icon"]),
+		ProjectURL:     template.ProjectURL,
+		DefaultVersion: template.DefaultVersion,
+	}
+	if template.Maintainer != "" {
+		metadata.Maintainers = []ChartMaintainer{{Name: template.Maintainer}}
+	}
+	return metadata
+}
+
+// addChartMetadata completes metadata with the Chart.yaml of the default
+// version of the template, which lists all maintainers and the sources.
+func addChartMetadata(c *cliclient.MasterClient, template *managementClient.Template, metadata *TemplateMetadata) error {
+	if template.DefaultTemplateVersionID == "" {
+		return nil
+	}
+	templateVersion, err := c.ManagementClient.TemplateVersion.ByID(template.DefaultTemplateVersionID)
+	if err != nil {
+		return err
+	}
+	chart, err := parseChartFile(templateVersion.Files)
+	if err != nil || chart == nil {
+		return err
+	}
+
+	if len(chart.Maintainers) > 0 {
+		metadata.Maintainers = chart.Maintainers
+	}
+	metadata.Sources = chart.Sources
+	metadata.Icon = valueOrDefault(metadata.Icon, chart.Icon)
+	metadata.ProjectURL = valueOrDefault(metadata.ProjectURL, chart.Home)
+	return nil
+}
+
+// parseChartFile returns the Chart.yaml of the files of a template version,
+// nil when there is none. The files are base64 encoded.
+func parseChartFile(files map[string]string) (*chartMetadata, error) {
+	for name, content := range files {
+		if !strings.HasSuffix(name, "/Chart.yaml") && !strings.HasSuffix(name, "/Chart.yml") {
+			continue
+		}
+		// the Chart.yaml of a subchart doesn't describe the template
+		if strings.Contains(name, "/charts/") {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			decoded = []byte(content)
+		}
+		chart := &chartMetadata{}
+		if err := yaml.Unmarshal(decoded, chart); err != nil {
+			return nil, err
+		}
+		return chart, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChartFile(t *testing.T) {
+	chart := `name: wordpress
+version: 10.0.1
+home: https://wordpress.org
+icon: https://example.com/wordpress.svg
+sources:
+- https://github.com/bitnami/bitnami-docker-wordpress
+maintainers:
+- name: Bitnami
+  email: containers@bitnami.com
+`
+	subchart := "name: mariadb\nversion: 7.3.1\nsources:\n- https://github.com/bitnami/bitnami-docker-mariadb\n"
+
+	files := map[string]string{
+		"wordpress/charts/mariadb/Chart.yaml": base64.StdEncoding.EncodeToString([]byte(subchart)),
+		"wordpress/Chart.yaml":                base64.StdEncoding.EncodeToString([]byte(chart)),
+		"wordpress/values.yaml":               base64.StdEncoding.EncodeToString([]byte("image: wordpress\n")),
+	}
+
+	parsed, err := parseChartFile(files)
+	require.NoError(t, err)
+	require.NotNil(t, parsed)
+	assert.Equal(t, "https://wordpress.org", parsed.Home)
+	assert.Equal(t, "https://example.com/wordpress.svg", parsed.Icon)
+	assert.Equal(t, []string{"https://github.com/bitnami/bitnami-docker-wordpress"}, parsed.Sources)
+	assert.Equal(t, []ChartMaintainer{{Name: "Bitnami", Email: "containers@bitnami.com"}}, parsed.Maintainers)
+
+	parsed, err = parseChartFile(map[string]string{"wordpress/values.yaml": ""})
+	require.NoError(t, err)
+	assert.Nil(t, parsed)
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	gover "github.com/hashicorp/go-version"
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+// templateVersionList is the parsed version list of a template, sorted oldest
+// first, along with the version link of each version.
+type templateVersionList struct {
+	versions []*gover.Version
+	links    map[*gover.Version]string
+}
+
+// templateVersionCache holds the version list of every template parsed during
+// this invocation, keyed by template ID. Templates are always fetched filtered
+// by the server's rancherVersion, so one list per ID is enough.
+var templateVersionCache sync.Map
+
+// getTemplateVersionList parses the version links of template, reusing the
+// list from an earlier call for the same template.
+func getTemplateVersionList(template *managementClient.Template) (*templateVersionList, error) {
+	if cached, ok := templateVersionCache.Load(template.ID); ok && template.ID != "" {
+		return cached.(*templateVersionList), nil
+	}
+
+	list := &templateVersionList{links: make(map[*gover.Version]string, len(template.VersionLinks))}
+	for key, link := range template.VersionLinks {
+		v, err := gover.NewVersion(key)
+		if err != nil {
+			return nil, err
+		}
+		list.versions = append(list.versions, v)
+		list.links[v] = link
+	}
+	sort.Sort(gover.Collection(list.versions))
+
+	if template.ID != "" {
+		templateVersionCache.Store(template.ID, list)
+	}
+	return list, nil
+}
+
+// link returns the version link for version, or for the newest version when
+// version is empty. Versions are compared semantically so "1.2" matches
+// "1.2.0". An empty link is returned when the template has no such version.
+func (l *templateVersionList) link(version string) (string, error) {
+	if len(l.versions) == 0 {
+		return "", errors.New("no versions found for this template (the chart you are trying to install may be intentionally hidden or deprecated for your Rancher version)")
+	}
+	if version == "" {
+		return l.links[l.versions[len(l.versions)-1]], nil
+	}
+
+	for _, v := range l.versions {
+		if v.Original() == version {
+			return l.links[v], nil
+		}
+	}
+	want, err := gover.NewVersion(version)
+	if err != nil {
+		return "", nil
+	}
+	for _, v := range l.versions {
+		if v.Equal(want) {
+			return l.links[v], nil
+		}
+	}
+	return "", nil
+}
+
+// sortTemplateVersions returns the versions of template, oldest first
+func sortTemplateVersions(template *managementClient.Template) ([]*gover.Version, error) {
+	list, err := getTemplateVersionList(template)
+	if err != nil {
+		return nil, err
+	}
+	return list.versions, nil
+}
+
+// resolveTemplateVersionID returns the ID of the template version requested
+// with --version, or of the newest version when the flag is unset. command is
+// the parent command name, used to point the user at show-template.
+func resolveTemplateVersionID(ctx *cli.Context, template *managementClient.Template, templateName, command string) (string, error) {
+	list, err := getTemplateVersionList(template)
+	if err != nil {
+		return "", err
+	}
+
+	userVersion := ctx.String("version")
+	link, err := list.link(userVersion)
+	if err != nil {
+		return "", err
+	}
+	if link == "" {
+		return "", fmt.Errorf(
+			"version %s for template %s is invalid, run 'rancher %s show-template %s' for a list of versions",
+			userVersion,
+			templateName,
+			command,
+			templateName,
+		)
+	}
+	return templateVersionIDFromVersionLink(link), nil
+}
+
+// templateVersionCompletion prints the versions of the template named in an
+// install command, for shell completion of --version. The CLI moves a flag
+// missing its value in front of the positional arguments, so while completing
+// `install TEMPLATE --version` the template name ends up as the flag value.
+func templateVersionCompletion(ctx *cli.Context) {
+	templateName := ctx.Args().First()
+	if templateName == "" {
+		templateName = ctx.String("version")
+	}
+	if templateName == "" || resolveTemplatePath(templateName) {
+		return
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return
+	}
+	resource, err := Lookup(c, templateName, managementClient.TemplateType)
+	if err != nil {
+		return
+	}
+	template, err := getFilteredTemplate(ctx, c, resource.ID)
+	if err != nil {
+		return
+	}
+	versions, err := sortTemplateVersions(template)
+	if err != nil {
+		return
+	}
+	for i := len(versions) - 1; i >= 0; i-- {
+		fmt.Fprintln(ctx.App.Writer, versions[i].Original())
+	}
+}
+
+// upgradeConstraint returns why the chart doesn't support upgrading from the
+// current template version to version, or "" when it does. The versions a
+// template version can be upgraded to are its upgradeVersionLinks, without
+// any the constraints aren't known. Upgrading to the current version is how
+// the answers of an app are changed.
+func upgradeConstraint(current *managementClient.TemplateVersion, version string) string {
+	if version == current.Version {
+		return ""
+	}
+	target, err := gover.NewVersion(version)
+	if err != nil {
+		return ""
+	}
+	from, err := gover.NewVersion(current.Version)
+	if err != nil {
+		return ""
+	}
+	if target.LessThan(from) {
+		return "downgrade"
+	}
+	if len(current.UpgradeVersionLinks) == 0 {
+		return ""
+	}
+	for key := range current.UpgradeVersionLinks {
+		if v, err := gover.NewVersion(key); err == nil && v.Equal(target) {
+			return ""
+		}
+	}
+	return "no upgrade from " + current.Version
+}
+
+// checkUpgradeVersion fails when the chart doesn't support upgrading from
+// current to version, unless force is set, and warns about a deprecated
+// version.
+func checkUpgradeVersion(current, target *managementClient.TemplateVersion, force bool) error {
+	if note := deprecationNote(target.RancherMaxVersion); note != "" {
+		logrus.Warnf("version %s is %s", target.Version, note)
+	}
+	constraint := upgradeConstraint(current, target.Version)
+	if constraint == "" || force {
+		return nil
+	}
+	if constraint == "downgrade" {
+		return fmt.Errorf("version %s is older than the current version %s, use rollback or --force to downgrade", target.Version, current.Version)
+	}
+	return fmt.Errorf("the chart doesn't support upgrading from version %s to %s, use --force to upgrade anyway", current.Version, target.Version)
+}
+
+// deprecationNote describes a template version with a rancherMaxVersion,
+// which Rancher stops offering once the server is upgraded past it.
+func deprecationNote(rancherMaxVersion string) string {
+	if rancherMaxVersion == "" {
+		return ""
+	}
+	return "deprecated, supported up to Rancher " + rancherMaxVersion
+}
+
+// versionNotes returns the notes shown for version by --show-versions
+func versionNotes(template *managementClient.Template, current *managementClient.TemplateVersion, version string) string {
+	var notes []string
+	for _, spec := range template.Versions {
+		if spec.Version == version {
+			if note := deprecationNote(spec.RancherMaxVersion); note != "" {
+				notes = append(notes, note)
+			}
+			break
+		}
+	}
+	if constraint := upgradeConstraint(current, version); constraint != "" {
+		notes = append(notes, constraint)
+	}
+	return strings.Join(notes, ", ")
+}
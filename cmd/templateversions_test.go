@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"testing"
+
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateVersionListLink(t *testing.T) {
+	template := &managementClient.Template{
+		VersionLinks: map[string]string{
+			"1.2":     "https://rancher/v3/templateVersions/cattle-global-data:library-redis-1.2",
+			"v1.10.0": "https://rancher/v3/templateVersions/cattle-global-data:library-redis-v1.10.0",
+			"1.9.3":   "https://rancher/v3/templateVersions/cattle-global-data:library-redis-1.9.3",
+		},
+	}
+	template.ID = "cattle-global-data:library-redis-link-test"
+
+	list, err := getTemplateVersionList(template)
+	require.NoError(t, err)
+
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"", "cattle-global-data:library-redis-v1.10.0"},
+		{"1.9.3", "cattle-global-data:library-redis-1.9.3"},
+		{"1.2.0", "cattle-global-data:library-redis-1.2"},
+		{"1.10.0", "cattle-global-data:library-redis-v1.10.0"},
+		{"2.0.0", ""},
+		{"not-a-version", ""},
+	}
+	for _, tt := range tests {
+		link, err := list.link(tt.version)
+		require.NoError(t, err)
+		if tt.want == "" {
+			assert.Empty(t, link, tt.version)
+			continue
+		}
+		assert.Equal(t, tt.want, templateVersionIDFromVersionLink(link), tt.version)
+	}
+
+	template.VersionLinks = nil
+	cached, err := getTemplateVersionList(template)
+	require.NoError(t, err)
+	assert.Same(t, list, cached, "the version list is parsed once per template")
+
+	_, err = (&templateVersionList{}).link("")
+	assert.Error(t, err)
+}
+
+func TestUpgradeConstraint(t *testing.T) {
+	current := &managementClient.TemplateVersion{
+		Version: "1.2.0",
+		UpgradeVersionLinks: map[string]string{
+			"1.3.0": "https://rancher/v3/templateversions/mysql-1.3.0",
+		},
+	}
+
+	assert.Equal(t, "", upgradeConstraint(current, "1.2.0"))
+	assert.Equal(t, "", upgradeConstraint(current, "1.3"))
+	assert.Equal(t, "downgrade", upgradeConstraint(current, "1.1.0"))
+	assert.Equal(t, "no upgrade from 1.2.0", upgradeConstraint(current, "2.0.0"))
+
+	// without upgradeVersionLinks the constraints aren't known
+	current.UpgradeVersionLinks = nil
+	assert.Equal(t, "", upgradeConstraint(current, "2.0.0"))
+
+	assert.NoError(t, checkUpgradeVersion(current, &managementClient.TemplateVersion{Version: "2.0.0"}, false))
+	assert.EqualError(t, checkUpgradeVersion(current, &managementClient.TemplateVersion{Version: "1.0.0"}, false),
+		"version 1.0.0 is older than the current version 1.2.0, use rollback or --force to downgrade")
+	assert.NoError(t, checkUpgradeVersion(current, &managementClient.TemplateVersion{Version: "1.0.0"}, true))
+}
+
+func TestVersionNotes(t *testing.T) {
+	template := &managementClient.Template{
+		Versions: []managementClient.TemplateVersionSpec{
+			{Version: "1.1.0", RancherMaxVersion: "2.5.99"},
+			{Version: "1.2.0"},
+			{Version: "2.0.0"},
+		},
+	}
+	current := &managementClient.TemplateVersion{
+		Version:             "1.2.0",
+		UpgradeVersionLinks: map[string]string{"1.3.0": ""},
+	}
+
+	assert.Equal(t, "deprecated, supported up to Rancher 2.5.99, downgrade", versionNotes(template, current, "1.1.0"))
+	assert.Equal(t, "", versionNotes(template, current, "1.2.0"))
+	assert.Equal(t, "no upgrade from 1.2.0", versionNotes(template, current, "2.0.0"))
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"errors"
+
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/urfave/cli"
+)
+
+const createTokenDescription = `
+Creates an API key of the current user and prints it. The key can't be read back afterwards.
+
+With --cluster the key is scoped to the cluster: it's only accepted for the Kubernetes API of
+that cluster, reached through Rancher, so that an automation job working on one cluster gets
+no access to the others or to the Rancher API. The key expires after --ttl, within the
+maximum the server allows; without --ttl it lasts as long as the server allows.
+
+Example:
+	# Create a key for the jobs of the production cluster, expiring in 30 days
+	$ rancher token create --cluster production --ttl 720h --description "nightly backups"
+
+	# Print only the key
+	$ rancher token create --cluster production --format '{{.Token}}'
+`
+
+// TokenData is an API key printed by token create
+type TokenData struct {
+	ID          string
+	Cluster     string
+	Description string
+	Expires     string
+	Token       string
+}
+
+func createTokenCommand() cli.Command {
+	return cli.Command{
+		Name:        "create",
+		Usage:       "Create an API key, optionally scoped to a cluster",
+		Description: createTokenDescription,
+		ArgsUsage:   "None",
+		Action:      tokenCreate,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "cluster",
+				Usage: "Name or ID of the cluster to scope the key to",
+			},
+			cli.StringFlag{
+				Name:  "description",
+				Usage: "Description of the key, such as what it's used for",
+			},
+			cli.DurationFlag{
+				Name:  "ttl",
+				Usage: "How long the key is valid, e.g. 720h. Defaults to the longest the server allows",
+			},
+			formatFlag,
+			noHeadersFlag,
+		},
+	}
+}
+
+func tokenCreate(ctx *cli.Context) error {
+	if ctx.Duration("ttl") < 0 {
+		return NewUsageError(errors.New("--ttl can't be negative"))
+	}
+	// the key is a secret and is only valid when freshly created
+	listingCache.disable()
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	token := &managementClient.Token{
+		Description: ctx.String("description"),
+		TTLMillis:   ctx.Duration("ttl").Milliseconds(),
+	}
+	clusterName := ""
+	if cluster := ctx.String("cluster"); cluster != "" {
+		resource, err := Lookup(c, cluster, managementClient.ClusterType)
+		if err != nil {
+			return err
+		}
+		token.ClusterID = resource.ID
+		clusterName = cluster
+	}
+
+	if err := checkPermissions(ctx, managementPermission(c, managementClient.TokenType, verbCreate)); err != nil {
+		return err
+	}
+	created, err := c.ManagementClient.Token.Create(token)
+	if err != nil {
+		return err
+	}
+
+	writer := NewTableWriter([][]string{
+		{"ID", "ID"},
+		{"CLUSTER", "Cluster"},
+		{"DESCRIPTION", "Description"},
+		{"EXPIRES", "Expires"},
+		{"TOKEN", "Token"},
+	}, ctx)
+	writer.Write(newTokenData(created, clusterName))
+	writer.Close()
+	return writer.Err()
+}
+
+// newTokenData returns the printed fields of a created token, clusterName is
+// the cluster it is scoped to as given by the user.
+func newTokenData(token *managementClient.Token, clusterName string) *TokenData {
+	return &TokenData{
+		ID:          token.ID,
+		Cluster:     valueOrDefault(clusterName, valueOrDefault(token.ClusterID, "all")),
+		Description: token.Description,
+		Expires:     valueOrDefault(token.ExpiresAt, "never"),
+		Token:       token.Token,
+	}
+}
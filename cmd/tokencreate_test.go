@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"testing"
+
+	managementClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTokenData(t *testing.T) {
+	tt := []struct {
+		name         string
+		token        *managementClient.Token
+		clusterName  string
+		expectedData *TokenData
+	}{
+		{
+			name: "scoped to a cluster",
+			token: &managementClient.Token{
+				ClusterID:   "c-abc12",
+				Description: "nightly backups",
+				ExpiresAt:   "2026-11-15T10:00:00Z",
+				Token:       "token-xyz34:secret",
+			},
+			clusterName: "production",
+			expectedData: &TokenData{
+				Cluster:     "production",
+				Description: "nightly backups",
+				Expires:     "2026-11-15T10:00:00Z",
+				Token:       "token-xyz34:secret",
+			},
+		},
+		{
+			name:  "unscoped and never expiring",
+			token: &managementClient.Token{Token: "token-xyz34:secret"},
+			expectedData: &TokenData{
+				Cluster: "all",
+				Expires: "never",
+				Token:   "token-xyz34:secret",
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedData, newTokenData(tc.token, tc.clusterName))
+		})
+	}
+}
@@ -1,39 +1,63 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
 	"os"
 
+	"github.com/ghodss/yaml"
 	"github.com/rancher/cli/cliclient"
 	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	"github.com/urfave/cli"
 )
 
+const upDescription = `
+Applies a compose config to the Rancher server.
+
+Several files can be given with -f, each file overriding the ones before it like docker-compose
+override files: maps are merged key by key while lists and other values are replaced. With
+--render the resulting config is printed instead of applied.
+
+Example:
+	$ rancher up -f rancher-compose.yaml -f rancher-compose.prod.yaml
+	$ rancher up -f rancher-compose.yaml -f rancher-compose.prod.yaml --render
+`
+
 func UpCommand() cli.Command {
 	return cli.Command{
-		Name:   "up",
-		Usage:  "apply compose config",
-		Action: defaultAction(apply),
+		Name:        "up",
+		Usage:       "apply compose config",
+		Description: upDescription,
+		Action:      defaultAction(apply),
 		Flags: []cli.Flag{
-			cli.StringFlag{
+			cli.StringSliceFlag{
 				Name:  "file,f",
-				Usage: "The location of compose config file",
+				Usage: "The location of compose config file, can be used multiple times with later files overriding earlier ones",
+			},
+			cli.BoolFlag{
+				Name:  "render",
+				Usage: "Print the resulting compose config without applying it",
 			},
 		},
 	}
 }
 
 func apply(ctx *cli.Context) error {
-	cf, err := lookupConfig(ctx)
+	compose, err := readComposeFiles(ctx.StringSlice("file"))
 	if err != nil {
 		return err
 	}
-	c, err := cliclient.NewManagementClient(cf)
+
+	if ctx.Bool("render") {
+		fmt.Print(string(compose))
+		return nil
+	}
+
+	cf, err := lookupConfig(ctx)
 	if err != nil {
 		return err
 	}
-
-	filePath := ctx.String("file")
-	compose, err := os.ReadFile(filePath)
+	c, err := cliclient.NewManagementClient(cf)
 	if err != nil {
 		return err
 	}
@@ -46,3 +70,42 @@ func apply(ctx *cli.Context) error {
 	}
 	return nil
 }
+
+// readComposeFiles returns the compose config of files, each overriding the
+// ones before it. A single file is returned as is.
+func readComposeFiles(files []string) ([]byte, error) {
+	if len(files) == 0 {
+		return nil, NewUsageError(errors.New("no compose config file given, use --file"))
+	}
+	if len(files) == 1 {
+		return os.ReadFile(files[0])
+	}
+
+	merged := map[string]interface{}{}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		config := map[string]interface{}{}
+		if err := yaml.Unmarshal(content, &config); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		mergeComposeConfig(merged, config)
+	}
+	return yaml.Marshal(merged)
+}
+
+// mergeComposeConfig merges override into base. Nested maps are merged,
+// any other value of override replaces the one of base.
+func mergeComposeConfig(base, override map[string]interface{}) {
+	for key, value := range override {
+		overrideMap, ok := value.(map[string]interface{})
+		baseMap, baseOK := base[key].(map[string]interface{})
+		if ok && baseOK {
+			mergeComposeConfig(baseMap, overrideMap)
+			continue
+		}
+		base[key] = value
+	}
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadComposeFiles(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	override := filepath.Join(dir, "override.yaml")
+	require.NoError(t, os.WriteFile(base, []byte(`# the base config
+clusters:
+  prod:
+    description: production
+    labels:
+      team: platform
+    nodePools: [a, b]
+version: v3
+`), 0600))
+	require.NoError(t, os.WriteFile(override, []byte(`clusters:
+  prod:
+    labels:
+      env: prod
+    nodePools: [c]
+`), 0600))
+
+	single, err := readComposeFiles([]string{base})
+	require.NoError(t, err)
+	assert.Contains(t, string(single), "# the base config")
+
+	merged, err := readComposeFiles([]string{base, override})
+	require.NoError(t, err)
+	assert.Equal(t, `clusters:
+  prod:
+    description: production
+    labels:
+      env: prod
+      team: platform
+    nodePools:
+    - c
+version: v3
+`, string(merged))
+
+	_, err = readComposeFiles(nil)
+	assert.EqualError(t, err, "no compose config file given, use --file")
+}
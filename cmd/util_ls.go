@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"strings"
+
 	"github.com/rancher/norman/types"
 	"github.com/urfave/cli"
 )
@@ -30,5 +32,99 @@ func defaultListOpts(ctx *cli.Context) *types.ListOpts {
 	} else {
 		listOpts.Filters["system"] = "false"
 	}
+	if pageSize := listPageSize(ctx); pageSize > 0 {
+		listOpts.Filters["limit"] = pageSize
+	}
+	return listOpts
+}
+
+// listPageSize returns how many resources to request per page, -1 meaning
+// all of them. Without --page-size only --limit resources are requested.
+func listPageSize(ctx *cli.Context) int {
+	if ctx == nil {
+		return -1
+	}
+	limit, pageSize := ctx.Int("limit"), ctx.Int("page-size")
+	switch {
+	case pageSize > 0 && (limit <= 0 || pageSize < limit):
+		return pageSize
+	case limit > 0:
+		return limit
+	}
+	return -1
+}
+
+// pageable is implemented by the generated collection types.
+type pageable[C any] interface {
+	comparable
+	Next() (C, error)
+}
+
+// forEachPage calls fn with the items of first and of the pages following it
+// as each page arrives, stopping once --limit items have been seen. Only one
+// page is held in memory at a time. flush, when set, is called after the items
+// of each page so that they are printed before the next page is fetched.
+func forEachPage[C pageable[C], T any](ctx *cli.Context, first C, data func(C) []T, fn func(T) error, flush func() error) error {
+	limit := 0
+	if ctx != nil {
+		limit = ctx.Int("limit")
+	}
+
+	var none C
+	seen := 0
+	for page := first; page != none; {
+		for _, item := range data(page) {
+			if err := fn(item); err != nil {
+				return err
+			}
+			seen++
+			if limit > 0 && seen >= limit {
+				return nil
+			}
+		}
+		if flush != nil {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		page, err = page.Next()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listAll returns the items of first and of the pages following it, stopping
+// once --limit items have been collected.
+func listAll[C pageable[C], T any](ctx *cli.Context, first C, data func(C) []T) ([]T, error) {
+	var items []T
+	err := forEachPage(ctx, first, data, func(item T) error {
+		items = append(items, item)
+		return nil
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// filteredListOpts adds the --filter values of an ls command to the default
+// list options so that the server can narrow the collection. Nested keys such
+// as "app.state" are only applied client-side by the TableWriter.
+func filteredListOpts(ctx *cli.Context) *types.ListOpts {
+	listOpts := defaultListOpts(ctx)
+	if ctx == nil {
+		return listOpts
+	}
+	for _, filter := range ctx.StringSlice("filter") {
+		key, value, ok := strings.Cut(filter, "=")
+		if !ok || key == "" || strings.Contains(key, ".") {
+			continue
+		}
+		listOpts.Filters[key] = value
+	}
 	return listOpts
 }
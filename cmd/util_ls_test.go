@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli"
+)
+
+type fakePage struct {
+	data []int
+	next *fakePage
+}
+
+func (p *fakePage) Next() (*fakePage, error) {
+	return p.next, nil
+}
+
+func newListContext(limit, pageSize int) *cli.Context {
+	set := flag.NewFlagSet("ls", flag.ContinueOnError)
+	set.Int("limit", limit, "")
+	set.Int("page-size", pageSize, "")
+	return cli.NewContext(nil, set, nil)
+}
+
+func TestListAll(t *testing.T) {
+	pages := &fakePage{data: []int{1, 2}, next: &fakePage{data: []int{3, 4}, next: &fakePage{data: []int{5}}}}
+	data := func(p *fakePage) []int { return p.data }
+
+	items, err := listAll(newListContext(0, 2), pages, data)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, items)
+
+	items, err = listAll(newListContext(3, 2), pages, data)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, items)
+}
+
+func TestListPageSize(t *testing.T) {
+	tests := []struct {
+		limit    int
+		pageSize int
+		want     int
+	}{
+		{0, 0, -1},
+		{10, 0, 10},
+		{0, 50, 50},
+		{10, 50, 10},
+		{100, 50, 50},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, listPageSize(newListContext(tt.limit, tt.pageSize)), "limit=%d page-size=%d", tt.limit, tt.pageSize)
+		assert.Equal(t, tt.want, defaultListOpts(newListContext(tt.limit, tt.pageSize)).Filters["limit"])
+	}
+}
+
+func TestForEachPageStopsAtLimit(t *testing.T) {
+	fetched := &fakePage{data: []int{3, 4}}
+	pages := &fakePage{data: []int{1, 2}, next: fetched}
+
+	var seen []int
+	err := forEachPage(newListContext(2, 0), pages, func(p *fakePage) []int {
+		if p == fetched {
+			t.Fatal("the next page must not be fetched once the limit is reached")
+		}
+		return p.data
+	}, func(item int) error {
+		seen = append(seen, item)
+		return nil
+	}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, seen)
+}
+
+func TestForEachPageFlushesEachPage(t *testing.T) {
+	pages := &fakePage{data: []int{1, 2}, next: &fakePage{data: []int{3}}}
+	out := &bytes.Buffer{}
+	writer := NewTableWriterWithConfig([][]string{{"VALUE", "Value"}}, &TableWriterConfig{Writer: out})
+
+	var printed []string
+	err := forEachPage(newListContext(0, 2), pages, func(p *fakePage) []int {
+		// the rows of the previous page are printed before the next one is fetched
+		printed = append(printed, out.String())
+		return p.data
+	}, func(item int) error {
+		writer.Write(struct{ Value int }{item})
+		return nil
+	}, writer.Flush)
+
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	assert.Equal(t, []string{"", "VALUE\n1\n2\n"}, printed)
+	assert.Equal(t, "VALUE\n1\n2\n3\n", out.String())
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	gover "github.com/hashicorp/go-version"
+	"github.com/rancher/cli/cliclient"
+	"github.com/rancher/cli/config"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+const serverVersionSetting = "server-version"
+
+// serverIncompatibilities lists the server versions this CLI has known
+// problems with.
+var serverIncompatibilities = []struct {
+	constraint string
+	message    string
+}{
+	{
+		constraint: "< 2.6.0",
+		message:    "Rancher servers older than v2.6 are not supported, some commands will fail",
+	},
+}
+
+var warnServerOnce sync.Once
+
+// VersionCommand defines the 'rancher version' command
+func VersionCommand() cli.Command {
+	return cli.Command{
+		Name:  "version",
+		Usage: "Show the CLI and server versions",
+		Description: `
+Show the version of the CLI, the version of the server in focus and whether
+the CLI is compatible with it.
+`,
+		Action: versionAction,
+	}
+}
+
+func versionAction(ctx *cli.Context) error {
+	fmt.Fprintf(ctx.App.Writer, "Client Version: %s\n", ctx.App.Version)
+
+	cf, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	sc, err := focusedServerConfig(ctx, cf)
+	if err != nil {
+		fmt.Fprintln(ctx.App.Writer, "Server Version: unknown, not logged in")
+		return nil
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+	serverVersion, err := getServerVersion(c)
+	if err != nil {
+		return err
+	}
+
+	// remember the version so other commands can warn without asking the server
+	if sc.ServerVersion != serverVersion {
+		err := updateConfig(ctx, func(cf *config.Config) error {
+			sc, err := focusedServerConfig(ctx, *cf)
+			if err != nil {
+				return err
+			}
+			sc.ServerVersion = serverVersion
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	printServerVersion(ctx.App.Writer, sc.URL, serverVersion)
+	return nil
+}
+
+func printServerVersion(out io.Writer, url, serverVersion string) {
+	fmt.Fprintf(out, "Server Version: %s (%s)\n", serverVersion, url)
+
+	compatible, message := serverCompatibility(serverVersion)
+	switch {
+	case message == "":
+		fmt.Fprintln(out, "Compatibility:  unknown, the server version could not be parsed")
+	case compatible:
+		fmt.Fprintln(out, "Compatibility:  compatible")
+	default:
+		fmt.Fprintf(out, "Compatibility:  incompatible, %s\n", message)
+	}
+}
+
+// getServerVersion returns the version reported by the server-version setting.
+func getServerVersion(c *cliclient.MasterClient) (string, error) {
+	setting, err := c.ManagementClient.Setting.ByID(serverVersionSetting)
+	if err != nil {
+		return "", err
+	}
+	return setting.Value, nil
+}
+
+// serverCompatibility reports whether the CLI works with the given server
+// version. The message is empty when the version can't be parsed, e.g. for
+// development builds of the server.
+func serverCompatibility(serverVersion string) (bool, string) {
+	v, err := gover.NewVersion(serverVersion)
+	if err != nil {
+		return false, ""
+	}
+	// compare release versions so that release candidates match too
+	segments := v.Segments()
+	v, err = gover.NewVersion(fmt.Sprintf("%d.%d.%d", segments[0], segments[1], segments[2]))
+	if err != nil {
+		return false, ""
+	}
+
+	for _, incompatibility := range serverIncompatibilities {
+		constraint, err := gover.NewConstraint(incompatibility.constraint)
+		if err != nil {
+			continue
+		}
+		if constraint.Check(v) {
+			return false, incompatibility.message
+		}
+	}
+	return true, "compatible"
+}
+
+// warnIncompatibleServer logs a warning, once per invocation, when the last
+// known version of the server has known incompatibilities.
+func warnIncompatibleServer(sc *config.ServerConfig) {
+	if sc.ServerVersion == "" {
+		return
+	}
+	warnServerOnce.Do(func() {
+		compatible, message := serverCompatibility(sc.ServerVersion)
+		if !compatible && message != "" {
+			logrus.Warnf("Server %s runs Rancher %s: %s", sc.URL, sc.ServerVersion, message)
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerCompatibility(t *testing.T) {
+	tests := []struct {
+		version    string
+		compatible bool
+		message    string
+	}{
+		{"v2.8.5", true, "compatible"},
+		{"v2.6.0-rc1", true, "compatible"},
+		{"v2.5.16", false, "Rancher servers older than v2.6 are not supported, some commands will fail"},
+		{"dev", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			compatible, message := serverCompatibility(tt.version)
+			assert.Equal(t, tt.compatible, compatible)
+			assert.Equal(t, tt.message, message)
+		})
+	}
+}
+
+func TestPrintServerVersion(t *testing.T) {
+	out := &bytes.Buffer{}
+	printServerVersion(out, "https://rancher.example.com", "v2.5.16")
+
+	assert.Equal(t, "Server Version: v2.5.16 (https://rancher.example.com)\n"+
+		"Compatibility:  incompatible, Rancher servers older than v2.6 are not supported, some commands will fail\n", out.String())
+}
@@ -0,0 +1,326 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rancher/norman/clientbase"
+	"github.com/rancher/norman/types"
+	"github.com/urfave/cli"
+)
+
+const (
+	virtualMachineType = "kubevirt.io.virtualmachine"
+	harvesterFeature   = "Harvester"
+
+	// harvesterRunStrategy is the run strategy Harvester gives running VMs
+	harvesterRunStrategy = "RerunOnFailure"
+)
+
+type VirtualMachine struct {
+	types.Resource
+	Metadata objectMeta `json:"metadata,omitempty"`
+	Spec     struct {
+		RunStrategy string `json:"runStrategy,omitempty"`
+		Template    struct {
+			Spec struct {
+				Domain struct {
+					CPU struct {
+						Cores int `json:"cores,omitempty"`
+					} `json:"cpu,omitempty"`
+					Resources struct {
+						Limits map[string]string `json:"limits,omitempty"`
+					} `json:"resources,omitempty"`
+				} `json:"domain,omitempty"`
+			} `json:"spec,omitempty"`
+		} `json:"template,omitempty"`
+	} `json:"spec,omitempty"`
+	Status struct {
+		PrintableStatus string `json:"printableStatus,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+type VirtualMachineCollection struct {
+	types.Collection
+	Data []VirtualMachine `json:"data,omitempty"`
+}
+
+type VirtualMachineData struct {
+	ID             string
+	VirtualMachine VirtualMachine
+	Namespace      string
+	Name           string
+	State          string
+	CPUs           int
+	Memory         string
+}
+
+var vmClusterFlag = cli.StringFlag{
+	Name:  "cluster",
+	Usage: "Harvester cluster to use, by default the cluster in the current context",
+}
+
+var vmNamespaceFlag = cli.StringFlag{
+	Name:  "namespace,n",
+	Usage: "Namespace of the VMs",
+	Value: "default",
+}
+
+func VMCommand() cli.Command {
+	return cli.Command{
+		Name:    "vm",
+		Aliases: []string{"vms"},
+		Usage:   "Operations on Harvester virtual machines",
+		Description: `
+Manages the virtual machines of a Harvester cluster imported in Rancher, and node templates
+creating Rancher nodes as Harvester VMs.
+`,
+		Action: defaultAction(vmLs),
+		Flags:  []cli.Flag{vmClusterFlag},
+		Subcommands: []cli.Command{
+			{
+				Name:      "ls",
+				Usage:     "List virtual machines",
+				ArgsUsage: "None",
+				Action:    vmLs,
+				Flags: []cli.Flag{
+					vmClusterFlag,
+					cli.StringFlag{
+						Name:  "namespace,n",
+						Usage: "Only list the VMs of this namespace",
+					},
+					formatFlag,
+					quietFlag,
+					filterFlag,
+					labelSelectorFlag,
+					sortByFlag,
+					noHeadersFlag,
+				},
+			},
+			{
+				Name:  "start",
+				Usage: "Start virtual machines",
+				Description: `
+VMs are given by name, in the namespace given with --namespace, or as NAMESPACE/NAME.
+
+Example:
+	$ rancher vm start --cluster harvester web-1 web-2
+`,
+				ArgsUsage: "[VM_NAME...]",
+				Action:    vmSetRunStrategy(harvesterRunStrategy, "Started"),
+				Flags:     []cli.Flag{vmClusterFlag, vmNamespaceFlag},
+			},
+			{
+				Name:      "stop",
+				Usage:     "Stop virtual machines",
+				ArgsUsage: "[VM_NAME...]",
+				Action:    vmSetRunStrategy("Halted", "Stopped"),
+				Flags:     []cli.Flag{vmClusterFlag, vmNamespaceFlag},
+			},
+			{
+				Name:    "node-template",
+				Aliases: []string{"node-templates"},
+				Usage:   "Operations on node templates backed by Harvester",
+				Subcommands: []cli.Command{
+					{
+						Name:  "create",
+						Usage: "Create a node template creating nodes as Harvester VMs",
+						Description: `
+Example:
+	$ rancher vm node-template create --cluster harvester --cloud-credential cattle-global-data:cc-x7k2p \
+		--image default/ubuntu-22.04 --network default/vlan10 --ssh-user ubuntu harvester-medium
+`,
+						ArgsUsage: "[NAME]",
+						Action:    vmNodeTemplateCreate,
+						Flags: []cli.Flag{
+							vmClusterFlag,
+							cli.StringFlag{
+								Name:  "cloud-credential",
+								Usage: "ID of the Harvester cloud credential",
+							},
+							cli.StringFlag{
+								Name:  "vm-namespace",
+								Usage: "Namespace the VMs are created in",
+								Value: "default",
+							},
+							cli.StringFlag{
+								Name:  "image",
+								Usage: "Image of the VMs as NAMESPACE/NAME",
+							},
+							cli.StringFlag{
+								Name:  "network",
+								Usage: "Network of the VMs as NAMESPACE/NAME",
+							},
+							cli.StringFlag{
+								Name:  "ssh-user",
+								Usage: "User to connect to the VMs with, must match the image",
+							},
+							cli.IntFlag{
+								Name:  "cpus",
+								Usage: "Number of CPUs of the VMs",
+								Value: 2,
+							},
+							cli.IntFlag{
+								Name:  "memory",
+								Usage: "Memory of the VMs in GiB",
+								Value: 4,
+							},
+							cli.IntFlag{
+								Name:  "disk",
+								Usage: "Disk size of the VMs in GiB",
+								Value: 40,
+							},
+							cli.StringFlag{
+								Name:  "user-data",
+								Usage: "File with cloud-init user data for the VMs",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func vmLs(ctx *cli.Context) error {
+	client, err := getClusterV1Client(ctx, ctx.String("cluster"), virtualMachineType, harvesterFeature)
+	if err != nil {
+		return err
+	}
+
+	opts := &types.ListOpts{Filters: map[string]interface{}{}}
+	if namespace := ctx.String("namespace"); namespace != "" {
+		opts.Filters["metadata.namespace"] = namespace
+	}
+	collection := &VirtualMachineCollection{}
+	if err := client.List(virtualMachineType, opts, collection); err != nil {
+		return err
+	}
+
+	writer := NewTableWriter([][]string{
+		{"NAMESPACE", "Namespace"},
+		{"NAME", "Name"},
+		{"STATE", "State"},
+		{"CPUS", "CPUs"},
+		{"MEMORY", "Memory"},
+	}, ctx)
+
+	defer writer.Close()
+
+	for _, item := range collection.Data {
+		domain := item.Spec.Template.Spec.Domain
+		writer.Write(&VirtualMachineData{
+			ID:             item.ID,
+			VirtualMachine: item,
+			Namespace:      item.Metadata.Namespace,
+			Name:           item.Metadata.Name,
+			State:          valueOrDefault(item.Status.PrintableStatus, objectState(item.Metadata)),
+			CPUs:           domain.CPU.Cores,
+			Memory:         domain.Resources.Limits["memory"],
+		})
+	}
+
+	return writer.Err()
+}
+
+// vmSetRunStrategy starts or stops VMs by setting their run strategy, which
+// is what the start and stop actions of Harvester do.
+func vmSetRunStrategy(runStrategy, done string) func(*cli.Context) error {
+	return func(ctx *cli.Context) error {
+		if ctx.NArg() == 0 {
+			return showSubcommandUsage(ctx)
+		}
+
+		client, err := getClusterV1Client(ctx, ctx.String("cluster"), virtualMachineType, harvesterFeature)
+		if err != nil {
+			return err
+		}
+
+		for _, arg := range ctx.Args() {
+			id := vmID(arg, ctx.String("namespace"))
+			if err := setVMRunStrategy(client, id, runStrategy); err != nil {
+				return fmt.Errorf("%s: %w", id, err)
+			}
+			fmt.Printf("%s %s\n", done, id)
+		}
+		return nil
+	}
+}
+
+func setVMRunStrategy(client *clientbase.APIBaseClient, id, runStrategy string) error {
+	object, resource, err := getRawObject(client, virtualMachineType, id)
+	if err != nil {
+		return err
+	}
+	spec := childMap(object, "spec")
+	// running and runStrategy are mutually exclusive
+	delete(spec, "running")
+	spec["runStrategy"] = runStrategy
+	return client.Update(virtualMachineType, resource, object, &VirtualMachine{})
+}
+
+// vmID returns the /v1 ID of a VM given as NAME or NAMESPACE/NAME
+func vmID(arg, namespace string) string {
+	if strings.Contains(arg, "/") {
+		return arg
+	}
+	return namespace + "/" + arg
+}
+
+func vmNodeTemplateCreate(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showSubcommandUsage(ctx)
+	}
+	for _, flag := range []string{"cloud-credential", "image", "network", "ssh-user"} {
+		if ctx.String(flag) == "" {
+			return fmt.Errorf("--%s is required", flag)
+		}
+	}
+
+	c, err := GetManagementClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	clusterID, err := resolveClusterID(c, ctx.String("cluster"))
+	if err != nil {
+		return err
+	}
+
+	config := map[string]interface{}{
+		"clusterType": "imported",
+		"clusterId":   clusterID,
+		"vmNamespace": ctx.String("vm-namespace"),
+		"imageName":   ctx.String("image"),
+		"networkName": ctx.String("network"),
+		"sshUser":     ctx.String("ssh-user"),
+		"cpuCount":    strconv.Itoa(ctx.Int("cpus")),
+		"memorySize":  strconv.Itoa(ctx.Int("memory")),
+		"diskSize":    strconv.Itoa(ctx.Int("disk")),
+	}
+	if file := ctx.String("user-data"); file != "" {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		config["userData"] = string(content)
+	}
+
+	// the configuration of a node driver is a field named after the driver,
+	// which the generated NodeTemplate type doesn't have
+	template := map[string]interface{}{
+		"name":              ctx.Args().First(),
+		"driver":            "harvester",
+		"cloudCredentialId": ctx.String("cloud-credential"),
+		"harvesterConfig":   config,
+	}
+	created := &types.Resource{}
+	if err := c.ManagementClient.Create("nodeTemplate", template, created); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created node template %s\n", created.ID)
+	return nil
+}
@@ -0,0 +1,12 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVMID(t *testing.T) {
+	assert.Equal(t, "default/web-1", vmID("web-1", "default"))
+	assert.Equal(t, "prod/web-1", vmID("prod/web-1", "default"))
+}
@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/rancher/cli/cliclient"
 	ntypes "github.com/rancher/norman/types"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
@@ -32,7 +36,7 @@ func WaitCommand() cli.Command {
 
 func wait(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
-		return cli.ShowCommandHelp(ctx, "wait")
+		return showCommandUsage(ctx, "wait")
 	}
 
 	c, err := GetClient(ctx)
@@ -45,44 +49,99 @@ func wait(ctx *cli.Context) error {
 		return err
 	}
 
+	return waitForResource(c, resource, time.Duration(ctx.Int("timeout"))*time.Second)
+}
+
+// waitForResource polls resource until it becomes active, reporting progress
+// until then. Ctrl-C stops waiting and reports the last known state.
+func waitForResource(c *cliclient.MasterClient, resource *ntypes.Resource, timeout time.Duration) error {
+	if c.DryRun {
+		return nil
+	}
+
+	ctx, cancel := waitContext(timeout)
+	defer cancel()
+
 	mapResource := map[string]interface{}{}
+	var p *progress
+	err := pollUntil(ctx, newBackoff(pollInitialInterval, pollMaxInterval), func() (bool, error) {
+		if err := c.ByID(resource, &mapResource); err != nil {
+			return false, err
+		}
+		ok, err := checkDone(resource, mapResource)
+		if err != nil || ok {
+			return ok, err
+		}
 
-	// Initial check shortcut
-	err = c.ByID(resource, &mapResource)
-	if err != nil {
+		// only report progress when the resource isn't already active
+		if p == nil {
+			p = newProgress(fmt.Sprintf("Waiting for %v %v", resource.Type, resource.ID))
+		}
+		p.Update(transitioningMessage(mapResource), waitSteps(mapResource))
+		return false, nil
+	})
+
+	if p == nil {
+		// the resource was active, or failed, on the first check
 		return err
 	}
 
-	ok, err := checkDone(resource, mapResource)
-	if err != nil {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		p.Done("Timeout reached")
+		return timeoutErrorf("Timeout reached %v:%v transitioningMessage: %v", resource.Type, resource.ID, mapResource["transitioningMessage"])
+	case errors.Is(err, context.Canceled):
+		p.Done("Interrupted")
+		return interruptedErrorf("interrupted waiting for %v:%v, state: %v transitioningMessage: %v", resource.Type, resource.ID,
+			mapResource["state"], mapResource["transitioningMessage"])
+	case err != nil:
+		p.Done("Failed")
 		return err
 	}
-	if ok {
-		return nil
-	}
-
-	timeout := time.After(time.Duration(ctx.Int("timeout")) * time.Second)
-	ticker := time.NewTicker(time.Second)
+	p.Done(fmt.Sprintf("%v %v is active", resource.Type, resource.ID))
+	return nil
+}
 
-	for {
-		select {
-		case <-timeout:
-			return fmt.Errorf("Timeout reached %v:%v transitioningMessage: %v", resource.Type, resource.ID, mapResource["transitioningMessage"])
-		case <-ticker.C:
-			err = c.ByID(resource, &mapResource)
-			if err != nil {
-				return err
-			}
+func transitioningMessage(data map[string]interface{}) string {
+	if message, ok := data["transitioningMessage"].(string); ok {
+		return message
+	}
+	return ""
+}
 
-			ok, err := checkDone(resource, mapResource)
-			if err != nil {
-				return err
+// waitSteps returns the state of each target of a multi-cluster app keyed by
+// project ID, or the status of each condition of a cluster keyed by type.
+// Other resources have no steps.
+func waitSteps(data map[string]interface{}) map[string]string {
+	steps := map[string]string{}
+	if data["type"] == "cluster" {
+		conditions, _ := data["conditions"].([]interface{})
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if !ok {
+				continue
 			}
-			if ok {
-				return nil
+			state := fmt.Sprint(condition["status"])
+			if message, _ := condition["message"].(string); message != "" {
+				state += ", " + firstLine(message)
 			}
+			steps[fmt.Sprint(condition["type"])] = state
+		}
+		return steps
+	}
+	targets, _ := data["targets"].([]interface{})
+	for _, t := range targets {
+		target, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		state := fmt.Sprint(target["state"])
+		if target["state"] == nil {
+			state = "pending"
 		}
+		steps[fmt.Sprint(target["projectId"])] = state
 	}
+	return steps
 }
 
 func checkDone(resource *ntypes.Resource, data map[string]interface{}) (bool, error) {
@@ -102,3 +161,89 @@ func checkDone(resource *ntypes.Resource, data map[string]interface{}) (bool, er
 
 	return data["state"] == "active", nil
 }
+
+// The policies of --wait-policy for waiting on the targets of a multi-cluster
+// app.
+const (
+	// waitPolicyAll waits until every target is active
+	waitPolicyAll = "all"
+	// waitPolicyAny waits until one target is active
+	waitPolicyAny = "any"
+	// waitPolicyNone doesn't wait
+	waitPolicyNone = "none"
+)
+
+// waitForTargets polls a multi-cluster app until its targets satisfy policy,
+// reporting the state of each target until then.
+func waitForTargets(c *cliclient.MasterClient, resource *ntypes.Resource, timeout time.Duration, policy string, continueOnError bool) error {
+	if c.DryRun || policy == waitPolicyNone {
+		return nil
+	}
+
+	ctx, cancel := waitContext(timeout)
+	defer cancel()
+
+	mapResource := map[string]interface{}{}
+	p := newProgress(fmt.Sprintf("Waiting for %s targets of %v %v", policy, resource.Type, resource.ID))
+	err := pollUntil(ctx, newBackoff(pollInitialInterval, pollMaxInterval), func() (bool, error) {
+		if err := c.ByID(resource, &mapResource); err != nil {
+			return false, err
+		}
+		steps := waitSteps(mapResource)
+		p.Update(transitioningMessage(mapResource), steps)
+		return targetsDone(policy, steps, continueOnError)
+	})
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		p.Done("Timeout reached")
+		return timeoutErrorf("Timeout reached waiting for %s targets of %v:%v", policy, resource.Type, resource.ID)
+	case errors.Is(err, context.Canceled):
+		p.Done("Interrupted")
+		return interruptedErrorf("interrupted waiting for the targets of %v:%v", resource.Type, resource.ID)
+	case err != nil:
+		p.Done("Failed")
+		return err
+	}
+	p.Done(fmt.Sprintf("%s targets of %v %v are active", policy, resource.Type, resource.ID))
+	return nil
+}
+
+// targetsDone reports whether the states of the targets, keyed by project ID,
+// satisfy policy. A failed target fails the wait unless continueOnError is
+// set, then the wait goes on as long as the policy can still be satisfied.
+// When only some targets of the all policy are active the error has
+// ExitCodePartial.
+func targetsDone(policy string, steps map[string]string, continueOnError bool) (bool, error) {
+	var active int
+	var failed []string
+	for projectID, state := range steps {
+		switch strings.ToLower(state) {
+		case "active":
+			active++
+		case "error", "failed":
+			failed = append(failed, projectID)
+		}
+	}
+	sort.Strings(failed)
+	if len(steps) == 0 {
+		return false, nil
+	}
+
+	if policy == waitPolicyAny && active > 0 {
+		return true, nil
+	}
+	if len(failed) > 0 && !continueOnError {
+		return false, fmt.Errorf("targets failed: %s", strings.Join(failed, ", "))
+	}
+	if len(failed) == len(steps) {
+		return false, fmt.Errorf("all targets failed: %s", strings.Join(failed, ", "))
+	}
+	if policy == waitPolicyAny || active+len(failed) < len(steps) {
+		return false, nil
+	}
+	if len(failed) > 0 {
+		return false, partialErrorf("%d of %d targets failed: %s", len(failed), len(steps), strings.Join(failed, ", "))
+	}
+	return true, nil
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetsDone(t *testing.T) {
+	tests := []struct {
+		name            string
+		policy          string
+		steps           map[string]string
+		continueOnError bool
+		done            bool
+		err             string
+		code            int
+	}{
+		{
+			name:   "all pending",
+			policy: waitPolicyAll,
+			steps:  map[string]string{"p-1": "active", "p-2": "installing"},
+		},
+		{
+			name:   "all active",
+			policy: waitPolicyAll,
+			steps:  map[string]string{"p-1": "active", "p-2": "active"},
+			done:   true,
+		},
+		{
+			name:   "all with a failed target",
+			policy: waitPolicyAll,
+			steps:  map[string]string{"p-1": "active", "p-2": "error", "p-3": "installing"},
+			err:    "targets failed: p-2",
+			code:   ExitCodeError,
+		},
+		{
+			name:            "all continuing on error",
+			policy:          waitPolicyAll,
+			steps:           map[string]string{"p-1": "active", "p-2": "error", "p-3": "installing"},
+			continueOnError: true,
+		},
+		{
+			name:            "all partially active",
+			policy:          waitPolicyAll,
+			steps:           map[string]string{"p-1": "active", "p-2": "error", "p-3": "failed"},
+			continueOnError: true,
+			err:             "2 of 3 targets failed: p-2, p-3",
+			code:            ExitCodePartial,
+		},
+		{
+			name:            "all targets failed",
+			policy:          waitPolicyAll,
+			steps:           map[string]string{"p-1": "error", "p-2": "error"},
+			continueOnError: true,
+			err:             "all targets failed: p-1, p-2",
+			code:            ExitCodeError,
+		},
+		{
+			name:   "any active",
+			policy: waitPolicyAny,
+			steps:  map[string]string{"p-1": "error", "p-2": "active"},
+			done:   true,
+		},
+		{
+			name:            "any continuing on error",
+			policy:          waitPolicyAny,
+			steps:           map[string]string{"p-1": "error", "p-2": "installing"},
+			continueOnError: true,
+		},
+		{
+			name:   "no targets yet",
+			policy: waitPolicyAll,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done, err := targetsDone(tt.policy, tt.steps, tt.continueOnError)
+			assert.Equal(t, tt.done, done)
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+			assert.Equal(t, tt.code, ExitCode(err))
+		})
+	}
+}
+
+func TestWaitStepsClusterConditions(t *testing.T) {
+	cluster := map[string]interface{}{
+		"type": "cluster",
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Provisioned", "status": "True"},
+			map[string]interface{}{"type": "Ready", "status": "Unknown", "message": "Waiting for API to be available\nretrying"},
+		},
+	}
+	assert.Equal(t, map[string]string{
+		"Provisioned": "True",
+		"Ready":       "Unknown, Waiting for API to be available",
+	}, waitSteps(cluster))
+}
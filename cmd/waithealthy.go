@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rancher/cli/cliclient"
+	ntypes "github.com/rancher/norman/types"
+	projectClient "github.com/rancher/rancher/pkg/client/generated/project/v3"
+	"github.com/urfave/cli"
+)
+
+// The conditions of --wait-for for the install of a multi-cluster app
+const (
+	// waitForInstalled waits for the multi-cluster app to be installed
+	waitForInstalled = "installed"
+	// waitForHealthy also waits for the apps of its targets and their
+	// workloads to be active
+	waitForHealthy = "healthy"
+)
+
+// appIDLabel is the label of the workloads deployed by an app, its value is
+// the name of the app.
+const appIDLabel = "io.cattle.field/appId"
+
+var waitForFlag = cli.StringFlag{
+	Name:  "wait-for",
+	Usage: "What to wait for: the multi-cluster app to be 'installed', or 'healthy' for the apps of its targets and their workloads to be active too. Implies --wait",
+}
+
+// waitForHealthyTargets polls the apps a multi-cluster app deployed to its
+// targets until they and their workloads are active, for the targets policy
+// asks for, reporting the health of each target until then.
+func waitForHealthyTargets(c *cliclient.MasterClient, resource *ntypes.Resource, timeout time.Duration, policy string, continueOnError bool) error {
+	if c.DryRun || policy == waitPolicyNone {
+		return nil
+	}
+
+	ctx, cancel := waitContext(timeout)
+	defer cancel()
+
+	// the project clients of the targets are kept between polls as creating
+	// one fetches the schemas
+	clients := map[string]*cliclient.MasterClient{}
+	p := newProgress(fmt.Sprintf("Waiting for %s targets of %v %v to be healthy", policy, resource.Type, resource.ID))
+	err := pollUntil(ctx, newBackoff(pollInitialInterval, pollMaxInterval), func() (bool, error) {
+		app, err := c.ManagementClient.MultiClusterApp.ByID(resource.ID)
+		if err != nil {
+			return false, err
+		}
+		steps := map[string]string{}
+		for _, target := range app.Targets {
+			pc, ok := clients[target.ProjectID]
+			if !ok {
+				sc := *c.UserConfig
+				sc.Project = target.ProjectID
+				if pc, err = cliclient.NewProjectClient(&sc); err != nil {
+					return false, err
+				}
+				clients[target.ProjectID] = pc
+			}
+			if steps[target.ProjectID], err = targetHealth(pc, target.AppID); err != nil {
+				return false, err
+			}
+		}
+		p.Update("", steps)
+		return targetsDone(policy, steps, continueOnError)
+	})
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		p.Done("Timeout reached")
+		return timeoutErrorf("Timeout reached waiting for %s targets of %v:%v to be healthy", policy, resource.Type, resource.ID)
+	case errors.Is(err, context.Canceled):
+		p.Done("Interrupted")
+		return interruptedErrorf("interrupted waiting for the targets of %v:%v to be healthy", resource.Type, resource.ID)
+	case err != nil:
+		p.Done("Failed")
+		return err
+	}
+	p.Done(fmt.Sprintf("%s targets of %v %v are healthy", policy, resource.Type, resource.ID))
+	return nil
+}
+
+// targetHealth returns the health of the app appID of a target as a state of
+// targetsDone: active once the app and its workloads are active, error when
+// the app failed, or else what is still pending.
+func targetHealth(pc *cliclient.MasterClient, appID string) (string, error) {
+	if appID == "" {
+		return "pending", nil
+	}
+	app, err := pc.ProjectClient.App.ByID(appID)
+	if err != nil {
+		return "", err
+	}
+	if app.Transitioning == "error" {
+		return "error", nil
+	}
+	if app.State != "active" {
+		return "app " + app.State, nil
+	}
+
+	opts := defaultListOpts(nil)
+	opts.Filters["namespaceId"] = app.TargetNamespace
+	collection, err := pc.ProjectClient.Workload.List(opts)
+	if err != nil {
+		return "", err
+	}
+	workloads, err := listAll(nil, collection, func(c *projectClient.WorkloadCollection) []projectClient.Workload { return c.Data })
+	if err != nil {
+		return "", err
+	}
+	return workloadsHealth(appWorkloads(workloads, app.Name)), nil
+}
+
+// appWorkloads returns the workloads deployed by the app named appName, out
+// of the workloads of its namespace. Charts that don't label their workloads
+// have all the workloads of the namespace.
+func appWorkloads(workloads []projectClient.Workload, appName string) []projectClient.Workload {
+	var labeled []projectClient.Workload
+	for _, workload := range workloads {
+		if workload.Labels[appIDLabel] == appName {
+			labeled = append(labeled, workload)
+		}
+	}
+	if len(labeled) == 0 {
+		return workloads
+	}
+	return labeled
+}
+
+// workloadsHealth returns active once all workloads are active, or how many
+// of them are. Failing workloads aren't a failure of the target as they may
+// recover, such as pods restarting until a dependency is up.
+func workloadsHealth(workloads []projectClient.Workload) string {
+	var active int
+	for _, workload := range workloads {
+		if workload.State == "active" {
+			active++
+		}
+	}
+	if active == len(workloads) {
+		return "active"
+	}
+	return fmt.Sprintf("%d of %d workloads active", active, len(workloads))
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"testing"
+
+	projectClient "github.com/rancher/rancher/pkg/client/generated/project/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppWorkloadsHealth(t *testing.T) {
+	workload := func(name, app, state string) projectClient.Workload {
+		w := projectClient.Workload{Name: name, State: state}
+		if app != "" {
+			w.Labels = map[string]string{appIDLabel: app}
+		}
+		return w
+	}
+
+	tt := []struct {
+		name           string
+		workloads      []projectClient.Workload
+		expectedHealth string
+	}{
+		{
+			name: "workloads of the app active",
+			workloads: []projectClient.Workload{
+				workload("redis-master", "redis", "active"),
+				workload("redis-slave", "redis", "active"),
+				workload("other", "other", "updating"),
+			},
+			expectedHealth: "active",
+		},
+		{
+			name: "workloads of the app updating",
+			workloads: []projectClient.Workload{
+				workload("redis-master", "redis", "active"),
+				workload("redis-slave", "redis", "updating"),
+			},
+			expectedHealth: "1 of 2 workloads active",
+		},
+		{
+			name: "unlabeled workloads of the namespace",
+			workloads: []projectClient.Workload{
+				workload("redis-master", "", "active"),
+				workload("redis-slave", "", "error"),
+			},
+			expectedHealth: "1 of 2 workloads active",
+		},
+		{
+			name:           "no workloads",
+			expectedHealth: "active",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedHealth, workloadsHealth(appWorkloads(tc.workloads, "redis")))
+		})
+	}
+}
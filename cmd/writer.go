@@ -1,34 +1,84 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/ghodss/yaml"
 	"github.com/urfave/cli"
 )
 
+const (
+	customColumnsPrefix  = "custom-columns="
+	goTemplateFilePrefix = "go-template-file="
+
+	// wideFormat shows the default table including the columns marked "wide"
+	wideFormat = "wide"
+	csvFormat  = "csv"
+)
+
 type TableWriter struct {
 	HeaderFormat  string
 	ValueFormat   string
 	err           error
 	headerPrinted bool
+	filter        *rowFilter
+	columns       [][]string
+	sorted        bool
+	sortDesc      bool
+	sortTemplate  string
+	rows          []sortableRow
+	csv           *csv.Writer
+	pagerBuffer   *bytes.Buffer
+	pagerOut      io.Writer
 	Writer        *tabwriter.Writer
 }
 
+type sortableRow struct {
+	key string
+	obj interface{}
+}
+
 type TableWriterConfig struct {
-	Quiet  bool
-	Format string
-	Writer io.Writer
+	Quiet         bool
+	Format        string
+	Filters       []string
+	LabelSelector string
+	SortBy        string
+	NoHeaders     bool
+	Color         bool
+	Pager         bool
+	Writer        io.Writer
+	// Recorder receives a copy of the output when set
+	Recorder io.Writer
 }
 
 func NewTableWriter(values [][]string, ctx *cli.Context) *TableWriter {
 	cfg := &TableWriterConfig{
-		Writer: os.Stdout,
-		Quiet:  ctx.Bool("quiet"),
-		Format: ctx.String("format"),
+		Writer:        os.Stdout,
+		Quiet:         ctx.Bool("quiet"),
+		Format:        ctx.String("format"),
+		Filters:       ctx.StringSlice("filter"),
+		LabelSelector: ctx.String("label-selector"),
+		SortBy:        ctx.String("sort-by"),
+		NoHeaders:     ctx.Bool("no-headers"),
+		Color:         colorEnabled(ctx),
+		Pager:         pagerEnabled(ctx),
+		Recorder:      listingCache.recorder(),
+	}
+	// foreach-server reads the tables of the commands it runs as CSV
+	if cfg.Format == "" && os.Getenv(foreachServerEnv) != "" {
+		cfg.Format = csvFormat
+		cfg.Recorder = nil
 	}
 
 	return NewTableWriterWithConfig(values, cfg)
@@ -40,23 +90,82 @@ func NewTableWriterWithConfig(values [][]string, config *TableWriterConfig) *Tab
 		writer = os.Stdout
 	}
 
+	// buffer all output to decide at Close whether it needs a pager
+	var pagerBuffer *bytes.Buffer
+	pagerOut := writer
+	if config.Pager {
+		pagerBuffer = &bytes.Buffer{}
+		writer = pagerBuffer
+	}
+	if config.Recorder != nil {
+		writer = io.MultiWriter(writer, config.Recorder)
+	}
+
 	t := &TableWriter{
-		Writer: tabwriter.NewWriter(writer, 10, 1, 3, ' ', 0),
+		Writer:      tabwriter.NewWriter(writer, 10, 1, 3, ' ', 0),
+		columns:     visibleColumns(values, config.Format == wideFormat),
+		pagerBuffer: pagerBuffer,
+		pagerOut:    pagerOut,
+	}
+
+	t.filter, t.err = newRowFilter(config.Filters, config.LabelSelector)
+	if t.err != nil {
+		return t
+	}
+
+	// custom-columns replaces the default columns but keeps the table layout
+	customColumns := strings.HasPrefix(config.Format, customColumnsPrefix)
+	if customColumns {
+		t.columns, t.err = parseCustomColumns(strings.TrimPrefix(config.Format, customColumnsPrefix))
+		if t.err != nil {
+			return t
+		}
+	}
+	t.HeaderFormat, t.ValueFormat = SimpleFormat(t.columns)
+
+	if config.SortBy != "" {
+		sortColumns := values
+		if customColumns {
+			sortColumns = t.columns
+		}
+		t.err = t.setSortBy(config.SortBy, sortColumns)
+		if t.err != nil {
+			return t
+		}
 	}
-	t.HeaderFormat, t.ValueFormat = SimpleFormat(values)
 
-	// remove headers if quiet or with a different format
-	if config.Quiet || config.Format != "" {
+	tableFormat := config.Format == "" || config.Format == wideFormat || customColumns
+	if tableFormat && config.Color {
+		t.HeaderFormat, t.ValueFormat = SimpleFormat(colorColumns(t.columns))
+	}
+
+	// remove headers if quiet, asked to or with a different format
+	if config.Quiet || config.NoHeaders || !tableFormat {
 		t.HeaderFormat = ""
 	}
 
 	// when quiet show only the ID
 	if config.Quiet {
 		t.ValueFormat = "{{.ID}}\n"
+		return t
 	}
 
-	// check for custom formatting
-	if config.Format != "" {
+	switch {
+	case tableFormat:
+	case config.Format == csvFormat:
+		t.csv = csv.NewWriter(writer)
+		if !config.NoHeaders {
+			t.HeaderFormat, _ = SimpleFormat(t.columns)
+		}
+	case strings.HasPrefix(config.Format, goTemplateFilePrefix):
+		// a template read from a file is used verbatim
+		content, err := os.ReadFile(strings.TrimPrefix(config.Format, goTemplateFilePrefix))
+		if err != nil {
+			t.err = err
+			return t
+		}
+		t.ValueFormat = string(content)
+	case config.Format != "":
 		customFormat := config.Format
 
 		// add a newline for other custom formats
@@ -69,6 +178,58 @@ func NewTableWriterWithConfig(values [][]string, config *TableWriterConfig) *Tab
 	return t
 }
 
+// setSortBy configures the writer to buffer rows and order them by the column
+// named in spec, which takes the form HEADER[:desc].
+func (t *TableWriter) setSortBy(spec string, columns [][]string) error {
+	name, order, _ := strings.Cut(spec, ":")
+	switch strings.ToLower(order) {
+	case "", "asc":
+	case "desc":
+		t.sortDesc = true
+	default:
+		return fmt.Errorf("invalid sort order %q, expected 'asc' or 'desc'", order)
+	}
+
+	for _, column := range columns {
+		if strings.EqualFold(column[0], name) {
+			t.sortTemplate = columnTemplate(column[1])
+			t.sorted = true
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown sort column %q", name)
+}
+
+// visibleColumns drops the columns marked as "wide" unless wide output was
+// requested, a column is marked by adding "wide" as its third element.
+func visibleColumns(values [][]string, wide bool) [][]string {
+	var columns [][]string
+	for _, v := range values {
+		if len(v) > 2 && v[2] == wideFormat && !wide {
+			continue
+		}
+		columns = append(columns, v)
+	}
+	return columns
+}
+
+// parseCustomColumns converts a spec such as "NAME:.App.Name,STATE:.App.State"
+// into the header/field pairs accepted by SimpleFormat.
+func parseCustomColumns(spec string) ([][]string, error) {
+	var columns [][]string
+	for _, column := range strings.Split(spec, ",") {
+		header, field, ok := strings.Cut(column, ":")
+		if !ok || header == "" || field == "" {
+			return nil, fmt.Errorf("invalid custom column %q, expected HEADER:.Field.Path", column)
+		}
+		if !strings.Contains(field, "{{") {
+			field = strings.TrimPrefix(field, ".")
+		}
+		columns = append(columns, []string{header, field})
+	}
+	return columns, nil
+}
+
 func (t *TableWriter) Err() error {
 	return t.err
 }
@@ -76,6 +237,14 @@ func (t *TableWriter) Err() error {
 func (t *TableWriter) writeHeader() {
 	if t.HeaderFormat != "" && !t.headerPrinted {
 		t.headerPrinted = true
+		if t.csv != nil {
+			var headers []string
+			for _, column := range t.columns {
+				headers = append(headers, column[0])
+			}
+			t.err = t.csv.Write(headers)
+			return
+		}
 		t.err = printTemplate(t.Writer, t.HeaderFormat, struct{}{})
 		if t.err != nil {
 			return
@@ -88,12 +257,36 @@ func (t *TableWriter) Write(obj interface{}) {
 		return
 	}
 
+	if t.filter != nil {
+		ok, err := t.filter.Match(obj)
+		if err != nil {
+			t.err = err
+			return
+		}
+		if !ok {
+			return
+		}
+	}
+
+	if t.sorted {
+		key := &bytes.Buffer{}
+		t.err = printTemplate(key, t.sortTemplate, obj)
+		t.rows = append(t.rows, sortableRow{key: key.String(), obj: obj})
+		return
+	}
+
+	t.writeObj(obj)
+}
+
+func (t *TableWriter) writeObj(obj interface{}) {
 	t.writeHeader()
 	if t.err != nil {
 		return
 	}
 
-	if t.ValueFormat == "json" {
+	if t.csv != nil {
+		t.err = t.writeCSV(obj)
+	} else if t.ValueFormat == "json" {
 		content, err := json.Marshal(obj)
 		t.err = err
 		if t.err != nil {
@@ -112,13 +305,110 @@ func (t *TableWriter) Write(obj interface{}) {
 	}
 }
 
+// writeCSV renders each selected column separately so that values are quoted
+// as a whole by the csv writer.
+func (t *TableWriter) writeCSV(obj interface{}) error {
+	var record []string
+	for _, column := range t.columns {
+		value := &bytes.Buffer{}
+		if err := printTemplate(value, columnTemplate(column[1]), obj); err != nil {
+			return err
+		}
+		record = append(record, value.String())
+	}
+	return t.csv.Write(record)
+}
+
+// Flush prints the rows written so far, so that the rows of a page of a
+// listing are shown while the next page is fetched. The columns of a table are
+// aligned per flush. Sorted rows are only printed by Close.
+func (t *TableWriter) Flush() error {
+	if t.err != nil || t.sorted {
+		return t.err
+	}
+	if t.csv != nil {
+		t.csv.Flush()
+		if err := t.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return t.Writer.Flush()
+}
+
 func (t *TableWriter) Close() error {
 	if t.err != nil {
 		return t.err
 	}
+	if t.sorted {
+		sort.SliceStable(t.rows, func(i, j int) bool {
+			if t.sortDesc {
+				return compareColumnValues(t.rows[j].key, t.rows[i].key) < 0
+			}
+			return compareColumnValues(t.rows[i].key, t.rows[j].key) < 0
+		})
+		for _, row := range t.rows {
+			t.writeObj(row.obj)
+			if t.err != nil {
+				return t.err
+			}
+		}
+		t.rows = nil
+	}
 	t.writeHeader()
 	if t.err != nil {
 		return t.err
 	}
-	return t.Writer.Flush()
+	if t.csv != nil {
+		t.csv.Flush()
+		if err := t.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if err := t.Writer.Flush(); err != nil {
+		return err
+	}
+	if t.pagerBuffer != nil {
+		content := t.pagerBuffer.Bytes()
+		t.pagerBuffer = nil
+		return page(content, t.pagerOut)
+	}
+	return nil
+}
+
+// compareColumnValues orders two rendered column values, comparing them as
+// numbers, ages or timestamps when both parse as one and case-insensitively
+// otherwise.
+func compareColumnValues(a, b string) int {
+	ad, aOk := parseHumanDuration(a)
+	bd, bOk := parseHumanDuration(b)
+	if aOk && bOk {
+		switch {
+		case ad < bd:
+			return -1
+		case ad > bd:
+			return 1
+		}
+		return 0
+	}
+
+	for _, layout := range []string{time.RFC3339, "02 Jan 2006 15:04:05 MST"} {
+		at, aErr := time.Parse(layout, a)
+		bt, bErr := time.Parse(layout, b)
+		if aErr == nil && bErr == nil {
+			return at.Compare(bt)
+		}
+	}
+
+	af, aErr := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	bf, bErr := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	if aErr == nil && bErr == nil {
+		switch {
+		case af < bf:
+			return -1
+		case af > bf:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
 }
@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type writerTestRow struct {
+	ID  string
+	App struct {
+		Name  string
+		State string
+	}
+}
+
+func newWriterTestRow(id, name, state string) writerTestRow {
+	row := writerTestRow{ID: id}
+	row.App.Name = name
+	row.App.State = state
+	return row
+}
+
+func TestTableWriterFormats(t *testing.T) {
+	templateFile := filepath.Join(t.TempDir(), "tmpl")
+	assert.NoError(t, os.WriteFile(templateFile, []byte("{{.App.Name}}={{.App.State}}\n"), 0600))
+
+	tt := []struct {
+		name           string
+		format         string
+		quiet          bool
+		expectedOutput string
+		expectedErr    string
+	}{
+		{
+			name:           "default columns",
+			expectedOutput: "ID        NAME\np-1       app1\n",
+		},
+		{
+			name:           "custom template",
+			format:         "{{.App.Name}}",
+			expectedOutput: "app1\n",
+		},
+		{
+			name:           "custom columns",
+			format:         "custom-columns=APP:.App.Name,STATE:.App.State",
+			expectedOutput: "APP       STATE\napp1      active\n",
+		},
+		{
+			name:           "custom columns with quiet",
+			format:         "custom-columns=APP:.App.Name",
+			quiet:          true,
+			expectedOutput: "p-1\n",
+		},
+		{
+			name:        "invalid custom columns",
+			format:      "custom-columns=APP",
+			expectedErr: `invalid custom column "APP", expected HEADER:.Field.Path`,
+		},
+		{
+			name:           "go template file",
+			format:         "go-template-file=" + templateFile,
+			expectedOutput: "app1=active\n",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			writer := NewTableWriterWithConfig([][]string{
+				{"ID", "ID"},
+				{"NAME", "App.Name"},
+			}, &TableWriterConfig{
+				Format: tc.format,
+				Quiet:  tc.quiet,
+				Writer: out,
+			})
+			writer.Write(newWriterTestRow("p-1", "app1", "active"))
+
+			err := writer.Close()
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, out.String())
+		})
+	}
+}
+
+func TestTableWriterSortBy(t *testing.T) {
+	rows := []writerTestRow{
+		newWriterTestRow("p-1", "app10", "active"),
+		newWriterTestRow("p-2", "app2", "error"),
+		newWriterTestRow("p-3", "App1", "active"),
+	}
+
+	tt := []struct {
+		name           string
+		sortBy         string
+		expectedOutput string
+		expectedErr    string
+	}{
+		{
+			name:           "ascending",
+			sortBy:         "name",
+			expectedOutput: "p-3\np-1\np-2\n",
+		},
+		{
+			name:           "descending",
+			sortBy:         "NAME:desc",
+			expectedOutput: "p-2\np-1\np-3\n",
+		},
+		{
+			name:           "stable for equal values",
+			sortBy:         "STATE",
+			expectedOutput: "p-1\np-3\np-2\n",
+		},
+		{
+			name:        "unknown column",
+			sortBy:      "AGE",
+			expectedErr: `unknown sort column "AGE"`,
+		},
+		{
+			name:        "invalid order",
+			sortBy:      "NAME:up",
+			expectedErr: `invalid sort order "up", expected 'asc' or 'desc'`,
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			writer := NewTableWriterWithConfig([][]string{
+				{"ID", "ID"},
+				{"NAME", "App.Name"},
+				{"STATE", "App.State"},
+			}, &TableWriterConfig{
+				Format: "{{.ID}}",
+				SortBy: tc.sortBy,
+				Writer: out,
+			})
+			for _, row := range rows {
+				writer.Write(row)
+			}
+
+			err := writer.Close()
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, out.String())
+		})
+	}
+}
+
+func TestCompareColumnValues(t *testing.T) {
+	assert.Equal(t, -1, compareColumnValues("9", "10"))
+	assert.Equal(t, 1, compareColumnValues("b", "A"))
+	assert.Equal(t, -1, compareColumnValues("01 Feb 2021 10:00:00 UTC", "01 Jan 2022 10:00:00 UTC"))
+	assert.Equal(t, 0, compareColumnValues("same", "SAME"))
+}
+
+func TestTableWriterWideAndNoHeaders(t *testing.T) {
+	tt := []struct {
+		name           string
+		format         string
+		noHeaders      bool
+		expectedOutput string
+	}{
+		{
+			name:           "wide columns hidden by default",
+			expectedOutput: "ID        NAME\np-1       app1\n",
+		},
+		{
+			name:           "wide columns shown",
+			format:         "wide",
+			expectedOutput: "ID        NAME      STATE\np-1       app1      active\n",
+		},
+		{
+			name:           "no headers",
+			noHeaders:      true,
+			expectedOutput: "p-1       app1\n",
+		},
+		{
+			name:           "no headers with wide",
+			format:         "wide",
+			noHeaders:      true,
+			expectedOutput: "p-1       app1      active\n",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			writer := NewTableWriterWithConfig([][]string{
+				{"ID", "ID"},
+				{"NAME", "App.Name"},
+				{"STATE", "App.State", wideFormat},
+			}, &TableWriterConfig{
+				Format:    tc.format,
+				NoHeaders: tc.noHeaders,
+				Writer:    out,
+			})
+			writer.Write(newWriterTestRow("p-1", "app1", "active"))
+
+			assert.NoError(t, writer.Close())
+			assert.Equal(t, tc.expectedOutput, out.String())
+		})
+	}
+}
+
+func TestTableWriterCSV(t *testing.T) {
+	tt := []struct {
+		name           string
+		noHeaders      bool
+		quiet          bool
+		expectedOutput string
+	}{
+		{
+			name:           "with headers",
+			expectedOutput: "ID,NAME\np-1,\"app, \"\"one\"\"\"\n",
+		},
+		{
+			name:           "no headers",
+			noHeaders:      true,
+			expectedOutput: "p-1,\"app, \"\"one\"\"\"\n",
+		},
+		{
+			name:           "quiet",
+			quiet:          true,
+			expectedOutput: "p-1\n",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			writer := NewTableWriterWithConfig([][]string{
+				{"ID", "ID"},
+				{"NAME", "App.Name"},
+			}, &TableWriterConfig{
+				Format:    "csv",
+				NoHeaders: tc.noHeaders,
+				Quiet:     tc.quiet,
+				Writer:    out,
+			})
+			writer.Write(newWriterTestRow("p-1", `app, "one"`, "active"))
+
+			assert.NoError(t, writer.Close())
+			assert.Equal(t, tc.expectedOutput, out.String())
+		})
+	}
+}
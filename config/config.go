@@ -26,14 +26,19 @@ type Config struct {
 
 // ServerConfig holds the config for each server the user has setup
 type ServerConfig struct {
-	AccessKey       string                     `json:"accessKey"`
-	SecretKey       string                     `json:"secretKey"`
-	TokenKey        string                     `json:"tokenKey"`
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
+	TokenKey  string `json:"tokenKey"`
+	// TokenSource references a token kept outside the config file, see
+	// ResolveToken
+	TokenSource     string                     `json:"tokenSource,omitempty"`
 	URL             string                     `json:"url"`
 	Project         string                     `json:"project"`
 	CACerts         string                     `json:"cacert"`
 	KubeCredentials map[string]*ExecCredential `json:"kubeCredentials"`
 	KubeConfigs     map[string]*api.Config     `json:"kubeConfigs"`
+	// ServerVersion is the Rancher version last reported by the server
+	ServerVersion string `json:"serverVersion,omitempty"`
 }
 
 // LoadFromPath attempts to load a config from the given file path. If the file
@@ -86,21 +91,78 @@ func GetFilePermissionWarnings(path string) ([]string, error) {
 	return warnings, nil
 }
 
+// Write saves the config to its path. Concurrent writers are serialized with a
+// file lock and the file is replaced atomically, so readers never see a
+// partially written config. Use Update to change the config on disk without
+// losing the changes of other writers.
 func (c Config) Write() error {
-	err := os.MkdirAll(filepath.Dir(c.Path), 0700)
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0700); err != nil {
+		return err
+	}
+	unlock, err := lockFile(c.Path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return c.write()
+}
+
+// Update reloads the config from its path, changes it with update and saves
+// it, holding the file lock throughout so that the changes of concurrent
+// writers aren't overwritten. Nothing is saved when update returns an error.
+func (c *Config) Update(update func(*Config) error) error {
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0700); err != nil {
+		return err
+	}
+	unlock, err := lockFile(c.Path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	loaded, err := LoadFromPath(c.Path)
 	if err != nil {
 		return err
 	}
+	*c = loaded
+	if err := update(c); err != nil {
+		return err
+	}
+	return c.write()
+}
+
+// write saves the config to its path, the caller holds the file lock.
+func (c Config) write() error {
 	logrus.Infof("Saving config to %s", c.Path)
 	p := c.Path
 	c.Path = ""
-	output, err := os.OpenFile(p, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	c.Servers = withoutSourcedTokens(c.Servers)
+
+	// keep the permissions of an existing config file
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(p); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	output, err := os.CreateTemp(filepath.Dir(p), filepath.Base(p)+".tmp-*")
 	if err != nil {
 		return err
 	}
-	defer output.Close()
+	defer os.Remove(output.Name())
 
-	return json.NewEncoder(output).Encode(c)
+	if err := output.Chmod(mode); err != nil {
+		output.Close()
+		return err
+	}
+	if err := json.NewEncoder(output).Encode(c); err != nil {
+		output.Close()
+		return err
+	}
+	if err := output.Close(); err != nil {
+		return err
+	}
+	return os.Rename(output.Name(), p)
 }
 
 func (c Config) FocusedServer() (*ServerConfig, error) {
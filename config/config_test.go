@@ -1,8 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -209,3 +211,139 @@ func Test_LoadFromPath(t *testing.T) {
 		})
 	}
 }
+
+func Test_ConcurrentWrite(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "rancher-cli-test-*")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "cli2.json")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			conf := Config{
+				Path:          path,
+				CurrentServer: fmt.Sprintf("server-%d", i),
+				Servers: map[string]*ServerConfig{
+					fmt.Sprintf("server-%d", i): {URL: "https://example.com"},
+				},
+			}
+			assert.NoError(conf.Write())
+		}(i)
+	}
+	wg.Wait()
+
+	// whichever write came last, the file must hold one complete config
+	conf, err := LoadFromPath(path)
+	assert.NoError(err)
+	assert.Len(conf.Servers, 1)
+	assert.Contains(conf.Servers, conf.CurrentServer)
+
+	matches, err := filepath.Glob(filepath.Join(dir, "cli2.json.tmp-*"))
+	assert.NoError(err)
+	assert.Empty(matches)
+}
+
+func Test_ConcurrentUpdate(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "rancher-cli-test-*")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "cli2.json")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			conf := Config{Path: path}
+			assert.NoError(conf.Update(func(conf *Config) error {
+				conf.Servers[fmt.Sprintf("server-%d", i)] = &ServerConfig{URL: "https://example.com"}
+				return nil
+			}))
+		}(i)
+	}
+	wg.Wait()
+
+	// no update may be lost
+	conf, err := LoadFromPath(path)
+	assert.NoError(err)
+	assert.Len(conf.Servers, 10)
+}
+
+func Test_ResolveToken(t *testing.T) {
+	var ran []string
+	runTokenCommand = func(name string, args ...string) ([]byte, error) {
+		ran = append([]string{name}, args...)
+		return []byte("token-abc:secret\nurl: https://rancher.example.com\n"), nil
+	}
+	resolvedTokens = map[string]string{}
+	t.Cleanup(func() {
+		runTokenCommand = defaultRunTokenCommand
+		resolvedTokens = map[string]string{}
+	})
+	t.Setenv("RANCHER_TEST_TOKEN", "token-env:envsecret")
+
+	tests := []struct {
+		source  string
+		command []string
+		token   string
+	}{
+		{"vault:secret/rancher", []string{"vault", "kv", "get", "-field=token", "secret/rancher"}, "token-abc:secret"},
+		{"vault:secret/rancher#key", []string{"vault", "kv", "get", "-field=key", "secret/rancher"}, "token-abc:secret"},
+		{"command:pass show rancher", []string{"sh", "-c", "pass show rancher"}, "token-abc:secret"},
+		{"env:RANCHER_TEST_TOKEN", nil, "token-env:envsecret"},
+	}
+	for _, tt := range tests {
+		ran = nil
+		server := &ServerConfig{TokenSource: tt.source}
+		assert.NoError(t, server.ResolveToken(), tt.source)
+		assert.Equal(t, tt.command, ran, tt.source)
+		assert.Equal(t, tt.token, server.TokenKey, tt.source)
+	}
+
+	for _, source := range []string{"keyring:rancher", "vault:", "env:RANCHER_TEST_UNSET"} {
+		assert.Error(t, (&ServerConfig{TokenSource: source}).ResolveToken(), source)
+	}
+}
+
+func Test_ResolveTokenOnce(t *testing.T) {
+	runs := 0
+	runTokenCommand = func(name string, args ...string) ([]byte, error) {
+		runs++
+		return []byte("token-abc:secret\n"), nil
+	}
+	resolvedTokens = map[string]string{}
+	t.Cleanup(func() {
+		runTokenCommand = defaultRunTokenCommand
+		resolvedTokens = map[string]string{}
+	})
+
+	for i := 0; i < 3; i++ {
+		server := &ServerConfig{TokenSource: "command:pass show rancher"}
+		assert.NoError(t, server.ResolveToken())
+		assert.Equal(t, "token-abc:secret", server.TokenKey)
+	}
+	assert.Equal(t, 1, runs, "the source must be read once per process")
+}
+
+func Test_WriteOmitsSourcedTokens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cli2.json")
+	server := &ServerConfig{TokenSource: "env:TOKEN", AccessKey: "token-abc", SecretKey: "secret", TokenKey: "token-abc:secret"}
+	cf := Config{Path: path, Servers: map[string]*ServerConfig{"rancherDefault": server}}
+
+	assert.NoError(t, cf.Write())
+
+	loaded, err := LoadFromPath(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "env:TOKEN", loaded.Servers["rancherDefault"].TokenSource)
+	assert.Empty(t, loaded.Servers["rancherDefault"].TokenKey)
+	assert.Equal(t, "token-abc:secret", server.TokenKey, "the in-memory config keeps the token")
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// runTokenCommand runs a token source command and returns its output, it is a
+// variable so that tests can replace it.
+var runTokenCommand = defaultRunTokenCommand
+
+// resolvedTokens are the tokens read from each source by this process, so
+// that a source, which may prompt for a passphrase, is read only once.
+var (
+	resolvedTokensLock sync.Mutex
+	resolvedTokens     = map[string]string{}
+)
+
+func defaultRunTokenCommand(name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	// a password manager may prompt for a passphrase
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = os.Stderr
+	return cmd.Output()
+}
+
+// ResolveToken sets the keys of a server configured with a TokenSource by
+// reading the token from the source. A TokenSource is one of:
+//
+//	vault:PATH[#FIELD]  the FIELD of a Vault secret, "token" by default
+//	command:COMMAND     the output of a shell command, e.g. "command:pass show rancher"
+//	env:NAME            an environment variable
+//
+// The keys read from a source are never written to the config file, and each
+// source is read once per process.
+func (c *ServerConfig) ResolveToken() error {
+	if c.TokenSource == "" {
+		return nil
+	}
+
+	token, err := resolveTokenSource(c.TokenSource)
+	if err != nil {
+		return fmt.Errorf("reading the token from %s: %w", c.TokenSource, err)
+	}
+
+	accessKey, secretKey, ok := strings.Cut(token, ":")
+	if !ok || accessKey == "" || secretKey == "" {
+		return fmt.Errorf("the token read from %s is not a valid token", c.TokenSource)
+	}
+	c.AccessKey = accessKey
+	c.SecretKey = secretKey
+	c.TokenKey = token
+	return nil
+}
+
+// resolveTokenSource returns the token read from source, reading it only the
+// first time. Failures aren't kept, the source is read again next time.
+func resolveTokenSource(source string) (string, error) {
+	resolvedTokensLock.Lock()
+	defer resolvedTokensLock.Unlock()
+	if token, ok := resolvedTokens[source]; ok {
+		return token, nil
+	}
+	token, err := readTokenSource(source)
+	if err != nil {
+		return "", err
+	}
+	resolvedTokens[source] = token
+	return token, nil
+}
+
+func readTokenSource(source string) (string, error) {
+	kind, ref, _ := strings.Cut(source, ":")
+	if ref == "" {
+		return "", fmt.Errorf("invalid token source %q", source)
+	}
+
+	var output []byte
+	var err error
+	switch kind {
+	case "vault":
+		path, field, _ := strings.Cut(ref, "#")
+		if field == "" {
+			field = "token"
+		}
+		output, err = runTokenCommand("vault", "kv", "get", "-field="+field, path)
+	case "command":
+		if runtime.GOOS == "windows" {
+			output, err = runTokenCommand("cmd", "/C", ref)
+		} else {
+			output, err = runTokenCommand("sh", "-c", ref)
+		}
+	case "env":
+		value, ok := os.LookupEnv(ref)
+		if !ok {
+			return "", fmt.Errorf("%s is not set", ref)
+		}
+		output = []byte(value)
+	default:
+		return "", fmt.Errorf("unknown token source %q, expected vault:, command: or env:", kind)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// like 'pass', many tools print more lines after the secret
+	line, _, _ := bytes.Cut(bytes.TrimSpace(output), []byte("\n"))
+	return strings.TrimSpace(string(line)), nil
+}
+
+// withoutSourcedTokens returns the servers with the keys of those configured
+// with a TokenSource removed, so that they aren't written to the config file.
+func withoutSourcedTokens(servers map[string]*ServerConfig) map[string]*ServerConfig {
+	result := make(map[string]*ServerConfig, len(servers))
+	for name, server := range servers {
+		if server != nil && server.TokenSource != "" {
+			copied := *server
+			copied.AccessKey, copied.SecretKey, copied.TokenKey = "", "", ""
+			server = &copied
+		}
+		result[name] = server
+	}
+	return result
+}
@@ -4,8 +4,10 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/rancher/cli/cliclient"
 	"github.com/rancher/cli/cmd"
 	"github.com/rancher/cli/config"
 	"github.com/sirupsen/logrus"
@@ -54,7 +56,8 @@ Options:
 
 func main() {
 	if err := mainErr(); err != nil {
-		logrus.Fatal(err)
+		logrus.Error(err)
+		os.Exit(cmd.ExitCode(err))
 	}
 }
 
@@ -67,6 +70,11 @@ func mainErr() error {
 	app.Name = "rancher"
 	app.Usage = "Rancher CLI, managing containers one UTF-8 character at a time"
 	app.Before = func(ctx *cli.Context) error {
+		formatter, err := logFormatter(ctx.GlobalString("log-format"))
+		if err != nil {
+			return err
+		}
+		logrus.SetFormatter(formatter)
 		if ctx.GlobalBool("debug") {
 			logrus.SetLevel(logrus.DebugLevel)
 		}
@@ -81,9 +89,13 @@ func mainErr() error {
 			logrus.Warning(warning)
 		}
 
-		return nil
+		return cmd.ConfigureClients(ctx)
+	}
+	app.After = func(ctx *cli.Context) error {
+		return cmd.WriteRequestStats(os.Stderr)
 	}
 	app.Version = VERSION
+	app.EnableBashCompletion = true
 	app.Author = "Rancher Labs, Inc."
 	app.Email = ""
 
@@ -97,43 +109,169 @@ func mainErr() error {
 			Name:  "debug",
 			Usage: "Debug logging",
 		},
+		cli.StringFlag{
+			Name:   "log-format",
+			Usage:  "Format of the logs written to stderr, 'text' or 'json'",
+			EnvVar: "RANCHER_LOG_FORMAT",
+			Value:  "text",
+		},
+		cli.BoolFlag{
+			Name:  "debug-http",
+			Usage: "Log the method, URL, status and duration of every API request",
+		},
+		cli.BoolFlag{
+			Name:  "debug-http-bodies",
+			Usage: "Like --debug-http and also log request and response bodies with secrets redacted",
+		},
+		cli.DurationFlag{
+			Name:  "request-timeout",
+			Usage: "How long each API request may take before it's aborted",
+			Value: time.Minute,
+		},
+		cli.IntFlag{
+			Name:  "retries",
+			Usage: "How many times to retry requests failing with a transient error, 0 disables retries",
+			Value: cliclient.DefaultRetries,
+		},
+		cli.BoolFlag{
+			Name:  "stats",
+			Usage: "Print the API calls, bytes transferred and time spent per endpoint once the command finishes",
+		},
+		cli.BoolFlag{
+			Name:  "check-permissions",
+			Usage: "Check that the current token has the permissions a command needs before it changes anything, listing the missing ones",
+		},
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Print the requests that would change resources instead of sending them",
+		},
+		cli.StringFlag{
+			Name:   "audit-log",
+			Usage:  "Append every request changing a resource to this JSON lines file, see 'rancher audit'",
+			EnvVar: "RANCHER_AUDIT_LOG",
+		},
+		cli.StringFlag{
+			Name:  "dry-run-format",
+			Usage: "Format used to print requests with --dry-run, 'json' or 'yaml'",
+			Value: "json",
+		},
+		cli.BoolFlag{
+			Name:  "show-secrets",
+			Usage: "Print the values of secret answers instead of masking them",
+		},
+		cli.StringFlag{
+			Name:   "secret-patterns",
+			Usage:  "Comma separated substrings of the keys whose values are masked in answers and in the bodies logged by --debug-http-bodies",
+			EnvVar: "RANCHER_SECRET_PATTERNS",
+			Value:  cmd.DefaultSecretPatterns,
+		},
+		cli.BoolFlag{
+			Name:  "no-pager",
+			Usage: "Don't pipe long output through $PAGER",
+		},
+		cli.BoolFlag{
+			Name:  "no-color",
+			Usage: "Disable colored output, also disabled when NO_COLOR is set to a non-empty value",
+		},
+		cli.BoolFlag{
+			Name:  "cached",
+			Usage: "Print the output cached by the last successful run of a read-only command, such as ls, when the server is unreachable",
+		},
+		cli.DurationFlag{
+			Name:  "cache-ttl",
+			Usage: "How long names resolved to IDs are cached, 0 disables the cache",
+			Value: cmd.DefaultLookupCacheTTL,
+		},
+		cli.StringFlag{
+			Name:   "server",
+			Usage:  "Name of the configured server to use instead of the current server",
+			EnvVar: "RANCHER_CLI_SERVER",
+		},
+		cli.StringFlag{
+			Name:   "project",
+			Usage:  "ID of the project to use instead of the current project",
+			EnvVar: "RANCHER_PROJECT",
+		},
+		cli.StringFlag{
+			Name:  "as",
+			Usage: "ID or username of a user to send the requests as, for admins to see what the user can access",
+		},
 		cli.StringFlag{
 			Name:   "config, c",
-			Usage:  "Path to rancher config",
-			EnvVar: "RANCHER_CONFIG_DIR",
+			Usage:  "Path to the rancher config file or the directory holding it, may also be given after the command",
+			EnvVar: "RANCHER_CONFIG,RANCHER_CONFIG_DIR",
 			Value:  configDir,
 		},
 	}
 	app.Commands = []cli.Command{
+		cmd.AccessCommand(),
 		cmd.AppCommand(),
+		cmd.AuditCommand(),
+		cmd.BackupCommand(),
+		cmd.BulkCommand(),
+		cmd.CacheCommand(),
 		cmd.CatalogCommand(),
+		cmd.CISCommand(),
 		cmd.ClusterCommand(),
+		cmd.ConfigCommand(),
 		cmd.ContextCommand(),
+		cmd.DiffCommand(),
+		cmd.DNSRecordCommand(),
+		cmd.ExportCommand(),
+		cmd.FleetCommand(),
+		cmd.ForeachServerCommand(),
+		cmd.GatekeeperCommand(),
 		cmd.GlobalDNSCommand(),
+		cmd.IDCommand(),
 		cmd.InspectCommand(),
+		cmd.KubeAPICommand(),
 		cmd.KubectlCommand(),
 		cmd.LoginCommand(),
+		cmd.LonghornCommand(),
 		cmd.MachineCommand(),
+		cmd.MetricsCommand(),
 		cmd.MultiClusterAppCommand(),
 		cmd.NamespaceCommand(),
 		cmd.NodeCommand(),
+		cmd.NodePoolCommand(),
+		cmd.NotifierCommand(),
+		cmd.PipelineCommand(),
 		cmd.ProjectCommand(),
 		cmd.PsCommand(),
+		cmd.ReportCommand(),
+		cmd.RunCommand(),
 		cmd.ServerCommand(),
 		cmd.SettingsCommand(),
 		cmd.SSHCommand(),
 		cmd.UpCommand(),
+		cmd.VersionCommand(),
+		cmd.VMCommand(),
 		cmd.WaitCommand(),
 		cmd.CredentialCommand(),
 	}
+	app.OnUsageError = cmd.OnUsageError
+	cmd.SetUsageErrorHandler(app.Commands)
 
 	parsed, err := parseArgs(os.Args)
 	if err != nil {
 		logrus.Error(err)
-		os.Exit(1)
+		os.Exit(cmd.ExitCodeUsage)
 	}
+	parsed = hoistConfigFlag(parsed)
 
-	return app.Run(parsed)
+	return cmd.FinishOutputCache(app.Run(parsed))
+}
+
+// logFormatter returns the logrus formatter of a --log-format
+func logFormatter(format string) (logrus.Formatter, error) {
+	switch format {
+	case "text", "":
+		return &logrus.TextFormatter{}, nil
+	case "json":
+		return &logrus.JSONFormatter{}, nil
+	default:
+		return nil, cmd.NewUsageError(errors.Errorf("invalid log format %q, expected text or json", format))
+	}
 }
 
 var singleAlphaLetterRegxp = regexp.MustCompile("[a-zA-Z]")
@@ -161,3 +299,29 @@ func parseArgs(args []string) ([]string, error) {
 	}
 	return result, nil
 }
+
+// hoistConfigFlag moves a --config given after the command in front of it, so
+// that it applies to the command like the global flag. Arguments after a --
+// are left alone as they belong to another program.
+func hoistConfigFlag(args []string) []string {
+	if len(args) == 0 {
+		return args
+	}
+	var hoisted, rest []string
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			rest = append(rest, args[i:]...)
+			i = len(args)
+		case arg == "--config" && i+1 < len(args):
+			hoisted = append(hoisted, arg, args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--config="):
+			hoisted = append(hoisted, arg)
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return append(append([]string{args[0]}, hoisted...), rest...)
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"testing"
 
+	"github.com/sirupsen/logrus"
 	"gopkg.in/check.v1"
 )
 
@@ -62,3 +63,25 @@ func (m *MainTestSuite) TestParseArgs(c *check.C) {
 	}
 	c.Assert(r5, check.DeepEquals, []string{"rancher", "run", "--debug", "-"})
 }
+
+func (m *MainTestSuite) TestHoistConfigFlag(c *check.C) {
+	c.Assert(hoistConfigFlag([]string{"rancher", "clusters", "ls", "--config", "/tmp/ci.json", "--format", "json"}), check.DeepEquals,
+		[]string{"rancher", "--config", "/tmp/ci.json", "clusters", "ls", "--format", "json"})
+	c.Assert(hoistConfigFlag([]string{"rancher", "--debug", "ps", "--config=/tmp/ci.json"}), check.DeepEquals,
+		[]string{"rancher", "--config=/tmp/ci.json", "--debug", "ps"})
+	c.Assert(hoistConfigFlag([]string{"rancher", "ssh", "node-1", "--", "app", "--config", "x"}), check.DeepEquals,
+		[]string{"rancher", "ssh", "node-1", "--", "app", "--config", "x"})
+}
+
+func (m *MainTestSuite) TestLogFormatter(c *check.C) {
+	formatter, err := logFormatter("json")
+	c.Assert(err, check.IsNil)
+	c.Assert(formatter, check.FitsTypeOf, &logrus.JSONFormatter{})
+
+	formatter, err = logFormatter("text")
+	c.Assert(err, check.IsNil)
+	c.Assert(formatter, check.FitsTypeOf, &logrus.TextFormatter{})
+
+	_, err = logFormatter("xml")
+	c.Assert(err, check.NotNil)
+}